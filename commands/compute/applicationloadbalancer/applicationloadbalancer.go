@@ -26,7 +26,7 @@ func ApplicationLoadBalancerCmd() *core.Command {
 			Use:              "applicationloadbalancer",
 			Aliases:          []string{"alb"},
 			Short:            "Application Load Balancer Operations",
-			Long:             "The sub-commands of `ionosctl compute applicationloadbalancer` allow you to create, list, get, update, delete Application Load Balancers.",
+			Long:             "The sub-commands of `ionosctl compute applicationloadbalancer` allow you to create, list, get, update, delete Application Load Balancers, and to see the status of their Forwarding Rules and Targets.",
 			TraverseChildren: true,
 		},
 	}
@@ -37,6 +37,7 @@ func ApplicationLoadBalancerCmd() *core.Command {
 	applicationloadbalancerCmd.AddCommand(ApplicationLoadBalancerCreateCmd())
 	applicationloadbalancerCmd.AddCommand(ApplicationLoadBalancerUpdateCmd())
 	applicationloadbalancerCmd.AddCommand(ApplicationLoadBalancerDeleteCmd())
+	applicationloadbalancerCmd.AddCommand(ApplicationLoadBalancerStatusCmd())
 
 	applicationloadbalancerCmd.AddCommand(rule.ApplicationLoadBalancerRuleCmd())
 	applicationloadbalancerCmd.AddCommand(flowlog.ApplicationLoadBalancerFlowLogCmd())
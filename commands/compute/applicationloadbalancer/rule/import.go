@@ -0,0 +1,267 @@
+package rule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFile   = "file"
+	FlagDryRun = "dry-run"
+)
+
+func ApplicationLoadBalancerForwardingRuleImportCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "applicationloadbalancer",
+		Resource:  "rule",
+		Verb:      "import",
+		Aliases:   []string{"i"},
+		ShortDesc: "Create Forwarding Rules, HTTP Rules and Target Groups from a load balancer file",
+		LongDesc: `Use this command to create the Target Groups, and the Forwarding Rules with their HTTP Rules, of an Application Load Balancer from a YAML file. The keys are the flags of ` + "`targetgroup create`" + `, ` + "`rule create`" + ` and ` + "`rule httprule add`" + `. Each listener is a Forwarding Rule, and each of its routes is an HTTP Rule that forwards to a ` + "`target-group`" + `, redirects to a ` + "`location`" + ` or responds with a static ` + "`message`" + `:
+
+    target-groups:
+      - name: web
+        targets:
+          - ip: 10.0.0.5
+            port: 8080
+        health-check:
+          path: /healthz
+    listeners:
+      - name: https
+        listener-ip: 203.0.113.20
+        listener-port: 443
+        server-certificates: [CERTIFICATE_ID]
+        routes:
+          - name: api
+            host: api.example.com
+            path: /v1
+            target-group: web
+          - name: legacy
+            path: /old
+            location: https://www.example.com/new
+          - name: maintenance
+            message: Down for maintenance
+            status-code: 503
+
+A ` + "`host`" + ` starting with ` + "`*.`" + ` matches its subdomains, a ` + "`path`" + ` matches the paths starting with it, and ` + "`conditions`" + ` adds conditions with the keys of ` + "`rule httprule add`" + `. Routes refer to a Target Group of the file by its name, or to an existing Target Group by its ID.
+
+The resources are created one by one, waiting for each request. If a request fails, the resources created until then are deleted again. Use ` + "`--dry-run`" + ` to only validate the file and print what would be created.
+
+Required values to run command:
+
+* Data Center Id
+* Application Load Balancer Id
+* File`,
+		Example: `ionosctl compute applicationloadbalancer rule import --datacenter-id DATACENTER_ID --applicationloadbalancer-id APPLICATIONLOADBALANCER_ID --file lb.yaml
+ionosctl compute applicationloadbalancer rule import --datacenter-id DATACENTER_ID --applicationloadbalancer-id APPLICATIONLOADBALANCER_ID --file lb.yaml --dry-run`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return core.CheckRequiredFlags(c.Command, c.NS, cloudapiv6.ArgDataCenterId, cloudapiv6.ArgApplicationLoadBalancerId, FlagFile)
+		},
+		CmdRun:     RunApplicationLoadBalancerForwardingRuleImport,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgApplicationLoadBalancerId, "", "", cloudapiv6.ApplicationLoadBalancerId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgApplicationLoadBalancerId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ApplicationLoadBalancersIds(viper.GetString(core.GetFlagName(cmd.Name(), cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringFlag(FlagFile, "", "", "Path to the load balancer file. '-' reads from stdin", core.RequiredFlagOption())
+	cmd.AddBoolFlag(FlagDryRun, "", false, "Only validate the file and print what would be created")
+	cmd.AddColsFlag(allAlbForwardingRuleCols)
+
+	return cmd
+}
+
+func RunApplicationLoadBalancerForwardingRuleImport(c *core.CommandConfig) error {
+	dcId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+	albId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgApplicationLoadBalancerId))
+	c.Verbose(constants.DatacenterId, dcId)
+	c.Verbose(constants.ApplicationLoadBalancerId, albId)
+
+	var r io.Reader = c.Command.Command.InOrStdin()
+	if path := viper.GetString(core.GetFlagName(c.NS, FlagFile)); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	file, err := parseLbFile(r)
+	if err != nil {
+		return err
+	}
+
+	existing, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().ListForwardingRules(dcId, albId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed listing Forwarding Rules: %w", err)
+	}
+	if existing.Items != nil {
+		if err = checkListenerConflicts(file.Listeners, *existing.Items); err != nil {
+			return err
+		}
+	}
+
+	if viper.GetBool(core.GetFlagName(c.NS, FlagDryRun)) {
+		out := c.Command.Command.OutOrStdout()
+		for _, tg := range file.TargetGroups {
+			fmt.Fprintf(out, "create Target Group %s with %d targets\n", tg.Name, len(tg.Targets))
+		}
+		for _, l := range file.Listeners {
+			fmt.Fprintf(out, "create Forwarding Rule %s on %s:%d with %d HTTP Rules\n", l.Name, l.ListenerIp, l.ListenerPort, len(l.Routes))
+		}
+		return nil
+	}
+
+	imp := &lbImport{c: c, datacenterId: dcId, albId: albId}
+	rules, err := imp.run(file)
+	if err != nil {
+		return err
+	}
+
+	return c.Printer(allAlbForwardingRuleCols).Print(rules)
+}
+
+// checkListenerConflicts returns an error if a listener has the name, or the IP and port, of an existing Forwarding Rule.
+func checkListenerConflicts(listeners []lbListener, existing []ionoscloud.ApplicationLoadBalancerForwardingRule) error {
+	for _, rule := range existing {
+		p := rule.Properties
+		if p == nil {
+			continue
+		}
+		for _, l := range listeners {
+			if p.Name != nil && *p.Name == l.Name {
+				return fmt.Errorf("listener %s: a Forwarding Rule with this name already exists: %s", l.Name, *rule.Id)
+			}
+			if p.ListenerIp != nil && *p.ListenerIp == l.ListenerIp && p.ListenerPort != nil && *p.ListenerPort == l.ListenerPort {
+				return fmt.Errorf("listener %s: Forwarding Rule %s already listens on %s:%d", l.Name, *rule.Id, l.ListenerIp, l.ListenerPort)
+			}
+		}
+	}
+	return nil
+}
+
+// lbImport creates the resources of a load balancer file and keeps track of them, so they can be deleted again.
+type lbImport struct {
+	c            *core.CommandConfig
+	datacenterId string
+	albId        string
+
+	targetGroupIds map[string]string
+	// created are the Target Groups, and then the Forwarding Rules, in the order they were created.
+	created []lbCreated
+}
+
+type lbCreated struct {
+	Kind, Name, Id string
+}
+
+func (imp *lbImport) wait(resp *resources.Response) error {
+	if resp == nil {
+		return nil
+	}
+	imp.c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err := imp.c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run creates the Target Groups and then the Forwarding Rules of the file. If a request fails, the created resources
+// are deleted in reverse order.
+func (imp *lbImport) run(file *lbFile) ([]ionoscloud.ApplicationLoadBalancerForwardingRule, error) {
+	imp.targetGroupIds = map[string]string{}
+	var rules []ionoscloud.ApplicationLoadBalancerForwardingRule
+
+	err := func() error {
+		for _, tg := range file.TargetGroups {
+			imp.c.Verbose("Creating Target Group %s", tg.Name)
+			properties := tg.properties()
+			created, resp, err := imp.c.CloudApiV6Services.TargetGroups().Create(resources.TargetGroup{
+				TargetGroup: ionoscloud.TargetGroup{Properties: &properties},
+			})
+			if created != nil && created.Id != nil {
+				imp.targetGroupIds[tg.Name] = *created.Id
+				imp.created = append(imp.created, lbCreated{"Target Group", tg.Name, *created.Id})
+			}
+			if err == nil {
+				err = imp.wait(resp)
+			}
+			if err != nil {
+				return fmt.Errorf("failed creating Target Group %s: %w", tg.Name, err)
+			}
+		}
+
+		for _, l := range file.Listeners {
+			imp.c.Verbose("Creating Forwarding Rule %s", l.Name)
+			properties := l.properties(imp.targetGroupIds)
+			created, resp, err := imp.c.CloudApiV6Services.ApplicationLoadBalancers().CreateForwardingRule(imp.datacenterId, imp.albId,
+				resources.ApplicationLoadBalancerForwardingRule{
+					ApplicationLoadBalancerForwardingRule: ionoscloud.ApplicationLoadBalancerForwardingRule{Properties: &properties},
+				})
+			if created != nil && created.Id != nil {
+				imp.created = append(imp.created, lbCreated{"Forwarding Rule", l.Name, *created.Id})
+				rules = append(rules, created.ApplicationLoadBalancerForwardingRule)
+			}
+			if err == nil {
+				err = imp.wait(resp)
+			}
+			if err != nil {
+				return fmt.Errorf("failed creating Forwarding Rule %s: %w", l.Name, err)
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		return nil, imp.rollback(err)
+	}
+	return rules, nil
+}
+
+// rollback deletes the created resources, Forwarding Rules before the Target Groups they forward to.
+func (imp *lbImport) rollback(cause error) error {
+	errs := []error{cause}
+	for i := len(imp.created) - 1; i >= 0; i-- {
+		r := imp.created[i]
+		imp.c.Verbose("Rolling back: deleting %s %s (%s)", r.Kind, r.Name, r.Id)
+
+		var resp *resources.Response
+		var err error
+		if r.Kind == "Forwarding Rule" {
+			resp, err = imp.c.CloudApiV6Services.ApplicationLoadBalancers().DeleteForwardingRule(imp.datacenterId, imp.albId, r.Id)
+		} else {
+			resp, err = imp.c.CloudApiV6Services.TargetGroups().Delete(r.Id)
+		}
+		if err == nil {
+			err = imp.wait(resp)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed rolling back %s %s (%s), delete it manually: %w", r.Kind, r.Name, r.Id, err))
+		}
+	}
+	if len(imp.created) > 0 && len(errs) == 1 {
+		errs = append(errs, fmt.Errorf("rolled back %d created resources", len(imp.created)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,153 @@
+package rule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testLbFile = `
+target-groups:
+  - name: web
+    targets:
+      - ip: 10.0.0.5
+        port: 8080
+      - ip: 10.0.0.6
+        port: 8080
+        weight: 10
+    health-check:
+      check-interval: 5000
+      path: /healthz
+listeners:
+  - name: https
+    listener-ip: 203.0.113.20
+    listener-port: 443
+    server-certificates: [cert]
+    routes:
+      - name: api
+        host: "*.example.com"
+        path: /v1
+        conditions:
+          - condition-type: header
+            condition: exists
+            condition-key: Authorization
+        target-group: web
+      - name: legacy
+        path: /old
+        location: https://www.example.com/new
+      - name: maintenance
+        message: Down for maintenance
+`
+
+func TestParseLbFile(t *testing.T) {
+	f, err := parseLbFile(strings.NewReader(testLbFile))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	tg := f.TargetGroups[0].properties()
+	assert.Equal(t, "ROUND_ROBIN", *tg.Algorithm)
+	assert.Equal(t, []ionoscloud.TargetGroupTarget{
+		{Ip: pointer.From("10.0.0.5"), Port: pointer.From(int32(8080)), Weight: pointer.From(int32(1))},
+		{Ip: pointer.From("10.0.0.6"), Port: pointer.From(int32(8080)), Weight: pointer.From(int32(10))},
+	}, *tg.Targets)
+	assert.Equal(t, int32(5000), *tg.HealthCheck.CheckInterval)
+	assert.Equal(t, "/healthz", *tg.HttpHealthCheck.Path)
+	assert.Equal(t, "STATUS_CODE", *tg.HttpHealthCheck.MatchType)
+
+	rule := f.Listeners[0].properties(map[string]string{"web": "tg-1"})
+	assert.Equal(t, "HTTP", *rule.Protocol)
+	assert.Equal(t, []string{"cert"}, *rule.ServerCertificates)
+	httpRules := *rule.HttpRules
+	assert.Equal(t, ionoscloud.ApplicationLoadBalancerHttpRule{
+		Name: pointer.From("api"), Type: pointer.From("FORWARD"), TargetGroup: pointer.From("tg-1"),
+		Conditions: &[]ionoscloud.ApplicationLoadBalancerHttpRuleCondition{
+			{Type: pointer.From("HOST"), Condition: pointer.From("ENDS_WITH"), Value: pointer.From(".example.com")},
+			{Type: pointer.From("PATH"), Condition: pointer.From("STARTS_WITH"), Value: pointer.From("/v1")},
+			{Type: pointer.From("HEADER"), Condition: pointer.From("EXISTS"), Key: pointer.From("Authorization"), Negate: pointer.From(false)},
+		},
+	}, httpRules[0])
+	assert.Equal(t, "REDIRECT", *httpRules[1].Type)
+	assert.Equal(t, int32(301), *httpRules[1].StatusCode)
+	assert.Equal(t, "STATIC", *httpRules[2].Type)
+	assert.Equal(t, int32(503), *httpRules[2].StatusCode)
+	assert.Nil(t, httpRules[2].Conditions)
+}
+
+func TestParseLbFileErrors(t *testing.T) {
+	for name, tc := range map[string]struct{ file, err string }{
+		"no listeners": {"target-groups: []", "the load balancer file has no listeners"},
+		"unknown key":  {"listeners:\n  - name: a\n    port: 80", "field port not found"},
+		"unknown target group": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, routes: [{name: r, target-group: web}]}",
+			"listener a: route r: target group web is neither defined in the file nor a Target Group ID"},
+		"two actions": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, routes: [{name: r, location: /x, message: y}]}",
+			"listener a: route r: exactly one of target-group, location or message must be set"},
+		"redirect code": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, routes: [{name: r, location: /x, status-code: 200}]}",
+			"listener a: route r: status-code of a redirect must be one of 301, 302, 303, 307, 308"},
+		"duplicate listener": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80}\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 81}",
+			"listener a is defined more than once"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseLbFile(strings.NewReader(tc.file))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestCheckListenerConflicts(t *testing.T) {
+	listeners := []lbListener{{Name: "https", ListenerIp: "203.0.113.20", ListenerPort: 443}}
+	existing := []ionoscloud.ApplicationLoadBalancerForwardingRule{{Id: pointer.From("fr-1"), Properties: &ionoscloud.ApplicationLoadBalancerForwardingRuleProperties{
+		Name: pointer.From("old"), ListenerIp: pointer.From("203.0.113.20"), ListenerPort: pointer.From(int32(443)),
+	}}}
+	assert.EqualError(t, checkListenerConflicts(listeners, existing), "listener https: Forwarding Rule fr-1 already listens on 203.0.113.20:443")
+
+	(*existing[0].Properties.ListenerPort)++
+	assert.NoError(t, checkListenerConflicts(listeners, existing))
+}
+
+func TestRunApplicationLoadBalancerForwardingRuleImportRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lb.yaml")
+	if !assert.NoError(t, os.WriteFile(path, []byte(testLbFile), 0o600)) {
+		return
+	}
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgApplicationLoadBalancerId), "alb")
+		viper.Set(core.GetFlagName(cfg.NS, FlagFile), path)
+
+		rm.CloudApiV6Mocks.ApplicationLoadBalancer.EXPECT().ListForwardingRules("dc", "alb").Return(resources.ApplicationLoadBalancerForwardingRules{}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Create(gomock.Any()).Return(&resources.TargetGroup{TargetGroup: ionoscloud.TargetGroup{Id: pointer.From("tg-1")}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.ApplicationLoadBalancer.EXPECT().CreateForwardingRule("dc", "alb", gomock.Any()).
+			DoAndReturn(func(_, _ string, rule resources.ApplicationLoadBalancerForwardingRule) (*resources.ApplicationLoadBalancerForwardingRule, *resources.Response, error) {
+				assert.Equal(t, "tg-1", *(*rule.Properties.HttpRules)[0].TargetGroup)
+				return &resources.ApplicationLoadBalancerForwardingRule{}, nil, errors.New("listener IP is not assigned")
+			})
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Delete("tg-1").Return(&testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil).Times(2)
+
+		err := RunApplicationLoadBalancerForwardingRuleImport(cfg)
+		assert.EqualError(t, err, "failed creating Forwarding Rule https: listener IP is not assigned\nrolled back 1 created resources")
+	})
+}
@@ -0,0 +1,357 @@
+package rule
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// lbFile is a load balancer file with the Target Groups and the listeners of an Application Load Balancer.
+// The keys are the flags of 'targetgroup create', 'rule create' and 'rule httprule add'.
+type lbFile struct {
+	TargetGroups []lbTargetGroup `yaml:"target-groups"`
+	Listeners    []lbListener    `yaml:"listeners"`
+}
+
+type lbTargetGroup struct {
+	Name        string         `yaml:"name"`
+	Algorithm   string         `yaml:"algorithm,omitempty"`
+	Protocol    string         `yaml:"protocol,omitempty"`
+	Targets     []lbTarget     `yaml:"targets,omitempty"`
+	HealthCheck *lbHealthCheck `yaml:"health-check,omitempty"`
+}
+
+type lbTarget struct {
+	Ip                 string `yaml:"ip"`
+	Port               int32  `yaml:"port"`
+	Weight             *int32 `yaml:"weight,omitempty"`
+	HealthCheckEnabled *bool  `yaml:"health-check-enabled,omitempty"`
+	MaintenanceEnabled *bool  `yaml:"maintenance-enabled,omitempty"`
+}
+
+type lbHealthCheck struct {
+	CheckTimeout  *int32 `yaml:"check-timeout,omitempty"`
+	CheckInterval *int32 `yaml:"check-interval,omitempty"`
+	Retries       *int32 `yaml:"retries,omitempty"`
+	Path          string `yaml:"path,omitempty"`
+	Method        string `yaml:"method,omitempty"`
+	MatchType     string `yaml:"match-type,omitempty"`
+	Response      string `yaml:"response,omitempty"`
+	Regex         bool   `yaml:"regex,omitempty"`
+	Negate        bool   `yaml:"negate,omitempty"`
+}
+
+// lbListener is a Forwarding Rule, with its HTTP Rules as routes.
+type lbListener struct {
+	Name               string    `yaml:"name"`
+	ListenerIp         string    `yaml:"listener-ip"`
+	ListenerPort       int32     `yaml:"listener-port"`
+	ClientTimeout      *int32    `yaml:"client-timeout,omitempty"`
+	ServerCertificates []string  `yaml:"server-certificates,omitempty"`
+	Routes             []lbRoute `yaml:"routes,omitempty"`
+}
+
+// lbRoute is an HTTP Rule. Its type is FORWARD with target-group, REDIRECT with location, or STATIC with message.
+type lbRoute struct {
+	Name        string        `yaml:"name"`
+	Host        string        `yaml:"host,omitempty"`
+	Path        string        `yaml:"path,omitempty"`
+	Conditions  []lbCondition `yaml:"conditions,omitempty"`
+	TargetGroup string        `yaml:"target-group,omitempty"`
+	Location    string        `yaml:"location,omitempty"`
+	DropQuery   bool          `yaml:"drop-query,omitempty"`
+	Message     string        `yaml:"message,omitempty"`
+	ContentType string        `yaml:"content-type,omitempty"`
+	StatusCode  *int32        `yaml:"status-code,omitempty"`
+}
+
+type lbCondition struct {
+	ConditionType  string `yaml:"condition-type"`
+	Condition      string `yaml:"condition,omitempty"`
+	ConditionKey   string `yaml:"condition-key,omitempty"`
+	ConditionValue string `yaml:"condition-value,omitempty"`
+	Negate         bool   `yaml:"negate,omitempty"`
+}
+
+var (
+	lbConditionTypes = []string{"HEADER", "PATH", "QUERY", "METHOD", "HOST", "COOKIE", "SOURCE_IP"}
+	lbConditions     = []string{"EXISTS", "CONTAINS", "EQUALS", "MATCHES", "STARTS_WITH", "ENDS_WITH"}
+	lbRedirectCodes  = []int32{301, 302, 303, 307, 308}
+	uuidPattern      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// parseLbFile reads and validates a load balancer file. Routes refer to Target Groups by the name of a Target Group
+// of the file, or by the ID of an existing Target Group.
+func parseLbFile(r io.Reader) (*lbFile, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var f lbFile
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed reading load balancer file: %w", err)
+	}
+	if len(f.Listeners) == 0 {
+		return nil, fmt.Errorf("the load balancer file has no listeners")
+	}
+
+	groups := map[string]bool{}
+	for i, tg := range f.TargetGroups {
+		if tg.Name == "" {
+			return nil, fmt.Errorf("target group %d has no name", i+1)
+		}
+		if groups[tg.Name] {
+			return nil, fmt.Errorf("target group %s is defined more than once", tg.Name)
+		}
+		groups[tg.Name] = true
+		for _, t := range tg.Targets {
+			if net.ParseIP(t.Ip) == nil {
+				return nil, fmt.Errorf("target group %s: target IP %q is invalid", tg.Name, t.Ip)
+			}
+			if t.Port < 1 || t.Port > 65535 {
+				return nil, fmt.Errorf("target group %s: port of target %s must be between 1 and 65535", tg.Name, t.Ip)
+			}
+		}
+	}
+
+	listeners := map[string]bool{}
+	for i, l := range f.Listeners {
+		if l.Name == "" {
+			return nil, fmt.Errorf("listener %d has no name", i+1)
+		}
+		if listeners[l.Name] {
+			return nil, fmt.Errorf("listener %s is defined more than once", l.Name)
+		}
+		listeners[l.Name] = true
+		if net.ParseIP(l.ListenerIp) == nil {
+			return nil, fmt.Errorf("listener %s: listener-ip %q is invalid", l.Name, l.ListenerIp)
+		}
+		if l.ListenerPort < 1 || l.ListenerPort > 65535 {
+			return nil, fmt.Errorf("listener %s: listener-port must be between 1 and 65535", l.Name)
+		}
+
+		routes := map[string]bool{}
+		for j := range l.Routes {
+			route := &l.Routes[j]
+			if route.Name == "" {
+				return nil, fmt.Errorf("listener %s: route %d has no name", l.Name, j+1)
+			}
+			if routes[route.Name] {
+				return nil, fmt.Errorf("listener %s: route %s is defined more than once", l.Name, route.Name)
+			}
+			routes[route.Name] = true
+			if err := route.validate(groups); err != nil {
+				return nil, fmt.Errorf("listener %s: route %s: %w", l.Name, route.Name, err)
+			}
+		}
+	}
+	return &f, nil
+}
+
+func (r *lbRoute) validate(groups map[string]bool) error {
+	actions := 0
+	for _, set := range []bool{r.TargetGroup != "", r.Location != "", r.Message != ""} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("exactly one of target-group, location or message must be set")
+	}
+
+	switch {
+	case r.TargetGroup != "":
+		if !groups[r.TargetGroup] && !uuidPattern.MatchString(r.TargetGroup) {
+			return fmt.Errorf("target group %s is neither defined in the file nor a Target Group ID", r.TargetGroup)
+		}
+		if r.StatusCode != nil {
+			return fmt.Errorf("status-code is only valid with location or message")
+		}
+	case r.Location != "":
+		if r.StatusCode != nil && !slices.Contains(lbRedirectCodes, *r.StatusCode) {
+			return fmt.Errorf("status-code of a redirect must be one of 301, 302, 303, 307, 308")
+		}
+	case r.Message != "":
+		if r.StatusCode != nil && (*r.StatusCode < 200 || *r.StatusCode > 599) {
+			return fmt.Errorf("status-code of a static response must be between 200 and 599")
+		}
+	}
+
+	for i := range r.Conditions {
+		c := &r.Conditions[i]
+		c.ConditionType = strings.ToUpper(c.ConditionType)
+		c.Condition = strings.ToUpper(c.Condition)
+		if !slices.Contains(lbConditionTypes, c.ConditionType) {
+			return fmt.Errorf("condition-type must be one of %s", strings.Join(lbConditionTypes, ", "))
+		}
+		if c.ConditionType == "SOURCE_IP" {
+			if c.Condition != "" {
+				return fmt.Errorf("condition must not be set for condition-type SOURCE_IP")
+			}
+			continue
+		}
+		if !slices.Contains(lbConditions, c.Condition) {
+			return fmt.Errorf("condition must be one of %s", strings.Join(lbConditions, ", "))
+		}
+	}
+	return nil
+}
+
+// conditions returns the conditions of the route, with host and path as the first conditions. A host starting with
+// '*.' matches its subdomains, and a path matches the paths that start with it.
+func (r lbRoute) conditions() []ionoscloud.ApplicationLoadBalancerHttpRuleCondition {
+	var conditions []ionoscloud.ApplicationLoadBalancerHttpRuleCondition
+	if r.Host != "" {
+		condition, value := "EQUALS", r.Host
+		if strings.HasPrefix(r.Host, "*.") {
+			condition, value = "ENDS_WITH", r.Host[1:]
+		}
+		conditions = append(conditions, ionoscloud.ApplicationLoadBalancerHttpRuleCondition{
+			Type: pointer.From("HOST"), Condition: pointer.From(condition), Value: pointer.From(value),
+		})
+	}
+	if r.Path != "" {
+		conditions = append(conditions, ionoscloud.ApplicationLoadBalancerHttpRuleCondition{
+			Type: pointer.From("PATH"), Condition: pointer.From("STARTS_WITH"), Value: pointer.From(r.Path),
+		})
+	}
+	for _, c := range r.Conditions {
+		condition := ionoscloud.ApplicationLoadBalancerHttpRuleCondition{Type: pointer.From(c.ConditionType), Negate: pointer.From(c.Negate)}
+		if c.Condition != "" {
+			condition.Condition = pointer.From(c.Condition)
+		}
+		if c.ConditionKey != "" {
+			condition.Key = pointer.From(c.ConditionKey)
+		}
+		if c.ConditionValue != "" {
+			condition.Value = pointer.From(c.ConditionValue)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions
+}
+
+// httpRule returns the HTTP Rule of the route. targetGroupIds maps the names of the Target Groups of the file to
+// the IDs of the created Target Groups.
+func (r lbRoute) httpRule(targetGroupIds map[string]string) ionoscloud.ApplicationLoadBalancerHttpRule {
+	rule := ionoscloud.ApplicationLoadBalancerHttpRule{Name: pointer.From(r.Name)}
+	if conditions := r.conditions(); len(conditions) > 0 {
+		rule.Conditions = &conditions
+	}
+
+	switch {
+	case r.TargetGroup != "":
+		rule.Type = pointer.From("FORWARD")
+		id, ok := targetGroupIds[r.TargetGroup]
+		if !ok {
+			id = r.TargetGroup
+		}
+		rule.TargetGroup = pointer.From(id)
+	case r.Location != "":
+		rule.Type = pointer.From("REDIRECT")
+		rule.Location = pointer.From(r.Location)
+		rule.DropQuery = pointer.From(r.DropQuery)
+		rule.StatusCode = pointer.From(int32(301))
+		if r.StatusCode != nil {
+			rule.StatusCode = r.StatusCode
+		}
+	default:
+		rule.Type = pointer.From("STATIC")
+		rule.ResponseMessage = pointer.From(r.Message)
+		rule.ContentType = pointer.From("text/plain")
+		if r.ContentType != "" {
+			rule.ContentType = pointer.From(r.ContentType)
+		}
+		rule.StatusCode = pointer.From(int32(503))
+		if r.StatusCode != nil {
+			rule.StatusCode = r.StatusCode
+		}
+	}
+	return rule
+}
+
+// properties returns the Forwarding Rule of the listener, with its routes as HTTP Rules.
+func (l lbListener) properties(targetGroupIds map[string]string) ionoscloud.ApplicationLoadBalancerForwardingRuleProperties {
+	p := ionoscloud.ApplicationLoadBalancerForwardingRuleProperties{
+		Name:         pointer.From(l.Name),
+		Protocol:     pointer.From("HTTP"),
+		ListenerIp:   pointer.From(l.ListenerIp),
+		ListenerPort: pointer.From(l.ListenerPort),
+	}
+	if l.ClientTimeout != nil {
+		p.ClientTimeout = l.ClientTimeout
+	}
+	if len(l.ServerCertificates) > 0 {
+		p.ServerCertificates = pointer.From(l.ServerCertificates)
+	}
+	if len(l.Routes) > 0 {
+		rules := make([]ionoscloud.ApplicationLoadBalancerHttpRule, len(l.Routes))
+		for i, route := range l.Routes {
+			rules[i] = route.httpRule(targetGroupIds)
+		}
+		p.HttpRules = &rules
+	}
+	return p
+}
+
+// properties returns the Target Group, with the defaults of 'targetgroup create'.
+func (tg lbTargetGroup) properties() ionoscloud.TargetGroupProperties {
+	p := ionoscloud.TargetGroupProperties{
+		Name:      pointer.From(tg.Name),
+		Algorithm: pointer.From("ROUND_ROBIN"),
+		Protocol:  pointer.From("HTTP"),
+	}
+	if tg.Algorithm != "" {
+		p.Algorithm = pointer.From(strings.ToUpper(tg.Algorithm))
+	}
+	if tg.Protocol != "" {
+		p.Protocol = pointer.From(strings.ToUpper(tg.Protocol))
+	}
+
+	if len(tg.Targets) > 0 {
+		targets := make([]ionoscloud.TargetGroupTarget, len(tg.Targets))
+		for i, t := range tg.Targets {
+			targets[i] = ionoscloud.TargetGroupTarget{
+				Ip: pointer.From(t.Ip), Port: pointer.From(t.Port), Weight: pointer.From(int32(1)),
+				HealthCheckEnabled: t.HealthCheckEnabled, MaintenanceEnabled: t.MaintenanceEnabled,
+			}
+			if t.Weight != nil {
+				targets[i].Weight = t.Weight
+			}
+		}
+		p.Targets = &targets
+	}
+
+	if hc := tg.HealthCheck; hc != nil {
+		p.HealthCheck = &ionoscloud.TargetGroupHealthCheck{CheckTimeout: hc.CheckTimeout, CheckInterval: hc.CheckInterval, Retries: hc.Retries}
+		if hc.Path != "" || hc.Method != "" || hc.MatchType != "" || hc.Response != "" {
+			http := ionoscloud.TargetGroupHttpHealthCheck{
+				Path:      pointer.From("/"),
+				Method:    pointer.From("GET"),
+				MatchType: pointer.From("STATUS_CODE"),
+				Response:  pointer.From("200"),
+				Regex:     pointer.From(hc.Regex),
+				Negate:    pointer.From(hc.Negate),
+			}
+			if hc.Path != "" {
+				http.Path = pointer.From(hc.Path)
+			}
+			if hc.Method != "" {
+				http.Method = pointer.From(strings.ToUpper(hc.Method))
+			}
+			if hc.MatchType != "" {
+				http.MatchType = pointer.From(strings.ToUpper(hc.MatchType))
+			}
+			if hc.Response != "" {
+				http.Response = pointer.From(hc.Response)
+			}
+			p.HttpHealthCheck = &http
+		}
+	}
+	return p
+}
@@ -25,7 +25,7 @@ func ApplicationLoadBalancerRuleCmd() *core.Command {
 			Use:              "rule",
 			Aliases:          []string{"r", "forwardingrule"},
 			Short:            "Application Load Balancer Forwarding Rule Operations",
-			Long:             "The sub-commands of `ionosctl compute alb rule` allow you to create, list, get, update, delete Application Load Balancer Forwarding Rules.",
+			Long:             "The sub-commands of `ionosctl compute alb rule` allow you to create, list, get, update, delete Application Load Balancer Forwarding Rules, and to import them from a load balancer file.",
 			TraverseChildren: true,
 		},
 	}
@@ -35,6 +35,7 @@ func ApplicationLoadBalancerRuleCmd() *core.Command {
 	albRuleCmd.AddCommand(ApplicationLoadBalancerForwardingRuleCreateCmd())
 	albRuleCmd.AddCommand(ApplicationLoadBalancerForwardingRuleUpdateCmd())
 	albRuleCmd.AddCommand(ApplicationLoadBalancerForwardingRuleDeleteCmd())
+	albRuleCmd.AddCommand(ApplicationLoadBalancerForwardingRuleImportCmd())
 
 	albRuleCmd.AddCommand(httprule.AlbRuleHttpRuleCmd())
 
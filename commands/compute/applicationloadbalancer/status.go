@@ -0,0 +1,242 @@
+package applicationloadbalancer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var allStatusCols = []table.Column{
+	{Name: "LoadBalancer", JSONPath: "loadBalancer", Default: true},
+	{Name: "ForwardingRule", JSONPath: "forwardingRule", Default: true},
+	{Name: "Listener", JSONPath: "listener", Default: true},
+	{Name: "HttpRule", JSONPath: "httpRule", Default: true},
+	{Name: "Action", JSONPath: "action", Default: true},
+	{Name: "TargetGroup", JSONPath: "targetGroup", Default: true},
+	{Name: "Target", JSONPath: "target", Default: true},
+	{Name: "Weight", JSONPath: "weight", Default: true},
+	{Name: "Status", JSONPath: "status", Default: true},
+	{Name: "LoadBalancerId", JSONPath: "loadBalancerId"},
+	{Name: "ForwardingRuleId", JSONPath: "forwardingRuleId"},
+	{Name: "TargetGroupId", JSONPath: "targetGroupId"},
+	{Name: "State", JSONPath: "state"},
+}
+
+// statusRow is a Target of a Target Group that an HTTP Rule forwards to, or an HTTP Rule that does not forward.
+type statusRow struct {
+	LoadBalancer     string `json:"loadBalancer"`
+	LoadBalancerId   string `json:"loadBalancerId"`
+	ForwardingRule   string `json:"forwardingRule"`
+	ForwardingRuleId string `json:"forwardingRuleId"`
+	Listener         string `json:"listener"`
+	HttpRule         string `json:"httpRule"`
+	Action           string `json:"action"`
+	TargetGroup      string `json:"targetGroup,omitempty"`
+	TargetGroupId    string `json:"targetGroupId,omitempty"`
+	Target           string `json:"target,omitempty"`
+	Weight           *int32 `json:"weight,omitempty"`
+	// Status is active, drained (weight 0) or maintenance for Targets.
+	Status string `json:"status,omitempty"`
+	// State is the state of the Target Group.
+	State string `json:"state,omitempty"`
+}
+
+type statusInput struct {
+	ALB   ionoscloud.ApplicationLoadBalancer
+	Rules []ionoscloud.ApplicationLoadBalancerForwardingRule
+}
+
+func ApplicationLoadBalancerStatusCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "applicationloadbalancer",
+		Resource:  "applicationloadbalancer",
+		Verb:      "status",
+		Aliases:   []string{"st"},
+		ShortDesc: "Show the Forwarding Rules of Application Load Balancers with their Target Groups and Targets",
+		LongDesc: `Use this command to see how the traffic of Application Load Balancers is routed: the Forwarding Rules, their HTTP Rules, and the Targets of the Target Groups they forward to, with their weights. The status of a Target is active, drained if its weight is 0, or maintenance. Without ` + "`--applicationloadbalancer-id`" + `, all Application Load Balancers of the Data Center are shown.
+
+Required values to run command:
+
+* Data Center Id`,
+		Example: `ionosctl compute applicationloadbalancer status --datacenter-id DATACENTER_ID
+ionosctl compute applicationloadbalancer status --datacenter-id DATACENTER_ID --applicationloadbalancer-id APPLICATIONLOADBALANCER_ID`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return core.CheckRequiredFlags(c.Command, c.NS, cloudapiv6.ArgDataCenterId)
+		},
+		CmdRun:     RunApplicationLoadBalancerStatus,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgApplicationLoadBalancerId, cloudapiv6.ArgIdShort, "", cloudapiv6.ApplicationLoadBalancerId)
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgApplicationLoadBalancerId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ApplicationLoadBalancersIds(viper.GetString(core.GetFlagName(cmd.Name(), cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddColsFlag(allStatusCols)
+
+	return cmd
+}
+
+func RunApplicationLoadBalancerStatus(c *core.CommandConfig) error {
+	dcId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+	c.Verbose(constants.DatacenterId, dcId)
+
+	var albs []ionoscloud.ApplicationLoadBalancer
+	if albId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgApplicationLoadBalancerId)); albId != "" {
+		alb, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().Get(dcId, albId)
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return err
+		}
+		albs = append(albs, alb.ApplicationLoadBalancer)
+	} else {
+		list, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().List(dcId)
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return err
+		}
+		if list.Items != nil {
+			albs = *list.Items
+		}
+	}
+
+	var in []statusInput
+	for _, alb := range albs {
+		rules, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().ListForwardingRules(dcId, *alb.Id)
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return fmt.Errorf("failed listing Forwarding Rules of Application Load Balancer %s: %w", *alb.Id, err)
+		}
+		s := statusInput{ALB: alb}
+		if rules.Items != nil {
+			s.Rules = *rules.Items
+		}
+		in = append(in, s)
+	}
+
+	var targetGroups []ionoscloud.TargetGroup
+	if len(in) > 0 {
+		list, resp, err := c.CloudApiV6Services.TargetGroups().List()
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return fmt.Errorf("failed listing Target Groups: %w", err)
+		}
+		if list.Items != nil {
+			targetGroups = *list.Items
+		}
+	}
+
+	return c.Printer(allStatusCols).Print(statusRows(in, targetGroups))
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// statusRows joins the Forwarding Rules and HTTP Rules of the load balancers with the Targets of the Target Groups.
+func statusRows(in []statusInput, targetGroups []ionoscloud.TargetGroup) []statusRow {
+	groups := map[string]ionoscloud.TargetGroup{}
+	for _, tg := range targetGroups {
+		groups[str(tg.Id)] = tg
+	}
+
+	rows := []statusRow{}
+	for _, s := range in {
+		base := statusRow{LoadBalancerId: str(s.ALB.Id), LoadBalancer: str(s.ALB.Id)}
+		if s.ALB.Properties != nil && str(s.ALB.Properties.Name) != "" {
+			base.LoadBalancer = str(s.ALB.Properties.Name)
+		}
+
+		for _, rule := range s.Rules {
+			p := rule.Properties
+			if p == nil {
+				continue
+			}
+			row := base
+			row.ForwardingRule, row.ForwardingRuleId = str(p.Name), str(rule.Id)
+			if p.ListenerPort != nil {
+				row.Listener = fmt.Sprintf("%s:%d", str(p.ListenerIp), *p.ListenerPort)
+			}
+			if p.HttpRules == nil || len(*p.HttpRules) == 0 {
+				rows = append(rows, row)
+				continue
+			}
+
+			for _, http := range *p.HttpRules {
+				row := row
+				row.HttpRule, row.Action = str(http.Name), str(http.Type)
+				switch row.Action {
+				case "REDIRECT":
+					row.Target = str(http.Location)
+				case "STATIC":
+					if http.StatusCode != nil {
+						row.Target = fmt.Sprintf("%d %s", *http.StatusCode, str(http.ResponseMessage))
+					}
+				}
+				if http.TargetGroup == nil {
+					rows = append(rows, row)
+					continue
+				}
+
+				row.TargetGroupId, row.TargetGroup = *http.TargetGroup, *http.TargetGroup
+				tg, ok := groups[*http.TargetGroup]
+				if !ok || tg.Properties == nil {
+					row.State = "NOT_FOUND"
+					rows = append(rows, row)
+					continue
+				}
+				if str(tg.Properties.Name) != "" {
+					row.TargetGroup = str(tg.Properties.Name)
+				}
+				if tg.Metadata != nil {
+					row.State = str(tg.Metadata.State)
+				}
+				if tg.Properties.Targets == nil || len(*tg.Properties.Targets) == 0 {
+					rows = append(rows, row)
+					continue
+				}
+
+				for _, t := range *tg.Properties.Targets {
+					row := row
+					row.Target, row.Weight, row.Status = str(t.Ip), t.Weight, targetStatus(t)
+					if t.Port != nil {
+						row.Target = fmt.Sprintf("%s:%d", str(t.Ip), *t.Port)
+					}
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func targetStatus(t ionoscloud.TargetGroupTarget) string {
+	switch {
+	case t.MaintenanceEnabled != nil && *t.MaintenanceEnabled:
+		return "maintenance"
+	case t.Weight != nil && *t.Weight == 0:
+		return "drained"
+	}
+	return "active"
+}
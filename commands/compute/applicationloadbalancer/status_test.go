@@ -0,0 +1,53 @@
+package applicationloadbalancer
+
+import (
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusRows(t *testing.T) {
+	in := []statusInput{{
+		ALB: ionoscloud.ApplicationLoadBalancer{Id: pointer.From("alb-1"), Properties: &ionoscloud.ApplicationLoadBalancerProperties{Name: pointer.From("front")}},
+		Rules: []ionoscloud.ApplicationLoadBalancerForwardingRule{
+			{Id: pointer.From("fr-1"), Properties: &ionoscloud.ApplicationLoadBalancerForwardingRuleProperties{
+				Name: pointer.From("https"), ListenerIp: pointer.From("203.0.113.20"), ListenerPort: pointer.From(int32(443)),
+				HttpRules: &[]ionoscloud.ApplicationLoadBalancerHttpRule{
+					{Name: pointer.From("api"), Type: pointer.From("FORWARD"), TargetGroup: pointer.From("tg-1")},
+					{Name: pointer.From("old"), Type: pointer.From("REDIRECT"), Location: pointer.From("https://example.com")},
+					{Name: pointer.From("gone"), Type: pointer.From("FORWARD"), TargetGroup: pointer.From("tg-2")},
+				},
+			}},
+			{Id: pointer.From("fr-2"), Properties: &ionoscloud.ApplicationLoadBalancerForwardingRuleProperties{
+				Name: pointer.From("http"), ListenerIp: pointer.From("203.0.113.20"), ListenerPort: pointer.From(int32(80)),
+			}},
+		},
+	}}
+	targetGroups := []ionoscloud.TargetGroup{{
+		Id:       pointer.From("tg-1"),
+		Metadata: &ionoscloud.DatacenterElementMetadata{State: pointer.From("AVAILABLE")},
+		Properties: &ionoscloud.TargetGroupProperties{Name: pointer.From("web"), Targets: &[]ionoscloud.TargetGroupTarget{
+			{Ip: pointer.From("10.0.0.5"), Port: pointer.From(int32(8080)), Weight: pointer.From(int32(10))},
+			{Ip: pointer.From("10.0.0.6"), Port: pointer.From(int32(8080)), Weight: pointer.From(int32(0))},
+			{Ip: pointer.From("10.0.0.7"), Port: pointer.From(int32(8080)), Weight: pointer.From(int32(1)), MaintenanceEnabled: pointer.From(true)},
+		}},
+	}}
+
+	rows := statusRows(in, targetGroups)
+	var got [][]string
+	for _, r := range rows {
+		got = append(got, []string{r.ForwardingRule, r.Listener, r.HttpRule, r.Action, r.TargetGroup, r.Target, r.Status, r.State})
+	}
+	assert.Equal(t, [][]string{
+		{"https", "203.0.113.20:443", "api", "FORWARD", "web", "10.0.0.5:8080", "active", "AVAILABLE"},
+		{"https", "203.0.113.20:443", "api", "FORWARD", "web", "10.0.0.6:8080", "drained", "AVAILABLE"},
+		{"https", "203.0.113.20:443", "api", "FORWARD", "web", "10.0.0.7:8080", "maintenance", "AVAILABLE"},
+		{"https", "203.0.113.20:443", "old", "REDIRECT", "", "https://example.com", "", ""},
+		{"https", "203.0.113.20:443", "gone", "FORWARD", "tg-2", "", "", "NOT_FOUND"},
+		{"http", "203.0.113.20:80", "", "", "", "", "", ""},
+	}, got)
+	assert.Equal(t, "front", rows[0].LoadBalancer)
+	assert.Equal(t, int32(10), *rows[0].Weight)
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/sdk-go-bundle/shared/fileconfiguration"
+	"github.com/spf13/cobra"
+)
+
+var allFindingCols = []table.Column{
+	{Name: "Severity", JSONPath: "severity", Default: true},
+	{Name: "Check", JSONPath: "check", Default: true},
+	{Name: "Resource", JSONPath: "resource", Default: true},
+	{Name: "ResourceId", JSONPath: "resourceId"},
+	{Name: "Message", JSONPath: "message", Default: true},
+}
+
+func AuditCmd() *core.Command {
+	auditCmd := &core.Command{
+		Command: &cobra.Command{
+			Use:              "audit",
+			Short:            "Audit Operations",
+			Long:             "The sub-commands of `ionosctl compute audit` check Compute Engine resources for security issues, using only read requests.",
+			TraverseChildren: true,
+		},
+	}
+	auditCmd.AddColsFlag(allFindingCols)
+
+	auditCmd.AddCommand(AuditFirewallCmd())
+
+	return core.WithConfigOverride(auditCmd, []string{fileconfiguration.Cloud, "compute"}, "")
+}
@@ -0,0 +1,309 @@
+package audit
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+)
+
+// Severities of findings, from the most to the least severe.
+var severities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// Finding is an issue found by an audit.
+type Finding struct {
+	Severity   string `json:"severity"`
+	Check      string `json:"check"`
+	Resource   string `json:"resource"`
+	ResourceId string `json:"resourceId"`
+	Message    string `json:"message"`
+}
+
+// check is a kind of finding, with the severity of its findings.
+type check struct {
+	Id          string
+	Severity    string
+	Description string
+}
+
+var (
+	checkFirewallDisabled = check{"nic-firewall-disabled", "CRITICAL",
+		"NIC in a public LAN has its firewall disabled"}
+	checkFirewallEgressOnly = check{"nic-firewall-egress-only", "CRITICAL",
+		"NIC in a public LAN only filters egress traffic"}
+	checkOpenAll = check{"rule-open-all", "HIGH",
+		"Firewall rule allows all traffic from any source"}
+	checkOpenSensitivePort = check{"rule-open-sensitive-port", "HIGH",
+		"Firewall rule allows a sensitive port from any source"}
+	checkFailoverMismatch = check{"failover-firewall-mismatch", "MEDIUM",
+		"NICs of an IP failover group have different firewall settings"}
+	checkIpBlockUnused = check{"ipblock-unused", "MEDIUM",
+		"IP block has no IP in use"}
+	checkIpBlockIpsUnused = check{"ipblock-ips-unused", "LOW",
+		"IP block has IPs that are not in use"}
+)
+
+var checks = []check{
+	checkFirewallDisabled, checkFirewallEgressOnly, checkOpenAll, checkOpenSensitivePort,
+	checkFailoverMismatch, checkIpBlockUnused, checkIpBlockIpsUnused,
+}
+
+// sensitivePort is a port of a service that should not be reachable from any source.
+type sensitivePort struct {
+	Protocol string
+	Port     int32
+	Service  string
+}
+
+var sensitivePorts = []sensitivePort{
+	{"TCP", 21, "FTP"},
+	{"TCP", 22, "SSH"},
+	{"TCP", 23, "Telnet"},
+	{"UDP", 161, "SNMP"},
+	{"TCP", 445, "SMB"},
+	{"TCP", 1433, "MSSQL"},
+	{"TCP", 2375, "Docker API"},
+	{"TCP", 2379, "etcd"},
+	{"TCP", 3306, "MySQL"},
+	{"TCP", 3389, "RDP"},
+	{"TCP", 5432, "PostgreSQL"},
+	{"TCP", 5900, "VNC"},
+	{"TCP", 6379, "Redis"},
+	{"TCP", 6443, "Kubernetes API"},
+	{"TCP", 9200, "Elasticsearch"},
+	{"TCP", 11211, "Memcached"},
+	{"UDP", 11211, "Memcached"},
+	{"TCP", 27017, "MongoDB"},
+}
+
+// auditNic is a NIC of a server, with its firewall rules.
+type auditNic struct {
+	Server ionoscloud.Server
+	Nic    ionoscloud.Nic
+	Rules  []ionoscloud.FirewallRule
+}
+
+// firewallInput is the state of a data center that the firewall audit checks.
+type firewallInput struct {
+	Location string
+	Lans     []ionoscloud.Lan
+	Nics     []auditNic
+	IpBlocks []ionoscloud.IpBlock
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func (n auditNic) name() string {
+	nic := str(n.Nic.Id)
+	if n.Nic.Properties != nil && str(n.Nic.Properties.Name) != "" {
+		nic = str(n.Nic.Properties.Name)
+	}
+	server := str(n.Server.Id)
+	if n.Server.Properties != nil && str(n.Server.Properties.Name) != "" {
+		server = str(n.Server.Properties.Name)
+	}
+	return fmt.Sprintf("server %s / NIC %s", server, nic)
+}
+
+// filtersIngress reports whether the firewall of the NIC filters incoming traffic.
+func (n auditNic) filtersIngress() bool {
+	p := n.Nic.Properties
+	if p == nil || p.FirewallActive == nil || !*p.FirewallActive {
+		return false
+	}
+	return p.FirewallType == nil || !strings.EqualFold(*p.FirewallType, "EGRESS")
+}
+
+func (n auditNic) ips() []string {
+	if n.Nic.Properties == nil || n.Nic.Properties.Ips == nil {
+		return nil
+	}
+	return *n.Nic.Properties.Ips
+}
+
+func newFinding(c check, resource, resourceId, format string, a ...any) Finding {
+	return Finding{Severity: c.Severity, Check: c.Id, Resource: resource, ResourceId: resourceId, Message: fmt.Sprintf(format, a...)}
+}
+
+// publicLans returns the IDs of the public LANs.
+func publicLans(lans []ionoscloud.Lan) map[int32]bool {
+	public := map[int32]bool{}
+	for _, lan := range lans {
+		if lan.Id == nil || lan.Properties == nil || lan.Properties.Public == nil || !*lan.Properties.Public {
+			continue
+		}
+		if id, err := strconv.ParseInt(*lan.Id, 10, 32); err == nil {
+			public[int32(id)] = true
+		}
+	}
+	return public
+}
+
+// auditFirewall returns the findings of the data center, sorted by severity.
+func auditFirewall(in firewallInput) []Finding {
+	public := publicLans(in.Lans)
+	var findings []Finding
+	nicsById := map[string]auditNic{}
+	for _, n := range in.Nics {
+		nicsById[str(n.Nic.Id)] = n
+		if n.Nic.Properties == nil || n.Nic.Properties.Lan == nil || !public[*n.Nic.Properties.Lan] {
+			continue
+		}
+		lan := *n.Nic.Properties.Lan
+
+		switch {
+		case n.Nic.Properties.FirewallActive == nil || !*n.Nic.Properties.FirewallActive:
+			findings = append(findings, newFinding(checkFirewallDisabled, n.name(), str(n.Nic.Id),
+				"the firewall is disabled, so all ports of %s in public LAN %d are reachable", strings.Join(n.ips(), ", "), lan))
+			continue
+		case !n.filtersIngress():
+			findings = append(findings, newFinding(checkFirewallEgressOnly, n.name(), str(n.Nic.Id),
+				"the firewall only filters egress traffic, so all ports of %s in public LAN %d are reachable", strings.Join(n.ips(), ", "), lan))
+			continue
+		}
+
+		for _, rule := range n.Rules {
+			if f, ok := auditRule(n, rule); ok {
+				findings = append(findings, f)
+			}
+		}
+	}
+
+	findings = append(findings, auditFailover(in.Lans, nicsById)...)
+	findings = append(findings, auditIpBlocks(in.Location, in.IpBlocks)...)
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return slices.Index(severities, a.Severity) - slices.Index(severities, b.Severity)
+	})
+	return findings
+}
+
+// auditRule returns a finding if an ingress rule allows a sensitive port, or all traffic, from any source.
+func auditRule(n auditNic, rule ionoscloud.FirewallRule) (Finding, bool) {
+	p := rule.Properties
+	if p == nil || (p.Type != nil && !strings.EqualFold(*p.Type, "INGRESS")) || str(p.SourceMac) != "" {
+		return Finding{}, false
+	}
+	if source := str(p.SourceIp); source != "" && source != "0.0.0.0/0" && source != "::/0" {
+		return Finding{}, false
+	}
+
+	name := str(p.Name)
+	if name == "" {
+		name = str(rule.Id)
+	}
+	resource := fmt.Sprintf("%s / rule %s", n.name(), name)
+	protocol := strings.ToUpper(str(p.Protocol))
+	if protocol == "ANY" {
+		return newFinding(checkOpenAll, resource, str(rule.Id), "the rule allows all protocols and ports from any source"), true
+	}
+
+	var open []string
+	for _, s := range sensitivePorts {
+		if s.Protocol != protocol {
+			continue
+		}
+		if p.PortRangeStart != nil && *p.PortRangeStart > s.Port || p.PortRangeEnd != nil && *p.PortRangeEnd < s.Port {
+			continue
+		}
+		open = append(open, fmt.Sprintf("%d (%s)", s.Port, s.Service))
+	}
+	if len(open) == 0 {
+		return Finding{}, false
+	}
+	return newFinding(checkOpenSensitivePort, resource, str(rule.Id),
+		"the rule allows %s %s from any source", protocol, strings.Join(open, ", ")), true
+}
+
+// auditFailover returns a finding for every IP failover group of a public LAN whose NICs do not all filter ingress traffic
+// the same way, so a failover changes which ports are reachable.
+func auditFailover(lans []ionoscloud.Lan, nics map[string]auditNic) []Finding {
+	var findings []Finding
+	for _, lan := range lans {
+		p := lan.Properties
+		if p == nil || p.IpFailover == nil || p.Public == nil || !*p.Public {
+			continue
+		}
+		groups := map[string][]auditNic{}
+		var ips []string
+		for _, f := range *p.IpFailover {
+			n, ok := nics[str(f.NicUuid)]
+			if !ok {
+				continue
+			}
+			if _, ok := groups[str(f.Ip)]; !ok {
+				ips = append(ips, str(f.Ip))
+			}
+			groups[str(f.Ip)] = append(groups[str(f.Ip)], n)
+		}
+
+		for _, ip := range ips {
+			var filtered, unfiltered []string
+			for _, n := range groups[ip] {
+				if n.filtersIngress() {
+					filtered = append(filtered, n.name())
+				} else {
+					unfiltered = append(unfiltered, n.name())
+				}
+			}
+			if len(filtered) == 0 || len(unfiltered) == 0 {
+				continue
+			}
+			findings = append(findings, newFinding(checkFailoverMismatch, fmt.Sprintf("LAN %s / failover IP %s", str(lan.Id), ip), str(lan.Id),
+				"%s filter ingress traffic, but %s do not, so all ports of %s are reachable after a failover",
+				strings.Join(filtered, ", "), strings.Join(unfiltered, ", "), ip))
+		}
+	}
+	return findings
+}
+
+// auditIpBlocks returns findings for the IP blocks of the location with IPs that no resource uses.
+func auditIpBlocks(location string, blocks []ionoscloud.IpBlock) []Finding {
+	var findings []Finding
+	for _, b := range blocks {
+		p := b.Properties
+		if p == nil || p.Ips == nil || str(p.Location) != location {
+			continue
+		}
+		used := map[string]bool{}
+		if p.IpConsumers != nil {
+			for _, c := range *p.IpConsumers {
+				used[str(c.Ip)] = true
+			}
+		}
+		var unused []string
+		for _, ip := range *p.Ips {
+			if !used[ip] {
+				unused = append(unused, ip)
+			}
+		}
+
+		resource := "IP block " + str(b.Id)
+		if str(p.Name) != "" {
+			resource = "IP block " + str(p.Name)
+		}
+		switch {
+		case len(unused) == 0:
+		case len(unused) == len(*p.Ips):
+			findings = append(findings, newFinding(checkIpBlockUnused, resource, str(b.Id),
+				"none of the %d IPs is in use: %s", len(unused), strings.Join(unused, ", ")))
+		default:
+			findings = append(findings, newFinding(checkIpBlockIpsUnused, resource, str(b.Id),
+				"%d of %d IPs are not in use: %s", len(unused), len(*p.Ips), strings.Join(unused, ", ")))
+		}
+	}
+	return findings
+}
+
+// atLeast reports whether any finding is at least as severe as the severity.
+func atLeast(findings []Finding, severity string) bool {
+	limit := slices.Index(severities, severity)
+	return slices.ContainsFunc(findings, func(f Finding) bool { return slices.Index(severities, f.Severity) <= limit })
+}
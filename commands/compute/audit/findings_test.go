@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/sarif"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func testNic(id string, lan int32, firewall bool, firewallType string, rules ...ionoscloud.FirewallruleProperties) auditNic {
+	n := auditNic{
+		Server: ionoscloud.Server{Id: pointer.From("srv-" + id), Properties: &ionoscloud.ServerProperties{Name: pointer.From("web-" + id)}},
+		Nic: ionoscloud.Nic{Id: pointer.From(id), Properties: &ionoscloud.NicProperties{
+			Lan: pointer.From(lan), FirewallActive: pointer.From(firewall), FirewallType: pointer.From(firewallType),
+			Ips: &[]string{"203.0.113." + id},
+		}},
+	}
+	for i := range rules {
+		n.Rules = append(n.Rules, ionoscloud.FirewallRule{Id: pointer.From("rule-" + id), Properties: &rules[i]})
+	}
+	return n
+}
+
+func testLan(id string, public bool, failover ...ionoscloud.IPFailover) ionoscloud.Lan {
+	return ionoscloud.Lan{Id: pointer.From(id), Properties: &ionoscloud.LanProperties{Public: pointer.From(public), IpFailover: &failover}}
+}
+
+func TestAuditFirewall(t *testing.T) {
+	in := firewallInput{
+		Location: "de/txl",
+		Lans: []ionoscloud.Lan{
+			testLan("1", true, ionoscloud.IPFailover{Ip: pointer.From("203.0.113.100"), NicUuid: pointer.From("3")},
+				ionoscloud.IPFailover{Ip: pointer.From("203.0.113.100"), NicUuid: pointer.From("4")}),
+			testLan("2", false),
+		},
+		Nics: []auditNic{
+			testNic("1", 2, false, "INGRESS"), // private LAN
+			testNic("2", 1, true, "EGRESS"),
+			testNic("3", 1, true, "INGRESS",
+				ionoscloud.FirewallruleProperties{Name: pointer.From("ssh"), Protocol: pointer.From("TCP"),
+					PortRangeStart: pointer.From(int32(22)), PortRangeEnd: pointer.From(int32(22))},
+				ionoscloud.FirewallruleProperties{Name: pointer.From("office"), Protocol: pointer.From("TCP"), SourceIp: pointer.From("198.51.100.1")},
+				ionoscloud.FirewallruleProperties{Name: pointer.From("https"), Protocol: pointer.From("TCP"),
+					PortRangeStart: pointer.From(int32(443)), PortRangeEnd: pointer.From(int32(443))},
+				ionoscloud.FirewallruleProperties{Name: pointer.From("db"), Protocol: pointer.From("TCP"), SourceIp: pointer.From("0.0.0.0/0"),
+					PortRangeStart: pointer.From(int32(3000)), PortRangeEnd: pointer.From(int32(6000))},
+				ionoscloud.FirewallruleProperties{Name: pointer.From("all"), Protocol: pointer.From("ANY"), Type: pointer.From("INGRESS")},
+				ionoscloud.FirewallruleProperties{Name: pointer.From("out"), Protocol: pointer.From("ANY"), Type: pointer.From("EGRESS")},
+			),
+			testNic("4", 1, false, "INGRESS"),
+		},
+		IpBlocks: []ionoscloud.IpBlock{
+			{Id: pointer.From("b1"), Properties: &ionoscloud.IpBlockProperties{Location: pointer.From("de/txl"), Ips: &[]string{"198.51.100.10", "198.51.100.11"},
+				IpConsumers: &[]ionoscloud.IpConsumer{{Ip: pointer.From("198.51.100.10")}}}},
+			{Id: pointer.From("b2"), Properties: &ionoscloud.IpBlockProperties{Name: pointer.From("spare"), Location: pointer.From("de/txl"), Ips: &[]string{"198.51.100.20"}}},
+			{Id: pointer.From("b3"), Properties: &ionoscloud.IpBlockProperties{Location: pointer.From("us/las"), Ips: &[]string{"192.0.2.1"}}},
+		},
+	}
+
+	findings := auditFirewall(in)
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Severity+" "+f.Check+" "+f.Resource)
+	}
+	assert.Equal(t, []string{
+		"CRITICAL nic-firewall-egress-only server web-2 / NIC 2",
+		"CRITICAL nic-firewall-disabled server web-4 / NIC 4",
+		"HIGH rule-open-sensitive-port server web-3 / NIC 3 / rule ssh",
+		"HIGH rule-open-sensitive-port server web-3 / NIC 3 / rule db",
+		"HIGH rule-open-all server web-3 / NIC 3 / rule all",
+		"MEDIUM failover-firewall-mismatch LAN 1 / failover IP 203.0.113.100",
+		"MEDIUM ipblock-unused IP block spare",
+		"LOW ipblock-ips-unused IP block b1",
+	}, got)
+	assert.Equal(t, "the rule allows TCP 3306 (MySQL), 3389 (RDP), 5432 (PostgreSQL), 5900 (VNC) from any source", findings[3].Message)
+	assert.Equal(t, "1 of 2 IPs are not in use: 198.51.100.11", findings[7].Message)
+
+	assert.True(t, atLeast(findings, "CRITICAL"))
+	assert.False(t, atLeast(findings[5:], "HIGH"))
+	assert.True(t, atLeast(findings[5:], "MEDIUM"))
+}
+
+func TestWriteSarif(t *testing.T) {
+	var b bytes.Buffer
+	err := writeSarif(&b, []Finding{{Severity: "CRITICAL", Check: checkFirewallDisabled.Id, Resource: "server a / NIC b", ResourceId: "b", Message: "disabled"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var log sarif.Log
+	if !assert.NoError(t, json.Unmarshal(b.Bytes(), &log)) {
+		return
+	}
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Len(t, log.Runs[0].Tool.Driver.Rules, len(checks))
+	assert.Equal(t, "CRITICAL", log.Runs[0].Tool.Driver.Rules[0].Properties["tags"].([]any)[1])
+	assert.Equal(t, sarif.Result{
+		RuleID:  "nic-firewall-disabled",
+		Level:   "error",
+		Message: sarif.Message{Text: "server a / NIC b: disabled"},
+		Locations: []sarif.Location{{LogicalLocations: []sarif.LogicalLocation{{
+			Name: "server a / NIC b", FullyQualifiedName: "b", Kind: "resource",
+		}}}},
+	}, log.Runs[0].Results[0])
+}
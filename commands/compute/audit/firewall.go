@@ -0,0 +1,186 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFailOn       = "fail-on"
+	FlagReportFormat = "report-format"
+	FlagReportFile   = "report-file"
+
+	reportFormatSARIF = "sarif"
+)
+
+func AuditFirewallCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "audit",
+		Resource:  "audit",
+		Verb:      "firewall",
+		Aliases:   []string{"fw"},
+		ShortDesc: "Report the exposure of a Data Center through its firewalls",
+		LongDesc: `Use this command to check the Servers, NICs, Firewall Rules, IP Failover groups and IP Blocks of a Data Center for exposure to the internet. Only read requests are made. The findings are sorted by severity:
+
+* CRITICAL: NICs in a public LAN whose firewall is disabled, or only filters egress traffic
+* HIGH: Firewall Rules of NICs in a public LAN that allow all traffic, or a sensitive port such as SSH, RDP or a database, from any source
+* MEDIUM: IP Failover groups whose NICs do not all filter ingress traffic, and IP Blocks of the location of the Data Center without IPs in use
+* LOW: IP Blocks with some IPs not in use
+
+Use ` + "`--report-format sarif`" + ` to write a SARIF 2.1.0 log for code scanning tools instead of the table, to ` + "`--report-file`" + ` or stdout, and ` + "`--fail-on`" + ` to exit with an error in CI if there are findings of a severity or higher. The report is always written completely before the command fails.
+
+Required values to run command:
+
+* Data Center Id`,
+		Example: `ionosctl compute audit firewall --datacenter-id DATACENTER_ID
+ionosctl compute audit firewall --datacenter-id DATACENTER_ID --fail-on HIGH --report-format sarif --report-file firewall.sarif`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			if err := core.CheckRequiredFlags(c.Command, c.NS, cloudapiv6.ArgDataCenterId); err != nil {
+				return err
+			}
+			if viper.IsSet(core.GetFlagName(c.NS, FlagReportFile)) && !viper.IsSet(core.GetFlagName(c.NS, FlagReportFormat)) {
+				return fmt.Errorf("--%s requires --%s", FlagReportFile, FlagReportFormat)
+			}
+			return nil
+		},
+		CmdRun:     RunAuditFirewall,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddSetFlag(FlagReportFormat, "", "", []string{reportFormatSARIF}, "Write a report in this format instead of the table")
+	cmd.AddStringFlag(FlagReportFile, "", "", "Write the report to this file instead of stdout")
+	cmd.AddSetFlag(FlagFailOn, "", "", severities, "Exit with an error if there are findings of this severity or higher")
+
+	return cmd
+}
+
+func RunAuditFirewall(c *core.CommandConfig) error {
+	in, err := collectFirewallInput(c, viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId)))
+	if err != nil {
+		return err
+	}
+	findings := auditFirewall(in)
+
+	if err = writeFindings(c, findings); err != nil {
+		return err
+	}
+
+	if severity := viper.GetString(core.GetFlagName(c.NS, FlagFailOn)); severity != "" && atLeast(findings, severity) {
+		return fmt.Errorf("found issues of severity %s or higher", severity)
+	}
+	return nil
+}
+
+// writeFindings prints the findings as a table, or writes them as a report in --report-format.
+func writeFindings(c *core.CommandConfig, findings []Finding) error {
+	if viper.GetString(core.GetFlagName(c.NS, FlagReportFormat)) == "" {
+		return c.Printer(allFindingCols).Print(findings)
+	}
+
+	var w io.Writer = c.Command.Command.OutOrStdout()
+	if path := viper.GetString(core.GetFlagName(c.NS, FlagReportFile)); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return writeSarif(w, findings)
+}
+
+// collectFirewallInput reads the LANs, the NICs of the Servers with the Firewall Rules of NICs in public LANs,
+// and the IP Blocks of a Data Center.
+func collectFirewallInput(c *core.CommandConfig, datacenterId string) (firewallInput, error) {
+	var in firewallInput
+
+	dc, resp, err := c.CloudApiV6Services.DataCenters().Get(datacenterId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return in, fmt.Errorf("failed getting Data Center %s: %w", datacenterId, err)
+	}
+	if dc.Properties != nil {
+		in.Location = str(dc.Properties.Location)
+	}
+
+	lans, resp, err := c.CloudApiV6Services.Lans().List(datacenterId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return in, fmt.Errorf("failed listing LANs: %w", err)
+	}
+	if lans.Items != nil {
+		in.Lans = *lans.Items
+	}
+	public := publicLans(in.Lans)
+
+	servers, resp, err := c.CloudApiV6Services.Servers().List(datacenterId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return in, fmt.Errorf("failed listing Servers: %w", err)
+	}
+	var serverItems []ionoscloud.Server
+	if servers.Items != nil {
+		serverItems = *servers.Items
+	}
+	for _, server := range serverItems {
+		nics, resp, err := c.CloudApiV6Services.Nics().List(datacenterId, str(server.Id))
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return in, fmt.Errorf("failed listing NICs of Server %s: %w", str(server.Id), err)
+		}
+		if nics.Items == nil {
+			continue
+		}
+
+		for _, nic := range *nics.Items {
+			n := auditNic{Server: server, Nic: nic}
+			if nic.Properties != nil && nic.Properties.Lan != nil && public[*nic.Properties.Lan] {
+				rules, resp, err := c.CloudApiV6Services.FirewallRules().List(datacenterId, str(server.Id), str(nic.Id))
+				if resp != nil {
+					c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+				}
+				if err != nil {
+					return in, fmt.Errorf("failed listing Firewall Rules of NIC %s: %w", str(nic.Id), err)
+				}
+				if rules.Items != nil {
+					n.Rules = *rules.Items
+				}
+			}
+			in.Nics = append(in.Nics, n)
+		}
+	}
+
+	blocks, resp, err := c.CloudApiV6Services.IpBlocks().List()
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return in, fmt.Errorf("failed listing IP Blocks: %w", err)
+	}
+	if blocks.Items != nil {
+		in.IpBlocks = *blocks.Items
+	}
+
+	return in, nil
+}
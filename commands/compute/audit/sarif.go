@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"io"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/sarif"
+)
+
+// sarifLevels maps severities to SARIF levels and to the security-severity scores of GitHub code scanning.
+var sarifLevels = map[string]struct{ Level, Score string }{
+	"CRITICAL": {sarif.LevelError, "9.5"},
+	"HIGH":     {sarif.LevelError, "8.0"},
+	"MEDIUM":   {sarif.LevelWarning, "5.0"},
+	"LOW":      {sarif.LevelNote, "2.0"},
+}
+
+func writeSarif(w io.Writer, findings []Finding) error {
+	run := sarif.NewRun("ionosctl compute audit")
+	for _, c := range checks {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarif.Rule{
+			ID:               c.Id,
+			ShortDescription: sarif.Message{Text: c.Description},
+			Properties: map[string]any{
+				"tags":              []string{"security", c.Severity},
+				"security-severity": sarifLevels[c.Severity].Score,
+			},
+		})
+	}
+
+	for _, f := range findings {
+		run.Results = append(run.Results, sarif.Result{
+			RuleID:  f.Check,
+			Level:   sarifLevels[f.Severity].Level,
+			Message: sarif.Message{Text: f.Resource + ": " + f.Message},
+			Locations: []sarif.Location{{LogicalLocations: []sarif.LogicalLocation{{
+				Name: f.Resource, FullyQualifiedName: f.ResourceId, Kind: "resource",
+			}}}},
+		})
+	}
+
+	return sarif.Write(w, run)
+}
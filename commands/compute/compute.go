@@ -2,6 +2,7 @@ package compute
 
 import (
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/applicationloadbalancer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/audit"
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/backupunit"
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/contract"
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/datacenter"
@@ -73,6 +74,7 @@ func Root() *core.Command {
 	cmd.AddCommand(k8s.K8sCmd())
 	cmd.AddCommand(targetgroup.TargetGroupCmd())
 	cmd.AddCommand(template.TemplateCmd())
+	cmd.AddCommand(audit.AuditCmd())
 
 	return cmd
 }
@@ -27,7 +27,7 @@ func DatacenterCmd() *core.Command {
 			Aliases:          []string{"d", "dc", "vdc"},
 			Args:             cobra.ExactValidArgs(1),
 			Short:            "Data Center Operations",
-			Long:             "The sub-commands of `ionosctl compute datacenter` allow you to create, list, get, update and delete Data Centers.",
+			Long:             "The sub-commands of `ionosctl compute datacenter` allow you to create, list, get, update and delete Data Centers, and to print the network topology of a Data Center as a graph.",
 			TraverseChildren: true,
 		},
 	}
@@ -38,6 +38,7 @@ func DatacenterCmd() *core.Command {
 	datacenterCmd.AddCommand(DatacenterCreateCmd())
 	datacenterCmd.AddCommand(DatacenterUpdateCmd())
 	datacenterCmd.AddCommand(DatacenterDeleteCmd())
+	datacenterCmd.AddCommand(DatacenterGraphCmd())
 
 	return core.WithConfigOverride(datacenterCmd, []string{fileconfiguration.Cloud, "compute"}, "")
 }
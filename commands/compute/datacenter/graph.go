@@ -0,0 +1,182 @@
+package datacenter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const FlagFormat = "format"
+
+func DatacenterGraphCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "datacenter",
+		Resource:  "datacenter",
+		Verb:      "graph",
+		Aliases:   []string{"topology"},
+		ShortDesc: "Print the network topology of a Data Center as a graph",
+		LongDesc: `Use this command to print a graph of the LANs, Servers with their NICs, Application and Network Load Balancers with their Target Groups and targets, NAT Gateways, Cross Connects and IP Failover groups of a Data Center. Only read requests are made.
+
+The graph is printed as text, in the DOT language of Graphviz or as a Mermaid flowchart. Render it with e.g. ` + "`dot -Tsvg`" + `, or paste the Mermaid flowchart in Markdown. The svg-free format prints an SVG image with a simple layout of its own, for when Graphviz is not installed. Load Balancer targets with the IP of a NIC in the Data Center are connected to that NIC. Logical connections, such as targets and IP Failover group members, are dashed.
+
+Required values to run command:
+
+* Data Center Id`,
+		Example: `ionosctl compute datacenter graph --datacenter-id DATACENTER_ID | dot -Tsvg > datacenter.svg
+ionosctl compute datacenter graph --datacenter-id DATACENTER_ID --format mermaid
+ionosctl compute datacenter graph --datacenter-id DATACENTER_ID --format svg-free > datacenter.svg`,
+		PreCmdRun:  PreRunDataCenterId,
+		CmdRun:     RunDataCenterGraph,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, cloudapiv6.ArgIdShort, "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddSetFlag(FlagFormat, "", "dot", graphFormats, "Language of the graph")
+
+	return cmd
+}
+
+func RunDataCenterGraph(c *core.CommandConfig) error {
+	in, err := collectTopologyInput(c, viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId)))
+	if err != nil {
+		return err
+	}
+	out, err := renderTopology(viper.GetString(core.GetFlagName(c.NS, FlagFormat)), buildTopology(in))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(c.Command.Command.OutOrStdout(), out)
+	return err
+}
+
+// collectTopologyInput reads the resources of a Data Center that are part of its graph.
+func collectTopologyInput(c *core.CommandConfig, datacenterId string) (topologyInput, error) {
+	var in topologyInput
+	verbose := func(resp *resources.Response) {
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+	}
+
+	dc, resp, err := c.CloudApiV6Services.DataCenters().Get(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed getting Data Center %s: %w", datacenterId, err)
+	}
+	in.Datacenter = dc.Datacenter
+
+	lans, resp, err := c.CloudApiV6Services.Lans().List(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed listing LANs: %w", err)
+	}
+	if lans.Items != nil {
+		in.Lans = *lans.Items
+	}
+
+	servers, resp, err := c.CloudApiV6Services.Servers().List(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed listing Servers: %w", err)
+	}
+	if servers.Items != nil {
+		for _, server := range *servers.Items {
+			nics, resp, err := c.CloudApiV6Services.Nics().List(datacenterId, str(server.Id))
+			verbose(resp)
+			if err != nil {
+				return in, fmt.Errorf("failed listing NICs of Server %s: %w", str(server.Id), err)
+			}
+			s := topologyServer{Server: server}
+			if nics.Items != nil {
+				s.Nics = *nics.Items
+			}
+			in.Servers = append(in.Servers, s)
+		}
+	}
+
+	albs, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().List(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed listing Application Load Balancers: %w", err)
+	}
+	if albs.Items != nil {
+		for _, alb := range *albs.Items {
+			rules, resp, err := c.CloudApiV6Services.ApplicationLoadBalancers().ListForwardingRules(datacenterId, str(alb.Id))
+			verbose(resp)
+			if err != nil {
+				return in, fmt.Errorf("failed listing Forwarding Rules of Application Load Balancer %s: %w", str(alb.Id), err)
+			}
+			a := topologyALB{ALB: alb}
+			if rules.Items != nil {
+				a.Rules = *rules.Items
+			}
+			in.ALBs = append(in.ALBs, a)
+		}
+	}
+
+	nlbs, resp, err := c.CloudApiV6Services.NetworkLoadBalancers().List(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed listing Network Load Balancers: %w", err)
+	}
+	if nlbs.Items != nil {
+		for _, nlb := range *nlbs.Items {
+			rules, resp, err := c.CloudApiV6Services.NetworkLoadBalancers().ListForwardingRules(datacenterId, str(nlb.Id))
+			verbose(resp)
+			if err != nil {
+				return in, fmt.Errorf("failed listing Forwarding Rules of Network Load Balancer %s: %w", str(nlb.Id), err)
+			}
+			n := topologyNLB{NLB: nlb}
+			if rules.Items != nil {
+				n.Rules = *rules.Items
+			}
+			in.NLBs = append(in.NLBs, n)
+		}
+	}
+
+	if len(in.ALBs) > 0 {
+		groups, resp, err := c.CloudApiV6Services.TargetGroups().List()
+		verbose(resp)
+		if err != nil {
+			return in, fmt.Errorf("failed listing Target Groups: %w", err)
+		}
+		if groups.Items != nil {
+			in.TargetGroups = *groups.Items
+		}
+	}
+
+	nats, resp, err := c.CloudApiV6Services.NatGateways().List(datacenterId)
+	verbose(resp)
+	if err != nil {
+		return in, fmt.Errorf("failed listing NAT Gateways: %w", err)
+	}
+	if nats.Items != nil {
+		in.NatGateways = *nats.Items
+	}
+
+	for _, lan := range in.Lans {
+		if lan.Properties == nil || str(lan.Properties.Pcc) == "" {
+			continue
+		}
+		pccs, resp, err := c.CloudApiV6Services.Pccs().List()
+		verbose(resp)
+		if err != nil {
+			return in, fmt.Errorf("failed listing Cross Connects: %w", err)
+		}
+		if pccs.Items != nil {
+			in.PrivateCrossConnects = *pccs.Items
+		}
+		break
+	}
+
+	return in, nil
+}
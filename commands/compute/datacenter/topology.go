@@ -0,0 +1,748 @@
+package datacenter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+)
+
+// Formats of graph.
+var graphFormats = []string{"dot", "mermaid", "svg-free"}
+
+// nodeKind is the kind of resource of a node, which decides its shape.
+type nodeKind string
+
+const (
+	kindLan         nodeKind = "lan"
+	kindNic         nodeKind = "nic"
+	kindALB         nodeKind = "alb"
+	kindNLB         nodeKind = "nlb"
+	kindTargetGroup nodeKind = "targetgroup"
+	kindTarget      nodeKind = "target"
+	kindNatGateway  nodeKind = "natgateway"
+	kindPcc         nodeKind = "pcc"
+	kindPeer        nodeKind = "peer"
+	kindFailover    nodeKind = "failover"
+)
+
+type node struct {
+	Id    string
+	Kind  nodeKind
+	Label []string
+}
+
+type edge struct {
+	From, To string
+	Label    string
+	// Dashed edges are logical, e.g. a load balancer target, instead of a network connection.
+	Dashed bool
+}
+
+// cluster groups the NICs of a server.
+type cluster struct {
+	Label string
+	Nodes []string
+}
+
+// topology is the graph of a data center.
+type topology struct {
+	Title    string
+	Nodes    []node
+	Edges    []edge
+	Clusters []cluster
+	// ids maps the keys of resources to the ids of their nodes, which are short and valid in every format.
+	ids map[string]string
+}
+
+// topologyInput is the state of a data center that the graph shows.
+type topologyInput struct {
+	Datacenter           ionoscloud.Datacenter
+	Lans                 []ionoscloud.Lan
+	Servers              []topologyServer
+	ALBs                 []topologyALB
+	NLBs                 []topologyNLB
+	TargetGroups         []ionoscloud.TargetGroup
+	NatGateways          []ionoscloud.NatGateway
+	PrivateCrossConnects []ionoscloud.PrivateCrossConnect
+}
+
+type topologyServer struct {
+	Server ionoscloud.Server
+	Nics   []ionoscloud.Nic
+}
+
+type topologyALB struct {
+	ALB   ionoscloud.ApplicationLoadBalancer
+	Rules []ionoscloud.ApplicationLoadBalancerForwardingRule
+}
+
+type topologyNLB struct {
+	NLB   ionoscloud.NetworkLoadBalancer
+	Rules []ionoscloud.NetworkLoadBalancerForwardingRule
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func strs(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
+
+// nameOr returns the name, or the id if the name is empty.
+func nameOr(name *string, id *string) string {
+	if str(name) != "" {
+		return str(name)
+	}
+	return str(id)
+}
+
+// node returns the id of the node of a resource, adding the node if it does not exist yet.
+func (t *topology) node(key string, kind nodeKind, label ...string) string {
+	if id, ok := t.ids[key]; ok {
+		return id
+	}
+	id := fmt.Sprintf("n%d", len(t.Nodes)+1)
+	t.ids[key] = id
+	t.Nodes = append(t.Nodes, node{Id: id, Kind: kind, Label: label})
+	return id
+}
+
+func (t *topology) edge(from, to, label string, dashed bool) {
+	t.Edges = append(t.Edges, edge{From: from, To: to, Label: label, Dashed: dashed})
+}
+
+func (t *topology) lan(id int32, lans map[int32]ionoscloud.Lan) string {
+	key := fmt.Sprintf("lan/%d", id)
+	lan, ok := lans[id]
+	if !ok {
+		return t.node(key, kindLan, fmt.Sprintf("LAN %d", id))
+	}
+	label := []string{fmt.Sprintf("LAN %d", id)}
+	if p := lan.Properties; p != nil {
+		if str(p.Name) != "" {
+			label[0] += " " + str(p.Name)
+		}
+		if p.Public != nil && *p.Public {
+			label = append(label, "public")
+		} else {
+			label = append(label, "private")
+		}
+		if str(p.Ipv4CidrBlock) != "" {
+			label = append(label, str(p.Ipv4CidrBlock))
+		}
+	}
+	return t.node(key, kindLan, label...)
+}
+
+// buildTopology returns the graph of LANs, servers with their NICs, load balancers with their targets,
+// NAT gateways, cross connects and IP failover groups of a data center.
+func buildTopology(in topologyInput) *topology {
+	t := &topology{ids: map[string]string{}}
+	t.Title = nameOr(nil, in.Datacenter.Id)
+	if p := in.Datacenter.Properties; p != nil {
+		t.Title = fmt.Sprintf("%s (%s)", nameOr(p.Name, in.Datacenter.Id), str(p.Location))
+	}
+
+	lans := map[int32]ionoscloud.Lan{}
+	for _, lan := range in.Lans {
+		var id int32
+		if _, err := fmt.Sscan(str(lan.Id), &id); err == nil {
+			lans[id] = lan
+			t.lan(id, lans)
+		}
+	}
+
+	// NICs by IP, so load balancer targets can be connected to them.
+	nicsByIp := map[string]string{}
+	for _, s := range in.Servers {
+		c := cluster{Label: "Server " + nameOr(s.Server.Properties.GetName(), s.Server.Id)}
+		for _, nic := range s.Nics {
+			p := nic.Properties
+			if p == nil {
+				continue
+			}
+			label := append([]string{"NIC " + nameOr(p.Name, nic.Id)}, strs(p.Ips)...)
+			if p.FirewallActive != nil && *p.FirewallActive {
+				label = append(label, "firewall")
+			}
+			id := t.node("nic/"+str(nic.Id), kindNic, label...)
+			c.Nodes = append(c.Nodes, id)
+			for _, ip := range strs(p.Ips) {
+				nicsByIp[ip] = id
+			}
+			if p.Lan != nil {
+				t.edge(id, t.lan(*p.Lan, lans), "", false)
+			}
+		}
+		t.Clusters = append(t.Clusters, c)
+	}
+
+	for _, lan := range in.Lans {
+		if lan.Properties == nil || lan.Properties.IpFailover == nil {
+			continue
+		}
+		for _, f := range *lan.Properties.IpFailover {
+			id := t.node("failover/"+str(lan.Id)+"/"+str(f.Ip), kindFailover, "Failover IP", str(f.Ip))
+			if _, ok := t.ids["lan/"+str(lan.Id)]; ok {
+				t.edge(id, t.ids["lan/"+str(lan.Id)], "", false)
+			}
+			if nic, ok := t.ids["nic/"+str(f.NicUuid)]; ok {
+				t.edge(id, nic, "", true)
+			}
+		}
+	}
+
+	target := func(ip string) string {
+		if nic, ok := nicsByIp[ip]; ok {
+			return nic
+		}
+		return t.node("target/"+ip, kindTarget, ip)
+	}
+	port := func(p *int32) string {
+		if p == nil {
+			return ""
+		}
+		return fmt.Sprint(*p)
+	}
+
+	targetGroups := map[string]ionoscloud.TargetGroup{}
+	for _, tg := range in.TargetGroups {
+		targetGroups[str(tg.Id)] = tg
+	}
+	for _, alb := range in.ALBs {
+		p := alb.ALB.Properties
+		if p == nil {
+			continue
+		}
+		id := t.node("alb/"+str(alb.ALB.Id), kindALB, append([]string{"ALB " + nameOr(p.Name, alb.ALB.Id)}, strs(p.Ips)...)...)
+		if p.ListenerLan != nil {
+			t.edge(t.lan(*p.ListenerLan, lans), id, "listener", false)
+		}
+		if p.TargetLan != nil {
+			t.edge(id, t.lan(*p.TargetLan, lans), "targets", false)
+		}
+		for _, rule := range alb.Rules {
+			rp := rule.Properties
+			if rp == nil || rp.HttpRules == nil {
+				continue
+			}
+			for _, http := range *rp.HttpRules {
+				tg, ok := targetGroups[str(http.TargetGroup)]
+				if !ok {
+					continue
+				}
+				tgId := t.node("targetgroup/"+str(tg.Id), kindTargetGroup, "Target group "+nameOr(tg.Properties.GetName(), tg.Id))
+				t.edge(id, tgId, fmt.Sprintf("%s :%s", nameOr(rp.Name, rule.Id), port(rp.ListenerPort)), true)
+				if tg.Properties == nil || tg.Properties.Targets == nil {
+					continue
+				}
+				for _, tt := range *tg.Properties.Targets {
+					t.edge(tgId, target(str(tt.Ip)), ":"+port(tt.Port), true)
+				}
+			}
+		}
+	}
+
+	for _, nlb := range in.NLBs {
+		p := nlb.NLB.Properties
+		if p == nil {
+			continue
+		}
+		id := t.node("nlb/"+str(nlb.NLB.Id), kindNLB, append([]string{"NLB " + nameOr(p.Name, nlb.NLB.Id)}, strs(p.Ips)...)...)
+		if p.ListenerLan != nil {
+			t.edge(t.lan(*p.ListenerLan, lans), id, "listener", false)
+		}
+		if p.TargetLan != nil {
+			t.edge(id, t.lan(*p.TargetLan, lans), "targets", false)
+		}
+		for _, rule := range nlb.Rules {
+			rp := rule.Properties
+			if rp == nil || rp.Targets == nil {
+				continue
+			}
+			for _, tt := range *rp.Targets {
+				t.edge(id, target(str(tt.Ip)), fmt.Sprintf("%s :%s", nameOr(rp.Name, rule.Id), port(tt.Port)), true)
+			}
+		}
+	}
+
+	for _, nat := range in.NatGateways {
+		p := nat.Properties
+		if p == nil {
+			continue
+		}
+		id := t.node("natgateway/"+str(nat.Id), kindNatGateway, append([]string{"NAT gateway " + nameOr(p.Name, nat.Id)}, strs(p.PublicIps)...)...)
+		if p.Lans == nil {
+			continue
+		}
+		for _, lan := range *p.Lans {
+			if lan.Id != nil {
+				t.edge(id, t.lan(*lan.Id, lans), strings.Join(strs(lan.GatewayIps), ", "), false)
+			}
+		}
+	}
+
+	pccs := map[string]ionoscloud.PrivateCrossConnect{}
+	for _, pcc := range in.PrivateCrossConnects {
+		pccs[str(pcc.Id)] = pcc
+	}
+	for _, lan := range in.Lans {
+		if lan.Properties == nil || str(lan.Properties.Pcc) == "" {
+			continue
+		}
+		pcc := pccs[str(lan.Properties.Pcc)]
+		label := "Cross connect " + str(lan.Properties.Pcc)
+		if pcc.Properties != nil && str(pcc.Properties.Name) != "" {
+			label = "Cross connect " + str(pcc.Properties.Name)
+		}
+		id := t.node("pcc/"+str(lan.Properties.Pcc), kindPcc, label)
+		t.edge(t.ids["lan/"+str(lan.Id)], id, "", false)
+		if pcc.Properties == nil || pcc.Properties.Peers == nil {
+			continue
+		}
+		for _, peer := range *pcc.Properties.Peers {
+			if str(peer.DatacenterId) == str(in.Datacenter.Id) {
+				continue
+			}
+			peerId := t.node("peer/"+str(peer.DatacenterId)+"/"+str(peer.Id), kindPeer,
+				"LAN "+nameOr(peer.Name, peer.Id), "in "+nameOr(peer.DatacenterName, peer.DatacenterId))
+			t.edge(id, peerId, "", false)
+		}
+	}
+
+	return t
+}
+
+func renderTopology(format string, t *topology) (string, error) {
+	switch format {
+	case "dot":
+		return renderDot(t), nil
+	case "mermaid":
+		return renderMermaid(t), nil
+	case "svg-free":
+		return renderSVG(t), nil
+	}
+	return "", fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(graphFormats, ", "))
+}
+
+var dotShapes = map[nodeKind]string{
+	kindLan:         "ellipse",
+	kindNic:         "box",
+	kindALB:         "hexagon",
+	kindNLB:         "hexagon",
+	kindTargetGroup: "folder",
+	kindTarget:      "plaintext",
+	kindNatGateway:  "octagon",
+	kindPcc:         "doublecircle",
+	kindPeer:        "ellipse",
+	kindFailover:    "diamond",
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// renderDot renders the graph for Graphviz, e.g. 'dot -Tsvg'.
+func renderDot(t *topology) string {
+	b := &strings.Builder{}
+	b.WriteString("digraph datacenter {\n")
+	fmt.Fprintf(b, "  label=%s;\n  labelloc=t;\n  rankdir=LR;\n  node [fontsize=10];\n  edge [fontsize=9];\n", dotQuote(t.Title))
+
+	nodes := map[string]node{}
+	for _, n := range t.Nodes {
+		nodes[n.Id] = n
+	}
+	writeNode := func(indent string, n node) {
+		style := ""
+		if n.Kind == kindPeer {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(b, "%s%s [label=%s, shape=%s%s];\n", indent, n.Id, dotQuote(strings.Join(n.Label, "\n")), dotShapes[n.Kind], style)
+	}
+
+	clustered := map[string]bool{}
+	for i, c := range t.Clusters {
+		fmt.Fprintf(b, "\n  subgraph cluster_%d {\n    label=%s;\n", i+1, dotQuote(c.Label))
+		for _, id := range c.Nodes {
+			clustered[id] = true
+			writeNode("    ", nodes[id])
+		}
+		b.WriteString("  }\n")
+	}
+
+	b.WriteString("\n")
+	for _, n := range t.Nodes {
+		if !clustered[n.Id] {
+			writeNode("  ", n)
+		}
+	}
+
+	b.WriteString("\n")
+	for _, e := range t.Edges {
+		var attrs []string
+		if e.Label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.Label))
+		}
+		if e.Dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, "  %s -> %s [%s];\n", e.From, e.To, strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(b, "  %s -> %s;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidShapes are the opening and closing brackets of the shapes of nodes.
+var mermaidShapes = map[nodeKind][2]string{
+	kindLan:         {"([", "])"},
+	kindNic:         {"[", "]"},
+	kindALB:         {"{{", "}}"},
+	kindNLB:         {"{{", "}}"},
+	kindTargetGroup: {"[[", "]]"},
+	kindTarget:      {">", "]"},
+	kindNatGateway:  {"[/", "/]"},
+	kindPcc:         {"((", "))"},
+	kindPeer:        {"([", "])"},
+	kindFailover:    {"{", "}"},
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+}
+
+// renderMermaid renders the graph as a Mermaid flowchart, which e.g. GitHub and GitLab render in Markdown.
+func renderMermaid(t *topology) string {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "---\ntitle: %s\n---\nflowchart LR\n", mermaidQuote(t.Title))
+
+	nodes := map[string]node{}
+	for _, n := range t.Nodes {
+		nodes[n.Id] = n
+	}
+	writeNode := func(indent string, n node) {
+		shape := mermaidShapes[n.Kind]
+		fmt.Fprintf(b, "%s%s%s%s%s\n", indent, n.Id, shape[0], mermaidQuote(strings.Join(n.Label, "\n")), shape[1])
+	}
+
+	clustered := map[string]bool{}
+	for i, c := range t.Clusters {
+		fmt.Fprintf(b, "  subgraph server%d[%s]\n", i+1, mermaidQuote(c.Label))
+		for _, id := range c.Nodes {
+			clustered[id] = true
+			writeNode("    ", nodes[id])
+		}
+		b.WriteString("  end\n")
+	}
+	for _, n := range t.Nodes {
+		if !clustered[n.Id] {
+			writeNode("  ", n)
+		}
+	}
+
+	for _, e := range t.Edges {
+		arrow := "-->"
+		if e.Dashed {
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			fmt.Fprintf(b, "  %s %s|%s| %s\n", e.From, arrow, mermaidQuote(e.Label), e.To)
+		} else {
+			fmt.Fprintf(b, "  %s %s %s\n", e.From, arrow, e.To)
+		}
+	}
+	return b.String()
+}
+
+// Sizes of the SVG layout, in pixels.
+const (
+	svgMargin     = 20
+	svgTitle      = 30
+	svgColumnGap  = 90
+	svgNodeGap    = 20
+	svgCharWidth  = 7
+	svgLineHeight = 15
+	svgPadding    = 10
+)
+
+// svgShapes are the shapes of nodes: rect, round (a rect with rounded corners), ellipse, diamond or text.
+var svgShapes = map[nodeKind]string{
+	kindLan:         "ellipse",
+	kindNic:         "rect",
+	kindALB:         "round",
+	kindNLB:         "round",
+	kindTargetGroup: "rect",
+	kindTarget:      "text",
+	kindNatGateway:  "round",
+	kindPcc:         "ellipse",
+	kindPeer:        "ellipse",
+	kindFailover:    "diamond",
+}
+
+func svgEscape(s string) string {
+	b := &strings.Builder{}
+	_ = xml.EscapeText(b, []byte(s))
+	return b.String()
+}
+
+// svgBox is the position and size of a node.
+type svgBox struct {
+	X, Y, W, H float64
+}
+
+func (b svgBox) center() (float64, float64) {
+	return b.X + b.W/2, b.Y + b.H/2
+}
+
+// border returns the point where the line from the center of b towards (x, y) leaves b.
+func (b svgBox) border(x, y float64) (float64, float64) {
+	cx, cy := b.center()
+	dx, dy := x-cx, y-cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+	f := math.Inf(1)
+	if dx != 0 {
+		f = b.W / 2 / math.Abs(dx)
+	}
+	if dy != 0 {
+		f = math.Min(f, b.H/2/math.Abs(dy))
+	}
+	return cx + f*dx, cy + f*dy
+}
+
+// svgColumns assigns the nodes to columns, from left to right: a node is one column right of the nodes with an
+// edge to it. Dashed edges only place nodes without network connections, e.g. targets, so the network decides the
+// layout. Edges that close a cycle are ignored, and the NICs of a server share the column of the rightmost one.
+func svgColumns(t *topology) map[string]int {
+	out := map[string][]string{}
+	connected := map[string]bool{}
+	dashed := map[[2]string]bool{}
+	for _, e := range t.Edges {
+		out[e.From] = append(out[e.From], e.To)
+		if e.Dashed {
+			dashed[[2]string{e.From, e.To}] = true
+		} else {
+			connected[e.From], connected[e.To] = true, true
+		}
+	}
+
+	const visiting, visited = 1, 2
+	state := map[string]int{}
+	back := map[[2]string]bool{}
+	var order []string
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		for _, to := range out[id] {
+			switch state[to] {
+			case 0:
+				visit(to)
+			case visiting:
+				back[[2]string{id, to}] = true
+			}
+		}
+		state[id] = visited
+		order = append(order, id)
+	}
+	for _, n := range t.Nodes {
+		if state[n.Id] == 0 {
+			visit(n.Id)
+		}
+	}
+
+	columns := map[string]int{}
+	for i := len(order) - 1; i >= 0; i-- {
+		from := order[i]
+		for _, to := range out[from] {
+			if back[[2]string{from, to}] || dashed[[2]string{from, to}] && connected[to] {
+				continue
+			}
+			if columns[to] < columns[from]+1 {
+				columns[to] = columns[from] + 1
+			}
+		}
+	}
+	for _, c := range t.Clusters {
+		var column int
+		for _, id := range c.Nodes {
+			column = max(column, columns[id])
+		}
+		for _, id := range c.Nodes {
+			columns[id] = column
+		}
+	}
+	return columns
+}
+
+// renderSVG renders the graph as an SVG image with a simple layout of its own, so it needs no Graphviz.
+func renderSVG(t *topology) string {
+	nodes := map[string]node{}
+	for _, n := range t.Nodes {
+		nodes[n.Id] = n
+	}
+	size := func(n node) (float64, float64) {
+		var chars int
+		for _, l := range n.Label {
+			chars = max(chars, len(l))
+		}
+		w, h := float64(chars*svgCharWidth+2*svgPadding), float64(len(n.Label)*svgLineHeight+svgPadding)
+		switch svgShapes[n.Kind] {
+		case "ellipse":
+			w, h = w*1.3, h*1.3
+		case "diamond":
+			w, h = w*1.6, h*1.6
+		}
+		return w, h
+	}
+
+	// A group is a cluster or a single node, stacked in its column.
+	type group struct {
+		label string
+		nodes []string
+	}
+	columns := svgColumns(t)
+	var groups [][]group
+	add := func(g group) {
+		c := columns[g.nodes[0]]
+		for len(groups) <= c {
+			groups = append(groups, nil)
+		}
+		groups[c] = append(groups[c], g)
+	}
+	clustered := map[string]bool{}
+	for _, c := range t.Clusters {
+		if len(c.Nodes) == 0 {
+			continue
+		}
+		for _, id := range c.Nodes {
+			clustered[id] = true
+		}
+		add(group{c.Label, c.Nodes})
+	}
+	for _, n := range t.Nodes {
+		if !clustered[n.Id] {
+			add(group{nodes: []string{n.Id}})
+		}
+	}
+
+	boxes := map[string]svgBox{}
+	var clusters []struct {
+		label string
+		box   svgBox
+	}
+	width, height := float64(len(t.Title)*svgCharWidth*4/3+2*svgMargin), 0.0
+	x := float64(svgMargin)
+	for _, column := range groups {
+		var columnWidth, padding float64
+		for _, g := range column {
+			for _, id := range g.nodes {
+				w, _ := size(nodes[id])
+				columnWidth = max(columnWidth, w)
+			}
+			if g.label != "" {
+				columnWidth = max(columnWidth, float64(len(g.label)*svgCharWidth))
+				padding = 2 * svgPadding
+			}
+		}
+		columnWidth += padding
+
+		y := float64(svgMargin + svgTitle)
+		for _, g := range column {
+			top := y
+			if g.label != "" {
+				y += svgLineHeight + svgPadding
+			}
+			for _, id := range g.nodes {
+				w, h := size(nodes[id])
+				boxes[id] = svgBox{x + (columnWidth-w)/2, y, w, h}
+				y += h + svgNodeGap
+			}
+			if g.label != "" {
+				clusters = append(clusters, struct {
+					label string
+					box   svgBox
+				}{g.label, svgBox{x, top, columnWidth, y - svgNodeGap + svgPadding - top}})
+				y += svgPadding + svgNodeGap
+			}
+		}
+		height = max(height, y-svgNodeGap+svgMargin)
+		x += columnWidth + svgColumnGap
+	}
+	width = max(width, x-svgColumnGap+svgMargin)
+	height = max(height, svgMargin+svgTitle)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="12">`+"\n", width, height, width, height)
+	b.WriteString(`  <defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto"><path d="M0,0 L10,5 L0,10 z"/></marker></defs>` + "\n")
+	fmt.Fprintf(b, `  <rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(b, `  <text x="%.0f" y="%d" text-anchor="middle" font-size="16">%s</text>`+"\n", width/2, svgMargin+svgLineHeight, svgEscape(t.Title))
+
+	for _, c := range clusters {
+		fmt.Fprintf(b, `  <rect x="%.0f" y="%.0f" width="%.0f" height="%.0f" fill="#f4f4f4" stroke="#999"/>`+"\n", c.box.X, c.box.Y, c.box.W, c.box.H)
+		fmt.Fprintf(b, `  <text x="%.0f" y="%.0f" text-anchor="middle">%s</text>`+"\n", c.box.X+c.box.W/2, c.box.Y+svgLineHeight, svgEscape(c.label))
+	}
+
+	for _, e := range t.Edges {
+		from, to := boxes[e.From], boxes[e.To]
+		tx, ty := to.center()
+		x1, y1 := from.border(tx, ty)
+		fx, fy := from.center()
+		x2, y2 := to.border(fx, fy)
+		dash := ""
+		if e.Dashed {
+			dash = ` stroke-dasharray="5,4"`
+		}
+		fmt.Fprintf(b, `  <line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"%s marker-end="url(#arrow)"/>`+"\n", x1, y1, x2, y2, dash)
+		if e.Label != "" {
+			fmt.Fprintf(b, `  <text x="%.1f" y="%.1f" text-anchor="middle" font-size="10" stroke="white" stroke-width="3" paint-order="stroke">%s</text>`+"\n", (x1+x2)/2, (y1+y2)/2-3, svgEscape(e.Label))
+		}
+	}
+
+	for _, n := range t.Nodes {
+		box := boxes[n.Id]
+		cx, cy := box.center()
+		dash := ""
+		if n.Kind == kindPeer {
+			dash = ` stroke-dasharray="5,4"`
+		}
+		switch svgShapes[n.Kind] {
+		case "rect", "round":
+			rx := 0
+			if svgShapes[n.Kind] == "round" {
+				rx = 8
+			}
+			fmt.Fprintf(b, `  <rect x="%.0f" y="%.0f" width="%.0f" height="%.0f" rx="%d" fill="white" stroke="black"%s/>`+"\n", box.X, box.Y, box.W, box.H, rx, dash)
+		case "ellipse":
+			fmt.Fprintf(b, `  <ellipse cx="%.0f" cy="%.0f" rx="%.0f" ry="%.0f" fill="white" stroke="black"%s/>`+"\n", cx, cy, box.W/2, box.H/2, dash)
+		case "diamond":
+			fmt.Fprintf(b, `  <polygon points="%.0f,%.0f %.0f,%.0f %.0f,%.0f %.0f,%.0f" fill="white" stroke="black"%s/>`+"\n",
+				cx, box.Y, box.X+box.W, cy, cx, box.Y+box.H, box.X, cy, dash)
+		}
+		top := cy - float64(len(n.Label)*svgLineHeight)/2 + svgLineHeight - 3
+		fmt.Fprintf(b, `  <text x="%.0f" y="%.0f" text-anchor="middle">`, cx, top)
+		for i, l := range n.Label {
+			dy := 0
+			if i > 0 {
+				dy = svgLineHeight
+			}
+			fmt.Fprintf(b, `<tspan x="%.0f" dy="%d">%s</tspan>`, cx, dy, svgEscape(l))
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
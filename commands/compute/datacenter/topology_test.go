@@ -0,0 +1,178 @@
+package datacenter
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTopologyInput() topologyInput {
+	return topologyInput{
+		Datacenter: ionoscloud.Datacenter{Id: pointer.From("dc1"), Properties: &ionoscloud.DatacenterProperties{
+			Name: pointer.From("prod"), Location: pointer.From("de/txl")}},
+		Lans: []ionoscloud.Lan{
+			{Id: pointer.From("1"), Properties: &ionoscloud.LanProperties{Name: pointer.From("internet"), Public: pointer.From(true),
+				IpFailover: &[]ionoscloud.IPFailover{{Ip: pointer.From("203.0.113.10"), NicUuid: pointer.From("nic-a")}}}},
+			{Id: pointer.From("2"), Properties: &ionoscloud.LanProperties{Public: pointer.From(false), Pcc: pointer.From("pcc1")}},
+		},
+		Servers: []topologyServer{{
+			Server: ionoscloud.Server{Id: pointer.From("srv"), Properties: &ionoscloud.ServerProperties{Name: pointer.From("web \"1\"")}},
+			Nics: []ionoscloud.Nic{
+				{Id: pointer.From("nic-a"), Properties: &ionoscloud.NicProperties{Lan: pointer.From(int32(1)), Ips: &[]string{"203.0.113.10"}}},
+				{Id: pointer.From("nic-b"), Properties: &ionoscloud.NicProperties{Name: pointer.From("backend"), Lan: pointer.From(int32(2)),
+					Ips: &[]string{"10.0.0.5"}, FirewallActive: pointer.From(true)}},
+			},
+		}},
+		ALBs: []topologyALB{{
+			ALB: ionoscloud.ApplicationLoadBalancer{Id: pointer.From("alb"), Properties: &ionoscloud.ApplicationLoadBalancerProperties{
+				Name: pointer.From("front"), ListenerLan: pointer.From(int32(1)), TargetLan: pointer.From(int32(2)), Ips: &[]string{"203.0.113.20"}}},
+			Rules: []ionoscloud.ApplicationLoadBalancerForwardingRule{{Id: pointer.From("r1"), Properties: &ionoscloud.ApplicationLoadBalancerForwardingRuleProperties{
+				Name: pointer.From("https"), ListenerPort: pointer.From(int32(443)),
+				HttpRules: &[]ionoscloud.ApplicationLoadBalancerHttpRule{{TargetGroup: pointer.From("tg1")}}}}},
+		}},
+		TargetGroups: []ionoscloud.TargetGroup{{Id: pointer.From("tg1"), Properties: &ionoscloud.TargetGroupProperties{
+			Name: pointer.From("web"), Targets: &[]ionoscloud.TargetGroupTarget{
+				{Ip: pointer.From("10.0.0.5"), Port: pointer.From(int32(8080))},
+				{Ip: pointer.From("10.0.0.99"), Port: pointer.From(int32(8080))},
+			}}}},
+		NatGateways: []ionoscloud.NatGateway{{Id: pointer.From("nat"), Properties: &ionoscloud.NatGatewayProperties{
+			Name: pointer.From("egress"), PublicIps: &[]string{"203.0.113.30"},
+			Lans: &[]ionoscloud.NatGatewayLanProperties{{Id: pointer.From(int32(2)), GatewayIps: &[]string{"10.0.0.1/24"}}}}}},
+		PrivateCrossConnects: []ionoscloud.PrivateCrossConnect{{Id: pointer.From("pcc1"), Properties: &ionoscloud.PrivateCrossConnectProperties{
+			Name: pointer.From("link"), Peers: &[]ionoscloud.Peer{
+				{Id: pointer.From("2"), DatacenterId: pointer.From("dc1")},
+				{Id: pointer.From("3"), Name: pointer.From("shared"), DatacenterId: pointer.From("dc2"), DatacenterName: pointer.From("staging")},
+			}}}},
+	}
+}
+
+func TestRenderTopologyDot(t *testing.T) {
+	out, err := renderTopology("dot", buildTopology(testTopologyInput()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `digraph datacenter {
+  label="prod (de/txl)";
+  labelloc=t;
+  rankdir=LR;
+  node [fontsize=10];
+  edge [fontsize=9];
+
+  subgraph cluster_1 {
+    label="Server web \"1\"";
+    n3 [label="NIC nic-a\n203.0.113.10", shape=box];
+    n4 [label="NIC backend\n10.0.0.5\nfirewall", shape=box];
+  }
+
+  n1 [label="LAN 1 internet\npublic", shape=ellipse];
+  n2 [label="LAN 2\nprivate", shape=ellipse];
+  n5 [label="Failover IP\n203.0.113.10", shape=diamond];
+  n6 [label="ALB front\n203.0.113.20", shape=hexagon];
+  n7 [label="Target group web", shape=folder];
+  n8 [label="10.0.0.99", shape=plaintext];
+  n9 [label="NAT gateway egress\n203.0.113.30", shape=octagon];
+  n10 [label="Cross connect link", shape=doublecircle];
+  n11 [label="LAN shared\nin staging", shape=ellipse, style=dashed];
+
+  n3 -> n1;
+  n4 -> n2;
+  n5 -> n1;
+  n5 -> n3 [style=dashed];
+  n1 -> n6 [label="listener"];
+  n6 -> n2 [label="targets"];
+  n6 -> n7 [label="https :443", style=dashed];
+  n7 -> n4 [label=":8080", style=dashed];
+  n7 -> n8 [label=":8080", style=dashed];
+  n9 -> n2 [label="10.0.0.1/24"];
+  n2 -> n10;
+  n10 -> n11;
+}
+`, out)
+}
+
+func TestRenderTopologyMermaid(t *testing.T) {
+	out, err := renderTopology("mermaid", buildTopology(testTopologyInput()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `---
+title: "prod (de/txl)"
+---
+flowchart LR
+  subgraph server1["Server web #quot;1#quot;"]
+    n3["NIC nic-a<br/>203.0.113.10"]
+    n4["NIC backend<br/>10.0.0.5<br/>firewall"]
+  end
+  n1(["LAN 1 internet<br/>public"])
+  n2(["LAN 2<br/>private"])
+  n5{"Failover IP<br/>203.0.113.10"}
+  n6{{"ALB front<br/>203.0.113.20"}}
+  n7[["Target group web"]]
+  n8>"10.0.0.99"]
+  n9[/"NAT gateway egress<br/>203.0.113.30"/]
+  n10(("Cross connect link"))
+  n11(["LAN shared<br/>in staging"])
+  n3 --> n1
+  n4 --> n2
+  n5 --> n1
+  n5 -.-> n3
+  n1 -->|"listener"| n6
+  n6 -->|"targets"| n2
+  n6 -.->|"https :443"| n7
+  n7 -.->|":8080"| n4
+  n7 -.->|":8080"| n8
+  n9 -->|"10.0.0.1/24"| n2
+  n2 --> n10
+  n10 --> n11
+`, out)
+
+	_, err = renderTopology("svg", buildTopology(testTopologyInput()))
+	assert.EqualError(t, err, `unknown format "svg", must be one of dot, mermaid, svg-free`)
+}
+
+func TestRenderTopologySVG(t *testing.T) {
+	out, err := renderTopology("svg-free", buildTopology(testTopologyInput()))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// The image is well-formed XML, with every label escaped.
+	dec := xml.NewDecoder(strings.NewReader(out))
+	var text []string
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		if c, ok := tok.(xml.CharData); ok && strings.TrimSpace(string(c)) != "" {
+			text = append(text, string(c))
+		}
+	}
+	assert.True(t, strings.HasPrefix(out, "<svg "))
+	assert.Subset(t, text, []string{"prod (de/txl)", `Server web "1"`, "NIC backend", "10.0.0.5", "Target group web", "https :443", "LAN shared", "in staging"})
+
+	assert.Equal(t, 12, strings.Count(out, "<line "))
+	assert.Equal(t, 4, strings.Count(out, `stroke-dasharray="5,4" marker-end`))
+}
+
+func TestSvgColumns(t *testing.T) {
+	top := buildTopology(testTopologyInput())
+	columns := svgColumns(top)
+	// NICs are left of their LANs, which are left of the load balancers using them.
+	assert.Less(t, columns["n3"], columns["n1"])
+	assert.Less(t, columns["n1"], columns["n6"])
+	assert.Less(t, columns["n6"], columns["n2"])
+	assert.Equal(t, columns["n3"], columns["n4"])
+
+	// Edges closing a cycle are ignored.
+	cycle := &topology{Nodes: []node{{Id: "a"}, {Id: "b"}}, Edges: []edge{{From: "a", To: "b"}, {From: "b", To: "a"}}}
+	assert.Equal(t, map[string]int{"b": 1}, svgColumns(cycle))
+}
@@ -29,7 +29,7 @@ func FirewallruleCmd() *core.Command {
 			Use:              "firewallrule",
 			Aliases:          []string{"f", "fr", "firewall"},
 			Short:            "Firewall Rule Operations",
-			Long:             "The sub-commands of `ionosctl compute firewallrule` allow you to create, list, get, update, delete Firewall Rules.",
+			Long:             "The sub-commands of `ionosctl compute firewallrule` allow you to create, list, get, update, delete Firewall Rules, and sync them with a ruleset file.",
 			TraverseChildren: true,
 		},
 	}
@@ -40,6 +40,7 @@ func FirewallruleCmd() *core.Command {
 	firewallRuleCmd.AddCommand(FirewallRuleCreateCmd())
 	firewallRuleCmd.AddCommand(FirewallRuleUpdateCmd())
 	firewallRuleCmd.AddCommand(FirewallRuleDeleteCmd())
+	firewallRuleCmd.AddCommand(FirewallRuleSyncCmd())
 
 	return core.WithConfigOverride(firewallRuleCmd, []string{fileconfiguration.Cloud, "compute"}, "")
 }
@@ -0,0 +1,434 @@
+package firewallrule
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a firewall rule of a ruleset file. The keys are the flags of 'firewallrule create'.
+type Rule struct {
+	Name           string `yaml:"name"`
+	Protocol       string `yaml:"protocol"`
+	Direction      string `yaml:"direction,omitempty"`
+	IPVersion      string `yaml:"ip-version,omitempty"`
+	SourceMac      string `yaml:"source-mac,omitempty"`
+	SourceIP       string `yaml:"source-ip,omitempty"`
+	DestinationIP  string `yaml:"destination-ip,omitempty"`
+	IcmpType       *int32 `yaml:"icmp-type,omitempty"`
+	IcmpCode       *int32 `yaml:"icmp-code,omitempty"`
+	PortRangeStart *int32 `yaml:"port-range-start,omitempty"`
+	PortRangeEnd   *int32 `yaml:"port-range-end,omitempty"`
+}
+
+type ruleset struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+var rulesetProtocols = []string{"TCP", "UDP", "ICMP", "ICMPv6", "GRE", "VRRP", "ESP", "AH", "ANY"}
+
+// parseRuleset reads and validates a ruleset file. Rules are identified by their name, which has to be unique.
+func parseRuleset(r io.Reader) ([]Rule, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var s ruleset
+	if err := dec.Decode(&s); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed reading ruleset: %w", err)
+	}
+
+	names := map[string]bool{}
+	for i := range s.Rules {
+		rule := &s.Rules[i]
+		if rule.Name == "" {
+			return nil, fmt.Errorf("rule %d has no name", i+1)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("rule %s is defined more than once", rule.Name)
+		}
+		names[rule.Name] = true
+		if err := rule.normalize(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+	return s.Rules, nil
+}
+
+// normalize validates the rule and fills in the defaults of the API, so it compares equal to the rule the API returns.
+func (r *Rule) normalize() error {
+	i := slices.IndexFunc(rulesetProtocols, func(p string) bool { return strings.EqualFold(p, r.Protocol) })
+	if i < 0 {
+		return fmt.Errorf("protocol must be one of %s", strings.Join(rulesetProtocols, ", "))
+	}
+	r.Protocol = rulesetProtocols[i]
+
+	r.Direction = strings.ToUpper(r.Direction)
+	if r.Direction == "" {
+		r.Direction = "INGRESS"
+	}
+	if r.Direction != "INGRESS" && r.Direction != "EGRESS" {
+		return fmt.Errorf("direction must be INGRESS or EGRESS")
+	}
+	r.SourceMac = strings.ToLower(r.SourceMac)
+
+	version := ""
+	for _, ip := range []*string{&r.SourceIP, &r.DestinationIP} {
+		if *ip == "" {
+			continue
+		}
+		parsed := net.ParseIP(*ip)
+		if parsed == nil {
+			return fmt.Errorf("%q is not an IP address", *ip)
+		}
+		*ip = parsed.String()
+		v := "IPv6"
+		if parsed.To4() != nil {
+			v = "IPv4"
+		}
+		if version != "" && version != v {
+			return fmt.Errorf("source and destination IP must be of the same IP version")
+		}
+		version = v
+	}
+	switch {
+	case strings.EqualFold(r.IPVersion, "IPv4"), strings.EqualFold(r.IPVersion, "IPv6"):
+		r.IPVersion = "IPv" + r.IPVersion[3:]
+	case r.IPVersion == "":
+		r.IPVersion = version
+		if r.IPVersion == "" {
+			r.IPVersion = "IPv4"
+		}
+	default:
+		return fmt.Errorf("ip-version must be IPv4 or IPv6")
+	}
+	if version != "" && version != r.IPVersion {
+		return fmt.Errorf("the IPs must be of the IP version %s", r.IPVersion)
+	}
+
+	if (r.PortRangeStart != nil || r.PortRangeEnd != nil) && r.Protocol != "TCP" && r.Protocol != "UDP" {
+		return fmt.Errorf("port ranges are only allowed for TCP and UDP")
+	}
+	if r.PortRangeStart != nil && r.PortRangeEnd == nil {
+		r.PortRangeEnd = r.PortRangeStart
+	}
+	if r.PortRangeEnd != nil && r.PortRangeStart == nil {
+		r.PortRangeStart = r.PortRangeEnd
+	}
+	if (r.IcmpType != nil || r.IcmpCode != nil) && r.Protocol != "ICMP" && r.Protocol != "ICMPv6" {
+		return fmt.Errorf("ICMP type and code are only allowed for ICMP")
+	}
+	return nil
+}
+
+// ruleFromAPI converts a rule of the API for comparison with the rules of a ruleset.
+func ruleFromAPI(f ionoscloud.FirewallRule) Rule {
+	p := ionoscloud.FirewallruleProperties{}
+	if f.Properties != nil {
+		p = *f.Properties
+	}
+	str := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	ip := func(s *string) string {
+		if parsed := net.ParseIP(str(s)); parsed != nil {
+			return parsed.String()
+		}
+		return str(s)
+	}
+	r := Rule{
+		Name:           str(p.Name),
+		Protocol:       str(p.Protocol),
+		Direction:      strings.ToUpper(str(p.Type)),
+		IPVersion:      str(p.IpVersion),
+		SourceMac:      strings.ToLower(str(p.SourceMac)),
+		SourceIP:       ip(p.SourceIp),
+		DestinationIP:  ip(p.TargetIp),
+		IcmpType:       p.IcmpType,
+		IcmpCode:       p.IcmpCode,
+		PortRangeStart: p.PortRangeStart,
+		PortRangeEnd:   p.PortRangeEnd,
+	}
+	// Rules created before IPv6 support have no IP version.
+	if r.IPVersion == "" {
+		r.IPVersion = "IPv4"
+	}
+	return r
+}
+
+// properties converts the rule for create requests. Unset fields allow everything.
+func (r Rule) properties() ionoscloud.FirewallruleProperties {
+	p := ionoscloud.FirewallruleProperties{
+		Name:           &r.Name,
+		Protocol:       &r.Protocol,
+		Type:           &r.Direction,
+		IpVersion:      &r.IPVersion,
+		IcmpType:       r.IcmpType,
+		IcmpCode:       r.IcmpCode,
+		PortRangeStart: r.PortRangeStart,
+		PortRangeEnd:   r.PortRangeEnd,
+	}
+	if r.SourceMac != "" {
+		p.SourceMac = &r.SourceMac
+	}
+	if r.SourceIP != "" {
+		p.SourceIp = &r.SourceIP
+	}
+	if r.DestinationIP != "" {
+		p.TargetIp = &r.DestinationIP
+	}
+	return p
+}
+
+// String describes what the rule allows, e.g. 'TCP 22 INGRESS from 203.0.113.0'.
+func (r Rule) String() string {
+	s := r.Protocol
+	if r.PortRangeStart != nil {
+		s += fmt.Sprintf(" %d", *r.PortRangeStart)
+		if r.PortRangeEnd != nil && *r.PortRangeEnd != *r.PortRangeStart {
+			s += fmt.Sprintf("-%d", *r.PortRangeEnd)
+		}
+	}
+	if r.IcmpType != nil {
+		s += fmt.Sprintf(" type %d", *r.IcmpType)
+	}
+	if r.IcmpCode != nil {
+		s += fmt.Sprintf(" code %d", *r.IcmpCode)
+	}
+	s += " " + r.Direction
+	if r.SourceMac != "" {
+		s += " from " + r.SourceMac
+	}
+	if r.SourceIP != "" {
+		s += " from " + r.SourceIP
+	}
+	if r.DestinationIP != "" {
+		s += " to " + r.DestinationIP
+	}
+	return s
+}
+
+// fieldDiff is a field of a rule that differs between the API and the ruleset.
+type fieldDiff struct {
+	Field    string
+	Old, New string
+	// Unset is true if the field has to be removed from the rule.
+	Unset bool
+}
+
+func (d fieldDiff) String() string {
+	return fmt.Sprintf("%s %s -> %s", d.Field, orAny(d.Old), orAny(d.New))
+}
+
+func orAny(s string) string {
+	if s == "" {
+		return "(any)"
+	}
+	return s
+}
+
+func int32String(i *int32) string {
+	if i == nil {
+		return ""
+	}
+	return fmt.Sprint(*i)
+}
+
+// diffRules returns the fields of rule have that differ from rule want.
+func diffRules(have, want Rule) []fieldDiff {
+	var diffs []fieldDiff
+	for _, f := range []struct{ field, have, want string }{
+		{"name", have.Name, want.Name},
+		{"protocol", have.Protocol, want.Protocol},
+		{"direction", have.Direction, want.Direction},
+		{"ip-version", have.IPVersion, want.IPVersion},
+		{"source-mac", have.SourceMac, want.SourceMac},
+		{"source-ip", have.SourceIP, want.SourceIP},
+		{"destination-ip", have.DestinationIP, want.DestinationIP},
+		{"icmp-type", int32String(have.IcmpType), int32String(want.IcmpType)},
+		{"icmp-code", int32String(have.IcmpCode), int32String(want.IcmpCode)},
+		{"port-range-start", int32String(have.PortRangeStart), int32String(want.PortRangeStart)},
+		{"port-range-end", int32String(have.PortRangeEnd), int32String(want.PortRangeEnd)},
+	} {
+		if f.have != f.want {
+			diffs = append(diffs, fieldDiff{Field: f.field, Old: f.have, New: f.want, Unset: f.want == ""})
+		}
+	}
+	return diffs
+}
+
+// replaceRequired reports whether the differences cannot be patched, because the protocol cannot be changed
+// and port ranges cannot be removed from a rule.
+func replaceRequired(diffs []fieldDiff) bool {
+	return slices.ContainsFunc(diffs, func(d fieldDiff) bool {
+		return d.Field == "protocol" || (d.Unset && strings.HasPrefix(d.Field, "port-range"))
+	})
+}
+
+// patch returns the properties that change the rule with the differences.
+func patch(want Rule, diffs []fieldDiff) ionoscloud.FirewallruleProperties {
+	full := want.properties()
+	p := ionoscloud.FirewallruleProperties{}
+	for _, d := range diffs {
+		switch d.Field {
+		case "name":
+			p.Name = full.Name
+		case "direction":
+			p.Type = full.Type
+		case "ip-version":
+			p.IpVersion = full.IpVersion
+		case "source-mac":
+			p.SourceMac = full.SourceMac
+			if d.Unset {
+				p.SetSourceMacNil()
+			}
+		case "source-ip":
+			p.SourceIp = full.SourceIp
+			if d.Unset {
+				p.SetSourceIpNil()
+			}
+		case "destination-ip":
+			p.TargetIp = full.TargetIp
+			if d.Unset {
+				p.SetTargetIpNil()
+			}
+		case "icmp-type":
+			p.IcmpType = full.IcmpType
+			if d.Unset {
+				p.SetIcmpTypeNil()
+			}
+		case "icmp-code":
+			p.IcmpCode = full.IcmpCode
+			if d.Unset {
+				p.SetIcmpCodeNil()
+			}
+		case "port-range-start":
+			p.PortRangeStart = full.PortRangeStart
+		case "port-range-end":
+			p.PortRangeEnd = full.PortRangeEnd
+		}
+	}
+	return p
+}
+
+type syncAction string
+
+const (
+	syncCreate  syncAction = "create"
+	syncUpdate  syncAction = "update"
+	syncReplace syncAction = "replace"
+	syncDelete  syncAction = "delete"
+	// syncKeep is a rule that is not in the ruleset and is kept, because --prune is not set.
+	syncKeep syncAction = "keep"
+)
+
+// syncChange is a step of the plan of a NIC.
+type syncChange struct {
+	Action syncAction
+	// Rule is the rule of the ruleset, or the rule of the API for delete and keep.
+	Rule Rule
+	// ID is the ID of the rule of the API, empty for create.
+	ID    string
+	Diffs []fieldDiff
+}
+
+func (c syncChange) String() string {
+	switch c.Action {
+	case syncCreate:
+		return fmt.Sprintf("+ %s: create %s", c.Rule.Name, c.Rule)
+	case syncUpdate, syncReplace:
+		diffs := make([]string, len(c.Diffs))
+		for i, d := range c.Diffs {
+			diffs[i] = d.String()
+		}
+		symbol := "~"
+		if c.Action == syncReplace {
+			symbol = "-/+"
+		}
+		return fmt.Sprintf("%s %s: %s %s", symbol, c.Rule.Name, c.Action, strings.Join(diffs, ", "))
+	case syncDelete:
+		return fmt.Sprintf("- %s: delete %s (%s)", ruleName(c.Rule.Name), c.Rule, c.ID)
+	}
+	return fmt.Sprintf("  %s: not in the ruleset, kept %s (%s)", ruleName(c.Rule.Name), c.Rule, c.ID)
+}
+
+func ruleName(name string) string {
+	if name == "" {
+		return "(unnamed)"
+	}
+	return name
+}
+
+// planSync returns the changes that make the rules of a NIC equal to the ruleset. Rules of the NIC are matched
+// by name, or else by their properties. Rules that match no rule of the ruleset are deleted if prune is set.
+func planSync(want []Rule, have []ionoscloud.FirewallRule, prune bool) []syncChange {
+	haveRules := make([]Rule, len(have))
+	for i, f := range have {
+		haveRules[i] = ruleFromAPI(f)
+	}
+	matched := make([]bool, len(have))
+	match := func(ok func(Rule) bool) int {
+		for i, r := range haveRules {
+			if !matched[i] && ok(r) {
+				matched[i] = true
+				return i
+			}
+		}
+		return -1
+	}
+
+	var changes []syncChange
+	var unmatched []Rule
+	for _, w := range want {
+		i := match(func(r Rule) bool { return r.Name == w.Name })
+		if i < 0 {
+			unmatched = append(unmatched, w)
+			continue
+		}
+		changes = append(changes, changeOf(w, haveRules[i], have[i]))
+	}
+	for _, w := range unmatched {
+		i := match(func(r Rule) bool {
+			r.Name = w.Name
+			return len(diffRules(r, w)) == 0
+		})
+		if i < 0 {
+			changes = append(changes, syncChange{Action: syncCreate, Rule: w})
+			continue
+		}
+		changes = append(changes, changeOf(w, haveRules[i], have[i]))
+	}
+
+	for i, r := range haveRules {
+		if matched[i] {
+			continue
+		}
+		action := syncKeep
+		if prune {
+			action = syncDelete
+		}
+		changes = append(changes, syncChange{Action: action, Rule: r, ID: *have[i].Id})
+	}
+
+	return slices.DeleteFunc(changes, func(c syncChange) bool { return c.Action == "" })
+}
+
+// changeOf returns the change from a rule of the API to a rule of the ruleset, without action if they are equal.
+func changeOf(want, haveRule Rule, have ionoscloud.FirewallRule) syncChange {
+	diffs := diffRules(haveRule, want)
+	c := syncChange{Rule: want, ID: *have.Id, Diffs: diffs}
+	switch {
+	case len(diffs) == 0:
+	case replaceRequired(diffs):
+		c.Action = syncReplace
+	default:
+		c.Action = syncUpdate
+	}
+	return c
+}
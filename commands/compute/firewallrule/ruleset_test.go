@@ -0,0 +1,199 @@
+package firewallrule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testRuleset = `
+rules:
+  - name: ssh
+    protocol: tcp
+    port-range-start: 22
+    source-ip: 2001:DB8::10
+  - name: http
+    protocol: TCP
+    port-range-start: 80
+    port-range-end: 81
+  - name: ping
+    protocol: ICMP
+    icmp-type: 8
+`
+
+func apiRule(id string, p ionoscloud.FirewallruleProperties) ionoscloud.FirewallRule {
+	return ionoscloud.FirewallRule{Id: pointer.From(id), Properties: &p}
+}
+
+func TestParseRuleset(t *testing.T) {
+	rules, err := parseRuleset(strings.NewReader(testRuleset))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Rule{
+		Name:           "ssh",
+		Protocol:       "TCP",
+		Direction:      "INGRESS",
+		IPVersion:      "IPv6",
+		SourceIP:       "2001:db8::10",
+		PortRangeStart: pointer.From(int32(22)),
+		PortRangeEnd:   pointer.From(int32(22)),
+	}, rules[0])
+	assert.Equal(t, "IPv4", rules[2].IPVersion)
+
+	for ruleset, want := range map[string]string{
+		"rules:\n  - protocol: TCP\n":                                                   "has no name",
+		"rules:\n  - {name: a, protocol: TCP}\n  - {name: a, protocol: UDP}\n":          "more than once",
+		"rules:\n  - {name: a, protocol: SCTP}\n":                                       "protocol must be one of",
+		"rules:\n  - {name: a, protocol: ICMP, port-range-start: 1}\n":                  "only allowed for TCP and UDP",
+		"rules:\n  - {name: a, protocol: TCP, icmp-type: 1}\n":                          "only allowed for ICMP",
+		"rules:\n  - {name: a, protocol: TCP, source-ip: 10.0.0.1, ip-version: IPv6}\n": "IP version IPv6",
+		"rules:\n  - {name: a, protocol: TCP, port: 22}\n":                              "field port not found",
+	} {
+		_, err := parseRuleset(strings.NewReader(ruleset))
+		assert.ErrorContains(t, err, want, ruleset)
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	want, err := parseRuleset(strings.NewReader(testRuleset))
+	if !assert.NoError(t, err) {
+		return
+	}
+	have := []ionoscloud.FirewallRule{
+		// Equal, only written differently.
+		apiRule("1", ionoscloud.FirewallruleProperties{
+			Name: pointer.From("ssh"), Protocol: pointer.From("TCP"), Type: pointer.From("INGRESS"), IpVersion: pointer.From("IPv6"),
+			SourceIp: pointer.From("2001:0db8:0000::10"), PortRangeStart: pointer.From(int32(22)), PortRangeEnd: pointer.From(int32(22)),
+		}),
+		// Port range changed.
+		apiRule("2", ionoscloud.FirewallruleProperties{
+			Name: pointer.From("http"), Protocol: pointer.From("TCP"), Type: pointer.From("INGRESS"),
+			PortRangeStart: pointer.From(int32(80)), PortRangeEnd: pointer.From(int32(80)),
+		}),
+		// Equal to ping, but unnamed.
+		apiRule("3", ionoscloud.FirewallruleProperties{
+			Name: pointer.From(""), Protocol: pointer.From("ICMP"), Type: pointer.From("INGRESS"), IpVersion: pointer.From("IPv4"),
+			IcmpType: pointer.From(int32(8)),
+		}),
+		apiRule("4", ionoscloud.FirewallruleProperties{
+			Name: pointer.From("legacy"), Protocol: pointer.From("UDP"), Type: pointer.From("INGRESS"), IpVersion: pointer.From("IPv4"),
+		}),
+	}
+
+	plan := planSync(want, have, false)
+	if !assert.Len(t, plan, 3) {
+		return
+	}
+	assert.Equal(t, syncUpdate, plan[0].Action)
+	assert.Equal(t, "2", plan[0].ID)
+	assert.Equal(t, "~ http: update port-range-end 80 -> 81", plan[0].String())
+	assert.Equal(t, "81", plan[0].Diffs[0].New)
+	assert.Equal(t, syncUpdate, plan[1].Action)
+	assert.Equal(t, "3", plan[1].ID)
+	assert.Equal(t, []fieldDiff{{Field: "name", New: "ping"}}, plan[1].Diffs)
+	assert.Equal(t, syncKeep, plan[2].Action)
+	assert.Equal(t, "  legacy: not in the ruleset, kept UDP INGRESS (4)", plan[2].String())
+
+	plan = planSync(want, have, true)
+	assert.Equal(t, syncDelete, plan[2].Action)
+
+	plan = planSync(want, nil, false)
+	if assert.Len(t, plan, 3) {
+		assert.Equal(t, "+ ssh: create TCP 22 INGRESS from 2001:db8::10", plan[0].String())
+	}
+}
+
+func TestPlanSyncReplace(t *testing.T) {
+	want, _ := parseRuleset(strings.NewReader("rules:\n  - {name: dns, protocol: ANY}\n  - {name: web, protocol: TCP}\n"))
+	have := []ionoscloud.FirewallRule{
+		apiRule("1", ionoscloud.FirewallruleProperties{Name: pointer.From("dns"), Protocol: pointer.From("UDP"), Type: pointer.From("INGRESS")}),
+		apiRule("2", ionoscloud.FirewallruleProperties{
+			Name: pointer.From("web"), Protocol: pointer.From("TCP"), Type: pointer.From("INGRESS"),
+			PortRangeStart: pointer.From(int32(443)), PortRangeEnd: pointer.From(int32(443)),
+		}),
+	}
+
+	plan := planSync(want, have, false)
+	if assert.Len(t, plan, 2) {
+		assert.Equal(t, syncReplace, plan[0].Action)
+		assert.Equal(t, "-/+ dns: replace protocol UDP -> ANY", plan[0].String())
+		assert.Equal(t, syncReplace, plan[1].Action)
+	}
+}
+
+func TestPatch(t *testing.T) {
+	want := Rule{Name: "ssh", Protocol: "TCP", Direction: "EGRESS", IPVersion: "IPv4", PortRangeStart: pointer.From(int32(22))}
+	have := Rule{Name: "ssh", Protocol: "TCP", Direction: "INGRESS", IPVersion: "IPv4", SourceIP: "10.0.0.1", PortRangeStart: pointer.From(int32(22))}
+
+	p := patch(want, diffRules(have, want))
+	assert.Equal(t, "EGRESS", *p.Type)
+	assert.Nil(t, p.Name)
+	assert.Nil(t, p.PortRangeStart)
+	assert.Same(t, &ionoscloud.Nilstring, p.SourceIp)
+}
+
+func TestLabelledServers(t *testing.T) {
+	label := func(resourceType, href, key, value string) ionoscloud.Label {
+		return ionoscloud.Label{Properties: &ionoscloud.LabelProperties{
+			ResourceType: pointer.From(resourceType), ResourceHref: pointer.From(href), Key: pointer.From(key), Value: pointer.From(value),
+		}}
+	}
+	labels := []ionoscloud.Label{
+		label("server", "https://api.ionos.com/cloudapi/v6/datacenters/dc1/servers/s1", "role", "web"),
+		label("server", "https://api.ionos.com/cloudapi/v6/datacenters/dc1/servers/s1", "env", "prod"),
+		label("server", "https://api.ionos.com/cloudapi/v6/datacenters/dc2/servers/s2", "role", "web"),
+		label("server", "https://api.ionos.com/cloudapi/v6/datacenters/dc2/servers/s3", "role", "db"),
+		label("datacenter", "https://api.ionos.com/cloudapi/v6/datacenters/dc1", "role", "web"),
+	}
+
+	selector, err := parseSelector([]string{"role=web"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []nicTarget{{DatacenterId: "dc1", ServerId: "s1"}, {DatacenterId: "dc2", ServerId: "s2"}},
+		labelledServers(labels, selector, ""))
+	assert.Equal(t, []nicTarget{{DatacenterId: "dc2", ServerId: "s2"}}, labelledServers(labels, selector, "dc2"))
+
+	selector, _ = parseSelector([]string{"role=web", "env=prod"})
+	assert.Equal(t, []nicTarget{{DatacenterId: "dc1", ServerId: "s1"}}, labelledServers(labels, selector, ""))
+
+	_, err = parseSelector([]string{"role"})
+	assert.Error(t, err)
+}
+
+func TestApplySyncReplace(t *testing.T) {
+	target := nicTarget{DatacenterId: "dc1", ServerId: "s1", NicId: "n1"}
+	plan := []syncChange{{Action: syncReplace, ID: "1", Rule: Rule{Name: "dns", Protocol: "ANY", Direction: "INGRESS"}}}
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		gomock.InOrder(
+			rm.CloudApiV6Mocks.FirewallRule.EXPECT().Create("dc1", "s1", "n1", gomock.Any()).Return(&resources.FirewallRule{}, &testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.FirewallRule.EXPECT().Delete("dc1", "s1", "n1", "1").Return(&testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil),
+		)
+		assert.NoError(t, applySync(cfg, target, plan))
+
+		// The old rule is kept if its new rule fails to be created
+		gomock.InOrder(
+			rm.CloudApiV6Mocks.FirewallRule.EXPECT().Create("dc1", "s1", "n1", gomock.Any()).Return(&resources.FirewallRule{}, &testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(nil, errors.New("request failed")),
+		)
+		assert.EqualError(t, applySync(cfg, target, plan), "failed to replace Firewall Rule dns of NIC n1 of Server s1 in Data Center dc1: request failed")
+	})
+}
@@ -0,0 +1,339 @@
+package firewallrule
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/confirm"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFile     = "file"
+	FlagPrune    = "prune"
+	FlagSelector = "selector"
+	FlagDryRun   = "dry-run"
+)
+
+func FirewallRuleSyncCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "firewallrule",
+		Resource:  "firewallrule",
+		Verb:      "sync",
+		Aliases:   []string{"apply"},
+		ShortDesc: "Make the Firewall Rules of NICs match a ruleset file",
+		LongDesc: `Use this command to make the Firewall Rules of a NIC, or of all NICs of the Servers with the labels of ` + "`--selector`" + `, match a ruleset file. The rules of each NIC are compared with the ruleset, a plan of the changes is printed and, after confirmation, the minimal changes are applied.
+
+The ruleset is a YAML file with a list of rules. The keys are the flags of ` + "`firewallrule create`" + `, and ` + "`name`" + ` and ` + "`protocol`" + ` are required:
+
+    rules:
+      - name: ssh
+        protocol: TCP
+        port-range-start: 22
+        source-ip: 203.0.113.10
+      - name: ping
+        protocol: ICMP
+        icmp-type: 8
+
+Rules of a NIC are matched with the rules of the ruleset by name, or else by their properties. Changed rules are updated, or replaced if their protocol changes or a port range is removed, which cannot be updated. Each change is applied after the previous one finished, and a replaced rule is only deleted after its new rule was created. Rules that are not in the ruleset are kept, unless ` + "`--prune`" + ` is set.
+
+Use ` + "`--dry-run`" + ` to only print the plan.
+
+Required values to run command:
+
+* File
+* Data Center Id, Server Id and Nic Id, or Selector`,
+		Example: `ionosctl compute firewallrule sync --datacenter-id DATACENTER_ID --server-id SERVER_ID --nic-id NIC_ID --file rules.yaml --prune
+ionosctl compute firewallrule sync --selector role=web --file rules.yaml --dry-run`,
+		PreCmdRun:  PreRunFirewallRuleSync,
+		CmdRun:     RunFirewallRuleSync,
+		InitClient: true,
+	})
+	cmd.AddStringFlag(FlagFile, "", "", "Path to the ruleset file. '-' reads from stdin, which needs --force to apply the plan", core.RequiredFlagOption())
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId+". With --"+FlagSelector+", only Servers of this Data Center are selected")
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgServerId, "", "", cloudapiv6.ServerId)
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgServerId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ServersIds(viper.GetString(core.GetFlagName(cmd.NS, cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgNicId, "", "", cloudapiv6.NicId)
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgNicId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.NicsIds(viper.GetString(core.GetFlagName(cmd.NS, cloudapiv6.ArgDataCenterId)),
+			viper.GetString(core.GetFlagName(cmd.NS, cloudapiv6.ArgServerId)),
+		), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringSliceFlag(FlagSelector, "", nil, "Sync all NICs of the Servers with these labels, e.g. --selector env=prod,role=web")
+	cmd.AddBoolFlag(FlagPrune, "", false, "Delete the Firewall Rules that are not in the ruleset")
+	cmd.AddBoolFlag(FlagDryRun, "", false, "Only print the plan, without applying it")
+
+	return cmd
+}
+
+func PreRunFirewallRuleSync(c *core.PreCommandConfig) error {
+	c.Command.Command.MarkFlagsMutuallyExclusive(FlagSelector, cloudapiv6.ArgServerId)
+	c.Command.Command.MarkFlagsMutuallyExclusive(FlagSelector, cloudapiv6.ArgNicId)
+	if err := core.CheckRequiredFlagsSets(c.Command, c.NS,
+		[]string{FlagFile, cloudapiv6.ArgDataCenterId, cloudapiv6.ArgServerId, cloudapiv6.ArgNicId},
+		[]string{FlagFile, FlagSelector},
+	); err != nil {
+		return err
+	}
+	_, err := parseSelector(viper.GetStringSlice(core.GetFlagName(c.NS, FlagSelector)))
+	return err
+}
+
+// nicTarget is a NIC whose Firewall Rules are synced.
+type nicTarget struct {
+	DatacenterId, ServerId, NicId string
+}
+
+func (t nicTarget) String() string {
+	return fmt.Sprintf("NIC %s of Server %s in Data Center %s", t.NicId, t.ServerId, t.DatacenterId)
+}
+
+func RunFirewallRuleSync(c *core.CommandConfig) error {
+	rules, err := readRuleset(c, viper.GetString(core.GetFlagName(c.NS, FlagFile)))
+	if err != nil {
+		return err
+	}
+	targets, err := syncTargets(c)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		c.Msg("No NICs match the selector")
+		return nil
+	}
+
+	prune := viper.GetBool(core.GetFlagName(c.NS, FlagPrune))
+	out := c.Command.Command.OutOrStdout()
+	plans := make([][]syncChange, len(targets))
+	counts := map[syncAction]int{}
+	for i, t := range targets {
+		firewallRules, resp, err := c.CloudApiV6Services.FirewallRules().List(t.DatacenterId, t.ServerId, t.NicId)
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return fmt.Errorf("failed listing Firewall Rules of %s: %w", t, err)
+		}
+
+		var have []ionoscloud.FirewallRule
+		if items := firewallRules.GetItems(); items != nil {
+			have = *items
+		}
+		plans[i] = planSync(rules, have, prune)
+		fmt.Fprintf(out, "%s:\n", t)
+		if len(plans[i]) == 0 {
+			fmt.Fprintln(out, "  no changes")
+		}
+		for _, change := range plans[i] {
+			fmt.Fprintf(out, "  %s\n", change)
+			counts[change.Action]++
+		}
+	}
+	fmt.Fprintf(out, "\nPlan: %d to create, %d to update, %d to replace, %d to delete.\n",
+		counts[syncCreate], counts[syncUpdate], counts[syncReplace], counts[syncDelete])
+	if counts[syncKeep] > 0 {
+		fmt.Fprintf(out, "%d Firewall Rules are not in the ruleset and are kept, use --%s to delete them.\n", counts[syncKeep], FlagPrune)
+	}
+
+	if counts[syncCreate]+counts[syncUpdate]+counts[syncReplace]+counts[syncDelete] == 0 ||
+		viper.GetBool(core.GetFlagName(c.NS, FlagDryRun)) {
+		return nil
+	}
+	if !confirm.FAsk(c.Command.Command.InOrStdin(), "apply the plan", viper.GetBool(constants.ArgForce)) {
+		return fmt.Errorf(confirm.UserDenied)
+	}
+
+	for i, t := range targets {
+		if err := applySync(c, t, plans[i]); err != nil {
+			return err
+		}
+	}
+	c.Msg("Firewall Rules successfully synced")
+
+	return nil
+}
+
+func readRuleset(c *core.CommandConfig, path string) ([]Rule, error) {
+	var r io.Reader = c.Command.Command.InOrStdin()
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return parseRuleset(r)
+}
+
+// syncTargets returns the NIC of the flags, or the NICs of the Servers matching --selector.
+func syncTargets(c *core.CommandConfig) ([]nicTarget, error) {
+	datacenterId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+	if !viper.IsSet(core.GetFlagName(c.NS, FlagSelector)) {
+		return []nicTarget{{
+			DatacenterId: datacenterId,
+			ServerId:     viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgServerId)),
+			NicId:        viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgNicId)),
+		}}, nil
+	}
+
+	selector, err := parseSelector(viper.GetStringSlice(core.GetFlagName(c.NS, FlagSelector)))
+	if err != nil {
+		return nil, err
+	}
+	labels, resp, err := c.CloudApiV6Services.Labels().List()
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed listing labels: %w", err)
+	}
+
+	var items []ionoscloud.Label
+	if labels.GetItems() != nil {
+		items = *labels.GetItems()
+	}
+	var targets []nicTarget
+	for _, server := range labelledServers(items, selector, datacenterId) {
+		nics, resp, err := c.CloudApiV6Services.Nics().List(server.DatacenterId, server.ServerId)
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed listing NICs of Server %s: %w", server.ServerId, err)
+		}
+		if nics.GetItems() == nil {
+			continue
+		}
+		for _, nic := range *nics.GetItems() {
+			server.NicId = *nic.Id
+			targets = append(targets, server)
+		}
+	}
+	return targets, nil
+}
+
+// parseSelector parses labels in the form key=value.
+func parseSelector(selector []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, s := range selector {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid selector %q, must be in the form key=value", s)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+var serverHrefPattern = regexp.MustCompile(`/datacenters/([^/]+)/servers/([^/]+)$`)
+
+// labelledServers returns the Servers that have all labels of the selector, optionally only of one Data Center.
+func labelledServers(labels []ionoscloud.Label, selector map[string]string, datacenterId string) []nicTarget {
+	matches := map[string]int{}
+	var servers []nicTarget
+	for _, l := range labels {
+		p := l.Properties
+		if p == nil || p.ResourceType == nil || *p.ResourceType != "server" || p.Key == nil || p.Value == nil || p.ResourceHref == nil {
+			continue
+		}
+		if value, ok := selector[*p.Key]; !ok || value != *p.Value {
+			continue
+		}
+		m := serverHrefPattern.FindStringSubmatch(*p.ResourceHref)
+		if m == nil || (datacenterId != "" && m[1] != datacenterId) {
+			continue
+		}
+		matches[m[2]]++
+		if matches[m[2]] == len(selector) {
+			servers = append(servers, nicTarget{DatacenterId: m[1], ServerId: m[2]})
+		}
+	}
+	return servers
+}
+
+// applySync applies the plan of a NIC. Rules are created before rules are deleted, so no traffic is
+// blocked in between. Each request is waited for, so a replaced rule is only deleted once its new rule exists.
+func applySync(c *core.CommandConfig, t nicTarget, plan []syncChange) error {
+	rules := c.CloudApiV6Services.FirewallRules()
+	wait := func(resp *resources.Response) error {
+		if resp == nil {
+			return nil
+		}
+		if request.GetId(resp) != "" {
+			c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+		}
+		if path := request.GetRequestPath(resp); path != "" {
+			if _, err := c.CloudApiV6Services.Requests().Wait(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	create := func(r Rule) error {
+		properties := r.properties()
+		_, resp, err := rules.Create(t.DatacenterId, t.ServerId, t.NicId, resources.FirewallRule{
+			FirewallRule: ionoscloud.FirewallRule{Properties: &properties},
+		})
+		if err != nil {
+			return err
+		}
+		return wait(resp)
+	}
+	remove := func(id string) error {
+		resp, err := rules.Delete(t.DatacenterId, t.ServerId, t.NicId, id)
+		if err != nil {
+			return err
+		}
+		return wait(resp)
+	}
+
+	for _, action := range []syncAction{syncCreate, syncUpdate, syncReplace, syncDelete} {
+		for _, change := range plan {
+			if change.Action != action {
+				continue
+			}
+			c.Verbose("%s Firewall Rule %s of %s", action, change.Rule.Name, t)
+
+			var err error
+			switch action {
+			case syncCreate:
+				err = create(change.Rule)
+			case syncUpdate:
+				var resp *resources.Response
+				_, resp, err = rules.Update(t.DatacenterId, t.ServerId, t.NicId, change.ID,
+					resources.FirewallRuleProperties{FirewallruleProperties: patch(change.Rule, change.Diffs)})
+				if err == nil {
+					err = wait(resp)
+				}
+			case syncReplace:
+				if err = create(change.Rule); err == nil {
+					err = remove(change.ID)
+				}
+			case syncDelete:
+				err = remove(change.ID)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s Firewall Rule %s of %s: %w", action, ruleName(change.Rule.Name), t, err)
+			}
+		}
+	}
+	return nil
+}
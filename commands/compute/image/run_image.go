@@ -20,13 +20,24 @@ import (
 )
 
 const (
-	FlagRenameImages    = "rename"
-	FlagImage           = "image"
-	FlagSkipUpdate      = "skip-update"
-	FlagSkipVerify      = "skip-verify"
-	FlagFtpUrl          = "ftp-url"
-	FlagFtpPort         = "ftp-port"
-	FlagCertificatePath = "crt-path"
+	FlagRenameImages     = "rename"
+	FlagImage            = "image"
+	FlagSkipUpdate       = "skip-update"
+	FlagSkipVerify       = "skip-verify"
+	FlagFtpUrl           = "ftp-url"
+	FlagFtpPort          = "ftp-port"
+	FlagCertificatePath  = "crt-path"
+	FlagResume           = "resume"
+	FlagResumeUnverified = "resume-unverified"
+	FlagSkipChecksum     = "skip-checksum"
+	FlagBandwidthLimit   = "bandwidth-limit"
+	FlagSource           = "source"
+	FlagSourceChecksum   = "source-checksum"
+	FlagConvertTo        = "convert-to"
+	FlagQemuImg          = "qemu-img"
+	FlagPlatform         = "platform"
+	FlagSourceTokenName  = "source-token-name"
+	FlagSourceTokenPass  = "source-token-password"
 )
 
 func addPropertiesFlags(command *core.Command) {
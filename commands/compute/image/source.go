@@ -0,0 +1,277 @@
+package image
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+)
+
+// validImageExts are the image extensions the FTP servers accept.
+var validImageExts = []string{".iso", ".img", ".vmdk", ".vhd", ".vhdx", ".cow", ".qcow", ".qcow2", ".raw", ".vpc", ".vdi"}
+
+// convertFormats maps the --convert-to formats to the qemu-img output format and the extension of the converted image.
+var convertFormats = map[string]struct{ qemu, ext string }{
+	"raw":   {"raw", ".raw"},
+	"qcow2": {"qcow2", ".qcow2"},
+	"vmdk":  {"vmdk", ".vmdk"},
+	"vhd":   {"vpc", ".vhd"},
+}
+
+// inputFormats maps image extensions to the qemu-img input format. Images with other extensions are probed by qemu-img.
+var inputFormats = map[string]string{
+	".qcow2": "qcow2",
+	".qcow":  "qcow",
+	".raw":   "raw",
+	".vmdk":  "vmdk",
+	".vhd":   "vpc",
+	".vpc":   "vpc",
+	".vhdx":  "vhdx",
+	".vdi":   "vdi",
+}
+
+// imageSources stages the images to upload: it downloads --source images and converts images with --convert-to.
+type imageSources struct {
+	dir          string // Staging directory, removed by the caller
+	client       *http.Client
+	checksums    []string // Aligned with the https:// sources
+	skipChecksum bool
+	convertTo    string
+	qemuImg      string
+	artifact     ociregistry.ArtifactOptions
+	match        func(name string) bool // Selects the image file of an OCI artifact
+	stderr       io.Writer
+	verbose      func(format string, args ...any)
+}
+
+// stage returns the paths of the images to upload, first the local images, then the sources, in the order given.
+func (s *imageSources) stage(ctx context.Context, images, sources []string) ([]string, error) {
+	staged := slices.Clone(images)
+
+	var https int
+	for i, src := range sources {
+		dir := filepath.Join(s.dir, fmt.Sprintf("source-%d", i))
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			return nil, err
+		}
+
+		var img string
+		var err error
+		if ref, ok := strings.CutPrefix(src, "oci://"); ok {
+			img, err = s.pull(ctx, ref, dir)
+		} else {
+			var checksum string
+			if https < len(s.checksums) {
+				checksum = s.checksums[https]
+			}
+			https++
+			img, err = s.download(ctx, src, checksum, dir)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed staging %s: %w", src, err)
+		}
+		staged = append(staged, img)
+	}
+
+	if s.convertTo == "" {
+		return staged, nil
+	}
+	for i, img := range staged {
+		dir := filepath.Join(s.dir, fmt.Sprintf("converted-%d", i))
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			return nil, err
+		}
+		converted, err := s.convert(ctx, img, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting %s: %w", img, err)
+		}
+		staged[i] = converted
+	}
+	return staged, nil
+}
+
+// download downloads the image at rawURL into dir and verifies its SHA-256 against checksum. Without a checksum,
+// the checksum is read from the <rawURL>.sha256 file, unless the verification is skipped.
+func (s *imageSources) download(ctx context.Context, rawURL, checksum, dir string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if checksum == "" && !s.skipChecksum {
+		checksum, err = s.fetchChecksum(ctx, rawURL)
+		if err != nil {
+			return "", err
+		}
+	}
+	want, err := parseChecksum(checksum)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := s.get(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	defer body.Close()
+
+	name := filepath.Join(dir, path.Base(u.Path))
+	f, err := os.Create(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s.verbose("Downloading %s to %s", rawURL, name)
+	h := sha256.New()
+	n, err := io.Copy(f, io.TeeReader(body, h))
+	if err != nil {
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	got := hex.EncodeToString(h.Sum(nil))
+	if want != "" && got != want {
+		return "", fmt.Errorf("checksum mismatch: expected sha256:%s, got sha256:%s", want, got)
+	}
+	s.verbose("Downloaded %d bytes of %s, SHA-256 %s", n, rawURL, got)
+	return name, nil
+}
+
+// fetchChecksum reads the checksum of the image at rawURL from <rawURL>.sha256, in the format of sha256sum.
+func (s *imageSources) fetchChecksum(ctx context.Context, rawURL string) (string, error) {
+	body, err := s.get(ctx, rawURL+".sha256")
+	if err != nil {
+		return "", fmt.Errorf("no checksum for %s, pass it with --%s or use --%s: %w", rawURL, FlagSourceChecksum, FlagSkipChecksum, err)
+	}
+	defer body.Close()
+
+	line, err := bufio.NewReader(io.LimitReader(body, 4096)).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty checksum file %s.sha256", rawURL)
+	}
+	return fields[0], nil
+}
+
+func (s *imageSources) get(ctx context.Context, rawURL string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", rawURL, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// parseChecksum returns the hex encoded SHA-256 of a checksum with an optional sha256: prefix. An empty checksum
+// is not verified.
+func parseChecksum(checksum string) (string, error) {
+	if checksum == "" {
+		return "", nil
+	}
+	sum := strings.ToLower(strings.TrimPrefix(checksum, "sha256:"))
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("invalid checksum %q, expected a hex encoded SHA-256, e.g. sha256:<64 hex characters>", checksum)
+	}
+	return sum, nil
+}
+
+// pull pulls the image file of the OCI artifact at ref into dir.
+func (s *imageSources) pull(ctx context.Context, ref, dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "pull-*")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	file, err := ociregistry.PullArtifactFile(ctx, ref, s.artifact, s.match, f)
+	switch {
+	case errors.Is(err, ociregistry.ErrAuthRequired):
+		return "", fmt.Errorf("%w, set --%s and --%s", err, FlagSourceTokenName, FlagSourceTokenPass)
+	case errors.Is(err, ociregistry.ErrPlatformRequired):
+		return "", fmt.Errorf("%w with --%s", err, FlagPlatform)
+	case err != nil:
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	name := filepath.Join(dir, file.Name)
+	if err := os.Rename(f.Name(), name); err != nil {
+		return "", err
+	}
+	s.verbose("Pulled %s (%d bytes) from %s, digest %s", file.Name, file.Size, ref, file.Digest)
+	return name, nil
+}
+
+// convert converts the image to the --convert-to format in dir with qemu-img. Images already in that format
+// are returned as is.
+func (s *imageSources) convert(ctx context.Context, img, dir string) (string, error) {
+	args, out, ok := convertArgs(img, dir, s.convertTo)
+	if !ok {
+		return img, nil
+	}
+	qemuImg, err := exec.LookPath(s.qemuImg)
+	if err != nil {
+		return "", fmt.Errorf("%s not found, install it or set its path with --%s: %w", s.qemuImg, FlagQemuImg, err)
+	}
+
+	s.verbose("Converting %s to %s with %s", img, out, qemuImg)
+	cmd := exec.CommandContext(ctx, qemuImg, args...)
+	cmd.Stderr = s.stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("%s exited with status %d", s.qemuImg, exitErr.ExitCode())
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// convertArgs returns the qemu-img arguments converting img to format, and the path of the converted image in dir.
+// It returns false if img is already in that format.
+func convertArgs(img, dir, format string) ([]string, string, bool) {
+	to := convertFormats[format]
+	ext := strings.ToLower(filepath.Ext(img))
+	from, known := inputFormats[ext]
+	if known && from == to.qemu {
+		return nil, img, false
+	}
+
+	out := filepath.Join(dir, strings.TrimSuffix(filepath.Base(img), filepath.Ext(img))+to.ext)
+	args := []string{"convert", "-O", to.qemu}
+	if known {
+		args = append(args, "-f", from)
+	}
+	return append(args, img, out), out, true
+}
+
+// isValidImageExt reports if the FTP servers accept images with the extension of name.
+func isValidImageExt(name string) bool {
+	return slices.Contains(validImageExts, filepath.Ext(name))
+}
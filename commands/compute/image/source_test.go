@@ -0,0 +1,178 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSources(t *testing.T, client *http.Client) *imageSources {
+	return &imageSources{
+		dir:     t.TempDir(),
+		client:  client,
+		stderr:  io.Discard,
+		verbose: func(string, ...any) {},
+	}
+}
+
+func TestStageDownload(t *testing.T) {
+	disk := []byte("qcow2 image")
+	sum := sha256.Sum256(disk)
+	checksum := "sha256:" + hex.EncodeToString(sum[:])
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/images/disk.qcow2", "/unverified/disk.qcow2":
+			w.Write(disk)
+		case "/images/disk.qcow2.sha256":
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  disk.qcow2\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("checksum", func(t *testing.T) {
+		s := testSources(t, server.Client())
+		s.checksums = []string{checksum}
+		staged, err := s.stage(context.Background(), []string{"local.iso"}, []string{server.URL + "/images/disk.qcow2"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"local.iso", filepath.Join(s.dir, "source-0", "disk.qcow2")}, staged)
+		data, _ := os.ReadFile(staged[1])
+		assert.Equal(t, disk, data)
+	})
+
+	t.Run("checksum file", func(t *testing.T) {
+		s := testSources(t, server.Client())
+		_, err := s.stage(context.Background(), nil, []string{server.URL + "/images/disk.qcow2"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		s := testSources(t, server.Client())
+		s.checksums = []string{"sha256:" + hex.EncodeToString(make([]byte, sha256.Size))}
+		_, err := s.stage(context.Background(), nil, []string{server.URL + "/images/disk.qcow2"})
+		assert.ErrorContains(t, err, "checksum mismatch")
+	})
+
+	t.Run("missing checksum file", func(t *testing.T) {
+		s := testSources(t, server.Client())
+		_, err := s.stage(context.Background(), nil, []string{server.URL + "/unverified/disk.qcow2"})
+		assert.ErrorContains(t, err, "--"+FlagSourceChecksum)
+
+		s = testSources(t, server.Client())
+		s.skipChecksum = true
+		_, err = s.stage(context.Background(), nil, []string{server.URL + "/unverified/disk.qcow2"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		s := testSources(t, server.Client())
+		s.skipChecksum = true
+		_, err := s.stage(context.Background(), nil, []string{server.URL + "/images/missing.qcow2"})
+		assert.ErrorContains(t, err, "404")
+	})
+}
+
+func TestStagePullAuthRequired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="https://auth.example/token",service="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	s := testSources(t, nil)
+	s.artifact = ociregistry.ArtifactOptions{PlainHTTP: true, HTTPClient: server.Client()}
+	s.match = isValidImageExt
+	_, err := s.stage(context.Background(), nil, []string{"oci://" + strings.TrimPrefix(server.URL, "http://") + "/images/disk:1.0"})
+	assert.ErrorIs(t, err, ociregistry.ErrAuthRequired)
+	assert.ErrorContains(t, err, "set --"+FlagSourceTokenName+" and --"+FlagSourceTokenPass)
+}
+
+func TestConvertArgs(t *testing.T) {
+	args, out, ok := convertArgs("in/disk.vmdk", "out", "qcow2")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join("out", "disk.qcow2"), out)
+	assert.Equal(t, []string{"convert", "-O", "qcow2", "-f", "vmdk", "in/disk.vmdk", out}, args)
+
+	args, out, ok = convertArgs("disk.img", "out", "vhd")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"convert", "-O", "vpc", "disk.img", filepath.Join("out", "disk.vhd")}, args)
+	assert.Equal(t, filepath.Join("out", "disk.vhd"), out)
+
+	_, out, ok = convertArgs("disk.vpc", "out", "vhd")
+	assert.False(t, ok)
+	assert.Equal(t, "disk.vpc", out)
+}
+
+func TestStageConvert(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as qemu-img")
+	}
+	dir := t.TempDir()
+	// The fake qemu-img copies the input, its second to last argument, to the output.
+	qemuImg := filepath.Join(dir, "qemu-img")
+	script := "#!/bin/sh\nfor a; do in=$out; out=$a; done\ncp \"$in\" \"$out\"\n"
+	assert.NoError(t, os.WriteFile(qemuImg, []byte(script), 0o755))
+	img := filepath.Join(dir, "disk.raw")
+	assert.NoError(t, os.WriteFile(img, []byte("raw image"), 0o644))
+
+	s := testSources(t, nil)
+	s.convertTo, s.qemuImg = "qcow2", qemuImg
+	staged, err := s.stage(context.Background(), []string{img, filepath.Join(dir, "other.qcow2")}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(s.dir, "converted-0", "disk.qcow2"), filepath.Join(dir, "other.qcow2")}, staged)
+	data, _ := os.ReadFile(staged[0])
+	assert.Equal(t, "raw image", string(data))
+
+	s = testSources(t, nil)
+	s.convertTo, s.qemuImg = "qcow2", filepath.Join(dir, "missing")
+	_, err = s.stage(context.Background(), []string{img}, nil)
+	assert.ErrorContains(t, err, "--"+FlagQemuImg)
+}
+
+func TestPreRunImageUploadSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]any
+		err   string
+	}{
+		{"no image or source", map[string]any{}, FlagSource},
+		{"source only", map[string]any{FlagSource: []string{"https://example.com/disk.qcow2", "oci://registry.example/images/disk:1.0"}}, ""},
+		{"invalid scheme", map[string]any{FlagSource: []string{"ftp://example.com/disk.qcow2"}}, "https://"},
+		{"invalid extension", map[string]any{FlagSource: []string{"https://example.com/disk.tar"}}, "invalid image extension"},
+		{"extension of converted image", map[string]any{FlagSource: []string{"https://example.com/disk.tar"}, FlagConvertTo: "qcow2"}, ""},
+		{"checksum per https source", map[string]any{FlagSource: []string{"https://example.com/a.raw", "oci://registry.example/b:1"}, FlagSourceChecksum: []string{"sha256:00", "sha256:00"}}, "one checksum"},
+		{"invalid checksum", map[string]any{FlagSource: []string{"https://example.com/a.raw"}, FlagSourceChecksum: []string{"md5:00"}}, "invalid checksum"},
+		{"aliases of images and sources", map[string]any{FlagImage: []string{"a.iso"}, FlagSource: []string{"oci://registry.example/b:1"}, cloudapiv6.ArgImageAlias: []string{"a", "b"}}, ""},
+		{"confidential conversion", map[string]any{FlagSource: []string{"https://example.com/a.raw"}, FlagConvertTo: "vmdk", constants.FlagConfidential: true}, "QCOW2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runPreRunImageUpload(t, func(cfg *core.PreCommandConfig) {
+				for flag, value := range tt.flags {
+					viper.Set(core.GetFlagName(cfg.NS, flag), value)
+				}
+			})
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	"github.com/spf13/viper"
+	"golang.org/x/term"
+)
+
+// bandwidthLimiter shares a bandwidth, in bytes per second, between all uploads.
+type bandwidthLimiter struct {
+	bytesPerSecond int64
+
+	mu   sync.Mutex
+	next time.Time // when the bytes read so far fit into the bandwidth
+}
+
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &bandwidthLimiter{bytesPerSecond: bytesPerSecond}
+}
+
+// wait blocks until n more bytes fit into the bandwidth.
+func (l *bandwidthLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / float64(l.bytesPerSecond) * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reader limits r to the bandwidth. A nil limiter returns r.
+func (l *bandwidthLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, l: l}
+}
+
+type limitedReader struct {
+	ctx context.Context
+	r   io.Reader
+	l   *bandwidthLimiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// Read at most a tenth of a second of bandwidth at once, so concurrent uploads share it evenly
+	if chunk := int(max(r.l.bytesPerSecond/10, 1)); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		if werr := r.l.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+const progressTpl = `{{ string . "prefix" }} {{ counters . }} {{ bar . }} {{ percent . }} {{ speed . }}`
+
+// uploadProgress shows a progress bar per location on a terminal.
+type uploadProgress struct {
+	pool *pb.Pool
+	bars map[string]*pb.ProgressBar
+}
+
+// newUploadProgress starts a progress bar for each location, with its label and the total bytes uploaded to it.
+// It returns nil, and shows nothing, for JSON output, with --quiet, or if w is not a terminal.
+func newUploadProgress(w io.Writer, locations []string, labels map[string]string, totals map[string]int64) *uploadProgress {
+	switch viper.GetString(constants.ArgOutput) {
+	case "json", "api-json":
+		return nil
+	}
+	if viper.GetBool(constants.ArgQuiet) {
+		return nil
+	}
+	if f, ok := w.(*os.File); !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil
+	}
+
+	p := &uploadProgress{bars: map[string]*pb.ProgressBar{}}
+	var bars []*pb.ProgressBar
+	for _, loc := range locations {
+		bar := pb.New64(totals[loc])
+		bar.Set(pb.Bytes, true)
+		bar.Set("prefix", labels[loc])
+		bar.SetTemplateString(progressTpl)
+		p.bars[loc] = bar
+		bars = append(bars, bar)
+	}
+	p.pool = pb.NewPool(bars...)
+	p.pool.Output = w
+	if err := p.pool.Start(); err != nil {
+		return nil
+	}
+	return p
+}
+
+// wrap counts the bytes uploaded to the location on its bar, starting with the bytes already on the server.
+func (p *uploadProgress) wrap(loc string, offset int64, r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	bar := p.bars[loc]
+	bar.Add64(offset)
+	return bar.NewProxyReader(r)
+}
+
+func (p *uploadProgress) stop() {
+	if p == nil {
+		return
+	}
+	for _, bar := range p.bars {
+		bar.Finish()
+	}
+	p.pool.Stop()
+}
+
+// uploadWarning returns a warning if the upload of path to url was verified less than requested: only by its size,
+// although the SHA-256 was to be verified. It returns an empty string otherwise.
+func uploadWarning(result resources.UploadResult, skipChecksum bool, path, url string) string {
+	switch {
+	case result.Unverified > 0:
+		return fmt.Sprintf("Warning: the first %d bytes of %s at %s were already on the FTP server and were not verified, only the size of the image",
+			result.Unverified, path, url)
+	case !skipChecksum && result.Verified == "size":
+		return fmt.Sprintf("Warning: the FTP server at %s cannot hash files, so the SHA-256 of %s was not verified, only its size", url, path)
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ftps/ftpstest"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandwidthLimiter(t *testing.T) {
+	assert.Nil(t, newBandwidthLimiter(0))
+	r := bytes.NewReader(nil)
+	assert.Equal(t, io.Reader(r), newBandwidthLimiter(0).reader(context.Background(), r))
+
+	// 30 KB at 100 KB/s, shared by two readers, take about 0.3s
+	l := newBandwidthLimiter(100 * 1024)
+	start := time.Now()
+	done := make(chan int64)
+	for range 2 {
+		go func() {
+			n, _ := io.Copy(io.Discard, l.reader(context.Background(), bytes.NewReader(make([]byte, 15*1024))))
+			done <- n
+		}()
+	}
+	assert.Equal(t, int64(30*1024), <-done+<-done)
+	assert.GreaterOrEqual(t, time.Since(start), 250*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := io.Copy(io.Discard, newBandwidthLimiter(1024).reader(ctx, bytes.NewReader(make([]byte, 4096))))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func testUpload(s *ftpstest.Server, data []byte, transfer resources.TransferProperties) (resources.UploadResult, error) {
+	return resources.FtpUpload(context.Background(), resources.UploadProperties{
+		FTPServerProperties: resources.FTPServerProperties{
+			Url: s.Host, Port: s.Port, ServerCertificate: s.RootCAs, Username: "user", Password: "pass",
+		},
+		ImageFileProperties: resources.ImageFileProperties{
+			Path: "hdd-images/disk.qcow2", Data: bytes.NewReader(data), Size: int64(len(data)),
+		},
+		TransferProperties: transfer,
+	})
+}
+
+func TestFtpUpload(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789abcdef"), 8192)
+	sum := sha256.Sum256(data)
+
+	t.Run("verified by sha256", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		var sent int64
+		result, err := testUpload(s, data, resources.TransferProperties{
+			Wrap: func(offset int64, r io.Reader) io.Reader {
+				sent = offset
+				return io.TeeReader(r, writerFunc(func(p []byte) { sent += int64(len(p)) }))
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, resources.UploadResult{Sha256: hex.EncodeToString(sum[:]), Verified: "sha256"}, result)
+		assert.Equal(t, int64(len(data)), sent)
+		stored, _ := s.File("hdd-images/disk.qcow2")
+		assert.Equal(t, data, stored)
+	})
+
+	t.Run("verified by size without HASH", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		s.DisableHash()
+		result, err := testUpload(s, data, resources.TransferProperties{})
+		assert.NoError(t, err)
+		assert.Equal(t, "size", result.Verified)
+	})
+
+	t.Run("resume after interruption", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		s.InterruptNextUpload(10000)
+		_, err := testUpload(s, data, resources.TransferProperties{})
+		assert.ErrorContains(t, err, "--resume")
+
+		_, err = testUpload(s, data, resources.TransferProperties{})
+		assert.ErrorContains(t, err, "already exists")
+
+		result, err := testUpload(s, data, resources.TransferProperties{Resume: true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(10000), result.Offset)
+		assert.Equal(t, "sha256", result.Verified)
+		stored, _ := s.File("hdd-images/disk.qcow2")
+		assert.Equal(t, data, stored)
+
+		// Resuming a complete upload only verifies it
+		result, err = testUpload(s, data, resources.TransferProperties{Resume: true})
+		assert.NoError(t, err)
+		assert.Equal(t, resources.UploadResult{Offset: int64(len(data)), Sha256: hex.EncodeToString(sum[:]), Verified: "sha256"}, result)
+
+		_, err = testUpload(s, data, resources.TransferProperties{Resume: true, SkipChecksum: true})
+		assert.ErrorContains(t, err, "--resume-unverified")
+		result, err = testUpload(s, data, resources.TransferProperties{Resume: true, SkipChecksum: true, ResumeUnverified: true})
+		assert.NoError(t, err)
+		assert.Equal(t, resources.UploadResult{Offset: int64(len(data)), Verified: "size", Unverified: int64(len(data))}, result)
+	})
+
+	t.Run("resume without HASH", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		s.DisableHash()
+		s.InterruptNextUpload(10000)
+		_, err := testUpload(s, data, resources.TransferProperties{})
+		assert.ErrorContains(t, err, "--resume")
+
+		_, err = testUpload(s, data, resources.TransferProperties{Resume: true})
+		assert.ErrorContains(t, err, "the FTP server cannot hash files. Use --resume-unverified")
+
+		result, err := testUpload(s, data, resources.TransferProperties{Resume: true, ResumeUnverified: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "size", result.Verified)
+		assert.Equal(t, int64(10000), result.Unverified)
+		stored, _ := s.File("hdd-images/disk.qcow2")
+		assert.Equal(t, data, stored)
+	})
+
+	t.Run("resume onto another file", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		s.SetFile("hdd-images/disk.qcow2", []byte("not the image"))
+		_, err := testUpload(s, data, resources.TransferProperties{Resume: true})
+		assert.ErrorContains(t, err, "differ from the local image")
+		stored, _ := s.File("hdd-images/disk.qcow2")
+		assert.Equal(t, []byte("not the image"), stored)
+
+		// An unverified resume appends to the file anyway, and reports the bytes it could not verify
+		s.DisableHash()
+		result, err := testUpload(s, data, resources.TransferProperties{Resume: true, ResumeUnverified: true})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(len("not the image")), result.Unverified)
+	})
+
+	t.Run("remote larger than local", func(t *testing.T) {
+		s := ftpstest.NewServer(t, "hdd-images")
+		s.SetFile("hdd-images/disk.qcow2", append(data, 0))
+		_, err := testUpload(s, data, resources.TransferProperties{Resume: true})
+		assert.ErrorContains(t, err, "cannot resume")
+	})
+}
+
+func TestUploadWarning(t *testing.T) {
+	assert.Empty(t, uploadWarning(resources.UploadResult{Verified: "sha256"}, false, "hdd-images/disk.qcow2", "ftp-fra.ionos.com"))
+	assert.Empty(t, uploadWarning(resources.UploadResult{Verified: "size"}, true, "hdd-images/disk.qcow2", "ftp-fra.ionos.com"))
+	assert.Equal(t, "Warning: the FTP server at ftp-fra.ionos.com cannot hash files, so the SHA-256 of hdd-images/disk.qcow2 was not verified, only its size",
+		uploadWarning(resources.UploadResult{Verified: "size"}, false, "hdd-images/disk.qcow2", "ftp-fra.ionos.com"))
+	assert.Equal(t, "Warning: the first 10 bytes of hdd-images/disk.qcow2 at ftp-fra.ionos.com were already on the FTP server and were not verified, only the size of the image",
+		uploadWarning(resources.UploadResult{Verified: "size", Unverified: 10}, false, "hdd-images/disk.qcow2", "ftp-fra.ionos.com"))
+}
+
+type writerFunc func(p []byte)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	f(p)
+	return len(p), nil
+}
+
+func TestPreRunImageUploadBandwidthLimit(t *testing.T) {
+	for _, limit := range []string{"fast", "0", "-5MB"} {
+		t.Run(limit, func(t *testing.T) {
+			err := runPreRunImageUpload(t, func(cfg *core.PreCommandConfig) {
+				viper.Set(core.GetFlagName(cfg.NS, FlagImage), []string{"disk.qcow2"})
+				viper.Set(core.GetFlagName(cfg.NS, FlagBandwidthLimit), limit)
+			})
+			assert.ErrorContains(t, err, FlagBandwidthLimit)
+		})
+	}
+
+	err := runPreRunImageUpload(t, func(cfg *core.PreCommandConfig) {
+		viper.Set(core.GetFlagName(cfg.NS, FlagImage), []string{"disk.qcow2"})
+		viper.Set(core.GetFlagName(cfg.NS, FlagBandwidthLimit), "20MB")
+	})
+	assert.NoError(t, err)
+}
@@ -1,12 +1,15 @@
 package image
 
 import (
-	"bufio"
 	"context"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strings"
@@ -15,7 +18,9 @@ import (
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/convbytes"
 	"github.com/ionos-cloud/ionosctl/v6/pkg/functional"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
 	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
 	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
 	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
@@ -157,10 +162,11 @@ func Upload() *core.Command {
 This command requires that you are logged in using IONOS_USERNAME and IONOS_PASSWORD environment variables.
 
 High level steps:
-  1. Upload file(s) concurrently to the target FTP server(s).
-  2. If you do not use --skip-update, poll the Images API for the uploaded image(s) to appear.
-  3. When the API shows the uploaded image(s), perform PATCH /images/<UUID> to apply the requested image properties.
-  4. Print the resulting image objects to stdout in the chosen table or JSON format.
+  1. If you use --source or --convert-to, download the source image(s) and convert the image(s) into a temporary directory.
+  2. Upload file(s) concurrently to the target FTP server(s).
+  3. If you do not use --skip-update, poll the Images API for the uploaded image(s) to appear.
+  4. When the API shows the uploaded image(s), perform PATCH /images/<UUID> to apply the requested image properties.
+  5. Print the resulting image objects to stdout in the chosen table or JSON format.
 
 AUTH AND SAFETY
   - The FTP server relies on basic API credentials via environment variables IONOS_USERNAME and IONOS_PASSWORD. A bearer token (IONOS_TOKEN) cannot be used for the FTP upload, so if you authenticate with a token you must additionally set IONOS_USERNAME and IONOS_PASSWORD (they may be set alongside IONOS_TOKEN). You can debug your current setup with "ionosctl whoami --provenance".
@@ -177,6 +183,20 @@ FTP URLs
   - If you supply a custom --ftp-url that contains a placeholder, for example ftp://myftp.example/locations/%s, you must also supply one or more --location values. The command will replace %s with the location-specific fragment for each location. Example: --ftp-url ftp://myftp.example/locations/%s --location fra,fkb
   - If you supply a custom --ftp-url without a placeholder, you may provide multiple --ftp-url values to try multiple servers.
 
+RESUME, VERIFICATION AND BANDWIDTH
+  - Uploads to the same location run concurrently, and a progress bar per location shows the bytes uploaded to it, if stderr is a terminal. If an upload fails, the other uploads are cancelled.
+  - If an upload is interrupted, e.g. by a dropped connection, run the same command again with --resume. Uploads that already exist on the FTP server continue from the size of the file there, using FTP REST offsets, and complete uploads are only verified. The file already on the server is first compared with the start of the image by its SHA-256. If the FTP server cannot hash files, or with --skip-checksum, it cannot be compared, and resuming needs --resume-unverified as well.
+  - After each upload, the SHA-256 of the local image is compared with the SHA-256 computed by the FTP server, if it supports the HASH command. Otherwise, the sizes are compared, with a warning on stderr. Use --skip-checksum to only compare the sizes, without reading the image to hash it.
+  - Use --bandwidth-limit to limit the bandwidth shared by all uploads, in bytes per second, e.g. --bandwidth-limit 50MB.
+
+SOURCES AND CONVERSION
+  - Use --source instead of, or together with, --image to upload images that are not on your machine. They are uploaded after the --image files, with the file name of the source.
+      * https:// URLs are downloaded and their SHA-256 is verified. Pass the checksums with --source-checksum, one per https:// source in their order, otherwise they are read from <url>.sha256. Use --skip-checksum to download without verification.
+      * oci:// references are pulled from an OCI registry, e.g. the IONOS Container Registry. The image file is the layer with a file name annotation, as pushed by 'oras push', or else the first image file in the layers of a container image, e.g. a KubeVirt container disk. The digests of the layers are always verified. Use --platform to select the platform of a multi-platform artifact. The registry credentials are read from --source-token-name and --source-token-password, or the IONOS_CR_TOKEN_NAME and IONOS_CR_TOKEN_PASSWORD environment variables.
+  - Use --convert-to to convert all images to raw, qcow2, vmdk or vhd with qemu-img before the upload. Images already in that format are not converted. qemu-img must be installed, or its path set with --qemu-img.
+  - Sources and converted images are staged in the temporary directory, which must have space for them. Set TMPDIR to use another directory. It is removed when the command exits.
+  - The downloads and conversions count towards --timeout.
+
 POLLING AND TIMEOUTS
   - After upload, unless you use --skip-update, the command repeatedly queries GET /images with filters for the uploaded file names and locations.
   - Polling runs until either all expected images appear, or the command context deadline expires.
@@ -184,7 +204,7 @@ POLLING AND TIMEOUTS
 
 NOTES
   - Uploading multiple images with the same name to the same location is forbidden.
-  - The command does not delete or overwrite existing images on the FTP server. If an image with the same name already exists on the server, the upload will fail.
+  - The command does not delete or overwrite existing images on the FTP server. If an image with the same name already exists on the server, the upload will fail, unless you use --resume to continue an interrupted upload of it.
   - The command does not check if the uploaded image is valid or bootable. It only checks the file extension.
   - You can use 'ionosctl compute image list --filter public=false' to see your uploaded images.
   - You must contact support to delete images you uploaded via FTP. Deleting them via API will only set their size to 0B.
@@ -198,6 +218,14 @@ EXAMPLES
     ionosctl img upload -i image.iso -l de/fra
     Uploads to ftp://ftp-fra.ionos.com/iso-images, polls GET /images until the image appears, then PATCHes that image with the properties you supplied via flags.
 
+  - Resume interrupted uploads, with at most 20 MB per second:
+    ionosctl img upload -i image.qcow2 -l de/fra,de/txl --resume --bandwidth-limit 20MB
+    Continues the uploads from the bytes already on the FTP servers, if they match the start of image.qcow2, and verifies the SHA-256 of the uploaded images.
+
+  - Upload a cloud image converted to raw, and a disk from a container registry:
+    ionosctl img upload --source https://cloud.example/debian-12.qcow2,oci://my-registry.cr.de-fra.ionos.com/images/ubuntu:24.04 -l de/fra --convert-to raw
+    Downloads debian-12.qcow2 and verifies it against https://cloud.example/debian-12.qcow2.sha256, pulls the image file of ubuntu:24.04, converts both to raw with qemu-img, then uploads them to ftp://ftp-fra.ionos.com/hdd-images.
+
   - Use a custom FTP server:
     ionosctl img upload -i image.iso --ftp-url "ftp://myftp.example" --crt-path certificates/my-server-crt.pem --skip-update`,
 		PreCmdRun: core.PreRunWithDeprecatedFlags(PreRunImageUpload,
@@ -214,7 +242,14 @@ EXAMPLES
 			validLocationsStr, FlagFtpUrl), core.RequiredFlagOption())
 
 	upload.AddStringSliceFlag(FlagRenameImages, "", nil, "Rename the uploaded images before trying to upload. These names should not contain any extension. By default, this is the base of the image path")
-	upload.AddStringSliceFlag(FlagImage, "i", nil, "Slice of paths to images, can be absolute path or relative to current working directory", core.RequiredFlagOption())
+	upload.AddStringSliceFlag(FlagImage, "i", nil, fmt.Sprintf("Slice of paths to images, can be absolute path or relative to current working directory. Required, unless using --%s", FlagSource))
+	upload.AddStringSliceFlag(FlagSource, "", nil, "Slice of images to download and upload, as https:// URLs or oci:// references of OCI artifacts, e.g. oci://registry.example/images/debian:12")
+	upload.AddStringSliceFlag(FlagSourceChecksum, "", nil, "Slice of SHA-256 checksums of the https:// sources, in their order, e.g. sha256:<hex>. By default, the checksum is read from <url>.sha256")
+	upload.AddSetFlag(FlagConvertTo, "", "", []string{"raw", "qcow2", "vmdk", "vhd"}, "Convert the images to this format with qemu-img before the upload")
+	upload.AddStringFlag(FlagQemuImg, "", "qemu-img", "Path to the qemu-img executable used by --"+FlagConvertTo)
+	upload.AddStringFlag(FlagPlatform, "", "", "Platform of the oci:// sources with multiple platforms, e.g. linux/amd64")
+	upload.AddStringFlag(FlagSourceTokenName, "", "", "Name of the registry token for the oci:// sources. Defaults to "+ociregistry.EnvTokenName)
+	upload.AddStringFlag(FlagSourceTokenPass, "", "", "Password of the registry token for the oci:// sources. Defaults to "+ociregistry.EnvTokenPassword)
 	upload.AddStringFlag(FlagFtpUrl, "", "ftp-%s.ionos.com", "URL of FTP server, with %s flag if location is embedded into url")
 	upload.AddBoolFlag(FlagSkipVerify, "", false, "Skip verification of server certificate, useful if using a custom ftp-url. WARNING: You can be the target of a man-in-the-middle attack!")
 	upload.AddBoolFlag(FlagSkipUpdate, "", false, "Skip setting image properties after it has been uploaded. Normal behavior is to send a PATCH to the API, after the image has been uploaded, with the contents of the image properties flags and emulate a \"create\" command.")
@@ -223,6 +258,13 @@ EXAMPLES
 	upload.AddStringSliceFlag(cloudapiv6.ArgImageAlias, cloudapiv6.ArgImageAliasShort, nil, "")
 	upload.Command.Flags().MarkHidden(cloudapiv6.ArgImageAlias)
 
+	upload.AddBoolFlag(FlagResume, "", false, "Continue interrupted uploads of images that already exist on the FTP server from their size there, instead of failing. "+
+		"The part already on the server must have the SHA-256 of the start of the image")
+	upload.AddBoolFlag(FlagResumeUnverified, "", false, "With --"+FlagResume+", also continue uploads whose part already on the FTP server cannot be compared with the image, "+
+		"because the server cannot hash files or with --"+FlagSkipChecksum+". Only the size of these images is verified")
+	upload.AddBoolFlag(FlagSkipChecksum, "", false, "Skip comparing the SHA-256 of the images with the FTP server after the upload, only compare their sizes. Also skips verifying https:// sources without --"+FlagSourceChecksum)
+	upload.AddStringFlag(FlagBandwidthLimit, "", "", "Limit the bandwidth shared by all uploads, in bytes per second. Supports units, e.g. 500KB or 20MB. By default, the bandwidth is not limited")
+
 	upload.AddIntFlag(FlagFtpPort, "", 21, "FTP server port. Only valid together with --ftp-url, for custom FTP servers on non-standard ports")
 
 	upload.AddBoolFlag(constants.FlagConfidential, "", false, "Upload to the confidential-images/ directory for Confidential Computing (CoCo) images. Requires a QCOW2 image with an embedded LAUNCH_ARTIFACTS partition. Forces cloud-init NONE and disables hot-plug / legacy BIOS on the image.")
@@ -334,6 +376,42 @@ func RunImageUpload(c *core.CommandConfig) error {
 	defer cancel()
 	c.Context = ctx
 
+	if sources := viper.GetStringSlice(core.GetFlagName(c.NS, FlagSource)); len(sources) > 0 || viper.GetString(core.GetFlagName(c.NS, FlagConvertTo)) != "" {
+		dir, err := os.MkdirTemp("", "ionosctl-image-upload-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+
+		convertTo := viper.GetString(core.GetFlagName(c.NS, FlagConvertTo))
+		s := &imageSources{
+			dir:          dir,
+			client:       &http.Client{},
+			checksums:    viper.GetStringSlice(core.GetFlagName(c.NS, FlagSourceChecksum)),
+			skipChecksum: viper.GetBool(core.GetFlagName(c.NS, FlagSkipChecksum)),
+			convertTo:    convertTo,
+			qemuImg:      viper.GetString(core.GetFlagName(c.NS, FlagQemuImg)),
+			artifact: ociregistry.ArtifactOptions{
+				TokenName:     viper.GetString(core.GetFlagName(c.NS, FlagSourceTokenName)),
+				TokenPassword: viper.GetString(core.GetFlagName(c.NS, FlagSourceTokenPass)),
+				Platform:      viper.GetString(core.GetFlagName(c.NS, FlagPlatform)),
+				Log:           c.Verbose,
+			},
+			match: func(name string) bool {
+				if confidential && convertTo == "" {
+					return filepath.Ext(name) == ".qcow2" || filepath.Ext(name) == ".qcow"
+				}
+				return isValidImageExt(name)
+			},
+			stderr:  c.Command.Command.ErrOrStderr(),
+			verbose: c.Verbose,
+		}
+		images, err = s.stage(ctx, images, sources)
+		if err != nil {
+			return err
+		}
+	}
+
 	// just a simple patch to force entry into the `for` loop below if no locations are provided
 	if !strings.Contains(url, "%s") && len(locations) == 0 {
 		sentinel := []string{""}
@@ -345,20 +423,40 @@ func RunImageUpload(c *core.CommandConfig) error {
 	// more images from the API than can ever exist.
 	locations = deduplicateLocations(locations)
 
-	var eg errgroup.Group
+	limit, _ := convbytes.StrToBytesOk(viper.GetString(core.GetFlagName(c.NS, FlagBandwidthLimit)))
+	limiter := newBandwidthLimiter(limit)
+	transfer := resources.TransferProperties{
+		Resume:           viper.GetBool(core.GetFlagName(c.NS, FlagResume)),
+		SkipChecksum:     viper.GetBool(core.GetFlagName(c.NS, FlagSkipChecksum)),
+		ResumeUnverified: viper.GetBool(core.GetFlagName(c.NS, FlagResumeUnverified)),
+	}
+
+	type upload struct {
+		loc, url, serverFilePath string
+		file                     *os.File
+		size                     int64
+	}
+	var uploads []upload
+	defer func() {
+		for _, u := range uploads {
+			u.file.Close()
+		}
+	}()
 
 	c.Verbose("Uploading %+v to %+v", images, locations)
 
+	labels := map[string]string{}
+	totals := map[string]int64{}
 	originalURL := url
 	for _, loc := range locations {
+		url := url
+		if strings.Contains(originalURL, "%s") {
+			ftpSub := lookupFTP(loc)
+			url = fmt.Sprintf(originalURL, ftpSub) // Add the location modifier for FTP URL
+		}
+		labels[loc] = url
+
 		for imgIdx, img := range images {
-			// shadow outer url with a per-iteration copy so concurrent goroutines
-			// don't race on the shared variable
-			url := url
-			if strings.Contains(originalURL, "%s") {
-				ftpSub := lookupFTP(loc)
-				url = fmt.Sprintf(originalURL, ftpSub) // Add the location modifier for FTP URL
-			}
 			c.Verbose("Uploading %s to %s", img, url)
 
 			var serverDir string
@@ -382,31 +480,63 @@ func RunImageUpload(c *core.CommandConfig) error {
 			if err != nil {
 				return err
 			}
+			info, err := file.Stat()
+			if err != nil {
+				file.Close()
+				return err
+			}
+			uploads = append(uploads, upload{loc: loc, url: url, serverFilePath: serverFilePath, file: file, size: info.Size()})
+			totals[loc] += info.Size()
+		}
+	}
 
-			data := bufio.NewReader(file)
-			eg.Go(func() error {
-				defer file.Close()
-				return resources.FtpUpload(
-					c.Context,
-					resources.UploadProperties{
-						FTPServerProperties: resources.FTPServerProperties{
-							Url:               url,
-							Port:              viper.GetInt(core.GetFlagName(c.NS, FlagFtpPort)),
-							SkipVerify:        skipVerify,
-							ServerCertificate: certPool,
-							Username:          ftpUser,
-							Password:          ftpPass,
-						},
-						ImageFileProperties: resources.ImageFileProperties{
-							Path:       serverFilePath,
-							DataBuffer: data,
-						},
-					},
-				)
-			})
+	progress := newUploadProgress(c.Command.Command.ErrOrStderr(), locations, labels, totals)
+	// If an upload fails, the other uploads are cancelled instead of running to completion
+	eg, ctx := errgroup.WithContext(c.Context)
+	for _, u := range uploads {
+		transfer := transfer
+		transfer.Wrap = func(offset int64, r io.Reader) io.Reader {
+			return progress.wrap(u.loc, offset, limiter.reader(ctx, r))
 		}
+		eg.Go(func() error {
+			result, err := resources.FtpUpload(
+				ctx,
+				resources.UploadProperties{
+					FTPServerProperties: resources.FTPServerProperties{
+						Url:               u.url,
+						Port:              viper.GetInt(core.GetFlagName(c.NS, FlagFtpPort)),
+						SkipVerify:        skipVerify,
+						ServerCertificate: certPool,
+						Username:          ftpUser,
+						Password:          ftpPass,
+					},
+					ImageFileProperties: resources.ImageFileProperties{
+						Path: u.serverFilePath,
+						Data: u.file,
+						Size: u.size,
+					},
+					TransferProperties: transfer,
+				},
+			)
+			if err != nil {
+				return err
+			}
+			if result.Offset > 0 {
+				c.Verbose("Resumed upload of %s to %s at byte %d", u.serverFilePath, u.url, result.Offset)
+			}
+			if warning := uploadWarning(result, transfer.SkipChecksum, u.serverFilePath, u.url); warning != "" {
+				fmt.Fprintln(c.Command.Command.ErrOrStderr(), warning)
+			}
+			if result.Sha256 != "" {
+				c.Verbose("SHA-256 of %s: %s", u.serverFilePath, result.Sha256)
+			}
+			c.Verbose("Uploaded %s to %s, verified by %s", u.serverFilePath, u.url, result.Verified)
+			return nil
+		})
 	}
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	progress.stop()
+	if err != nil {
 		return err
 	}
 
@@ -417,7 +547,7 @@ func RunImageUpload(c *core.CommandConfig) error {
 	}
 
 	// Below, we query that the images have been uploaded, and then PATCH them with the given properties
-	names := images
+	names := functional.Map(images, filepath.Base)
 	if len(aliases) != 0 {
 		// Returns a slice containing `alias[i] + filepath.Ext(images[i])`
 		names = functional.MapIdx(aliases, func(k int, v string) string {
@@ -501,33 +631,68 @@ func getDiffUploadedImages(c *core.CommandConfig, names, locations []string) ([]
 }
 
 func PreRunImageUpload(c *core.PreCommandConfig) error {
-	err := c.Command.Command.MarkFlagRequired(FlagImage)
+	err := core.CheckRequiredFlagsSets(c.Command, c.NS, []string{FlagImage}, []string{FlagSource})
 	if err != nil {
 		return err
 	}
 
-	validExts := []string{".iso", ".img", ".vmdk", ".vhd", ".vhdx", ".cow", ".qcow", ".qcow2", ".raw", ".vpc", ".vdi"}
 	images := viper.GetStringSlice(core.GetFlagName(c.NS, FlagImage))
-	invalidImages := functional.Filter(
-		functional.Map(images, func(s string) string {
-			return filepath.Ext(s)
-		}),
-		func(ext string) bool {
-			return !slices.Contains(
-				validExts,
-				ext,
-			)
-		},
-	)
-	if len(invalidImages) > 0 {
-		return fmt.Errorf("%s is an invalid image extension. Valid extensions are: %s", strings.Join(invalidImages, ","), validExts)
+	sources := viper.GetStringSlice(core.GetFlagName(c.NS, FlagSource))
+	convertTo := viper.GetString(core.GetFlagName(c.NS, FlagConvertTo))
+	if _, ok := convertFormats[convertTo]; convertTo != "" && !ok {
+		return fmt.Errorf("--%s %q is not supported, use one of raw, qcow2, vmdk, vhd", FlagConvertTo, convertTo)
+	}
+
+	// The extension of downloaded images is the one of the URL path. The file name of OCI artifacts is only known after the pull.
+	files := slices.Clone(images)
+	var https int
+	for _, src := range sources {
+		u, err := url.Parse(src)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "oci") || u.Host == "" {
+			return fmt.Errorf("--%s %q must be an https:// URL or an oci:// reference", FlagSource, src)
+		}
+		if u.Scheme == "https" {
+			files = append(files, path.Base(u.Path))
+			https++
+		}
+	}
+	if checksums := viper.GetStringSlice(core.GetFlagName(c.NS, FlagSourceChecksum)); len(checksums) > 0 {
+		if len(checksums) != https {
+			return fmt.Errorf("--%s needs one checksum per https:// source, got %d checksums for %d sources", FlagSourceChecksum, len(checksums), https)
+		}
+		for _, checksum := range checksums {
+			if _, err := parseChecksum(checksum); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Converted images get the extension of the format, and qemu-img probes the format of other extensions.
+	if convertTo == "" {
+		invalidImages := functional.Filter(
+			functional.Map(files, func(s string) string {
+				return filepath.Ext(s)
+			}),
+			func(ext string) bool {
+				return !slices.Contains(
+					validImageExts,
+					ext,
+				)
+			},
+		)
+		if len(invalidImages) > 0 {
+			return fmt.Errorf("%s is an invalid image extension. Valid extensions are: %s", strings.Join(invalidImages, ","), validImageExts)
+		}
 	}
 
 	// Confidential Computing images must be QCOW2 and carry a fixed, restricted property set.
 	// Reject conflicting explicit flags up front rather than silently overriding a user's choice.
 	if viper.GetBool(core.GetFlagName(c.NS, constants.FlagConfidential)) {
-		for _, img := range images {
-			if ext := filepath.Ext(img); ext != ".qcow2" && ext != ".qcow" {
+		if convertTo != "" && convertTo != "qcow2" {
+			return fmt.Errorf("--%s requires QCOW2 images; do not pass --%s %s", constants.FlagConfidential, FlagConvertTo, convertTo)
+		}
+		for _, img := range files {
+			if ext := filepath.Ext(img); convertTo == "" && ext != ".qcow2" && ext != ".qcow" {
 				return fmt.Errorf("--%s requires QCOW2 images; %s has extension %q", constants.FlagConfidential, img, ext)
 			}
 		}
@@ -552,6 +717,12 @@ func PreRunImageUpload(c *core.PreCommandConfig) error {
 		}
 	}
 
+	if limit := viper.GetString(core.GetFlagName(c.NS, FlagBandwidthLimit)); limit != "" {
+		if bytes, ok := convbytes.StrToBytesOk(limit); !ok || bytes <= 0 {
+			return fmt.Errorf("--%s must be a positive number of bytes per second, optionally with a unit, e.g. 20MB; got %q", FlagBandwidthLimit, limit)
+		}
+	}
+
 	// --ftp-port only makes sense with a custom --ftp-url
 	if viper.IsSet(core.GetFlagName(c.NS, FlagFtpPort)) {
 		if !viper.IsSet(core.GetFlagName(c.NS, FlagFtpUrl)) {
@@ -584,7 +755,7 @@ func PreRunImageUpload(c *core.PreCommandConfig) error {
 	}
 
 	aliases := viper.GetStringSlice(core.GetFlagName(c.NS, cloudapiv6.ArgImageAlias))
-	if len(aliases) != 0 && len(aliases) != len(images) {
+	if len(aliases) != 0 && len(aliases) != len(images)+len(sources) {
 		return fmt.Errorf("slices of image files and image aliases are of different lengths. Uploading multiple images with the same alias is forbidden")
 	}
 
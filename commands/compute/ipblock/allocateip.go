@@ -0,0 +1,239 @@
+package ipblock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFor    = "for"
+	FlagDryRun = "dry-run"
+)
+
+var allAllocationCols = []table.Column{
+	{Name: "Ip", JSONPath: "ip", Default: true},
+	{Name: "IpBlockId", JSONPath: "ipBlockId", Default: true},
+	{Name: "Location", JSONPath: "location", Default: true},
+	{Name: "NewIpBlock", JSONPath: "newIpBlock", Default: true},
+	{Name: "NicId", JSONPath: "nicId", Default: true},
+}
+
+type allocation struct {
+	Ip         string `json:"ip"`
+	IpBlockId  string `json:"ipBlockId"`
+	Location   string `json:"location"`
+	NewIpBlock bool   `json:"newIpBlock"`
+	NicId      string `json:"nicId"`
+}
+
+func IpBlockAllocateIpCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "ipblock",
+		Resource:  "ipblock",
+		Verb:      "allocate-ip",
+		Aliases:   []string{"allocate"},
+		ShortDesc: "Assign a free IP of an IpBlock to a NIC, reserving a new IpBlock if needed",
+		LongDesc: `Use this command to assign a free IP to a NIC. A free IP of an existing IpBlock of the location of the Data Center is used, from the IpBlock with the fewest free IPs. If no IpBlock has a free IP, a new IpBlock with one IP is reserved. The IP is added to the IPs of the NIC, and the command waits for it. If the IP cannot be assigned, a new IpBlock is released again.
+
+Use ` + "`--dry-run`" + ` to only print which IP would be used, without reserving or assigning it.
+
+Required values to run command:
+
+* For, in the form nic:NIC_ID
+* Data Center Id
+* Server Id`,
+		Example:    "ionosctl compute ipblock allocate-ip --for nic:NIC_ID --datacenter-id DATACENTER_ID --server-id SERVER_ID",
+		PreCmdRun:  PreRunIpBlockAllocateIp,
+		CmdRun:     RunIpBlockAllocateIp,
+		InitClient: true,
+	})
+	cmd.AddStringFlag(FlagFor, "", "", "The resource to assign the IP to, in the form nic:NIC_ID", core.RequiredFlagOption())
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgServerId, "", "", cloudapiv6.ServerId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgServerId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ServersIds(viper.GetString(core.GetFlagName(cmd.NS, cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringFlag(cloudapiv6.ArgLocation, cloudapiv6.ArgLocationShort, "", "Location of the IpBlock. It has to be the location of the Data Center, which is used if not set")
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgLocation, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.LocationIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringFlag(cloudapiv6.ArgName, cloudapiv6.ArgNameShort, "", "Name of the IpBlock, if a new one is reserved")
+	cmd.AddBoolFlag(FlagDryRun, "", false, "Only print which IP would be used, without reserving or assigning it")
+	cmd.AddColsFlag(allAllocationCols)
+
+	return cmd
+}
+
+func PreRunIpBlockAllocateIp(c *core.PreCommandConfig) error {
+	if err := core.CheckRequiredFlags(c.Command, c.NS, FlagFor, cloudapiv6.ArgDataCenterId, cloudapiv6.ArgServerId); err != nil {
+		return err
+	}
+	_, err := parseFor(viper.GetString(core.GetFlagName(c.NS, FlagFor)))
+	return err
+}
+
+// parseFor returns the NIC ID of a --for value in the form nic:NIC_ID.
+func parseFor(value string) (string, error) {
+	kind, id, ok := strings.Cut(value, ":")
+	if !ok || id == "" {
+		return "", fmt.Errorf("invalid --%s %q, must be in the form nic:NIC_ID", FlagFor, value)
+	}
+	if kind != "nic" {
+		return "", fmt.Errorf("invalid --%s %q, IPs can only be allocated for a nic", FlagFor, value)
+	}
+	return id, nil
+}
+
+func RunIpBlockAllocateIp(c *core.CommandConfig) error {
+	nicId, err := parseFor(viper.GetString(core.GetFlagName(c.NS, FlagFor)))
+	if err != nil {
+		return err
+	}
+	dcId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+	serverId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgServerId))
+
+	dc, resp, err := c.CloudApiV6Services.DataCenters().Get(dcId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed getting Data Center %s: %w", dcId, err)
+	}
+	location := ""
+	if dc.Properties != nil {
+		location = str(dc.Properties.Location)
+	}
+	if l := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgLocation)); l != "" && l != location {
+		return fmt.Errorf("the IpBlock has to be in location %s of the Data Center, not %s", location, l)
+	}
+
+	nic, resp, err := c.CloudApiV6Services.Nics().Get(dcId, serverId, nicId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed getting NIC %s: %w", nicId, err)
+	}
+
+	blocks, err := listIpBlocks(c)
+	if err != nil {
+		return err
+	}
+	a := allocation{Location: location, NicId: nicId}
+	var ok bool
+	a.Ip, a.IpBlockId, ok = pickFreeIp(blocks, location)
+	a.NewIpBlock = !ok
+
+	dryRun := viper.GetBool(core.GetFlagName(c.NS, FlagDryRun))
+	if dryRun {
+		return c.Printer(allAllocationCols).Print(a)
+	}
+	if a.NewIpBlock {
+		if a.Ip, a.IpBlockId, err = reserveIp(c, location); err != nil {
+			return withReleased(c, a.IpBlockId, err)
+		}
+	}
+
+	var ips []string
+	if nic.Properties != nil && nic.Properties.Ips != nil {
+		ips = *nic.Properties.Ips
+	}
+	if slices.Contains(ips, a.Ip) {
+		return c.Printer(allAllocationCols).Print(a)
+	}
+	ips = append(ips, a.Ip)
+
+	c.Verbose("Assigning IP %s of IpBlock %s to NIC %s", a.Ip, a.IpBlockId, nicId)
+	_, resp, err = c.CloudApiV6Services.Nics().Update(dcId, serverId, nicId, resources.NicProperties{
+		NicProperties: ionoscloud.NicProperties{Ips: &ips},
+	})
+	if resp != nil && request.GetId(resp) != "" {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if err == nil {
+		if path := request.GetRequestPath(resp); path != "" {
+			_, err = c.CloudApiV6Services.Requests().Wait(path)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("failed assigning IP %s to NIC %s: %w", a.Ip, nicId, err)
+		if a.NewIpBlock {
+			return withReleased(c, a.IpBlockId, err)
+		}
+		return err
+	}
+
+	return c.Printer(allAllocationCols).Print(a)
+}
+
+// reserveIp reserves a new IpBlock with one IP, and waits for it to get its IP. It returns the ID of the IpBlock
+// once it is reserved, also with an error.
+func reserveIp(c *core.CommandConfig, location string) (string, string, error) {
+	name := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgName))
+	c.Verbose("No IpBlock in %s has a free IP, reserving a new IpBlock", location)
+
+	block, resp, err := c.CloudApiV6Services.IpBlocks().Create(name, location, 1)
+	if resp != nil && request.GetId(resp) != "" {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed reserving an IpBlock: %w", err)
+	}
+	id := str(block.Id)
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err = c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return "", id, fmt.Errorf("failed waiting for IpBlock %s: %w", id, err)
+		}
+	}
+
+	block, resp, err = c.CloudApiV6Services.IpBlocks().Get(id)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return "", id, fmt.Errorf("failed getting IpBlock %s: %w", id, err)
+	}
+	if block.Properties == nil || block.Properties.Ips == nil || len(*block.Properties.Ips) == 0 {
+		return "", id, fmt.Errorf("IpBlock %s has no IP", id)
+	}
+	return (*block.Properties.Ips)[0], id, nil
+}
+
+// withReleased releases the IpBlock reserved for an allocation that failed with err, so it is not billed without
+// being used. It returns err, joined with whether the IpBlock was released.
+func withReleased(c *core.CommandConfig, ipBlockId string, err error) error {
+	if ipBlockId == "" {
+		return err
+	}
+	c.Verbose("Releasing the new IpBlock %s", ipBlockId)
+	resp, rerr := c.CloudApiV6Services.IpBlocks().Delete(ipBlockId)
+	if resp != nil && request.GetId(resp) != "" {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if rerr == nil {
+		if path := request.GetRequestPath(resp); path != "" {
+			_, rerr = c.CloudApiV6Services.Requests().Wait(path)
+		}
+	}
+	if rerr != nil {
+		return errors.Join(err, fmt.Errorf("failed releasing the new IpBlock %s, delete it manually: %w", ipBlockId, rerr))
+	}
+	return errors.Join(err, fmt.Errorf("released the new IpBlock %s", ipBlockId))
+}
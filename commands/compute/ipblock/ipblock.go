@@ -22,7 +22,7 @@ func IpblockCmd() *core.Command {
 			Use:              "ipblock",
 			Aliases:          []string{"ip", "ipb"},
 			Short:            "IpBlock Operations",
-			Long:             "The sub-commands of `ionosctl compute ipblock` allow you to create/reserve, list, get, update, delete IpBlocks.",
+			Long:             "The sub-commands of `ionosctl compute ipblock` allow you to create/reserve, list, get, update, delete IpBlocks, see their usage and allocate their IPs.",
 			TraverseChildren: true,
 		},
 	}
@@ -33,6 +33,8 @@ func IpblockCmd() *core.Command {
 	ipblockCmd.AddCommand(IpBlockCreateCmd())
 	ipblockCmd.AddCommand(IpBlockUpdateCmd())
 	ipblockCmd.AddCommand(IpBlockDeleteCmd())
+	ipblockCmd.AddCommand(IpBlockUsageCmd())
+	ipblockCmd.AddCommand(IpBlockAllocateIpCmd())
 
 	return core.WithConfigOverride(ipblockCmd, []string{fileconfiguration.Cloud, "compute"}, "")
 }
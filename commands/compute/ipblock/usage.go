@@ -0,0 +1,167 @@
+package ipblock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var allUsageCols = []table.Column{
+	{Name: "IpBlockId", JSONPath: "ipBlockId", Default: true},
+	{Name: "Name", JSONPath: "name", Default: true},
+	{Name: "Location", JSONPath: "location", Default: true},
+	{Name: "Size", JSONPath: "size", Default: true},
+	{Name: "Used", JSONPath: "used", Default: true},
+	{Name: "Utilization", JSONPath: "utilization", Default: true},
+	{Name: "FreeIps", JSONPath: "freeIps", Default: true},
+	{Name: "Unused", JSONPath: "unused", Default: true},
+}
+
+// usage is the utilization of an IpBlock.
+type usage struct {
+	IpBlockId   string   `json:"ipBlockId"`
+	Name        string   `json:"name"`
+	Location    string   `json:"location"`
+	Size        int      `json:"size"`
+	Used        int      `json:"used"`
+	Utilization string   `json:"utilization"`
+	FreeIps     []string `json:"freeIps"`
+	// Unused is set if no IP of the IpBlock is in use, so the IpBlock is billed without being used.
+	Unused bool `json:"unused"`
+}
+
+func IpBlockUsageCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "ipblock",
+		Resource:  "ipblock",
+		Verb:      "usage",
+		Aliases:   []string{"u"},
+		ShortDesc: "Show the utilization and the free IPs of IpBlocks",
+		LongDesc: `Use this command to see how many IPs of each IpBlock are used by IP consumers, such as NICs and NAT Gateways, and which IPs are free. IpBlocks without any IP in use are marked as unused, as they are billed without being used.
+
+Use ` + "`--location`" + ` to only show the IpBlocks of a location.`,
+		Example:    "ionosctl compute ipblock usage --location de/fra",
+		PreCmdRun:  core.NoPreRun,
+		CmdRun:     RunIpBlockUsage,
+		InitClient: true,
+	})
+	cmd.AddStringFlag(cloudapiv6.ArgLocation, cloudapiv6.ArgLocationShort, "", "Only show the IpBlocks of this location")
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgLocation, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.LocationIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddColsFlag(allUsageCols)
+
+	return cmd
+}
+
+func RunIpBlockUsage(c *core.CommandConfig) error {
+	blocks, err := listIpBlocks(c)
+	if err != nil {
+		return err
+	}
+	usages := blockUsages(blocks, viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgLocation)))
+
+	var unused []string
+	for _, u := range usages {
+		if u.Unused {
+			unused = append(unused, u.IpBlockId)
+		}
+	}
+	if len(unused) > 0 {
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(), "Warning: %d IpBlocks have no IP in use and are billed without being used: %s\n",
+			len(unused), strings.Join(unused, ", "))
+	}
+
+	return c.Printer(allUsageCols).Print(usages)
+}
+
+func listIpBlocks(c *core.CommandConfig) ([]ionoscloud.IpBlock, error) {
+	ipblocks, resp, err := c.CloudApiV6Services.IpBlocks().List()
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed listing IpBlocks: %w", err)
+	}
+	if ipblocks.Items == nil {
+		return nil, nil
+	}
+	return *ipblocks.Items, nil
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// blockUsages returns the utilization of the IpBlocks of the location, or of all locations if it is empty,
+// sorted by location and name.
+func blockUsages(blocks []ionoscloud.IpBlock, location string) []usage {
+	usages := []usage{}
+	for _, b := range blocks {
+		p := b.Properties
+		if p == nil || (location != "" && str(p.Location) != location) {
+			continue
+		}
+		var ips []string
+		if p.Ips != nil {
+			ips = *p.Ips
+		}
+		used := map[string]bool{}
+		if p.IpConsumers != nil {
+			for _, consumer := range *p.IpConsumers {
+				used[str(consumer.Ip)] = true
+			}
+		}
+
+		u := usage{IpBlockId: str(b.Id), Name: str(p.Name), Location: str(p.Location), Size: len(ips), FreeIps: []string{}}
+		for _, ip := range ips {
+			if used[ip] {
+				u.Used++
+			} else {
+				u.FreeIps = append(u.FreeIps, ip)
+			}
+		}
+		if u.Size > 0 {
+			u.Utilization = fmt.Sprintf("%d%%", u.Used*100/u.Size)
+		}
+		u.Unused = u.Size > 0 && u.Used == 0
+		usages = append(usages, u)
+	}
+
+	slices.SortStableFunc(usages, func(a, b usage) int {
+		if a.Location != b.Location {
+			return strings.Compare(a.Location, b.Location)
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return usages
+}
+
+// pickFreeIp returns a free IP of the IpBlocks of the location. The IpBlock with the fewest free IPs is used, so
+// IpBlocks with many free IPs can be released.
+func pickFreeIp(blocks []ionoscloud.IpBlock, location string) (ip, ipBlockId string, ok bool) {
+	var best *usage
+	for _, u := range blockUsages(blocks, location) {
+		if len(u.FreeIps) == 0 || (best != nil && len(u.FreeIps) >= len(best.FreeIps)) {
+			continue
+		}
+		best = &u
+	}
+	if best == nil {
+		return "", "", false
+	}
+	return best.FreeIps[0], best.IpBlockId, true
+}
@@ -0,0 +1,189 @@
+package ipblock
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func usageTestBlock(id, name, location string, ips []string, used ...string) ionoscloud.IpBlock {
+	consumers := []ionoscloud.IpConsumer{}
+	for _, ip := range used {
+		consumers = append(consumers, ionoscloud.IpConsumer{Ip: pointer.From(ip)})
+	}
+	return ionoscloud.IpBlock{Id: pointer.From(id), Properties: &ionoscloud.IpBlockProperties{
+		Name: pointer.From(name), Location: pointer.From(location), Ips: &ips, IpConsumers: &consumers,
+	}}
+}
+
+var usageTestBlocks = []ionoscloud.IpBlock{
+	usageTestBlock("b-1", "web", "de/fra", []string{"203.0.113.1", "203.0.113.2", "203.0.113.3", "203.0.113.4"}, "203.0.113.1"),
+	usageTestBlock("b-2", "idle", "de/fra", []string{"203.0.113.10"}),
+	usageTestBlock("b-3", "api", "de/fra", []string{"203.0.113.20", "203.0.113.21"}, "203.0.113.20"),
+	usageTestBlock("b-4", "full", "us/las", []string{"198.51.100.1"}, "198.51.100.1"),
+}
+
+func TestBlockUsages(t *testing.T) {
+	usages := blockUsages(usageTestBlocks, "")
+	var ids []string
+	for _, u := range usages {
+		ids = append(ids, u.IpBlockId)
+	}
+	assert.Equal(t, []string{"b-3", "b-2", "b-1", "b-4"}, ids)
+
+	assert.Equal(t, usage{IpBlockId: "b-1", Name: "web", Location: "de/fra", Size: 4, Used: 1, Utilization: "25%",
+		FreeIps: []string{"203.0.113.2", "203.0.113.3", "203.0.113.4"}}, usages[2])
+	assert.True(t, usages[1].Unused)
+	assert.Equal(t, "0%", usages[1].Utilization)
+	assert.False(t, usages[3].Unused)
+	assert.Equal(t, "100%", usages[3].Utilization)
+	assert.Empty(t, usages[3].FreeIps)
+
+	assert.Len(t, blockUsages(usageTestBlocks, "us/las"), 1)
+	assert.Empty(t, blockUsages(usageTestBlocks, "es/vit"))
+}
+
+func TestPickFreeIp(t *testing.T) {
+	ip, id, ok := pickFreeIp(usageTestBlocks, "de/fra")
+	assert.True(t, ok)
+	assert.Equal(t, "203.0.113.21", ip)
+	assert.Equal(t, "b-3", id)
+
+	_, _, ok = pickFreeIp(usageTestBlocks, "us/las")
+	assert.False(t, ok)
+}
+
+func TestParseFor(t *testing.T) {
+	id, err := parseFor("nic:nic-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "nic-1", id)
+
+	for _, v := range []string{"nic-1", "nic:", "server:srv-1"} {
+		_, err = parseFor(v)
+		assert.Error(t, err, v)
+	}
+}
+
+func TestRunIpBlockUsage(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgLocation), "de/fra")
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().List().Return(resources.IpBlocks{IpBlocks: ionoscloud.IpBlocks{Items: &usageTestBlocks}}, &testutil.TestResponse, nil)
+		err := RunIpBlockUsage(cfg)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunIpBlockAllocateIp(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, FlagFor), "nic:nic-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgServerId), "srv-1")
+		dc := resources.Datacenter{Datacenter: ionoscloud.Datacenter{Properties: &ionoscloud.DatacenterProperties{Location: pointer.From("de/fra")}}}
+		nic := resources.Nic{Nic: ionoscloud.Nic{Properties: &ionoscloud.NicProperties{Ips: &[]string{"10.0.0.1"}}}}
+		ips := []string{"10.0.0.1", "203.0.113.21"}
+		rm.CloudApiV6Mocks.Datacenter.EXPECT().Get("dc-1").Return(&dc, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Get("dc-1", "srv-1", "nic-1").Return(&nic, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().List().Return(resources.IpBlocks{IpBlocks: ionoscloud.IpBlocks{Items: &usageTestBlocks}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Update("dc-1", "srv-1", "nic-1", resources.NicProperties{NicProperties: ionoscloud.NicProperties{Ips: &ips}}).Return(&nic, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil)
+		err := RunIpBlockAllocateIp(cfg)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunIpBlockAllocateIpNewBlock(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, FlagFor), "nic:nic-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgServerId), "srv-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgName), "reserved")
+		dc := resources.Datacenter{Datacenter: ionoscloud.Datacenter{Properties: &ionoscloud.DatacenterProperties{Location: pointer.From("us/las")}}}
+		nic := resources.Nic{Nic: ionoscloud.Nic{Properties: &ionoscloud.NicProperties{}}}
+		created := resources.IpBlock{IpBlock: usageTestBlock("b-5", "reserved", "us/las", []string{"198.51.100.7"})}
+		ips := []string{"198.51.100.7"}
+		rm.CloudApiV6Mocks.Datacenter.EXPECT().Get("dc-1").Return(&dc, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Get("dc-1", "srv-1", "nic-1").Return(&nic, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().List().Return(resources.IpBlocks{IpBlocks: ionoscloud.IpBlocks{Items: &usageTestBlocks}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().Create("reserved", "us/las", int32(1)).Return(&created, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil).Times(2)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().Get("b-5").Return(&created, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Update("dc-1", "srv-1", "nic-1", resources.NicProperties{NicProperties: ionoscloud.NicProperties{Ips: &ips}}).Return(&nic, &testutil.TestResponse, nil)
+		err := RunIpBlockAllocateIp(cfg)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunIpBlockAllocateIpNewBlockReleased(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, FlagFor), "nic:nic-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgServerId), "srv-1")
+		dc := resources.Datacenter{Datacenter: ionoscloud.Datacenter{Properties: &ionoscloud.DatacenterProperties{Location: pointer.From("us/las")}}}
+		nic := resources.Nic{Nic: ionoscloud.Nic{Properties: &ionoscloud.NicProperties{}}}
+		created := resources.IpBlock{IpBlock: usageTestBlock("b-5", "", "us/las", []string{"198.51.100.7"})}
+		rm.CloudApiV6Mocks.Datacenter.EXPECT().Get("dc-1").Return(&dc, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Get("dc-1", "srv-1", "nic-1").Return(&nic, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().List().Return(resources.IpBlocks{IpBlocks: ionoscloud.IpBlocks{Items: &usageTestBlocks}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().Create("", "us/las", int32(1)).Return(&created, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.IpBlocks.EXPECT().Get("b-5").Return(&created, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Nic.EXPECT().Update("dc-1", "srv-1", "nic-1", gomock.Any()).Return(&nic, &testutil.TestResponse, nil)
+		gomock.InOrder(
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(nil, errors.New("request failed")),
+			rm.CloudApiV6Mocks.IpBlocks.EXPECT().Delete("b-5").Return(&testutil.TestResponse, nil),
+			rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil),
+		)
+		err := RunIpBlockAllocateIp(cfg)
+		assert.EqualError(t, err, "failed assigning IP 198.51.100.7 to NIC nic-1: request failed\nreleased the new IpBlock b-5")
+	})
+}
+
+func TestRunIpBlockAllocateIpLocationErr(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, FlagFor), "nic:nic-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgServerId), "srv-1")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgLocation), "us/las")
+		dc := resources.Datacenter{Datacenter: ionoscloud.Datacenter{Properties: &ionoscloud.DatacenterProperties{Location: pointer.From("de/fra")}}}
+		rm.CloudApiV6Mocks.Datacenter.EXPECT().Get("dc-1").Return(&dc, &testutil.TestResponse, nil)
+		err := RunIpBlockAllocateIp(cfg)
+		assert.Error(t, err)
+	})
+}
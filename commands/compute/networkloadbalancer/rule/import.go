@@ -0,0 +1,217 @@
+package rule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFile   = "file"
+	FlagDryRun = "dry-run"
+)
+
+func NetworkLoadBalancerForwardingRuleImportCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "networkloadbalancer",
+		Resource:  "rule",
+		Verb:      "import",
+		Aliases:   []string{"i"},
+		ShortDesc: "Create Forwarding Rules and their targets from a load balancer file",
+		LongDesc: `Use this command to create the Forwarding Rules of a Network Load Balancer, with their targets, from a YAML file. The keys are the flags of ` + "`rule create`" + ` and ` + "`rule target add`" + `, with the same defaults. Each listener is a Forwarding Rule:
+
+    listeners:
+      - name: postgres
+        listener-ip: 203.0.113.30
+        listener-port: 5432
+        algorithm: LEAST_CONNECTION
+        health-check:
+          connection-timeout: 3000
+          retries: 2
+        targets:
+          - ip: 10.0.0.5
+            port: 5432
+          - ip: 10.0.0.6
+            port: 5432
+            weight: 10
+            proxy-protocol: v2
+            check-interval: 5000
+
+The Forwarding Rules are created one by one, waiting for each request. If a request fails, the Forwarding Rules created until then are deleted again. Use ` + "`--dry-run`" + ` to only validate the file and print what would be created.
+
+Required values to run command:
+
+* Data Center Id
+* Network Load Balancer Id
+* File`,
+		Example: `ionosctl compute networkloadbalancer rule import --datacenter-id DATACENTER_ID --networkloadbalancer-id NETWORKLOADBALANCER_ID --file nlb.yaml
+ionosctl compute networkloadbalancer rule import --datacenter-id DATACENTER_ID --networkloadbalancer-id NETWORKLOADBALANCER_ID --file nlb.yaml --dry-run`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return core.CheckRequiredFlags(c.Command, c.NS, cloudapiv6.ArgDataCenterId, cloudapiv6.ArgNetworkLoadBalancerId, FlagFile)
+		},
+		CmdRun:     RunNetworkLoadBalancerForwardingRuleImport,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgDataCenterId, "", "", cloudapiv6.DatacenterId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgDataCenterId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.DataCentersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddUUIDFlag(cloudapiv6.ArgNetworkLoadBalancerId, "", "", cloudapiv6.NetworkLoadBalancerId, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgNetworkLoadBalancerId, func(c *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.NetworkLoadBalancersIds(viper.GetString(core.GetFlagName(cmd.NS, cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringFlag(FlagFile, "", "", "Path to the load balancer file. '-' reads from stdin", core.RequiredFlagOption())
+	cmd.AddBoolFlag(FlagDryRun, "", false, "Only validate the file and print what would be created")
+
+	return cmd
+}
+
+func RunNetworkLoadBalancerForwardingRuleImport(c *core.CommandConfig) error {
+	dcId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+	nlbId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgNetworkLoadBalancerId))
+	c.Verbose(constants.DatacenterId, dcId)
+	c.Verbose(constants.NetworkLoadBalancerId, nlbId)
+
+	var r io.Reader = c.Command.Command.InOrStdin()
+	if path := viper.GetString(core.GetFlagName(c.NS, FlagFile)); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	file, err := parseNlbFile(r)
+	if err != nil {
+		return err
+	}
+
+	existing, resp, err := c.CloudApiV6Services.NetworkLoadBalancers().ListForwardingRules(dcId, nlbId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed listing Forwarding Rules: %w", err)
+	}
+	if existing.Items != nil {
+		if err = checkNlbListenerConflicts(file.Listeners, *existing.Items); err != nil {
+			return err
+		}
+	}
+
+	if viper.GetBool(core.GetFlagName(c.NS, FlagDryRun)) {
+		out := c.Command.Command.OutOrStdout()
+		for _, l := range file.Listeners {
+			fmt.Fprintf(out, "create Forwarding Rule %s on %s:%d with %d targets\n", l.Name, l.ListenerIp, l.ListenerPort, len(l.Targets))
+		}
+		return nil
+	}
+
+	imp := &nlbImport{c: c, datacenterId: dcId, nlbId: nlbId}
+	rules, err := imp.run(file)
+	if err != nil {
+		return err
+	}
+
+	return c.Printer(allCols).Print(rules)
+}
+
+// checkNlbListenerConflicts returns an error if a listener has the name, or the IP and port, of an existing Forwarding Rule.
+func checkNlbListenerConflicts(listeners []nlbListener, existing []ionoscloud.NetworkLoadBalancerForwardingRule) error {
+	for _, rule := range existing {
+		p := rule.Properties
+		if p == nil {
+			continue
+		}
+		for _, l := range listeners {
+			if p.Name != nil && *p.Name == l.Name {
+				return fmt.Errorf("listener %s: a Forwarding Rule with this name already exists: %s", l.Name, *rule.Id)
+			}
+			if p.ListenerIp != nil && *p.ListenerIp == l.ListenerIp && p.ListenerPort != nil && *p.ListenerPort == l.ListenerPort {
+				return fmt.Errorf("listener %s: Forwarding Rule %s already listens on %s:%d", l.Name, *rule.Id, l.ListenerIp, l.ListenerPort)
+			}
+		}
+	}
+	return nil
+}
+
+// nlbImport creates the Forwarding Rules of a load balancer file and keeps track of them, so they can be deleted again.
+type nlbImport struct {
+	c            *core.CommandConfig
+	datacenterId string
+	nlbId        string
+
+	// created are the names and IDs of the Forwarding Rules, in the order they were created.
+	created [][2]string
+}
+
+func (imp *nlbImport) wait(resp *resources.Response) error {
+	if resp == nil {
+		return nil
+	}
+	imp.c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err := imp.c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// run creates the Forwarding Rules of the file. If a request fails, the created Forwarding Rules are deleted in
+// reverse order.
+func (imp *nlbImport) run(file *nlbFile) ([]ionoscloud.NetworkLoadBalancerForwardingRule, error) {
+	var rules []ionoscloud.NetworkLoadBalancerForwardingRule
+	for _, l := range file.Listeners {
+		imp.c.Verbose("Creating Forwarding Rule %s with %d targets", l.Name, len(l.Targets))
+		properties := l.properties()
+		created, resp, err := imp.c.CloudApiV6Services.NetworkLoadBalancers().CreateForwardingRule(imp.datacenterId, imp.nlbId,
+			resources.NetworkLoadBalancerForwardingRule{
+				NetworkLoadBalancerForwardingRule: ionoscloud.NetworkLoadBalancerForwardingRule{Properties: &properties},
+			})
+		if created != nil && created.Id != nil {
+			imp.created = append(imp.created, [2]string{l.Name, *created.Id})
+			rules = append(rules, created.NetworkLoadBalancerForwardingRule)
+		}
+		if err == nil {
+			err = imp.wait(resp)
+		}
+		if err != nil {
+			return nil, imp.rollback(fmt.Errorf("failed creating Forwarding Rule %s: %w", l.Name, err))
+		}
+	}
+	return rules, nil
+}
+
+// rollback deletes the created Forwarding Rules.
+func (imp *nlbImport) rollback(cause error) error {
+	errs := []error{cause}
+	for i := len(imp.created) - 1; i >= 0; i-- {
+		name, id := imp.created[i][0], imp.created[i][1]
+		imp.c.Verbose("Rolling back: deleting Forwarding Rule %s (%s)", name, id)
+
+		resp, err := imp.c.CloudApiV6Services.NetworkLoadBalancers().DeleteForwardingRule(imp.datacenterId, imp.nlbId, id)
+		if err == nil {
+			err = imp.wait(resp)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed rolling back Forwarding Rule %s (%s), delete it manually: %w", name, id, err))
+		}
+	}
+	if len(imp.created) > 0 && len(errs) == 1 {
+		errs = append(errs, fmt.Errorf("rolled back %d created resources", len(imp.created)))
+	}
+	return errors.Join(errs...)
+}
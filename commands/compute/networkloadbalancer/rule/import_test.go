@@ -0,0 +1,133 @@
+package rule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const testNlbFile = `
+listeners:
+  - name: postgres
+    listener-ip: 203.0.113.30
+    listener-port: 5432
+    algorithm: least_connection
+    health-check:
+      connection-timeout: 3000
+      retries: 2
+    targets:
+      - ip: 10.0.0.5
+        port: 5432
+      - ip: 10.0.0.6
+        port: 5432
+        weight: 10
+        proxy-protocol: V2
+        check-interval: 5000
+  - name: redis
+    listener-ip: 203.0.113.30
+    listener-port: 6379
+`
+
+func TestParseNlbFile(t *testing.T) {
+	f, err := parseNlbFile(strings.NewReader(testNlbFile))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rule := f.Listeners[0].properties()
+	assert.Equal(t, "LEAST_CONNECTION", *rule.Algorithm)
+	assert.Equal(t, "TCP", *rule.Protocol)
+	assert.Equal(t, &ionoscloud.NetworkLoadBalancerForwardingRuleHealthCheck{ConnectTimeout: pointer.From(int32(3000)), Retries: pointer.From(int32(2))}, rule.HealthCheck)
+	assert.Equal(t, []ionoscloud.NetworkLoadBalancerForwardingRuleTarget{
+		{Ip: pointer.From("10.0.0.5"), Port: pointer.From(int32(5432)), Weight: pointer.From(int32(1)),
+			HealthCheck: &ionoscloud.NetworkLoadBalancerForwardingRuleTargetHealthCheck{Check: pointer.From(true), CheckInterval: pointer.From(int32(2000)), Maintenance: pointer.From(false)}},
+		{Ip: pointer.From("10.0.0.6"), Port: pointer.From(int32(5432)), Weight: pointer.From(int32(10)), ProxyProtocol: pointer.From("v2"),
+			HealthCheck: &ionoscloud.NetworkLoadBalancerForwardingRuleTargetHealthCheck{Check: pointer.From(true), CheckInterval: pointer.From(int32(5000)), Maintenance: pointer.From(false)}},
+	}, *rule.Targets)
+
+	rule = f.Listeners[1].properties()
+	assert.Equal(t, "ROUND_ROBIN", *rule.Algorithm)
+	assert.Nil(t, rule.HealthCheck)
+	assert.Empty(t, *rule.Targets)
+}
+
+func TestParseNlbFileErrors(t *testing.T) {
+	for name, tc := range map[string]struct{ file, err string }{
+		"no listeners": {"listeners: []", "the load balancer file has no listeners"},
+		"unknown key":  {"listeners:\n  - name: a\n    routes: []", "field routes not found"},
+		"algorithm": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, algorithm: fastest}",
+			"listener a: algorithm must be one of ROUND_ROBIN, LEAST_CONNECTION, RANDOM, SOURCE_IP"},
+		"target port": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, targets: [{ip: 10.0.0.5}]}",
+			"listener a: port of target 10.0.0.5 must be between 1 and 65535"},
+		"proxy protocol": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80, targets: [{ip: 10.0.0.5, port: 80, proxy-protocol: v3}]}",
+			"listener a: proxy-protocol of target 10.0.0.5 must be one of none, v1, v2, v2ssl"},
+		"duplicate listener": {"listeners:\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 80}\n  - {name: a, listener-ip: 10.0.0.1, listener-port: 81}",
+			"listener a is defined more than once"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseNlbFile(strings.NewReader(tc.file))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.err)
+			}
+		})
+	}
+}
+
+func TestCheckNlbListenerConflicts(t *testing.T) {
+	listeners := []nlbListener{{Name: "postgres", ListenerIp: "203.0.113.30", ListenerPort: 5432}}
+	existing := []ionoscloud.NetworkLoadBalancerForwardingRule{{Id: pointer.From("fr-1"), Properties: &ionoscloud.NetworkLoadBalancerForwardingRuleProperties{
+		Name: pointer.From("old"), ListenerIp: pointer.From("203.0.113.30"), ListenerPort: pointer.From(int32(5432)),
+	}}}
+	assert.EqualError(t, checkNlbListenerConflicts(listeners, existing), "listener postgres: Forwarding Rule fr-1 already listens on 203.0.113.30:5432")
+
+	(*existing[0].Properties.ListenerPort)++
+	assert.NoError(t, checkNlbListenerConflicts(listeners, existing))
+}
+
+func TestRunNetworkLoadBalancerForwardingRuleImportRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nlb.yaml")
+	if !assert.NoError(t, os.WriteFile(path, []byte(testNlbFile), 0o600)) {
+		return
+	}
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), "dc")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgNetworkLoadBalancerId), "nlb")
+		viper.Set(core.GetFlagName(cfg.NS, FlagFile), path)
+
+		rm.CloudApiV6Mocks.NetworkLoadBalancer.EXPECT().ListForwardingRules("dc", "nlb").Return(resources.NetworkLoadBalancerForwardingRules{}, &testutil.TestResponse, nil)
+		gomock.InOrder(
+			rm.CloudApiV6Mocks.NetworkLoadBalancer.EXPECT().CreateForwardingRule("dc", "nlb", gomock.Any()).
+				DoAndReturn(func(_, _ string, rule resources.NetworkLoadBalancerForwardingRule) (*resources.NetworkLoadBalancerForwardingRule, *resources.Response, error) {
+					assert.Len(t, *rule.Properties.Targets, 2)
+					return &resources.NetworkLoadBalancerForwardingRule{NetworkLoadBalancerForwardingRule: ionoscloud.NetworkLoadBalancerForwardingRule{Id: pointer.From("fr-1")}}, &testutil.TestResponse, nil
+				}),
+			rm.CloudApiV6Mocks.NetworkLoadBalancer.EXPECT().CreateForwardingRule("dc", "nlb", gomock.Any()).
+				Return(&resources.NetworkLoadBalancerForwardingRule{}, nil, errors.New("listener IP is not assigned")),
+			rm.CloudApiV6Mocks.NetworkLoadBalancer.EXPECT().DeleteForwardingRule("dc", "nlb", "fr-1").Return(&testutil.TestResponse, nil),
+		)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil).Times(2)
+
+		err := RunNetworkLoadBalancerForwardingRuleImport(cfg)
+		assert.EqualError(t, err, "failed creating Forwarding Rule redis: listener IP is not assigned\nrolled back 1 created resources")
+	})
+}
@@ -0,0 +1,158 @@
+package rule
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// nlbFile is a load balancer file with the listeners of a Network Load Balancer.
+// The keys are the flags of 'rule create' and 'rule target add'.
+type nlbFile struct {
+	Listeners []nlbListener `yaml:"listeners"`
+}
+
+// nlbListener is a Forwarding Rule, with its targets.
+type nlbListener struct {
+	Name         string          `yaml:"name"`
+	ListenerIp   string          `yaml:"listener-ip"`
+	ListenerPort int32           `yaml:"listener-port"`
+	Algorithm    string          `yaml:"algorithm,omitempty"`
+	Protocol     string          `yaml:"protocol,omitempty"`
+	HealthCheck  *nlbHealthCheck `yaml:"health-check,omitempty"`
+	Targets      []nlbTarget     `yaml:"targets,omitempty"`
+}
+
+type nlbHealthCheck struct {
+	ClientTimeout  *int32 `yaml:"client-timeout,omitempty"`
+	ConnectTimeout *int32 `yaml:"connection-timeout,omitempty"`
+	TargetTimeout  *int32 `yaml:"target-timeout,omitempty"`
+	Retries        *int32 `yaml:"retries,omitempty"`
+}
+
+type nlbTarget struct {
+	Ip            string `yaml:"ip"`
+	Port          int32  `yaml:"port"`
+	Weight        *int32 `yaml:"weight,omitempty"`
+	ProxyProtocol string `yaml:"proxy-protocol,omitempty"`
+	Check         *bool  `yaml:"check,omitempty"`
+	CheckInterval *int32 `yaml:"check-interval,omitempty"`
+	Maintenance   *bool  `yaml:"maintenance,omitempty"`
+}
+
+var (
+	nlbAlgorithms     = []string{"ROUND_ROBIN", "LEAST_CONNECTION", "RANDOM", "SOURCE_IP"}
+	nlbProtocols      = []string{"TCP", "HTTP"}
+	nlbProxyProtocols = []string{"none", "v1", "v2", "v2ssl"}
+)
+
+// parseNlbFile reads and validates a load balancer file of a Network Load Balancer.
+func parseNlbFile(r io.Reader) (*nlbFile, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var f nlbFile
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed reading load balancer file: %w", err)
+	}
+	if len(f.Listeners) == 0 {
+		return nil, fmt.Errorf("the load balancer file has no listeners")
+	}
+
+	listeners := map[string]bool{}
+	for i := range f.Listeners {
+		l := &f.Listeners[i]
+		if l.Name == "" {
+			return nil, fmt.Errorf("listener %d has no name", i+1)
+		}
+		if listeners[l.Name] {
+			return nil, fmt.Errorf("listener %s is defined more than once", l.Name)
+		}
+		listeners[l.Name] = true
+		if net.ParseIP(l.ListenerIp) == nil {
+			return nil, fmt.Errorf("listener %s: listener-ip %q is invalid", l.Name, l.ListenerIp)
+		}
+		if l.ListenerPort < 1 || l.ListenerPort > 65535 {
+			return nil, fmt.Errorf("listener %s: listener-port must be between 1 and 65535", l.Name)
+		}
+		l.Algorithm, l.Protocol = strings.ToUpper(l.Algorithm), strings.ToUpper(l.Protocol)
+		if l.Algorithm != "" && !slices.Contains(nlbAlgorithms, l.Algorithm) {
+			return nil, fmt.Errorf("listener %s: algorithm must be one of %s", l.Name, strings.Join(nlbAlgorithms, ", "))
+		}
+		if l.Protocol != "" && !slices.Contains(nlbProtocols, l.Protocol) {
+			return nil, fmt.Errorf("listener %s: protocol must be one of %s", l.Name, strings.Join(nlbProtocols, ", "))
+		}
+
+		for j := range l.Targets {
+			t := &l.Targets[j]
+			if net.ParseIP(t.Ip) == nil {
+				return nil, fmt.Errorf("listener %s: target IP %q is invalid", l.Name, t.Ip)
+			}
+			if t.Port < 1 || t.Port > 65535 {
+				return nil, fmt.Errorf("listener %s: port of target %s must be between 1 and 65535", l.Name, t.Ip)
+			}
+			if t.Weight != nil && (*t.Weight < 0 || *t.Weight > 256) {
+				return nil, fmt.Errorf("listener %s: weight of target %s must be between 0 and 256", l.Name, t.Ip)
+			}
+			t.ProxyProtocol = strings.ToLower(t.ProxyProtocol)
+			if t.ProxyProtocol != "" && !slices.Contains(nlbProxyProtocols, t.ProxyProtocol) {
+				return nil, fmt.Errorf("listener %s: proxy-protocol of target %s must be one of %s", l.Name, t.Ip, strings.Join(nlbProxyProtocols, ", "))
+			}
+		}
+	}
+	return &f, nil
+}
+
+// properties returns the Forwarding Rule of the listener, with the defaults of 'rule create' and 'rule target add'.
+func (l nlbListener) properties() ionoscloud.NetworkLoadBalancerForwardingRuleProperties {
+	p := ionoscloud.NetworkLoadBalancerForwardingRuleProperties{
+		Name:         pointer.From(l.Name),
+		Algorithm:    pointer.From("ROUND_ROBIN"),
+		Protocol:     pointer.From(string(ionoscloud.TCP)),
+		ListenerIp:   pointer.From(l.ListenerIp),
+		ListenerPort: pointer.From(l.ListenerPort),
+		Targets:      &[]ionoscloud.NetworkLoadBalancerForwardingRuleTarget{},
+	}
+	if l.Algorithm != "" {
+		p.Algorithm = pointer.From(l.Algorithm)
+	}
+	if l.Protocol != "" {
+		p.Protocol = pointer.From(l.Protocol)
+	}
+	if hc := l.HealthCheck; hc != nil {
+		p.HealthCheck = &ionoscloud.NetworkLoadBalancerForwardingRuleHealthCheck{
+			ClientTimeout: hc.ClientTimeout, ConnectTimeout: hc.ConnectTimeout, TargetTimeout: hc.TargetTimeout, Retries: hc.Retries,
+		}
+	}
+
+	for _, t := range l.Targets {
+		target := ionoscloud.NetworkLoadBalancerForwardingRuleTarget{
+			Ip: pointer.From(t.Ip), Port: pointer.From(t.Port), Weight: pointer.From(int32(1)),
+			HealthCheck: &ionoscloud.NetworkLoadBalancerForwardingRuleTargetHealthCheck{
+				Check: pointer.From(true), CheckInterval: pointer.From(int32(2000)), Maintenance: pointer.From(false),
+			},
+		}
+		if t.Weight != nil {
+			target.Weight = t.Weight
+		}
+		if t.ProxyProtocol != "" {
+			target.ProxyProtocol = pointer.From(t.ProxyProtocol)
+		}
+		if t.Check != nil {
+			target.HealthCheck.Check = t.Check
+		}
+		if t.CheckInterval != nil {
+			target.HealthCheck.CheckInterval = t.CheckInterval
+		}
+		if t.Maintenance != nil {
+			target.HealthCheck.Maintenance = t.Maintenance
+		}
+		*p.Targets = append(*p.Targets, target)
+	}
+	return p
+}
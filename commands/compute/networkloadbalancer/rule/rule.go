@@ -28,7 +28,7 @@ func NetworkloadbalancerRuleCmd() *core.Command {
 			Use:              "rule",
 			Aliases:          []string{"r", "forwardingrule"},
 			Short:            "Network Load Balancer Forwarding Rule Operations",
-			Long:             "The sub-commands of `ionosctl compute nlb rule` allow you to create, list, get, update, delete Network Load Balancer Forwarding Rules.",
+			Long:             "The sub-commands of `ionosctl compute nlb rule` allow you to create, list, get, update, delete Network Load Balancer Forwarding Rules, and to import them from a load balancer file.",
 			TraverseChildren: true,
 		},
 	}
@@ -39,6 +39,7 @@ func NetworkloadbalancerRuleCmd() *core.Command {
 	nlbRuleCmd.AddCommand(NetworkLoadBalancerForwardingRuleCreateCmd())
 	nlbRuleCmd.AddCommand(NetworkLoadBalancerForwardingRuleUpdateCmd())
 	nlbRuleCmd.AddCommand(NetworkLoadBalancerForwardingRuleDeleteCmd())
+	nlbRuleCmd.AddCommand(NetworkLoadBalancerForwardingRuleImportCmd())
 
 	nlbRuleCmd.AddCommand(target.NlbRuleTargetCmd())
 
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/globalwait"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"golang.org/x/sync/errgroup"
+)
+
+// nameTemplateData is the data of --name-template. Index starts at 1.
+type nameTemplateData struct {
+	Index int
+}
+
+// renderServerNames returns the names of count Servers. Without a template, a single Server gets the
+// name, and several Servers get the name suffixed with their index.
+func renderServerNames(count int, nameTemplate, name string) ([]string, error) {
+	if nameTemplate == "" {
+		if count == 1 {
+			return []string{name}, nil
+		}
+		nameTemplate = name + "-{{.Index}}"
+	}
+
+	tpl, err := template.New(constants.FlagNameTemplate).Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", constants.FlagNameTemplate, err)
+	}
+	names := make([]string, count)
+	for i := range names {
+		var b strings.Builder
+		if err = tpl.Execute(&b, nameTemplateData{Index: i + 1}); err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", constants.FlagNameTemplate, err)
+		}
+		names[i] = b.String()
+	}
+	return names, nil
+}
+
+func serverNames(c *core.CommandConfig) ([]string, error) {
+	return renderServerNames(
+		max(viper.GetInt(core.GetFlagName(c.NS, constants.FlagCount)), 1),
+		viper.GetString(core.GetFlagName(c.NS, constants.FlagNameTemplate)),
+		viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgName)),
+	)
+}
+
+// readUserData returns the cloud-init configuration of the file, base64 encoded as the API expects it.
+func readUserData(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed reading user data: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// getNewServerEntities returns the Volume and NIC created in the same request as the Server, or nil if
+// there are none.
+func getNewServerEntities(c *core.CommandConfig) (*ionoscloud.ServerEntities, error) {
+	serverType := viper.GetString(core.GetFlagName(c.NS, constants.FlagType))
+	userDataFile := viper.GetString(core.GetFlagName(c.NS, constants.FlagUserDataFile))
+	entities := ionoscloud.ServerEntities{}
+
+	// CUBE and GPU Servers are created with an attached Volume. A Confidential VM must be created
+	// together with a boot volume built from the confidential image, as the API derives cores + CPU
+	// family from that image. User data needs a boot volume built from the image too.
+	if serverType == serverCubeType || serverType == serverGPUType || userDataFile != "" ||
+		viper.GetBool(core.GetFlagName(c.NS, constants.FlagConfidential)) {
+		volume, err := getNewDAS(c)
+		if err != nil {
+			return nil, err
+		}
+		if userDataFile != "" {
+			userData, err := readUserData(userDataFile)
+			if err != nil {
+				return nil, err
+			}
+			volume.Properties.SetUserData(userData)
+		}
+		entities.Volumes = &ionoscloud.AttachedVolumes{Items: &[]ionoscloud.Volume{volume.Volume}}
+	}
+
+	if viper.IsSet(core.GetFlagName(c.NS, cloudapiv6.ArgLanId)) {
+		entities.Nics = &ionoscloud.Nics{Items: &[]ionoscloud.Nic{{Properties: &ionoscloud.NicProperties{
+			Lan:  pointer.From(viper.GetInt32(core.GetFlagName(c.NS, cloudapiv6.ArgLanId))),
+			Dhcp: pointer.From(true),
+		}}}}
+	}
+
+	if entities.Volumes == nil && entities.Nics == nil {
+		return nil, nil
+	}
+	return &entities, nil
+}
+
+// maxConcurrentCreates is the number of Servers created at the same time.
+const maxConcurrentCreates = 10
+
+// createServers creates a Server, with its Volume and NIC, for each of the names concurrently. With
+// --wait, all of them are waited for, under a single progress bar.
+func createServers(c *core.CommandConfig, input resources.Server, names []string) error {
+	dcId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId))
+
+	servers := make([]ionoscloud.Server, len(names))
+	errs := make([]error, len(names))
+	var eg errgroup.Group
+	eg.SetLimit(maxConcurrentCreates)
+	for i, name := range names {
+		eg.Go(func() error {
+			properties := *input.Properties
+			properties.Name = pointer.From(name)
+			server := input
+			server.Properties = &properties
+
+			svr, resp, err := c.CloudApiV6Services.Servers().Create(dcId, server)
+			if resp != nil && request.GetId(resp) != "" {
+				c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("failed creating Server %s: %w", name, err)
+				return nil
+			}
+			servers[i] = svr.Server
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	created := make([]ionoscloud.Server, 0, len(servers))
+	var ids []string
+	for i, svr := range servers {
+		if errs[i] == nil {
+			created = append(created, svr)
+			if svr.Id != nil {
+				ids = append(ids, *svr.Id)
+			}
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		// The created Servers are printed, so they can be found and deleted or kept.
+		if len(created) > 0 {
+			if perr := c.Printer(AllServerCols).Print(created); perr != nil {
+				err = errors.Join(err, perr)
+			}
+		}
+		msg := fmt.Sprintf("created %d of %d Servers", len(created), len(names))
+		if len(ids) > 0 {
+			msg += " (" + strings.Join(ids, ", ") + ")"
+		}
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	if viper.GetBool(constants.ArgWait) {
+		hrefs := make([]string, 0, len(created))
+		for _, svr := range created {
+			if svr.Href != nil {
+				hrefs = append(hrefs, *svr.Href)
+			}
+		}
+		cfg := client.Must().CloudClient.GetConfig()
+		if err := globalwait.WaitForHrefs(c.Command.Command.ErrOrStderr(), hrefs, cfg.Token, cfg.Username, cfg.Password); err != nil {
+			return err
+		}
+
+		// All waiting done inline — skip post-command WaitAndRerender.
+		globalwait.Reset()
+		globalwait.MarkDone()
+
+		// Re-fetch the Servers with their final state. Non-fatal: keep the POST response on failure.
+		for i, svr := range created {
+			if svr.Id == nil {
+				continue
+			}
+			if fresh, _, err := c.CloudApiV6Services.Servers().Get(dcId, *svr.Id); err == nil {
+				created[i] = fresh.Server
+			}
+		}
+	}
+
+	return c.Printer(AllServerCols).Print(created)
+}
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderServerNames(t *testing.T) {
+	names, err := renderServerNames(1, "", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"web"}, names)
+
+	names, err = renderServerNames(3, "", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"web-1", "web-2", "web-3"}, names)
+
+	names, err = renderServerNames(2, "load-{{.Index}}-test", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"load-1-test", "load-2-test"}, names)
+
+	_, err = renderServerNames(2, "web-{{.Index", "")
+	assert.Error(t, err)
+	_, err = renderServerNames(2, "web-{{.Number}}", "")
+	assert.Error(t, err)
+}
+
+func TestPreRunServerCreateCountErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		set  map[string]any
+	}{
+		{name: "count below 1", set: map[string]any{constants.FlagCount: 0}},
+		{name: "promote volume", set: map[string]any{constants.FlagCount: 2, constants.FlagPromoteVolume: true}},
+		{name: "invalid name template", set: map[string]any{constants.FlagCount: 2, constants.FlagNameTemplate: "web-{{"}},
+		{name: "user data without image", set: map[string]any{constants.FlagCount: 1, constants.FlagUserDataFile: "cloud-init.yaml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			w := bufio.NewWriter(&b)
+			core.PreCmdConfigTest(t, w, func(cfg *core.PreCommandConfig) {
+				viper.Reset()
+				viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+				viper.Set(constants.ArgWait, true)
+				viper.Set(core.GetFlagName(cfg.NS, constants.FlagType), serverCubeType)
+				viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), testServerVar)
+				viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgTemplateId), testServerVar)
+				for k, v := range tt.set {
+					viper.Set(core.GetFlagName(cfg.NS, k), v)
+				}
+
+				err := PreRunServerCreate(cfg)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
+
+// With --count, RunServerCreate must create every Server, each with its own name, the boot volume with
+// the user data, and a NIC.
+func TestRunServerCreateCount(t *testing.T) {
+	userDataFile := filepath.Join(t.TempDir(), "cloud-init.yaml")
+	assert.NoError(t, os.WriteFile(userDataFile, []byte("#cloud-config\npackages: [nginx]\n"), 0o600))
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), testServerVar)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagType), testServerEnterpriseType)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCores), cores)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCpuFamily), testServerVar)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagRam), strconv.Itoa(int(ram)))
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCount), 3)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagNameTemplate), "web-{{.Index}}")
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagUserDataFile), userDataFile)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagStorageType), "SSD")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgSize), "20")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgImageAlias), "ubuntu:latest")
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgLanId), 2)
+		viper.Set(constants.ArgWait, false)
+
+		var mu sync.Mutex
+		var names []string
+		rm.CloudApiV6Mocks.Server.EXPECT().Create(testServerVar, gomock.Any()).Times(3).DoAndReturn(
+			func(dcId string, input resources.Server) (*resources.Server, *resources.Response, error) {
+				mu.Lock()
+				names = append(names, *input.Properties.Name)
+				mu.Unlock()
+				if assert.NotNil(t, input.Entities) && assert.NotNil(t, input.Entities.Volumes) && assert.NotNil(t, input.Entities.Nics) {
+					volume := (*input.Entities.Volumes.Items)[0]
+					assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("#cloud-config\npackages: [nginx]\n")), *volume.Properties.UserData)
+					assert.Equal(t, "SSD", *volume.Properties.Type)
+					assert.Equal(t, float32(20), *volume.Properties.Size)
+					assert.Equal(t, int32(2), *(*input.Entities.Nics.Items)[0].Properties.Lan)
+				}
+				return &resources.Server{Server: ionoscloud.Server{Properties: input.Properties}}, &testutil.TestResponse, nil
+			})
+
+		err := RunServerCreate(cfg)
+		assert.NoError(t, err)
+		sort.Strings(names)
+		assert.Equal(t, []string{"web-1", "web-2", "web-3"}, names)
+	})
+}
+
+func TestRunServerCreateCountErr(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgDataCenterId), testServerVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgName), "web")
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagType), testServerEnterpriseType)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCores), cores)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCpuFamily), testServerVar)
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagRam), strconv.Itoa(int(ram)))
+		viper.Set(core.GetFlagName(cfg.NS, constants.FlagCount), 2)
+		viper.Set(constants.ArgWait, false)
+
+		rm.CloudApiV6Mocks.Server.EXPECT().Create(testServerVar, gomock.Any()).Times(2).DoAndReturn(
+			func(dcId string, input resources.Server) (*resources.Server, *resources.Response, error) {
+				if *input.Properties.Name == "web-2" {
+					return nil, nil, testServerErr
+				}
+				return &resources.Server{Server: ionoscloud.Server{Id: pointer.From("web-1-id"), Properties: input.Properties}}, &testutil.TestResponse, nil
+			})
+
+		err := RunServerCreate(cfg)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "created 1 of 2 Servers (web-1-id)")
+			assert.ErrorIs(t, err, testServerErr)
+		}
+		w.Flush()
+		assert.Contains(t, b.String(), "web-1-id")
+	})
+}
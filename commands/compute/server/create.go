@@ -78,8 +78,14 @@ Required values to create a Server of type GPU:
 
 By default, Licence Type for Direct Attached Storage is set to LINUX. You can set it using the ` + "`" + `--licence-type` + "`" + ` option or set an Image Id. For Image Id, it is needed to set a password or SSH keys.
 
-Use ` + "`" + `--wait` + "`" + ` (` + "`" + `-w` + "`" + `) to wait for the resource to reach AVAILABLE state.`,
-		Example:    "ionosctl compute server create --datacenter-id DATACENTER_ID --cores 2 --ram 256MB\nionosctl compute server create --datacenter-id DATACENTER_ID --type CUBE --template-id TEMPLATE_ID\nionosctl compute server create --datacenter-id DATACENTER_ID --type VCPU --cores 2 --ram 256MB\nionosctl compute server create --datacenter-id DATACENTER_ID --type GPU --template-id TEMPLATE_ID",
+5. Creating several Servers:
+
+Use ` + "`" + `--count` + "`" + ` to create identical Servers concurrently, each in its own request with its Volume and NIC. Their names are set from ` + "`" + `--name-template` + "`" + `, a Go template with the 1-based ` + "`" + `{{.Index}}` + "`" + ` of each Server. Use ` + "`" + `--lan-id` + "`" + ` to create a NIC with DHCP in a LAN for each Server.
+
+Use ` + "`" + `--user-data-file` + "`" + ` to set a cloud-init configuration as the user data of the boot volume. It is base64 encoded, and requires an Image with cloud-init support. For ENTERPRISE and VCPU Servers, a boot volume is then created from the Image, with the size and storage type set by ` + "`" + `--size` + "`" + ` and ` + "`" + `--storage-type` + "`" + `.
+
+Use ` + "`" + `--wait` + "`" + ` (` + "`" + `-w` + "`" + `) to wait for the resource to reach AVAILABLE state. With ` + "`" + `--count` + "`" + `, all Servers are waited for.`,
+		Example:    "ionosctl compute server create --datacenter-id DATACENTER_ID --cores 2 --ram 256MB\nionosctl compute server create --datacenter-id DATACENTER_ID --type CUBE --template-id TEMPLATE_ID\nionosctl compute server create --datacenter-id DATACENTER_ID --type VCPU --cores 2 --ram 256MB\nionosctl compute server create --datacenter-id DATACENTER_ID --type GPU --template-id TEMPLATE_ID\nionosctl compute server create --datacenter-id DATACENTER_ID --cores 2 --ram 4GB --count 10 --name-template web-{{.Index}} --image-alias ubuntu:latest --ssh-key-paths ~/.ssh/id_rsa.pub --user-data-file cloud-init.yaml --lan-id 1",
 		PreCmdRun:  PreRunServerCreate,
 		CmdRun:     RunServerCreate,
 		InitClient: true,
@@ -106,8 +112,8 @@ Use ` + "`" + `--wait` + "`" + ` (` + "`" + `-w` + "`" + `) to wait for the reso
 		"Create a Confidential Computing (SEV-SNP) VM from a confidential boot image. Requires --type ENTERPRISE and --image-id (a private, SEV-SNP image). "+
 			"Do not set --cores or --cpu-family: both are derived from the image's launch-config.json. "+
 			"A boot volume is created from --image-id and attached automatically; size it with --size and --storage-type.")
-	create.AddStringFlag(cloudapiv6.ArgSize, "", strconv.Itoa(cloudapiv6.DefaultVolumeSize), "[Confidential] Size of the confidential boot volume, e.g. --size 10 or --size 10GB")
-	create.AddSetFlag(constants.FlagStorageType, "", "HDD", []string{"HDD", "SSD", "SSD Standard", "SSD Premium"}, "[Confidential] Storage type of the confidential boot volume")
+	create.AddStringFlag(cloudapiv6.ArgSize, "", strconv.Itoa(cloudapiv6.DefaultVolumeSize), "[Confidential, User Data] Size of the boot volume, e.g. --size 10 or --size 10GB")
+	create.AddSetFlag(constants.FlagStorageType, "", "HDD", []string{"HDD", "SSD", "SSD Standard", "SSD Premium"}, "[Confidential, User Data] Storage type of the boot volume")
 	create.AddBoolFlag(constants.FlagNICMultiQueue, "", false, constants.FlagNICMultiQueueDescription)
 	create.AddStringFlag(constants.FlagAvailabilityZone, constants.FlagAvailabilityZoneShort, "AUTO", "Availability zone of the Server")
 	_ = create.Command.RegisterFlagCompletionFunc(constants.FlagAvailabilityZone, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -122,6 +128,13 @@ Use ` + "`" + `--wait` + "`" + ` (` + "`" + `-w` + "`" + `) to wait for the reso
 		return []string{"ENTERPRISE", "CUBE", "VCPU", "GPU"}, cobra.ShellCompDirectiveNoFileComp
 	})
 	create.AddBoolFlag(constants.FlagPromoteVolume, "", false, "For CUBE and GPU servers, promotes the attached volume to be the Boot Volume. Requires --wait")
+	create.AddIntFlag(constants.FlagCount, "", 1, "Number of identical Servers to create, up to 10 at a time")
+	create.AddStringFlag(constants.FlagNameTemplate, "", "", "Go template for the names of the Servers, with the 1-based {{.Index}} of each Server, e.g. web-{{.Index}}. Defaults to --name, suffixed with -{{.Index}} if --count is greater than 1")
+	create.AddStringFlag(constants.FlagUserDataFile, "", "", "Path of a cloud-init configuration file, set base64 encoded as the user data of the boot volume. Requires --image-id or --image-alias with cloud-init support")
+	create.AddIntFlag(cloudapiv6.ArgLanId, "", 0, "If set, create a NIC with DHCP in this LAN for each Server")
+	_ = create.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgLanId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.LansIds(viper.GetString(core.GetFlagName(create.NS, cloudapiv6.ArgDataCenterId))), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	// Volume Properties - for DAS Volume associated with Cube Server
 	create.AddStringFlag(cloudapiv6.ArgVolumeName, "N", "Unnamed Direct Attached Storage", "[CUBE Server] Name of the Direct Attached Storage")
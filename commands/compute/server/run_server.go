@@ -84,9 +84,28 @@ func PreRunServerCreate(c *core.PreCommandConfig) error {
 		}
 	}
 
+	// Batch creation (--count, --name-template)
+	count := viper.GetInt(core.GetFlagName(c.NS, constants.FlagCount))
+	if count < 1 {
+		return fmt.Errorf("--%s must be at least 1", constants.FlagCount)
+	}
+	if count > 1 && viper.GetBool(core.GetFlagName(c.NS, constants.FlagPromoteVolume)) {
+		return fmt.Errorf("--%s cannot be used with --%s greater than 1", constants.FlagPromoteVolume, constants.FlagCount)
+	}
+	if _, err = renderServerNames(count, viper.GetString(core.GetFlagName(c.NS, constants.FlagNameTemplate)), ""); err != nil {
+		return err
+	}
+
 	imageIdFlag := core.GetFlagName(c.NS, cloudapiv6.ArgImageId)
 	imageAliasFlag := core.GetFlagName(c.NS, cloudapiv6.ArgImageAlias)
 
+	// The cloud-init user data is set on the boot volume, which is built from the image.
+	if viper.GetString(core.GetFlagName(c.NS, constants.FlagUserDataFile)) != "" &&
+		!viper.IsSet(imageIdFlag) && !viper.IsSet(imageAliasFlag) {
+		return fmt.Errorf("--%s requires --%s or --%s with cloud-init support",
+			constants.FlagUserDataFile, cloudapiv6.ArgImageId, cloudapiv6.ArgImageAlias)
+	}
+
 	// Check if image ID or alias is set
 	if viper.IsSet(imageIdFlag) || viper.IsSet(imageAliasFlag) {
 		imageRequiredFlags := make([][]string, 0)
@@ -219,52 +238,22 @@ func RunServerCreate(c *core.CommandConfig) error {
 		return err
 	}
 
-	// If Server is of type CUBE, it will create an attached Volume
-	if viper.GetString(core.GetFlagName(c.NS, constants.FlagType)) == serverCubeType {
-		// Volume Properties
-		volumeDAS, err := getNewDAS(c)
-		if err != nil {
-			return err
-		}
-
-		// Attach Storage
-		input.SetEntities(ionoscloud.ServerEntities{
-			Volumes: &ionoscloud.AttachedVolumes{
-				Items: &[]ionoscloud.Volume{volumeDAS.Volume},
-			},
-		})
+	entities, err := getNewServerEntities(c)
+	if err != nil {
+		return err
 	}
-
-	// If Server is of type GPU, it will create an attached Volume
-	if viper.GetString(core.GetFlagName(c.NS, constants.FlagType)) == serverGPUType {
-		// Volume Properties
-		volumeGPU, err := getNewDAS(c)
-		if err != nil {
-			return err
-		}
-
-		// Attach Storage
-		input.SetEntities(ionoscloud.ServerEntities{
-			Volumes: &ionoscloud.AttachedVolumes{
-				Items: &[]ionoscloud.Volume{volumeGPU.Volume},
-			},
-		})
+	if entities != nil {
+		input.SetEntities(*entities)
 	}
 
-	// A Confidential VM must be created together with a boot volume built from the confidential
-	// image, in the same request — the API derives cores + CPU family from that image.
-	if viper.GetBool(core.GetFlagName(c.NS, constants.FlagConfidential)) {
-		volumeConf, err := getNewDAS(c)
-		if err != nil {
-			return err
-		}
-
-		input.SetEntities(ionoscloud.ServerEntities{
-			Volumes: &ionoscloud.AttachedVolumes{
-				Items: &[]ionoscloud.Volume{volumeConf.Volume},
-			},
-		})
+	names, err := serverNames(c)
+	if err != nil {
+		return err
 	}
+	if len(names) > 1 {
+		return createServers(c, *input, names)
+	}
+	input.Properties.SetName(names[0])
 
 	svr, resp, err := c.CloudApiV6Services.Servers().Create(viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgDataCenterId)), *input)
 	if resp != nil && request.GetId(resp) != "" {
@@ -771,9 +760,10 @@ func getNewDAS(c *core.CommandConfig) (*resources.Volume, error) {
 		volumeProper.SetType("DAS")
 	}
 
-	// Confidential boot volume: a normal sized volume (not template-based DAS) built from the
-	// confidential image. Set its storage type and size from the dedicated flags.
-	if viper.GetBool(core.GetFlagName(c.NS, constants.FlagConfidential)) {
+	// Boot volume of an ENTERPRISE or VCPU Server (confidential, or with user data): a normal sized
+	// volume (not template-based DAS) built from the image. Set its storage type and size from the
+	// dedicated flags.
+	if serverType != serverCubeType && serverType != serverGPUType {
 		volumeProper.SetType(viper.GetString(core.GetFlagName(c.NS, constants.FlagStorageType)))
 		size, err := utils2.ConvertSize(
 			viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgSize)),
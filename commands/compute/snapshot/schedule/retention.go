@@ -0,0 +1,189 @@
+package schedule
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+)
+
+const (
+	// LabelVolume is the label of a scheduled Snapshot with the ID of its Volume.
+	LabelVolume = "snapshot-volume"
+	// LabelTime is the label of a scheduled Snapshot with the time it was taken, in the timeLayout.
+	LabelTime = "snapshot-time"
+	// LabelSchedule is the label of a scheduled Snapshot with the ID of its schedule, see scheduleId.
+	LabelSchedule = "snapshot-schedule"
+
+	timeLayout = "20060102-150405"
+)
+
+// retention is the number of Snapshots kept per Volume: the newest Snapshot of each of the last Daily days,
+// Weekly ISO weeks and Monthly months.
+type retention struct {
+	Daily, Weekly, Monthly int
+}
+
+type retentionPolicy struct {
+	name   string
+	keep   int
+	period func(time.Time) string
+}
+
+func (r retention) policies() []retentionPolicy {
+	return []retentionPolicy{
+		{name: "daily", keep: r.Daily, period: func(t time.Time) string { return t.Format("2006-01-02") }},
+		{name: "weekly", keep: r.Weekly, period: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{name: "monthly", keep: r.Monthly, period: func(t time.Time) string { return t.Format("2006-01") }},
+	}
+}
+
+// retain returns the policies that keep each of the times, by index. Times without policies expired.
+func (r retention) retain(times []time.Time) map[int][]string {
+	newest := make([]int, len(times))
+	for i := range newest {
+		newest[i] = i
+	}
+	sort.SliceStable(newest, func(a, b int) bool { return times[newest[a]].After(times[newest[b]]) })
+
+	kept := map[int][]string{}
+	for _, p := range r.policies() {
+		periods := map[string]bool{}
+		for _, i := range newest {
+			period := p.period(times[i].UTC())
+			if periods[period] {
+				continue
+			}
+			if len(periods) == p.keep {
+				break
+			}
+			periods[period] = true
+			kept[i] = append(kept[i], p.name)
+		}
+	}
+	return kept
+}
+
+// labelled is a resource with its labels.
+type labelled struct {
+	Type, Id, Href string
+	Labels         map[string]string
+}
+
+// labelledResources groups the labels by the resource they are applied on, sorted by type and ID.
+func labelledResources(labels []ionoscloud.Label) []labelled {
+	byResource := map[string]*labelled{}
+	for _, l := range labels {
+		p := l.Properties
+		if p == nil || p.ResourceType == nil || p.ResourceId == nil || p.Key == nil || p.Value == nil {
+			continue
+		}
+		key := *p.ResourceType + "/" + *p.ResourceId
+		r, ok := byResource[key]
+		if !ok {
+			r = &labelled{Type: *p.ResourceType, Id: *p.ResourceId, Labels: map[string]string{}}
+			byResource[key] = r
+		}
+		if p.ResourceHref != nil {
+			r.Href = *p.ResourceHref
+		}
+		r.Labels[*p.Key] = *p.Value
+	}
+
+	resources := make([]labelled, 0, len(byResource))
+	for _, r := range byResource {
+		resources = append(resources, *r)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Type != resources[j].Type {
+			return resources[i].Type < resources[j].Type
+		}
+		return resources[i].Id < resources[j].Id
+	})
+	return resources
+}
+
+// matches reports whether the resource has all labels of the selector.
+func (l labelled) matches(selector map[string]string) bool {
+	for k, v := range selector {
+		if value, ok := l.Labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// parseSelector parses labels in the form key=value.
+func parseSelector(selector []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, s := range selector {
+		key, value, ok := strings.Cut(s, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid selector %q, must be in the form key=value", s)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+var volumeHrefPattern = regexp.MustCompile(`/datacenters/([^/]+)/volumes/([^/]+)$`)
+
+type volume struct {
+	DatacenterId, VolumeId string
+}
+
+// scheduledSnapshot is a Snapshot taken by a schedule.
+type scheduledSnapshot struct {
+	SnapshotId, VolumeId string
+	Time                 time.Time
+}
+
+// selectVolumes returns the Volumes with the labels of the selector.
+func selectVolumes(resources []labelled, selector map[string]string) []volume {
+	var volumes []volume
+	for _, r := range resources {
+		if r.Type != "volume" || !r.matches(selector) {
+			continue
+		}
+		if m := volumeHrefPattern.FindStringSubmatch(r.Href); m != nil {
+			volumes = append(volumes, volume{DatacenterId: m[1], VolumeId: m[2]})
+		}
+	}
+	return volumes
+}
+
+// scheduleId identifies the schedule of a selector by the first 16 hex digits of the SHA-256 of its labels, as JSON
+// with sorted keys.
+func scheduleId(selector map[string]string) string {
+	b, _ := json.Marshal(selector)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// selectSnapshots returns the Snapshots taken by the schedule of the selector. Snapshots without the labels
+// of a schedule are never selected, so they are never pruned. Neither are the Snapshots of other schedules, even
+// of a selector with more labels, such as backup=daily,tier=gold for backup=daily: they have their own retention.
+func selectSnapshots(resources []labelled, selector map[string]string) []scheduledSnapshot {
+	id := scheduleId(selector)
+	var snapshots []scheduledSnapshot
+	for _, r := range resources {
+		if r.Type != "snapshot" || r.Labels[LabelSchedule] != id || r.Labels[LabelVolume] == "" {
+			continue
+		}
+		t, err := time.Parse(timeLayout, r.Labels[LabelTime])
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, scheduledSnapshot{SnapshotId: r.Id, VolumeId: r.Labels[LabelVolume], Time: t})
+	}
+	return snapshots
+}
@@ -0,0 +1,293 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/ionosctl/v6/internal/request"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/confirm"
+	cloudapiv6 "github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagSelector    = "selector"
+	FlagKeepDaily   = "keep-daily"
+	FlagKeepWeekly  = "keep-weekly"
+	FlagKeepMonthly = "keep-monthly"
+	FlagDryRun      = "dry-run"
+)
+
+var allScheduleCols = []table.Column{
+	{Name: "Action", JSONPath: "action", Default: true},
+	{Name: "VolumeId", JSONPath: "volumeId", Default: true},
+	{Name: "SnapshotId", JSONPath: "snapshotId", Default: true},
+	{Name: "Time", JSONPath: "time", Default: true},
+	{Name: "Retention", JSONPath: "retention", Default: true},
+}
+
+// scheduleRow is a Snapshot of the schedule, and what the run did with it.
+type scheduleRow struct {
+	// Action is created, kept or deleted, or create, keep or delete with --dry-run.
+	Action     string `json:"action"`
+	VolumeId   string `json:"volumeId"`
+	SnapshotId string `json:"snapshotId,omitempty"`
+	Time       string `json:"time"`
+	// Retention lists the policies that keep the Snapshot.
+	Retention string `json:"retention,omitempty"`
+}
+
+var dryRunActions = map[string]string{"created": "create", "kept": "keep", "deleted": "delete"}
+
+// now is replaced in tests.
+var now = time.Now
+
+func ScheduleRunCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "snapshot",
+		Resource:  "schedule",
+		Verb:      "run",
+		Aliases:   []string{"r"},
+		ShortDesc: "Take Snapshots of the Volumes with the labels of a selector, and prune expired Snapshots",
+		LongDesc: `Use this command to take a Snapshot of every Volume with the labels of ` + "`--selector`" + `, and to delete the Snapshots that the retention policy no longer keeps. It is designed to be called periodically, e.g. from cron.
+
+Each Snapshot is labelled with the labels of the selector, the ID of the schedule (` + "`" + LabelSchedule + "`" + `), a hash of the selector, the ID of its Volume (` + "`" + LabelVolume + "`" + `) and the time it was taken (` + "`" + LabelTime + "`" + `). Only Snapshots with the ID of the schedule of the same selector are pruned, so Snapshots taken in other ways, or by schedules of other selectors such as ` + "`backup=daily,tier=gold`" + ` for ` + "`backup=daily`" + `, are never deleted.
+
+For each Volume, the newest Snapshot of each of the last ` + "`--keep-daily`" + ` days, ` + "`--keep-weekly`" + ` ISO weeks and ` + "`--keep-monthly`" + ` months is kept, in UTC. The other Snapshots expired and are deleted after confirmation. Use ` + "`--force`" + ` to delete them without confirmation, e.g. from cron, or ` + "`--dry-run`" + ` to only print what would be done.
+
+Required values to run command:
+
+* Selector
+* At least one of Keep Daily, Keep Weekly, Keep Monthly`,
+		Example: `ionosctl compute snapshot schedule run --selector backup=daily --keep-daily 7 --keep-weekly 4 --force
+ionosctl compute snapshot schedule run --selector backup=daily --keep-daily 7 --keep-weekly 4 --dry-run`,
+		PreCmdRun:  PreRunScheduleRun,
+		CmdRun:     RunScheduleRun,
+		InitClient: true,
+	})
+	cmd.AddStringSliceFlag(FlagSelector, "", nil, "Take Snapshots of the Volumes with these labels, e.g. --selector backup=daily", core.RequiredFlagOption())
+	cmd.AddIntFlag(FlagKeepDaily, "", 0, "Number of days to keep the newest Snapshot of")
+	cmd.AddIntFlag(FlagKeepWeekly, "", 0, "Number of ISO weeks to keep the newest Snapshot of")
+	cmd.AddIntFlag(FlagKeepMonthly, "", 0, "Number of months to keep the newest Snapshot of")
+	cmd.AddSetFlag(cloudapiv6.ArgLicenceType, "", "LINUX", constants.EnumLicenceType, "Licence Type of the Snapshots")
+	cmd.AddBoolFlag(FlagDryRun, "", false, "Only print the Snapshots that would be taken and deleted")
+	cmd.AddColsFlag(allScheduleCols)
+
+	return cmd
+}
+
+func PreRunScheduleRun(c *core.PreCommandConfig) error {
+	if err := core.CheckRequiredFlags(c.Command, c.NS, FlagSelector); err != nil {
+		return err
+	}
+	if _, err := parseSelector(viper.GetStringSlice(core.GetFlagName(c.NS, FlagSelector))); err != nil {
+		return err
+	}
+	r := flagRetention(c.NS)
+	if r.Daily < 0 || r.Weekly < 0 || r.Monthly < 0 {
+		return fmt.Errorf("--%s, --%s and --%s cannot be negative", FlagKeepDaily, FlagKeepWeekly, FlagKeepMonthly)
+	}
+	if r.Daily+r.Weekly+r.Monthly == 0 {
+		return fmt.Errorf("at least one of --%s, --%s, --%s must be set, or all Snapshots would expire",
+			FlagKeepDaily, FlagKeepWeekly, FlagKeepMonthly)
+	}
+	return nil
+}
+
+func flagRetention(ns string) retention {
+	return retention{
+		Daily:   viper.GetInt(core.GetFlagName(ns, FlagKeepDaily)),
+		Weekly:  viper.GetInt(core.GetFlagName(ns, FlagKeepWeekly)),
+		Monthly: viper.GetInt(core.GetFlagName(ns, FlagKeepMonthly)),
+	}
+}
+
+func RunScheduleRun(c *core.CommandConfig) error {
+	selector, err := parseSelector(viper.GetStringSlice(core.GetFlagName(c.NS, FlagSelector)))
+	if err != nil {
+		return err
+	}
+	dryRun := viper.GetBool(core.GetFlagName(c.NS, FlagDryRun))
+
+	labels, resp, err := c.CloudApiV6Services.Labels().List()
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	if err != nil {
+		return fmt.Errorf("failed listing labels: %w", err)
+	}
+	var items []ionoscloud.Label
+	if labels.GetItems() != nil {
+		items = *labels.GetItems()
+	}
+	resources := labelledResources(items)
+	snapshots := selectSnapshots(resources, selector)
+
+	var errs []error
+	created := map[string]bool{}
+	taken := now().UTC().Truncate(time.Second)
+	for _, v := range selectVolumes(resources, selector) {
+		s := scheduledSnapshot{VolumeId: v.VolumeId, Time: taken}
+		if !dryRun {
+			if s.SnapshotId, err = takeSnapshot(c, v, selector, taken); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+		created[s.SnapshotId+"/"+s.VolumeId] = true
+		snapshots = append(snapshots, s)
+	}
+
+	rows, expired := plan(snapshots, flagRetention(c.NS), created, dryRun)
+
+	if len(expired) > 0 && !dryRun {
+		if !confirm.FAsk(c.Command.Command.InOrStdin(), fmt.Sprintf("delete %d expired snapshots", len(expired)), viper.GetBool(constants.ArgForce)) {
+			return errors.Join(append(errs, fmt.Errorf(confirm.UserDenied))...)
+		}
+		for _, id := range expired {
+			resp, err := c.CloudApiV6Services.Snapshots().Delete(id)
+			if resp != nil && request.GetId(resp) != "" {
+				c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed deleting Snapshot %s: %w", id, err))
+			}
+		}
+	}
+
+	if err := c.Printer(allScheduleCols).Print(rows); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// takeSnapshot takes a Snapshot of the Volume, waits for it, and labels it with the selector, the schedule, the
+// Volume and the time.
+func takeSnapshot(c *core.CommandConfig, v volume, selector map[string]string, taken time.Time) (string, error) {
+	name := fmt.Sprintf("%s-%s", v.VolumeId, taken.Format(timeLayout))
+	description := fmt.Sprintf("Taken by ionosctl snapshot schedule run --selector %s", formatSelector(selector))
+	c.Verbose("Taking Snapshot %s of Volume %s", name, v.VolumeId)
+
+	s, resp, err := c.CloudApiV6Services.Snapshots().Create(v.DatacenterId, v.VolumeId, name, description,
+		viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgLicenceType)), false)
+	if resp != nil && request.GetId(resp) != "" {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed taking Snapshot of Volume %s: %w", v.VolumeId, err)
+	}
+	if s.Id == nil {
+		return "", fmt.Errorf("failed taking Snapshot of Volume %s: no Snapshot ID returned", v.VolumeId)
+	}
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err = c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return "", fmt.Errorf("failed waiting for Snapshot %s: %w", *s.Id, err)
+		}
+	}
+
+	snapshotLabels := map[string]string{
+		LabelVolume: v.VolumeId, LabelTime: taken.Format(timeLayout), LabelSchedule: scheduleId(selector),
+	}
+	for k, v := range selector {
+		snapshotLabels[k] = v
+	}
+	keys := make([]string, 0, len(snapshotLabels))
+	for k := range snapshotLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, resp, err := c.CloudApiV6Services.Labels().SnapshotCreate(*s.Id, k, snapshotLabels[k])
+		if resp != nil {
+			c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed labelling Snapshot %s with %s: %w", *s.Id, k, err)
+			// Without its labels, later runs would never find the Snapshot to expire it.
+			if derr := deleteSnapshot(c, *s.Id); derr != nil {
+				return "", errors.Join(err, fmt.Errorf("failed deleting the unlabelled Snapshot %s, delete it manually: %w", *s.Id, derr))
+			}
+			return "", errors.Join(err, fmt.Errorf("deleted the unlabelled Snapshot %s", *s.Id))
+		}
+	}
+	return *s.Id, nil
+}
+
+// deleteSnapshot deletes the Snapshot and waits for it.
+func deleteSnapshot(c *core.CommandConfig, id string) error {
+	c.Verbose("Deleting Snapshot %s", id)
+	resp, err := c.CloudApiV6Services.Snapshots().Delete(id)
+	if resp != nil && request.GetId(resp) != "" {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if err != nil {
+		return err
+	}
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err = c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatSelector(selector map[string]string) string {
+	labels := make([]string, 0, len(selector))
+	for k, v := range selector {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+// plan applies the retention to the Snapshots of each Volume. It returns a row per Snapshot, sorted by Volume
+// and newest first, and the IDs of the expired Snapshots.
+func plan(snapshots []scheduledSnapshot, r retention, created map[string]bool, dryRun bool) ([]scheduleRow, []string) {
+	byVolume := map[string][]scheduledSnapshot{}
+	var volumes []string
+	for _, s := range snapshots {
+		if _, ok := byVolume[s.VolumeId]; !ok {
+			volumes = append(volumes, s.VolumeId)
+		}
+		byVolume[s.VolumeId] = append(byVolume[s.VolumeId], s)
+	}
+	sort.Strings(volumes)
+
+	rows := []scheduleRow{}
+	var expired []string
+	for _, volumeId := range volumes {
+		volumeSnapshots := byVolume[volumeId]
+		sort.SliceStable(volumeSnapshots, func(i, j int) bool { return volumeSnapshots[i].Time.After(volumeSnapshots[j].Time) })
+		times := make([]time.Time, len(volumeSnapshots))
+		for i, s := range volumeSnapshots {
+			times[i] = s.Time
+		}
+		kept := r.retain(times)
+
+		for i, s := range volumeSnapshots {
+			row := scheduleRow{VolumeId: volumeId, SnapshotId: s.SnapshotId, Time: s.Time.Format(time.RFC3339), Retention: strings.Join(kept[i], ",")}
+			switch {
+			case created[s.SnapshotId+"/"+s.VolumeId]:
+				row.Action = "created"
+			case len(kept[i]) > 0:
+				row.Action = "kept"
+			default:
+				row.Action = "deleted"
+				expired = append(expired, s.SnapshotId)
+			}
+			if dryRun {
+				row.Action = dryRunActions[row.Action]
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, expired
+}
@@ -0,0 +1,23 @@
+package schedule
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/sdk-go-bundle/shared/fileconfiguration"
+	"github.com/spf13/cobra"
+)
+
+func ScheduleCmd() *core.Command {
+	scheduleCmd := &core.Command{
+		Command: &cobra.Command{
+			Use:              "schedule",
+			Aliases:          []string{"sched"},
+			Short:            "Snapshot Schedule Operations",
+			Long:             "The sub-commands of `ionosctl compute snapshot schedule` allow you to take Snapshots of labelled Volumes and to prune them by a retention policy.",
+			TraverseChildren: true,
+		},
+	}
+
+	scheduleCmd.AddCommand(ScheduleRunCmd())
+
+	return core.WithConfigOverride(scheduleCmd, []string{fileconfiguration.Cloud, "compute"}, "")
+}
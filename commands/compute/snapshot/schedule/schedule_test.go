@@ -0,0 +1,218 @@
+package schedule
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/ionosctl/v6/services/cloudapi-v6/resources"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRetain(t *testing.T) {
+	times := []time.Time{
+		at("2026-10-19T02:00:00Z"), // Monday, week 43
+		at("2026-10-18T14:00:00Z"), // Sunday, week 42
+		at("2026-10-18T02:00:00Z"),
+		at("2026-10-17T02:00:00Z"),
+		at("2026-10-11T02:00:00Z"), // Sunday, week 41
+		at("2026-10-04T02:00:00Z"), // Sunday, week 40
+		at("2026-09-27T02:00:00Z"), // Sunday, week 39
+	}
+
+	kept := retention{Daily: 2, Weekly: 3}.retain(times)
+	assert.Equal(t, map[int][]string{
+		0: {"daily", "weekly"},
+		1: {"daily", "weekly"},
+		4: {"weekly"},
+	}, kept)
+
+	kept = retention{Monthly: 2}.retain(times)
+	assert.Equal(t, map[int][]string{0: {"monthly"}, 6: {"monthly"}}, kept)
+
+	assert.Empty(t, retention{}.retain(times))
+}
+
+func testLabel(resourceType, id, href, key, value string) ionoscloud.Label {
+	return ionoscloud.Label{Properties: &ionoscloud.LabelProperties{
+		ResourceType: pointer.From(resourceType), ResourceId: pointer.From(id), ResourceHref: pointer.From(href),
+		Key: pointer.From(key), Value: pointer.From(value),
+	}}
+}
+
+var testLabels = []ionoscloud.Label{
+	testLabel("volume", "vol-1", "/datacenters/dc-1/volumes/vol-1", "backup", "daily"),
+	testLabel("volume", "vol-2", "/datacenters/dc-1/volumes/vol-2", "backup", "weekly"),
+	testLabel("server", "srv-1", "/datacenters/dc-1/servers/srv-1", "backup", "daily"),
+	testLabel("snapshot", "snap-1", "/snapshots/snap-1", "backup", "daily"),
+	testLabel("snapshot", "snap-1", "/snapshots/snap-1", LabelSchedule, "16328fed25cd87fc"),
+	testLabel("snapshot", "snap-1", "/snapshots/snap-1", LabelVolume, "vol-1"),
+	testLabel("snapshot", "snap-1", "/snapshots/snap-1", LabelTime, "20261018-020000"),
+	testLabel("snapshot", "snap-2", "/snapshots/snap-2", "backup", "daily"),
+	testLabel("snapshot", "snap-2", "/snapshots/snap-2", LabelSchedule, "16328fed25cd87fc"),
+	testLabel("snapshot", "snap-2", "/snapshots/snap-2", LabelVolume, "vol-1"),
+	testLabel("snapshot", "snap-2", "/snapshots/snap-2", LabelTime, "20261017-020000"),
+	// Not taken by a schedule: no schedule and time labels.
+	testLabel("snapshot", "manual", "/snapshots/manual", "backup", "daily"),
+	testLabel("snapshot", "manual", "/snapshots/manual", LabelVolume, "vol-1"),
+	// Taken by the schedule of backup=daily,tier=gold, which has its own retention.
+	testLabel("snapshot", "gold", "/snapshots/gold", "backup", "daily"),
+	testLabel("snapshot", "gold", "/snapshots/gold", "tier", "gold"),
+	testLabel("snapshot", "gold", "/snapshots/gold", LabelSchedule, "4fd354c2a3b7d722"),
+	testLabel("snapshot", "gold", "/snapshots/gold", LabelVolume, "vol-1"),
+	testLabel("snapshot", "gold", "/snapshots/gold", LabelTime, "20261001-020000"),
+}
+
+func TestSelect(t *testing.T) {
+	selector, err := parseSelector([]string{"backup=daily"})
+	assert.NoError(t, err)
+
+	resources := labelledResources(testLabels)
+	assert.Equal(t, []volume{{DatacenterId: "dc-1", VolumeId: "vol-1"}}, selectVolumes(resources, selector))
+	assert.Equal(t, []scheduledSnapshot{
+		{SnapshotId: "snap-1", VolumeId: "vol-1", Time: at("2026-10-18T02:00:00Z")},
+		{SnapshotId: "snap-2", VolumeId: "vol-1", Time: at("2026-10-17T02:00:00Z")},
+	}, selectSnapshots(resources, selector))
+
+	gold, err := parseSelector([]string{"backup=daily", "tier=gold"})
+	assert.NoError(t, err)
+	assert.Equal(t, []scheduledSnapshot{
+		{SnapshotId: "gold", VolumeId: "vol-1", Time: at("2026-10-01T02:00:00Z")},
+	}, selectSnapshots(resources, gold))
+
+	_, err = parseSelector([]string{"backup"})
+	assert.Error(t, err)
+}
+
+func TestScheduleId(t *testing.T) {
+	assert.Equal(t, "16328fed25cd87fc", scheduleId(map[string]string{"backup": "daily"}))
+	assert.Equal(t, scheduleId(map[string]string{"tier": "gold", "backup": "daily"}), scheduleId(map[string]string{"backup": "daily", "tier": "gold"}))
+	assert.NotEqual(t, scheduleId(map[string]string{"a": "b,c=d"}), scheduleId(map[string]string{"a": "b", "c": "d"}))
+}
+
+func TestPlan(t *testing.T) {
+	snapshots := []scheduledSnapshot{
+		{SnapshotId: "snap-1", VolumeId: "vol-1", Time: at("2026-10-18T02:00:00Z")},
+		{SnapshotId: "snap-2", VolumeId: "vol-1", Time: at("2026-10-17T02:00:00Z")},
+		{SnapshotId: "snap-3", VolumeId: "vol-1", Time: at("2026-10-19T02:00:00Z")},
+	}
+	created := map[string]bool{"snap-3/vol-1": true}
+
+	rows, expired := plan(snapshots, retention{Daily: 2}, created, false)
+	var actions []string
+	for _, r := range rows {
+		actions = append(actions, r.Action+" "+r.SnapshotId+" "+r.Retention)
+	}
+	assert.Equal(t, []string{"created snap-3 daily", "kept snap-1 daily", "deleted snap-2 "}, actions)
+	assert.Equal(t, []string{"snap-2"}, expired)
+
+	rows, _ = plan(snapshots, retention{Daily: 2}, created, true)
+	assert.Equal(t, "delete", rows[2].Action)
+}
+
+func TestRunScheduleRun(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	now = func() time.Time { return at("2026-10-19T02:00:00Z") }
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(constants.ArgForce, true)
+		viper.Set(core.GetFlagName(cfg.NS, FlagSelector), []string{"backup=daily"})
+		viper.Set(core.GetFlagName(cfg.NS, FlagKeepDaily), 2)
+		viper.Set(core.GetFlagName(cfg.NS, "licence-type"), "LINUX")
+
+		rm.CloudApiV6Mocks.Label.EXPECT().List().Return(resources.Labels{Labels: ionoscloud.Labels{Items: &testLabels}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Snapshot.EXPECT().Create("dc-1", "vol-1", "vol-1-20261019-020000", gomock.Any(), "LINUX", false).
+			Return(&resources.Snapshot{Snapshot: ionoscloud.Snapshot{Id: pointer.From("snap-3")}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Label.EXPECT().SnapshotCreate("snap-3", "backup", "daily").Return(&resources.LabelResource{}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Label.EXPECT().SnapshotCreate("snap-3", LabelSchedule, "16328fed25cd87fc").Return(&resources.LabelResource{}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Label.EXPECT().SnapshotCreate("snap-3", LabelTime, "20261019-020000").Return(&resources.LabelResource{}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Label.EXPECT().SnapshotCreate("snap-3", LabelVolume, "vol-1").Return(&resources.LabelResource{}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Snapshot.EXPECT().Delete("snap-2").Return(&testutil.TestResponse, nil)
+
+		err := RunScheduleRun(cfg)
+		assert.NoError(t, err)
+	})
+}
+
+func TestRunScheduleRunLabelErr(t *testing.T) {
+	defer func(n func() time.Time) { now = n }(now)
+	now = func() time.Time { return at("2026-10-19T02:00:00Z") }
+
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(constants.ArgForce, true)
+		viper.Set(core.GetFlagName(cfg.NS, FlagSelector), []string{"backup=daily"})
+		viper.Set(core.GetFlagName(cfg.NS, FlagKeepDaily), 3)
+
+		rm.CloudApiV6Mocks.Label.EXPECT().List().Return(resources.Labels{Labels: ionoscloud.Labels{Items: &testLabels}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Snapshot.EXPECT().Create("dc-1", "vol-1", "vol-1-20261019-020000", gomock.Any(), "", false).
+			Return(&resources.Snapshot{Snapshot: ionoscloud.Snapshot{Id: pointer.From("snap-3")}}, &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Label.EXPECT().SnapshotCreate("snap-3", "backup", "daily").Return(nil, nil, errors.New("label limit reached"))
+		rm.CloudApiV6Mocks.Snapshot.EXPECT().Delete("snap-3").Return(&testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil).Times(2)
+
+		err := RunScheduleRun(cfg)
+		assert.EqualError(t, err, "failed labelling Snapshot snap-3 with backup: label limit reached\ndeleted the unlabelled Snapshot snap-3")
+	})
+}
+
+func TestRunScheduleRunDryRun(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(constants.ArgQuiet, false)
+		viper.Set(core.GetFlagName(cfg.NS, FlagSelector), []string{"backup=daily"})
+		viper.Set(core.GetFlagName(cfg.NS, FlagKeepDaily), 1)
+		viper.Set(core.GetFlagName(cfg.NS, FlagDryRun), true)
+
+		rm.CloudApiV6Mocks.Label.EXPECT().List().Return(resources.Labels{Labels: ionoscloud.Labels{Items: &testLabels}}, &testutil.TestResponse, nil)
+
+		err := RunScheduleRun(cfg)
+		assert.NoError(t, err)
+	})
+}
+
+func TestPreRunScheduleRunErr(t *testing.T) {
+	for name, keep := range map[string]int{"no retention": 0, "negative retention": -1} {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			w := bufio.NewWriter(&b)
+			core.PreCmdConfigTest(t, w, func(cfg *core.PreCommandConfig) {
+				viper.Reset()
+				viper.Set(core.GetFlagName(cfg.NS, FlagSelector), []string{"backup=daily"})
+				viper.Set(core.GetFlagName(cfg.NS, FlagKeepDaily), keep)
+				err := PreRunScheduleRun(cfg)
+				assert.Error(t, err)
+			})
+		})
+	}
+}
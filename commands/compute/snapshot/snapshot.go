@@ -1,6 +1,7 @@
 package snapshot
 
 import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/compute/snapshot/schedule"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
 	"github.com/ionos-cloud/sdk-go-bundle/shared/fileconfiguration"
@@ -21,7 +22,7 @@ func SnapshotCmd() *core.Command {
 			Use:              "snapshot",
 			Aliases:          []string{"ss", "snap"},
 			Short:            "Snapshot Operations",
-			Long:             "The sub-commands of `ionosctl compute snapshot` allow you to see information, to create, update, delete Snapshots.",
+			Long:             "The sub-commands of `ionosctl compute snapshot` allow you to see information, to create, update, delete Snapshots, and to take them on a schedule with a retention policy.",
 			TraverseChildren: true,
 		},
 	}
@@ -34,5 +35,7 @@ func SnapshotCmd() *core.Command {
 	snapshotCmd.AddCommand(SnapshotRestoreCmd())
 	snapshotCmd.AddCommand(SnapshotDeleteCmd())
 
+	snapshotCmd.AddCommand(schedule.ScheduleCmd())
+
 	return core.WithConfigOverride(snapshotCmd, []string{fileconfiguration.Cloud, "compute"}, "")
 }
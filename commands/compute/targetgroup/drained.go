@@ -0,0 +1,74 @@
+package targetgroup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/config"
+	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
+)
+
+// drainedTargetsFileName is the file next to the config file that keeps the weights of drained Targets until they
+// are restored. Target Groups have no labels to keep them in.
+const drainedTargetsFileName = "drained-targets.json"
+
+// drainedTargets maps drainedTargetKey of a drained Target to its weight before it was drained.
+type drainedTargets map[string]int32
+
+func drainedTargetsPath() string {
+	return filepath.Join(filepath.Dir(config.GetConfigFilePath()), drainedTargetsFileName)
+}
+
+func drainedTargetKey(targetGroupId string, t ionoscloud.TargetGroupTarget) string {
+	var ip string
+	var port int32
+	if t.Ip != nil {
+		ip = *t.Ip
+	}
+	if t.Port != nil {
+		port = *t.Port
+	}
+	return fmt.Sprintf("%s/%s:%d", targetGroupId, ip, port)
+}
+
+// readDrainedTargets reads the weights of the drained Targets. Without a file, no Targets are drained.
+func readDrainedTargets() (drainedTargets, error) {
+	drained := drainedTargets{}
+	b, err := os.ReadFile(drainedTargetsPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return drained, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed reading the weights of drained Targets: %w", err)
+	}
+	if err = json.Unmarshal(b, &drained); err != nil {
+		return nil, fmt.Errorf("failed reading the weights of drained Targets from %s: %w", drainedTargetsPath(), err)
+	}
+	return drained, nil
+}
+
+// write writes the weights of the drained Targets, or removes the file if no Targets are drained.
+func (d drainedTargets) write() error {
+	path := drainedTargetsPath()
+	if len(d) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed removing %s: %w", path, err)
+		}
+		return nil
+	}
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed writing the weights of drained Targets: %w", err)
+	}
+	if err = os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed writing the weights of drained Targets: %w", err)
+	}
+	return nil
+}
@@ -3,7 +3,11 @@ package targetgroup
 import (
 	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -272,3 +276,218 @@ func getTargetGroupTargetsRemove(c *core.CommandConfig, targetsOld *[]ionoscloud
 	}
 	return &targetItems, nil
 }
+
+func PreRunTargetGroupIdTargetIp(c *core.PreCommandConfig) error {
+	return core.CheckRequiredFlags(c.Command, c.NS, cloudapiv6.ArgTargetGroupId, cloudapiv6.ArgIp)
+}
+
+// Defaults of the health check of Target Groups: the interval in milliseconds, and the retries.
+const (
+	defaultCheckInterval = 2000
+	defaultRetries       = 3
+)
+
+// healthCheckTime returns the time the health check of the Target Group takes to check a Target, and to retry: its
+// interval times its retries plus one.
+func healthCheckTime(targetGroup *resources.TargetGroup) time.Duration {
+	interval, retries := int32(defaultCheckInterval), int32(defaultRetries)
+	if targetGroup.Properties != nil && targetGroup.Properties.HealthCheck != nil {
+		if hc := targetGroup.Properties.HealthCheck; hc.CheckInterval != nil {
+			interval = *hc.CheckInterval
+		}
+		if hc := targetGroup.Properties.HealthCheck; hc.Retries != nil {
+			retries = *hc.Retries
+		}
+	}
+	return time.Duration(interval) * time.Millisecond * time.Duration(retries+1)
+}
+
+func RunTargetGroupTargetDrain(c *core.CommandConfig) error {
+	targetGroupId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgTargetGroupId))
+	drained, err := readDrainedTargets()
+	if err != nil {
+		return err
+	}
+	targetGroup, err := getTargetGroup(c)
+	if err != nil {
+		return err
+	}
+	previous, targets, err := setTargetGroupTargetsWeight(c, targetGroup, func(ionoscloud.TargetGroupTarget) (int32, error) {
+		return 0, nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range previous {
+		var port, weight int32
+		if t.Port != nil {
+			port = *t.Port
+		}
+		if t.Weight != nil {
+			weight = *t.Weight
+		}
+		// Draining a drained Target again keeps the weight it had before
+		if weight > 0 {
+			drained[drainedTargetKey(targetGroupId, t)] = weight
+		}
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(), "Target %s:%d drained, its previous weight was %d\n", *t.Ip, port, weight)
+	}
+	if err = drained.write(); err != nil {
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(), "Warning: %v. Pass the previous weights to targetgroup target restore with --%s\n", err, cloudapiv6.ArgWeight)
+	}
+
+	drainTime := viper.GetDuration(core.GetFlagName(c.NS, FlagDrainTime))
+	if !viper.IsSet(core.GetFlagName(c.NS, FlagDrainTime)) {
+		drainTime = healthCheckTime(targetGroup)
+	}
+	if drainTime > 0 {
+		ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		c.Verbose("Waiting %v for the connections of the Targets to finish", drainTime)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for the connections of the Targets to finish, the Targets stay drained: %w", ctx.Err())
+		case <-time.After(drainTime):
+		}
+	}
+
+	return c.Printer(allTargetGroupTargetCols).Print(targets)
+}
+
+func RunTargetGroupTargetRestore(c *core.CommandConfig) error {
+	targetGroupId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgTargetGroupId))
+	weightSet := viper.IsSet(core.GetFlagName(c.NS, cloudapiv6.ArgWeight))
+	weight := viper.GetInt32(core.GetFlagName(c.NS, cloudapiv6.ArgWeight))
+	if weightSet && (weight < 1 || weight > 256) {
+		return fmt.Errorf("weight must be between 1 and 256")
+	}
+	drained, err := readDrainedTargets()
+	if err != nil {
+		return err
+	}
+	targetGroup, err := getTargetGroup(c)
+	if err != nil {
+		return err
+	}
+	previous, targets, err := setTargetGroupTargetsWeight(c, targetGroup, func(t ionoscloud.TargetGroupTarget) (int32, error) {
+		if weightSet {
+			return weight, nil
+		}
+		if w, ok := drained[drainedTargetKey(targetGroupId, t)]; ok {
+			return w, nil
+		}
+		var port int32
+		if t.Port != nil {
+			port = *t.Port
+		}
+		return 0, fmt.Errorf("the weight of Target %s:%d before it was drained is unknown, set it with --%s", *t.Ip, port, cloudapiv6.ArgWeight)
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range previous {
+		delete(drained, drainedTargetKey(targetGroupId, t))
+	}
+	if err = drained.write(); err != nil {
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(), "Warning: %v\n", err)
+	}
+
+	return c.Printer(allTargetGroupTargetCols).Print(targets)
+}
+
+func getTargetGroup(c *core.CommandConfig) (*resources.TargetGroup, error) {
+	targetGroupId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgTargetGroupId))
+	c.Verbose(constants.TargetGroupId, targetGroupId)
+	targetGroup, resp, err := c.CloudApiV6Services.TargetGroups().Get(targetGroupId)
+	if resp != nil {
+		c.Verbose(constants.MessageRequestTime, resp.RequestTime)
+	}
+	return targetGroup, err
+}
+
+// setTargetGroupTargetsWeight sets the weight of the Targets of the Target Group with the IP, and the port if it is
+// set, to weightOf them, and waits for the request. It returns the Targets with their previous and their new weight.
+func setTargetGroupTargetsWeight(c *core.CommandConfig, targetGroup *resources.TargetGroup, weightOf func(ionoscloud.TargetGroupTarget) (int32, error)) ([]ionoscloud.TargetGroupTarget, []ionoscloud.TargetGroupTarget, error) {
+	targetGroupId := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgTargetGroupId))
+	ip := viper.GetString(core.GetFlagName(c.NS, cloudapiv6.ArgIp))
+	port := viper.GetInt32(core.GetFlagName(c.NS, cloudapiv6.ArgPort))
+	c.Verbose("Target IP: %v", ip)
+
+	var targets []ionoscloud.TargetGroupTarget
+	if targetGroup.Properties != nil && targetGroup.Properties.Targets != nil {
+		targets = *targetGroup.Properties.Targets
+	}
+
+	previous, updated, err := withTargetsWeight(targets, ip, port, weightOf)
+	if err != nil {
+		return nil, nil, err
+	}
+	matching := matchingTargets(updated, ip, port)
+	changed := 0
+	for i, t := range previous {
+		if t.Weight == nil || *t.Weight != *matching[i].Weight {
+			changed++
+		}
+	}
+	if changed == 0 {
+		c.Verbose("The Targets already have these weights")
+		return previous, matching, nil
+	}
+
+	c.Verbose("Setting the weight of %d Targets", changed)
+	_, resp, err := c.CloudApiV6Services.TargetGroups().Update(targetGroupId, &resources.TargetGroupProperties{
+		TargetGroupProperties: ionoscloud.TargetGroupProperties{Targets: &updated},
+	})
+	if resp != nil {
+		c.Verbose(constants.MessageRequestInfo, request.GetId(resp), resp.RequestTime)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if path := request.GetRequestPath(resp); path != "" {
+		if _, err = c.CloudApiV6Services.Requests().Wait(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return previous, matching, nil
+}
+
+// withTargetsWeight returns the matching Targets, and a copy of all Targets with the weight of the matching Targets
+// set to weightOf them. Targets match if they have the IP, and the port if it is not 0.
+func withTargetsWeight(targets []ionoscloud.TargetGroupTarget, ip string, port int32, weightOf func(ionoscloud.TargetGroupTarget) (int32, error)) ([]ionoscloud.TargetGroupTarget, []ionoscloud.TargetGroupTarget, error) {
+	previous := matchingTargets(targets, ip, port)
+	if len(previous) == 0 {
+		if port != 0 {
+			return nil, nil, fmt.Errorf("no target with IP %s and port %d found", ip, port)
+		}
+		return nil, nil, fmt.Errorf("no target with IP %s found", ip)
+	}
+
+	updated := make([]ionoscloud.TargetGroupTarget, len(targets))
+	copy(updated, targets)
+	for i, t := range updated {
+		if targetMatches(t, ip, port) {
+			weight, err := weightOf(t)
+			if err != nil {
+				return nil, nil, err
+			}
+			updated[i].Weight = &weight
+		}
+	}
+	return previous, updated, nil
+}
+
+func matchingTargets(targets []ionoscloud.TargetGroupTarget, ip string, port int32) []ionoscloud.TargetGroupTarget {
+	var matching []ionoscloud.TargetGroupTarget
+	for _, t := range targets {
+		if targetMatches(t, ip, port) {
+			matching = append(matching, t)
+		}
+	}
+	return matching
+}
+
+func targetMatches(t ionoscloud.TargetGroupTarget, ip string, port int32) bool {
+	return t.Ip != nil && *t.Ip == ip && (port == 0 || t.Port != nil && *t.Port == port)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const FlagDrainTime = "drain-time"
+
 var allTargetGroupTargetCols = []table.Column{
 	{Name: "TargetIp", JSONPath: "ip", Default: true},
 	{Name: "TargetPort", JSONPath: "port", Default: true},
@@ -25,7 +27,7 @@ func TargetGroupTargetCmd() *core.Command {
 			Use:              "target",
 			Aliases:          []string{"t"},
 			Short:            "Target Group Target Operations",
-			Long:             "The sub-commands of `ionosctl compute targetgroup target` allow you to see information, to add, remove Targets from Target Groups.",
+			Long:             "The sub-commands of `ionosctl compute targetgroup target` allow you to see information, to add, remove Targets from Target Groups, and to drain and restore Targets.",
 			TraverseChildren: true,
 		},
 	}
@@ -111,5 +113,68 @@ Required values to run command:
 	remove.AddBoolFlag(cloudapiv6.ArgAll, cloudapiv6.ArgAllShort, false, "Delete all Target Group Targets")
 	remove.AddColsFlag(allTargetGroupTargetCols)
 
+	/*
+		Drain Command
+	*/
+	drain := core.NewCommand(context.TODO(), targetGroupTargetCmd, core.CommandBuilder{
+		Namespace: "targetgroup",
+		Resource:  "target",
+		Verb:      "drain",
+		Aliases:   []string{"d"},
+		ShortDesc: "Take a Target out of rotation",
+		LongDesc: `Use this command to take a Target out of load balancing before e.g. deploying to it. The weight of the Target is set to 0, so it receives no new connections but still accepts persistent connections, and the command waits for these connections to finish. By default, it waits for the health check interval of the Target Group times its retries plus one, or for ` + "`--drain-time`" + `. Without ` + "`--port`" + `, all Targets with the IP are drained.
+
+The previous weight is printed on stderr, and kept in ` + "`" + drainedTargetsFileName + "`" + ` next to the config file, so ` + "`targetgroup target restore`" + ` can put the Target back into rotation with it.
+
+Required values to run command:
+
+* Target Group Id
+* Target Ip`,
+		Example: `ionosctl compute targetgroup target drain --targetgroup-id TARGET_GROUP_ID --ip 10.0.0.5
+ionosctl compute targetgroup target drain --targetgroup-id TARGET_GROUP_ID --ip 10.0.0.5 --port 8080 --drain-time 2m`,
+		PreCmdRun:  PreRunTargetGroupIdTargetIp,
+		CmdRun:     RunTargetGroupTargetDrain,
+		InitClient: true,
+	})
+	drain.AddUUIDFlag(cloudapiv6.ArgTargetGroupId, cloudapiv6.ArgIdShort, "", cloudapiv6.TargetGroupId, core.RequiredFlagOption())
+	_ = drain.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgTargetGroupId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.TargetGroupIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	drain.AddIpFlag(cloudapiv6.ArgIp, "", nil, "IP of the balanced target VM", core.RequiredFlagOption())
+	drain.AddIntFlag(cloudapiv6.ArgPort, cloudapiv6.ArgPortShort, 0, "Port of the balanced target service. If not set, all Targets with the IP are drained")
+	drain.AddDurationFlag(FlagDrainTime, "", 0, "Time to wait for the persistent connections of the Target to finish, after its weight is set to 0. "+
+		"Defaults to the health check interval of the Target Group times its retries plus one")
+	drain.AddColsFlag(allTargetGroupTargetCols)
+
+	/*
+		Restore Command
+	*/
+	restore := core.NewCommand(context.TODO(), targetGroupTargetCmd, core.CommandBuilder{
+		Namespace: "targetgroup",
+		Resource:  "target",
+		Verb:      "restore",
+		Aliases:   []string{"undrain"},
+		ShortDesc: "Put a drained Target back into rotation",
+		LongDesc: `Use this command to put a Target that was taken out of rotation with ` + "`targetgroup target drain`" + ` back into load balancing, by setting its weight back to the weight it had before it was drained. That weight is kept by drain in ` + "`" + drainedTargetsFileName + "`" + ` next to the config file, so restore must use the same config directory. Otherwise, or to set another weight, use ` + "`--weight`" + `. Without ` + "`--port`" + `, all Targets with the IP are restored.
+
+Required values to run command:
+
+* Target Group Id
+* Target Ip`,
+		Example: `ionosctl compute targetgroup target restore --targetgroup-id TARGET_GROUP_ID --ip 10.0.0.5
+ionosctl compute targetgroup target restore --targetgroup-id TARGET_GROUP_ID --ip 10.0.0.5 --weight 10`,
+		PreCmdRun:  PreRunTargetGroupIdTargetIp,
+		CmdRun:     RunTargetGroupTargetRestore,
+		InitClient: true,
+	})
+	restore.AddUUIDFlag(cloudapiv6.ArgTargetGroupId, cloudapiv6.ArgIdShort, "", cloudapiv6.TargetGroupId, core.RequiredFlagOption())
+	_ = restore.Command.RegisterFlagCompletionFunc(cloudapiv6.ArgTargetGroupId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.TargetGroupIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	restore.AddIpFlag(cloudapiv6.ArgIp, "", nil, "IP of the balanced target VM", core.RequiredFlagOption())
+	restore.AddIntFlag(cloudapiv6.ArgPort, cloudapiv6.ArgPortShort, 0, "Port of the balanced target service. If not set, all Targets with the IP are restored")
+	restore.AddIntFlag(cloudapiv6.ArgWeight, cloudapiv6.ArgWeightShort, 0, "Weight of the Target; valid range is 1 to 256. Defaults to the weight of the Target before it was drained")
+	restore.AddColsFlag(allTargetGroupTargetCols)
+
 	return core.WithConfigOverride(targetGroupTargetCmd, []string{fileconfiguration.Cloud, "compute"}, "")
 }
@@ -3,8 +3,11 @@ package targetgroup
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/testutil"
 
@@ -452,3 +455,142 @@ func TestRunTargetGroupTargetRemoveAskForConfirmErr(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestWithTargetsWeight(t *testing.T) {
+	ip, other := "10.0.0.5", "10.0.0.6"
+	port8080, port9090, weight := int32(8080), int32(9090), int32(10)
+	targets := []ionoscloud.TargetGroupTarget{
+		{Ip: &ip, Port: &port8080, Weight: &weight},
+		{Ip: &ip, Port: &port9090, Weight: &weight},
+		{Ip: &other, Port: &port8080, Weight: &weight},
+	}
+
+	drain := func(ionoscloud.TargetGroupTarget) (int32, error) { return 0, nil }
+	previous, updated, err := withTargetsWeight(targets, ip, 0, drain)
+	assert.NoError(t, err)
+	assert.Len(t, previous, 2)
+	assert.Equal(t, int32(10), *previous[0].Weight)
+	assert.Equal(t, []int32{0, 0, 10}, []int32{*updated[0].Weight, *updated[1].Weight, *updated[2].Weight})
+	assert.Equal(t, int32(10), *targets[0].Weight)
+
+	previous, updated, err = withTargetsWeight(targets, ip, port9090, drain)
+	assert.NoError(t, err)
+	assert.Len(t, previous, 1)
+	assert.Equal(t, []int32{10, 0, 10}, []int32{*updated[0].Weight, *updated[1].Weight, *updated[2].Weight})
+
+	_, _, err = withTargetsWeight(targets, other, port9090, drain)
+	assert.EqualError(t, err, "no target with IP 10.0.0.6 and port 9090 found")
+
+	_, _, err = withTargetsWeight(targets, ip, 0, func(ionoscloud.TargetGroupTarget) (int32, error) { return 0, testTargetGroupTargetErr })
+	assert.ErrorIs(t, err, testTargetGroupTargetErr)
+}
+
+func TestHealthCheckTime(t *testing.T) {
+	assert.Equal(t, 8*time.Second, healthCheckTime(&resources.TargetGroup{}))
+	interval, retries := int32(5000), int32(1)
+	assert.Equal(t, 10*time.Second, healthCheckTime(&resources.TargetGroup{TargetGroup: ionoscloud.TargetGroup{
+		Properties: &ionoscloud.TargetGroupProperties{HealthCheck: &ionoscloud.TargetGroupHealthCheck{CheckInterval: &interval, Retries: &retries}},
+	}}))
+}
+
+func TestRunTargetGroupTargetDrainRestore(t *testing.T) {
+	configDir := t.TempDir()
+	port, weight, drained := int32(8080), int32(10), int32(0)
+	targetGroup := func(weight int32) *resources.TargetGroup {
+		return &resources.TargetGroup{TargetGroup: ionoscloud.TargetGroup{
+			Id: &testTargetGroupTargetVar,
+			Properties: &ionoscloud.TargetGroupProperties{Targets: &[]ionoscloud.TargetGroupTarget{
+				{Ip: &testTargetGroupTargetVar, Port: &port, Weight: &weight},
+			}},
+		}}
+	}
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgConfig, filepath.Join(configDir, "config.yaml"))
+		viper.Set(constants.ArgOutput, constants.DefaultOutputFormat)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgTargetGroupId), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgIp), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, FlagDrainTime), 0)
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Get(testTargetGroupTargetVar).Return(targetGroup(weight), nil, nil)
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Update(testTargetGroupTargetVar, &resources.TargetGroupProperties{
+			TargetGroupProperties: *targetGroup(drained).Properties,
+		}).Return(targetGroup(drained), &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil)
+		err := RunTargetGroupTargetDrain(cfg)
+		assert.NoError(t, err)
+
+		kept, err := readDrainedTargets()
+		assert.NoError(t, err)
+		assert.Equal(t, drainedTargets{testTargetGroupTargetVar + "/" + testTargetGroupTargetVar + ":8080": 10}, kept)
+
+		// Draining again keeps the weight before the first drain
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Get(testTargetGroupTargetVar).Return(targetGroup(drained), nil, nil)
+		err = RunTargetGroupTargetDrain(cfg)
+		assert.NoError(t, err)
+
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Get(testTargetGroupTargetVar).Return(targetGroup(drained), nil, nil)
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Update(testTargetGroupTargetVar, &resources.TargetGroupProperties{
+			TargetGroupProperties: *targetGroup(weight).Properties,
+		}).Return(targetGroup(weight), &testutil.TestResponse, nil)
+		rm.CloudApiV6Mocks.Request.EXPECT().Wait(gomock.Any()).Return(&testutil.TestResponse, nil)
+		err = RunTargetGroupTargetRestore(cfg)
+		assert.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(configDir, drainedTargetsFileName))
+	})
+}
+
+func TestRunTargetGroupTargetDrainInterrupted(t *testing.T) {
+	port, weight := int32(8080), int32(0)
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgConfig, filepath.Join(t.TempDir(), "config.yaml"))
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgTargetGroupId), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgIp), testTargetGroupTargetVar)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cfg.Context = ctx
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Get(testTargetGroupTargetVar).Return(&resources.TargetGroup{TargetGroup: ionoscloud.TargetGroup{
+			Properties: &ionoscloud.TargetGroupProperties{Targets: &[]ionoscloud.TargetGroupTarget{
+				{Ip: &testTargetGroupTargetVar, Port: &port, Weight: &weight},
+			}},
+		}}, nil, nil)
+		err := RunTargetGroupTargetDrain(cfg)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRunTargetGroupTargetRestoreUnknownWeightErr(t *testing.T) {
+	port, weight := int32(8080), int32(0)
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(constants.ArgConfig, filepath.Join(t.TempDir(), "config.yaml"))
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgTargetGroupId), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgIp), testTargetGroupTargetVar)
+		rm.CloudApiV6Mocks.TargetGroup.EXPECT().Get(testTargetGroupTargetVar).Return(&resources.TargetGroup{TargetGroup: ionoscloud.TargetGroup{
+			Properties: &ionoscloud.TargetGroupProperties{Targets: &[]ionoscloud.TargetGroupTarget{
+				{Ip: &testTargetGroupTargetVar, Port: &port, Weight: &weight},
+			}},
+		}}, nil, nil)
+		err := RunTargetGroupTargetRestore(cfg)
+		assert.EqualError(t, err, "the weight of Target test-targetgroup-target:8080 before it was drained is unknown, set it with --weight")
+	})
+}
+
+func TestRunTargetGroupTargetRestoreWeightErr(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+	core.CmdConfigTest(t, w, func(cfg *core.CommandConfig, rm *core.ResourcesMocksTest) {
+		viper.Reset()
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgTargetGroupId), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgIp), testTargetGroupTargetVar)
+		viper.Set(core.GetFlagName(cfg.NS, cloudapiv6.ArgWeight), 0)
+		err := RunTargetGroupTargetRestore(cfg)
+		assert.EqualError(t, err, "weight must be between 1 and 256")
+	})
+}
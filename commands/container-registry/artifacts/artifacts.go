@@ -38,6 +38,7 @@ func ArtifactsCmd() *core.Command {
 
 	cmd.AddCommand(ArtifactsListCmd())
 	cmd.AddCommand(ArtifactsGetCmd())
+	cmd.AddCommand(ArtifactsScanReportCmd())
 
 	return cmd
 }
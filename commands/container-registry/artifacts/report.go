@@ -0,0 +1,309 @@
+package artifacts
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/sarif"
+	"github.com/ionos-cloud/sdk-go-bundle/products/containerregistry/v2"
+)
+
+// Severities in ascending order. Vulnerabilities with an unknown severity rank lowest.
+var severities = []string{"LOW", "MEDIUM", "HIGH", "CRITICAL"}
+
+const (
+	statusFail    = "FAIL"
+	statusIgnored = "IGNORED"
+	statusPass    = "PASS"
+)
+
+func severityRank(s string) int {
+	for i, sev := range severities {
+		if strings.EqualFold(s, sev) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// ignoreRule is a line of an ignore file, in the format of .trivyignore:
+//
+//	CVE-2024-1234 exp:2026-12-31 # no fix available, not reachable
+type ignoreRule struct {
+	ID      string
+	Expires time.Time
+	Comment string
+	Line    int
+}
+
+func (r ignoreRule) expired(now time.Time) bool {
+	return !r.Expires.IsZero() && !now.Before(r.Expires)
+}
+
+// parseIgnoreFile reads ignore rules. Blank lines and lines starting with # are skipped.
+func parseIgnoreFile(r io.Reader) ([]ignoreRule, error) {
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line, comment, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		rule := ignoreRule{ID: fields[0], Comment: strings.TrimSpace(comment), Line: n}
+		for _, f := range fields[1:] {
+			date, ok := strings.CutPrefix(f, "exp:")
+			if !ok {
+				return nil, fmt.Errorf("line %d: unexpected %q, expected a vulnerability ID optionally followed by exp:YYYY-MM-DD", n, f)
+			}
+			exp, err := time.Parse(time.DateOnly, date)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid expiry date %q, expected YYYY-MM-DD", n, date)
+			}
+			// Rules are valid until the end of the expiry date, in UTC.
+			rule.Expires = exp.AddDate(0, 0, 1)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// finding is a vulnerability of the artifact with the outcome of the policy.
+type finding struct {
+	Id              string   `json:"Id"`
+	Severity        string   `json:"Severity"`
+	Score           float32  `json:"Score"`
+	Fixable         bool     `json:"Fixable"`
+	Packages        []string `json:"Packages"`
+	Status          string   `json:"Status"`
+	Reason          string   `json:"Reason,omitempty"`
+	Description     string   `json:"-"`
+	Recommendations string   `json:"-"`
+	URL             string   `json:"-"`
+}
+
+// scanReport aggregates the vulnerabilities of an artifact and applies the gating policy.
+type scanReport struct {
+	// Artifact is shown in reports, e.g. repository:tag.
+	Artifact string
+	Digest   string
+	// FailOn is the lowest severity which fails the report, empty to never fail.
+	FailOn   string
+	Findings []finding
+	// Expired are ignore rules which matched a vulnerability, but are no longer valid.
+	Expired []ignoreRule
+}
+
+func newScanReport(artifact, digest, failOn string, vulns []containerregistry.VulnerabilityRead, ignores []ignoreRule, now time.Time) *scanReport {
+	r := &scanReport{Artifact: artifact, Digest: digest, FailOn: strings.ToUpper(failOn), Findings: []finding{}}
+
+	rules := map[string]ignoreRule{}
+	for _, rule := range ignores {
+		rules[rule.ID] = rule
+	}
+
+	// seen maps the IDs of vulnerabilities to their findings.
+	seen := map[string]int{}
+	for _, v := range vulns {
+		// The same vulnerability is listed once per affected package by some data sources.
+		if i, ok := seen[v.Id]; ok {
+			for _, pkg := range v.Properties.Affects {
+				if name := pkg.Name + "@" + pkg.Version; !slices.Contains(r.Findings[i].Packages, name) {
+					r.Findings[i].Packages = append(r.Findings[i].Packages, name)
+				}
+			}
+			continue
+		}
+		seen[v.Id] = len(r.Findings)
+
+		p := v.Properties
+		f := finding{
+			Id:          v.Id,
+			Severity:    strings.ToUpper(p.Severity),
+			Score:       p.Score,
+			Fixable:     p.Fixable,
+			Description: p.Description,
+			Status:      statusPass,
+		}
+		if p.Recommendations != nil {
+			f.Recommendations = *p.Recommendations
+		}
+		if p.DataSource.Url != nil {
+			f.URL = *p.DataSource.Url
+		}
+		for _, pkg := range p.Affects {
+			f.Packages = append(f.Packages, pkg.Name+"@"+pkg.Version)
+		}
+
+		rule, ignored := rules[f.Id]
+		if ignored && rule.expired(now) {
+			r.Expired = append(r.Expired, rule)
+			ignored = false
+		}
+		switch {
+		case ignored:
+			f.Status = statusIgnored
+			f.Reason = rule.Comment
+			if !rule.Expires.IsZero() {
+				f.Reason = strings.TrimSpace(fmt.Sprintf("until %s %s", rule.Expires.AddDate(0, 0, -1).Format(time.DateOnly), f.Reason))
+			}
+		case r.fails(f.Severity):
+			f.Status = statusFail
+		}
+		r.Findings = append(r.Findings, f)
+	}
+
+	// Most severe first, like the vulnerability tables of the console.
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		a, b := r.Findings[i], r.Findings[j]
+		if severityRank(a.Severity) != severityRank(b.Severity) {
+			return severityRank(a.Severity) > severityRank(b.Severity)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Id < b.Id
+	})
+	return r
+}
+
+func (r *scanReport) fails(severity string) bool {
+	return r.FailOn != "" && severityRank(severity) >= severityRank(r.FailOn)
+}
+
+// Failures returns the number of findings which fail the report.
+func (r *scanReport) Failures() int {
+	return r.count(statusFail)
+}
+
+func (r *scanReport) count(status string) int {
+	n := 0
+	for _, f := range r.Findings {
+		if f.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *scanReport) sarifLevel(f finding) string {
+	switch {
+	case f.Status == statusFail:
+		return sarif.LevelError
+	case severityRank(f.Severity) >= severityRank("MEDIUM"):
+		return sarif.LevelWarning
+	}
+	return sarif.LevelNote
+}
+
+// WriteSARIF writes the report for code scanning tools. Ignored findings are included as suppressed results.
+func (r *scanReport) WriteSARIF(w io.Writer) error {
+	run := sarif.NewRun("ionosctl container-registry")
+
+	loc := sarif.Location{PhysicalLocation: &sarif.PhysicalLocation{
+		ArtifactLocation: sarif.ArtifactLocation{URI: r.Artifact},
+		Region:           sarif.Region{StartLine: 1},
+	}}
+
+	for _, f := range r.Findings {
+		rule := sarif.Rule{
+			ID:               f.Id,
+			ShortDescription: sarif.Message{Text: fmt.Sprintf("%s %s vulnerability", f.Id, f.Severity)},
+			FullDescription:  &sarif.Message{Text: f.Description},
+			HelpURI:          f.URL,
+			Properties: map[string]any{
+				"tags":              []string{"security", "vulnerability", f.Severity},
+				"security-severity": strconv.FormatFloat(float64(f.Score), 'f', 1, 32),
+			},
+			DefaultConfig: map[string]string{"level": r.sarifLevel(f)},
+		}
+		if f.Recommendations != "" {
+			rule.Help = &sarif.Message{Text: f.Recommendations}
+		}
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		result := sarif.Result{
+			RuleID: f.Id,
+			Level:  r.sarifLevel(f),
+			Message: sarif.Message{Text: fmt.Sprintf("%s (%s, score %.1f) in %s, affected packages: %s",
+				f.Id, f.Severity, f.Score, r.Artifact, strings.Join(f.Packages, ", "))},
+			Locations: []sarif.Location{loc},
+		}
+		if f.Status == statusIgnored {
+			result.Suppressions = []sarif.Suppression{{Kind: "external", Justification: f.Reason}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return sarif.Write(w, run)
+}
+
+type (
+	junitTestSuites struct {
+		XMLName xml.Name         `xml:"testsuites"`
+		Suites  []junitTestSuite `xml:"testsuite"`
+	}
+	junitTestSuite struct {
+		Name     string          `xml:"name,attr"`
+		Tests    int             `xml:"tests,attr"`
+		Failures int             `xml:"failures,attr"`
+		Skipped  int             `xml:"skipped,attr"`
+		Cases    []junitTestCase `xml:"testcase"`
+	}
+	junitTestCase struct {
+		ClassName string        `xml:"classname,attr"`
+		Name      string        `xml:"name,attr"`
+		Failure   *junitMessage `xml:"failure,omitempty"`
+		Skipped   *junitMessage `xml:"skipped,omitempty"`
+	}
+	junitMessage struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr,omitempty"`
+		Text    string `xml:",chardata"`
+	}
+)
+
+// WriteJUnit writes the report as JUnit XML, one test case per vulnerability. Failing
+// vulnerabilities are failures, ignored ones are skipped.
+func (r *scanReport) WriteJUnit(w io.Writer) error {
+	suite := junitTestSuite{
+		Name:     "vulnerabilities of " + r.Artifact,
+		Tests:    len(r.Findings),
+		Failures: r.count(statusFail),
+		Skipped:  r.count(statusIgnored),
+		Cases:    []junitTestCase{},
+	}
+	for _, f := range r.Findings {
+		tc := junitTestCase{ClassName: r.Artifact, Name: fmt.Sprintf("%s (%s)", f.Id, f.Severity)}
+		switch f.Status {
+		case statusFail:
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%s vulnerability in %s", f.Severity, strings.Join(f.Packages, ", ")),
+				Type:    f.Severity,
+				Text:    strings.TrimSpace(f.Description + "\n" + f.Recommendations),
+			}
+		case statusIgnored:
+			tc.Skipped = &junitMessage{Message: strings.TrimSpace("ignored " + f.Reason)}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
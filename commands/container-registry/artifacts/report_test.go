@@ -0,0 +1,142 @@
+package artifacts
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/sarif"
+	"github.com/ionos-cloud/sdk-go-bundle/products/containerregistry/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func vuln(id, severity string, score float32, pkgs ...string) containerregistry.VulnerabilityRead {
+	v := containerregistry.VulnerabilityRead{Id: id}
+	v.Properties.Severity = severity
+	v.Properties.Score = score
+	v.Properties.Description = "description of " + id
+	for _, p := range pkgs {
+		name, version, _ := strings.Cut(p, "@")
+		v.Properties.Affects = append(v.Properties.Affects, containerregistry.Purl{Name: name, Version: version})
+	}
+	return v
+}
+
+var testVulns = []containerregistry.VulnerabilityRead{
+	vuln("CVE-2024-0001", "low", 2.1, "zlib@1.2"),
+	vuln("CVE-2024-0002", "critical", 9.8, "openssl@3.0.1"),
+	vuln("CVE-2024-0003", "high", 7.5, "curl@8.0"),
+	vuln("CVE-2024-0002", "critical", 9.8, "libssl@3.0.1"),
+	vuln("CVE-2024-0004", "high", 8.1, "glibc@2.36"),
+}
+
+func TestParseIgnoreFile(t *testing.T) {
+	rules, err := parseIgnoreFile(strings.NewReader("# accepted risks\n\nCVE-2024-0003 exp:2026-03-31 # not reachable\nGHSA-xxxx-yyyy\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []ignoreRule{
+		{ID: "CVE-2024-0003", Expires: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), Comment: "not reachable", Line: 3},
+		{ID: "GHSA-xxxx-yyyy", Line: 4},
+	}, rules)
+
+	assert.False(t, rules[0].expired(time.Date(2026, 3, 31, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, rules[0].expired(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, rules[1].expired(time.Now()))
+
+	_, err = parseIgnoreFile(strings.NewReader("CVE-1\nCVE-2 until:2026-01-01\n"))
+	assert.ErrorContains(t, err, `line 2: unexpected "until:2026-01-01"`)
+	_, err = parseIgnoreFile(strings.NewReader("CVE-1 exp:31.12.2026\n"))
+	assert.ErrorContains(t, err, `line 1: invalid expiry date "31.12.2026"`)
+}
+
+func TestScanReport(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	ignores := []ignoreRule{
+		{ID: "CVE-2024-0003", Expires: time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), Comment: "not reachable", Line: 1},
+		{ID: "CVE-2024-0004", Expires: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Line: 2},
+		{ID: "CVE-2024-9999", Line: 3},
+	}
+
+	r := newScanReport("app:1.0", "sha256:abc", "high", testVulns, ignores, now)
+	assert.Equal(t, "HIGH", r.FailOn)
+
+	var got []string
+	for _, f := range r.Findings {
+		got = append(got, f.Id+" "+f.Status)
+	}
+	assert.Equal(t, []string{
+		"CVE-2024-0002 FAIL",
+		"CVE-2024-0004 FAIL",
+		"CVE-2024-0003 IGNORED",
+		"CVE-2024-0001 PASS",
+	}, got)
+	assert.Equal(t, 2, r.Failures())
+	assert.Equal(t, []string{"openssl@3.0.1", "libssl@3.0.1"}, r.Findings[0].Packages)
+	assert.Equal(t, "until 2026-06-30 not reachable", r.Findings[2].Reason)
+	assert.Equal(t, []ignoreRule{ignores[1]}, r.Expired)
+
+	// Without a threshold the report never fails.
+	r = newScanReport("app:1.0", "sha256:abc", "", testVulns, nil, now)
+	assert.Equal(t, 0, r.Failures())
+
+	r = newScanReport("app:1.0", "sha256:abc", "CRITICAL", testVulns, nil, now)
+	assert.Equal(t, 1, r.Failures())
+}
+
+func TestWriteSARIF(t *testing.T) {
+	ignores := []ignoreRule{{ID: "CVE-2024-0003", Comment: "not reachable"}}
+	r := newScanReport("app:1.0", "sha256:abc", "CRITICAL", testVulns, ignores, time.Now())
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, r.WriteSARIF(&buf)) {
+		return
+	}
+	var log sarif.Log
+	if !assert.NoError(t, json.Unmarshal(buf.Bytes(), &log)) {
+		return
+	}
+	assert.Equal(t, "2.1.0", log.Version)
+	run := log.Runs[0]
+	assert.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "9.8", run.Tool.Driver.Rules[0].Properties["security-severity"])
+
+	levels := map[string]string{}
+	for _, res := range run.Results {
+		levels[res.RuleID] = res.Level
+		assert.Equal(t, "app:1.0", res.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	}
+	assert.Equal(t, map[string]string{
+		"CVE-2024-0002": "error",
+		"CVE-2024-0004": "warning",
+		"CVE-2024-0003": "warning",
+		"CVE-2024-0001": "note",
+	}, levels)
+	assert.Equal(t, []sarif.Suppression{{Kind: "external", Justification: "not reachable"}}, run.Results[2].Suppressions)
+}
+
+func TestWriteJUnit(t *testing.T) {
+	ignores := []ignoreRule{{ID: "CVE-2024-0001"}}
+	r := newScanReport("app:1.0", "sha256:abc", "HIGH", testVulns, ignores, time.Now())
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, r.WriteJUnit(&buf)) {
+		return
+	}
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+	var suites junitTestSuites
+	if !assert.NoError(t, xml.Unmarshal(buf.Bytes(), &suites)) {
+		return
+	}
+	suite := suites.Suites[0]
+	assert.Equal(t, 4, suite.Tests)
+	assert.Equal(t, 3, suite.Failures)
+	assert.Equal(t, 1, suite.Skipped)
+	assert.Equal(t, "CVE-2024-0002 (CRITICAL)", suite.Cases[0].Name)
+	assert.Equal(t, "CRITICAL", suite.Cases[0].Failure.Type)
+	assert.NotNil(t, suite.Cases[3].Skipped)
+}
@@ -0,0 +1,242 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/registry"
+	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/repository"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/sdk-go-bundle/products/containerregistry/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagRepository   = "repository"
+	FlagTag          = "tag"
+	FlagFailOn       = "fail-on"
+	FlagIgnoreFile   = "ignore-file"
+	FlagReportFormat = "report-format"
+	FlagReportFile   = "report-file"
+
+	reportFormatSARIF = "sarif"
+	reportFormatJUnit = "junit"
+
+	// pageLimit is the page size used to list all artifacts and vulnerabilities.
+	pageLimit = 100
+)
+
+var scanReportCols = []table.Column{
+	{Name: "Id", JSONPath: "Id", Default: true},
+	{Name: "Severity", JSONPath: "Severity", Default: true},
+	{Name: "Score", JSONPath: "Score", Default: true},
+	{Name: "Fixable", JSONPath: "Fixable", Default: true},
+	{Name: "Packages", JSONPath: "Packages", Default: true},
+	{Name: "Status", JSONPath: "Status", Default: true},
+	{Name: "Reason", JSONPath: "Reason"},
+}
+
+func ArtifactsScanReportCmd() *core.Command {
+	c := core.NewCommand(
+		context.TODO(), nil, core.CommandBuilder{
+			Namespace: "container-registry",
+			Resource:  "artifacts",
+			Verb:      "scan-report",
+			Aliases:   []string{"sr", "scan"},
+			ShortDesc: "Gate an artifact on its vulnerabilities",
+			LongDesc: "Aggregate the vulnerabilities found in an artifact and fail if any of them is at least as severe as --" + FlagFailOn + ". " +
+				"The artifact is selected by --" + FlagTag + " or by --" + constants.FlagArtifactId + ".\n\n" +
+				"Vulnerabilities can be accepted with an ignore file, one vulnerability ID per line, " +
+				"optionally followed by an expiry date after which it is reported again:\n\n" +
+				"  # not reachable, base image update pending\n" +
+				"  CVE-2024-1234 exp:2026-12-31\n\n" +
+				"With --" + FlagReportFormat + ", a SARIF or JUnit report is written instead of the table, to --" + FlagReportFile + " or stdout. " +
+				"The report is always written completely before the command fails, so CI systems can show it.",
+			Example: "ionosctl container-registry artifacts scan-report --registry-id ID --repository app --tag 1.0 --fail-on HIGH --ignore-file .ionosignore\n" +
+				"ionosctl container-registry artifacts scan-report --registry-id ID --repository app --tag 1.0 --fail-on CRITICAL --report-format sarif --report-file scan.sarif",
+			PreCmdRun: func(c *core.PreCommandConfig) error {
+				if err := core.CheckRequiredFlagsSets(c.Command, c.NS,
+					[]string{constants.FlagRegistryId, FlagRepository, FlagTag},
+					[]string{constants.FlagRegistryId, FlagRepository, constants.FlagArtifactId},
+				); err != nil {
+					return err
+				}
+				if viper.IsSet(core.GetFlagName(c.NS, FlagReportFile)) && !viper.IsSet(core.GetFlagName(c.NS, FlagReportFormat)) {
+					return fmt.Errorf("--%s requires --%s", FlagReportFile, FlagReportFormat)
+				}
+				return nil
+			},
+			CmdRun:     CmdScanReport,
+			InitClient: true,
+		},
+	)
+
+	c.AddStringFlag(constants.FlagRegistryId, constants.FlagRegistryIdShort, "", "Registry ID", core.RequiredFlagOption())
+	_ = c.Command.RegisterFlagCompletionFunc(
+		constants.FlagRegistryId,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return registry.RegsIds(), cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	c.AddStringFlag(FlagRepository, "", "", "Name of the repository of the artifact", core.RequiredFlagOption())
+	_ = c.Command.RegisterFlagCompletionFunc(
+		FlagRepository, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return repository.RepositoryNames(viper.GetString(core.GetFlagName(c.NS, constants.FlagRegistryId))),
+				cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	c.AddStringFlag(FlagTag, "", "", "Tag of the artifact")
+	c.AddStringFlag(constants.FlagArtifactId, "", "", "ID/digest of the artifact, instead of --"+FlagTag)
+	_ = c.Command.RegisterFlagCompletionFunc(
+		constants.FlagArtifactId,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return ArtifactsIds(
+					viper.GetString(core.GetFlagName(c.NS, constants.FlagRegistryId)),
+					viper.GetString(core.GetFlagName(c.NS, FlagRepository)),
+				),
+				cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+
+	c.AddSetFlag(FlagFailOn, "", "", severities, "Fail if a vulnerability which is not ignored has at least this severity")
+	c.AddStringFlag(FlagIgnoreFile, "", "", "Path of a file with vulnerability IDs to ignore, in the format of .trivyignore")
+	c.AddSetFlag(FlagReportFormat, "", "", []string{reportFormatSARIF, reportFormatJUnit}, "Write a report in this format instead of the table")
+	c.AddStringFlag(FlagReportFile, "", "", "Write the report to this file instead of stdout")
+
+	c.AddColsFlag(scanReportCols)
+
+	c.Command.SilenceUsage = true
+	return c
+}
+
+func CmdScanReport(c *core.CommandConfig) error {
+	regId := viper.GetString(core.GetFlagName(c.NS, constants.FlagRegistryId))
+	repo := viper.GetString(core.GetFlagName(c.NS, FlagRepository))
+	tag := viper.GetString(core.GetFlagName(c.NS, FlagTag))
+	digest := viper.GetString(core.GetFlagName(c.NS, constants.FlagArtifactId))
+
+	var ignores []ignoreRule
+	if path := viper.GetString(core.GetFlagName(c.NS, FlagIgnoreFile)); path != "" {
+		var err error
+		if ignores, err = readIgnoreFile(path); err != nil {
+			return err
+		}
+	}
+
+	name := repo + "@" + digest
+	if digest == "" {
+		var err error
+		if digest, err = findArtifactByTag(c.Context, regId, repo, tag); err != nil {
+			return err
+		}
+		name = repo + ":" + tag
+	}
+
+	vulns, err := listAllVulnerabilities(c.Context, regId, repo, digest)
+	if err != nil {
+		return err
+	}
+
+	report := newScanReport(name, digest, viper.GetString(core.GetFlagName(c.NS, FlagFailOn)), vulns, ignores, time.Now().UTC())
+	for _, rule := range report.Expired {
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(), "WARNING: ignore rule for %s on line %d expired on %s and is no longer applied\n",
+			rule.ID, rule.Line, rule.Expires.AddDate(0, 0, -1).Format(time.DateOnly))
+	}
+
+	if err := writeScanReport(c, report); err != nil {
+		return err
+	}
+
+	if n := report.Failures(); n > 0 {
+		return fmt.Errorf("%s has %d vulnerabilities with severity %s or higher", name, n, report.FailOn)
+	}
+	return nil
+}
+
+func writeScanReport(c *core.CommandConfig, report *scanReport) error {
+	format := viper.GetString(core.GetFlagName(c.NS, FlagReportFormat))
+	if format == "" {
+		return c.Out(table.Sprint(scanReportCols, report.Findings, c.Cols()))
+	}
+
+	var w io.Writer = c.Command.Command.OutOrStdout()
+	if path := viper.GetString(core.GetFlagName(c.NS, FlagReportFile)); path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if format == reportFormatJUnit {
+		return report.WriteJUnit(w)
+	}
+	return report.WriteSARIF(w)
+}
+
+func readIgnoreFile(path string) ([]ignoreRule, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("ignore file %s does not exist", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rules, err := parseIgnoreFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+func findArtifactByTag(ctx context.Context, regId, repo, tag string) (string, error) {
+	for offset := int32(0); ; offset += pageLimit {
+		arts, _, err := client.Must().RegistryClient.ArtifactsApi.RegistriesRepositoriesArtifactsGet(ctx, regId, repo).
+			Offset(offset).Limit(pageLimit).Execute()
+		if err != nil {
+			return "", err
+		}
+		for _, a := range arts.Items {
+			if slices.Contains(a.Properties.Tags, tag) {
+				return a.Id, nil
+			}
+		}
+		if arts.Links.Next == nil || len(arts.Items) == 0 {
+			return "", fmt.Errorf("no artifact tagged %q in repository %s", tag, repo)
+		}
+	}
+}
+
+func listAllVulnerabilities(ctx context.Context, regId, repo, digest string) ([]containerregistry.VulnerabilityRead, error) {
+	var all []containerregistry.VulnerabilityRead
+	for offset := int32(0); ; offset += pageLimit {
+		vulns, resp, err := client.Must().RegistryClient.ArtifactsApi.RegistriesRepositoriesArtifactsVulnerabilitiesGet(ctx, regId, repo, digest).
+			Offset(offset).Limit(pageLimit).Execute()
+		if err != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				return nil, fmt.Errorf("no vulnerability scan found for %s@%s, check that vulnerability scanning is enabled for the registry: %w", repo, digest, err)
+			}
+			return nil, err
+		}
+		all = append(all, vulns.Items...)
+		if vulns.Links.Next == nil || len(vulns.Items) == 0 {
+			return all, nil
+		}
+	}
+}
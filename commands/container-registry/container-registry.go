@@ -2,6 +2,7 @@ package container_registry
 
 import (
 	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/artifacts"
+	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/image"
 	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/location"
 	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/name"
 	"github.com/ionos-cloud/ionosctl/v6/commands/container-registry/registry"
@@ -34,6 +35,7 @@ func ContainerRegistryCmd() *core.Command {
 	contregCmd.AddCommand(repository.RegRepoDeleteCmd())
 	contregCmd.AddCommand(artifacts.ArtifactsCmd())
 	contregCmd.AddCommand(vulnerabilities.VulnerabilitiesCmd())
+	contregCmd.AddCommand(image.ImageCmd())
 
 	return core.WithConfigOverride(contregCmd, []string{fileconfiguration.ContainerRegistry}, constants.DefaultApiURL+"/containerregistries")
 }
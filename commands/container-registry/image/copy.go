@@ -0,0 +1,65 @@
+package image
+
+import (
+	"context"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	"github.com/spf13/viper"
+)
+
+func ImageCopyCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.TODO(), nil, core.CommandBuilder{
+			Namespace: "container-registry",
+			Resource:  "image",
+			Verb:      "copy",
+			Aliases:   []string{"cp"},
+			ShortDesc: "Copy an image between repositories or registries",
+			LongDesc: "Copy an image with all its platforms from one repository to another, without storing it locally. " +
+				"Within the same registry, blobs are mounted instead of downloaded and uploaded again.\n\n" +
+				"The token set with --" + FlagTokenName + " is used for both registries. " +
+				"To copy between registries with different tokens, set the token of the source registry with --" +
+				FlagSourceTokenName + " and --" + FlagSourceTokenPassword + ".",
+			Example: "ionosctl container-registry image copy --source myreg.cr.de-fra.ionos.com/app:1.0 --destination myreg.cr.de-fra.ionos.com/app:stable\n" +
+				"ionosctl container-registry image copy --source staging.cr.de-fra.ionos.com/app:1.0 --source-token-name ci --source-token-password $STAGING_PASSWORD " +
+				"--destination prod.cr.de-txl.ionos.com/app:1.0 --token-name ci --token-password $PROD_PASSWORD",
+			PreCmdRun: func(c *core.PreCommandConfig) error {
+				return core.CheckRequiredFlags(c.Command, c.NS, FlagSource, FlagDestination)
+			},
+			CmdRun: func(c *core.CommandConfig) error {
+				src, err := ociregistry.ParseReference(viper.GetString(core.GetFlagName(c.NS, FlagSource)))
+				if err != nil {
+					return err
+				}
+				dst, err := ociregistry.ParseReference(viper.GetString(core.GetFlagName(c.NS, FlagDestination)))
+				if err != nil {
+					return err
+				}
+
+				opts := newTransferOptions(c)
+				if viper.IsSet(core.GetFlagName(c.NS, FlagSourceTokenName)) || viper.IsSet(core.GetFlagName(c.NS, FlagSourceTokenPassword)) {
+					creds := flagCredentials(c, FlagSourceTokenName, FlagSourceTokenPassword)
+					opts.SourceCreds = &creds
+				}
+
+				result, err := ociregistry.Copy(c.Context, src, dst, opts)
+				if err != nil {
+					return withFlagHints(err)
+				}
+				return c.Printer(allCols).Print(result)
+			},
+			InitClient: false,
+		},
+	)
+
+	cmd.AddStringFlag(FlagSource, "", "", "Image reference to copy, e.g. myreg.cr.de-fra.ionos.com/app:1.0", core.RequiredFlagOption())
+	cmd.AddStringFlag(FlagDestination, "", "", "Image reference to copy to, e.g. myreg.cr.de-fra.ionos.com/app:stable", core.RequiredFlagOption())
+	addTransferFlags(cmd)
+	cmd.AddStringFlag(FlagSourceTokenName, "", "", "Name of the registry token for the source registry, if it differs from --"+FlagTokenName)
+	cmd.AddStringFlag(FlagSourceTokenPassword, "", "", "Password of the registry token for the source registry, if it differs from --"+FlagTokenPassword)
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+	return cmd
+}
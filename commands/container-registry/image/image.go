@@ -0,0 +1,107 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagSource              = "source"
+	FlagDestination         = "destination"
+	FlagPlatform            = "platform"
+	FlagLayoutRef           = "layout-ref"
+	FlagTokenName           = "token-name"
+	FlagTokenPassword       = "token-password"
+	FlagSourceTokenName     = "source-token-name"
+	FlagSourceTokenPassword = "source-token-password"
+	FlagPlainHTTP           = "plain-http"
+)
+
+var allCols = []table.Column{
+	{Name: "Source", JSONPath: "Source"},
+	{Name: "Destination", JSONPath: "Destination", Default: true},
+	{Name: "Digest", JSONPath: "Digest", Default: true},
+	{Name: "MediaType", JSONPath: "MediaType"},
+	{Name: "Blobs", JSONPath: "Blobs", Default: true},
+	{Name: "Bytes", JSONPath: "Bytes", Default: true},
+	{Name: "Mounted", JSONPath: "Mounted"},
+	{Name: "Existing", JSONPath: "Existing", Default: true},
+}
+
+func ImageCmd() *core.Command {
+	cmd := &core.Command{
+		Command: &cobra.Command{
+			Use:     "image",
+			Aliases: []string{"img"},
+			Short:   "Push, pull and copy images",
+			Long: "Move images to, from and between registries without a Docker daemon. " +
+				"The commands speak the OCI distribution protocol directly and authenticate with a registry token, " +
+				"see `ionosctl container-registry token create`. The token name and password can also be set with the " +
+				ociregistry.EnvTokenName + " and " + ociregistry.EnvTokenPassword + " environment variables.\n\n" +
+				"Local images are OCI image layouts, either as directory or as tarball, e.g. the output of `docker save`.",
+			TraverseChildren: true,
+		},
+	}
+
+	cmd.AddColsFlag(allCols)
+
+	cmd.AddCommand(ImagePushCmd())
+	cmd.AddCommand(ImagePullCmd())
+	cmd.AddCommand(ImageCopyCmd())
+
+	return cmd
+}
+
+func newTransferOptions(c *core.CommandConfig) ociregistry.Options {
+	return ociregistry.Options{
+		Creds:     flagCredentials(c, FlagTokenName, FlagTokenPassword),
+		PlainHTTP: viper.GetBool(core.GetFlagName(c.NS, FlagPlainHTTP)),
+		Platform:  viper.GetString(core.GetFlagName(c.NS, FlagPlatform)),
+		// No timeout, layers can be large. Requests are canceled with the command context.
+		HTTPClient: &http.Client{},
+		Log:        c.Verbose,
+	}
+}
+
+// flagCredentials returns the token credentials of the given flags, falling back to the environment.
+func flagCredentials(c *core.CommandConfig, nameFlag, passwordFlag string) ociregistry.Credentials {
+	creds := ociregistry.Credentials{
+		Username: viper.GetString(core.GetFlagName(c.NS, nameFlag)),
+		Password: viper.GetString(core.GetFlagName(c.NS, passwordFlag)),
+	}
+	if creds.Username == "" {
+		creds.Username = os.Getenv(ociregistry.EnvTokenName)
+	}
+	if creds.Password == "" {
+		creds.Password = os.Getenv(ociregistry.EnvTokenPassword)
+	}
+	return creds
+}
+
+func addTransferFlags(cmd *core.Command) {
+	cmd.AddStringFlag(FlagTokenName, "", "", "Name of the registry token. Defaults to $"+ociregistry.EnvTokenName)
+	cmd.AddStringFlag(FlagTokenPassword, "", "", "Password of the registry token. Defaults to $"+ociregistry.EnvTokenPassword)
+	cmd.AddStringFlag(FlagPlatform, "", "", "Only transfer the image for this platform of a multi-platform image, e.g. linux/amd64 or linux/arm/v7")
+	cmd.AddBoolFlag(FlagPlainHTTP, "", false, "Use HTTP instead of HTTPS, e.g. for a local test registry")
+}
+
+// withFlagHints names the flags which resolve an error of the registry client.
+func withFlagHints(err error) error {
+	switch {
+	case errors.Is(err, ociregistry.ErrAuthRequired):
+		return fmt.Errorf("%w, set --%s and --%s", err, FlagTokenName, FlagTokenPassword)
+	case errors.Is(err, ociregistry.ErrPlatformRequired):
+		return fmt.Errorf("%w with --%s", err, FlagPlatform)
+	case errors.Is(err, ociregistry.ErrLayoutRefRequired):
+		return fmt.Errorf("%w with --%s", err, FlagLayoutRef)
+	}
+	return err
+}
@@ -0,0 +1,22 @@
+package image
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFlagHints(t *testing.T) {
+	err := withFlagHints(fmt.Errorf("myreg.example.com: %w", ociregistry.ErrAuthRequired))
+	assert.EqualError(t, err, "myreg.example.com: authentication required, set --token-name and --token-password")
+	assert.ErrorIs(t, err, ociregistry.ErrAuthRequired)
+
+	err = withFlagHints(fmt.Errorf("app.tar contains 2 images (1.0, 2.0), %w", ociregistry.ErrLayoutRefRequired))
+	assert.EqualError(t, err, "app.tar contains 2 images (1.0, 2.0), select an image with --layout-ref")
+
+	other := errors.New("registry responded with 500")
+	assert.Equal(t, other, withFlagHints(other))
+}
@@ -0,0 +1,52 @@
+package image
+
+import (
+	"context"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	"github.com/spf13/viper"
+)
+
+func ImagePullCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.TODO(), nil, core.CommandBuilder{
+			Namespace: "container-registry",
+			Resource:  "image",
+			Verb:      "pull",
+			Aliases:   []string{"pl"},
+			ShortDesc: "Pull an image from a registry into a local OCI image layout",
+			LongDesc: "Pull an image from a registry into an OCI image layout. " +
+				"If --" + FlagDestination + " ends with .tar, a tarball is written, which can be loaded with `docker load`. " +
+				"Otherwise the image is added to the layout directory, which is created if needed, " +
+				"and blobs already present in it are not downloaded again.\n\n" +
+				"The digest of every manifest and blob is verified, content which does not match is never written.",
+			Example: "ionosctl container-registry image pull --source myreg.cr.de-fra.ionos.com/app:1.0 --destination ./app-oci\n" +
+				"ionosctl container-registry image pull --source myreg.cr.de-fra.ionos.com/app:1.0 --destination app.tar --platform linux/amd64 && docker load -i app.tar",
+			PreCmdRun: func(c *core.PreCommandConfig) error {
+				return core.CheckRequiredFlags(c.Command, c.NS, FlagSource, FlagDestination)
+			},
+			CmdRun: func(c *core.CommandConfig) error {
+				src, err := ociregistry.ParseReference(viper.GetString(core.GetFlagName(c.NS, FlagSource)))
+				if err != nil {
+					return err
+				}
+
+				result, err := ociregistry.Pull(c.Context, src, viper.GetString(core.GetFlagName(c.NS, FlagDestination)), newTransferOptions(c))
+				if err != nil {
+					return withFlagHints(err)
+				}
+				return c.Printer(allCols).Print(result)
+			},
+			InitClient: false,
+		},
+	)
+
+	cmd.AddStringFlag(FlagSource, "", "", "Image reference to pull, e.g. myreg.cr.de-fra.ionos.com/app:1.0 or myreg.cr.de-fra.ionos.com/app@sha256:...", core.RequiredFlagOption())
+	cmd.AddStringFlag(FlagDestination, "", "", "Path of the OCI image layout directory, or of a tarball ending with .tar", core.RequiredFlagOption())
+	addTransferFlags(cmd)
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+	return cmd
+}
@@ -0,0 +1,53 @@
+package image
+
+import (
+	"context"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ociregistry"
+	"github.com/spf13/viper"
+)
+
+func ImagePushCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.TODO(), nil, core.CommandBuilder{
+			Namespace: "container-registry",
+			Resource:  "image",
+			Verb:      "push",
+			Aliases:   []string{"p"},
+			ShortDesc: "Push a local OCI image layout to a registry",
+			LongDesc: "Push an image from an OCI image layout, either a directory or a tarball, to a registry. " +
+				"Blobs which already exist in the repository are not uploaded again.\n\n" +
+				"If the layout holds several images, select one with --" + FlagLayoutRef + ", " +
+				"which matches the " + ociregistry.AnnotationRefName + " annotation, usually the tag.",
+			Example: "ionosctl container-registry image push --source ./app-oci --destination myreg.cr.de-fra.ionos.com/app:1.0 --token-name ci --token-password $PASSWORD\n" +
+				"docker save app:1.0 -o app.tar && ionosctl container-registry image push --source app.tar --destination myreg.cr.de-fra.ionos.com/app:1.0",
+			PreCmdRun: func(c *core.PreCommandConfig) error {
+				return core.CheckRequiredFlags(c.Command, c.NS, FlagSource, FlagDestination)
+			},
+			CmdRun: func(c *core.CommandConfig) error {
+				dst, err := ociregistry.ParseReference(viper.GetString(core.GetFlagName(c.NS, FlagDestination)))
+				if err != nil {
+					return err
+				}
+
+				result, err := ociregistry.Push(c.Context, viper.GetString(core.GetFlagName(c.NS, FlagSource)), dst,
+					viper.GetString(core.GetFlagName(c.NS, FlagLayoutRef)), newTransferOptions(c))
+				if err != nil {
+					return withFlagHints(err)
+				}
+				return c.Printer(allCols).Print(result)
+			},
+			InitClient: false,
+		},
+	)
+
+	cmd.AddStringFlag(FlagSource, "", "", "Path of the OCI image layout directory or tarball", core.RequiredFlagOption())
+	cmd.AddStringFlag(FlagDestination, "", "", "Image reference to push to, e.g. myreg.cr.de-fra.ionos.com/app:1.0", core.RequiredFlagOption())
+	cmd.AddStringFlag(FlagLayoutRef, "", "", "Ref name of the image to push, if the layout holds several images")
+	addTransferFlags(cmd)
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+	return cmd
+}
@@ -0,0 +1,243 @@
+// Package connect opens interactive sessions to DBaaS databases with the command line client of each engine.
+package connect
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagUser     = "user"
+	FlagPrintURI = "print-uri"
+	FlagClient   = "client"
+)
+
+// Engine is the database engine of a cluster, which decides the client and the URI scheme.
+type Engine string
+
+const (
+	Postgres   Engine = "postgres"
+	MariaDB    Engine = "mariadb"
+	Mongo      Engine = "mongo"
+	InMemoryDB Engine = "inmemorydb"
+)
+
+var clients = map[Engine]string{
+	Postgres:   "psql",
+	MariaDB:    "mariadb",
+	Mongo:      "mongosh",
+	InMemoryDB: "redis-cli",
+}
+
+var ports = map[Engine]int{
+	Postgres:   5432,
+	MariaDB:    3306,
+	InMemoryDB: 6379,
+}
+
+// Target is a database to connect to.
+type Target struct {
+	Engine Engine
+	// Host is the DNS name of the cluster. Mongo clusters have a connection string instead.
+	Host string
+	// ConnectionString is the connection string of the cluster, without credentials.
+	ConnectionString string
+	User             string
+	Database         string
+}
+
+// URI returns the connection URI of the target, with TLS required. It never contains a password.
+func (t Target) URI() (string, error) {
+	switch t.Engine {
+	case Postgres:
+		u := url.URL{
+			Scheme:   "postgresql",
+			User:     url.User(t.User),
+			Host:     net.JoinHostPort(t.Host, strconv.Itoa(ports[t.Engine])),
+			Path:     "/" + t.Database,
+			RawQuery: "sslmode=require",
+		}
+		return u.String(), nil
+	case MariaDB:
+		u := url.URL{
+			Scheme:   "mysql",
+			User:     url.User(t.User),
+			Host:     net.JoinHostPort(t.Host, strconv.Itoa(ports[t.Engine])),
+			Path:     "/" + t.Database,
+			RawQuery: "ssl-mode=REQUIRED",
+		}
+		return u.String(), nil
+	case Mongo:
+		if t.ConnectionString == "" {
+			return "", errors.New("the cluster has no connection string yet")
+		}
+		u, err := url.Parse(t.ConnectionString)
+		if err != nil {
+			return "", fmt.Errorf("invalid connection string %q: %w", t.ConnectionString, err)
+		}
+		u.User = url.User(t.User)
+		if t.Database != "" {
+			u.Path = "/" + t.Database
+		} else if u.Path == "" {
+			u.Path = "/"
+		}
+		q := u.Query()
+		// Mongo users of DBaaS clusters are created in the admin database.
+		if !q.Has("authSource") {
+			q.Set("authSource", "admin")
+		}
+		if !q.Has("tls") && !q.Has("ssl") {
+			q.Set("tls", "true")
+		}
+		u.RawQuery = q.Encode()
+		return u.String(), nil
+	case InMemoryDB:
+		u := url.URL{
+			Scheme: "rediss",
+			User:   url.User(t.User),
+			Host:   net.JoinHostPort(t.Host, strconv.Itoa(ports[t.Engine])),
+		}
+		return u.String(), nil
+	}
+	return "", fmt.Errorf("unknown engine %q", t.Engine)
+}
+
+// Args returns the arguments of the client of the engine to connect to the target. The clients prompt for the
+// password themselves, so it never shows up in the process list.
+func (t Target) Args() ([]string, error) {
+	switch t.Engine {
+	case Postgres, Mongo:
+		uri, err := t.URI()
+		if err != nil {
+			return nil, err
+		}
+		return []string{uri}, nil
+	case MariaDB:
+		args := []string{
+			"--host=" + t.Host,
+			"--port=" + strconv.Itoa(ports[t.Engine]),
+			"--user=" + t.User,
+			"--password",
+			"--ssl",
+		}
+		if t.Database != "" {
+			args = append(args, t.Database)
+		}
+		return args, nil
+	case InMemoryDB:
+		return []string{
+			"-h", t.Host,
+			"-p", strconv.Itoa(ports[t.Engine]),
+			"--tls",
+			"--user", t.User,
+			"--askpass",
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown engine %q", t.Engine)
+}
+
+// AddFlags adds the flags shared by the connect commands. withDatabase adds --database for engines which select a
+// database when connecting.
+func AddFlags(cmd *core.Command, engine Engine, withDatabase bool) {
+	cmd.AddStringFlag(FlagUser, "", "", "The database user to connect as. By default, the user is taken from the cluster, or prompted for")
+	if withDatabase {
+		cmd.AddStringFlag(constants.FlagDatabase, "", "", "The database to connect to")
+	}
+	cmd.AddBoolFlag(FlagPrintURI, "", false, "Only print the connection URI, without a password, instead of starting the client")
+	cmd.AddStringFlag(FlagClient, "", clients[engine], "The path of the client to start")
+}
+
+// Run resolves the user of the target, then prints the connection URI with --print-uri, or starts the client.
+// The user is taken from --user, then from the target, then from users if it returns a single user, and is
+// prompted for otherwise. users may be nil.
+func Run(c *core.CommandConfig, t Target, users func() ([]string, error)) error {
+	if f := core.GetFlagName(c.NS, FlagUser); viper.IsSet(f) {
+		t.User = viper.GetString(f)
+	}
+	if f := core.GetFlagName(c.NS, constants.FlagDatabase); viper.IsSet(f) {
+		t.Database = viper.GetString(f)
+	}
+	if t.User == "" {
+		var known []string
+		if users != nil {
+			var err error
+			if known, err = users(); err != nil {
+				return fmt.Errorf("failed getting users: %w", err)
+			}
+		}
+		user, err := resolveUser(c.Stdin, c.Command.Command.ErrOrStderr(), known)
+		if err != nil {
+			return err
+		}
+		t.User = user
+	}
+
+	if viper.GetBool(core.GetFlagName(c.NS, FlagPrintURI)) {
+		uri, err := t.URI()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.Command.Command.OutOrStdout(), uri)
+		return err
+	}
+
+	args, err := t.Args()
+	if err != nil {
+		return err
+	}
+	client := viper.GetString(core.GetFlagName(c.NS, FlagClient))
+	path, err := exec.LookPath(client)
+	if err != nil {
+		return fmt.Errorf("%s not found, install it or set its path with --%s: %w", client, FlagClient, err)
+	}
+
+	address := t.Host
+	if address == "" {
+		address = t.ConnectionString
+	}
+	c.Verbose("Connecting to %s as %s with %s", address, t.User, path)
+	cmd := exec.CommandContext(c.Context, path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = c.Command.Command.OutOrStdout()
+	cmd.Stderr = c.Command.Command.ErrOrStderr()
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("%s exited with status %d", client, exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// resolveUser returns the only known user, or prompts for one of the known users.
+func resolveUser(in io.Reader, out io.Writer, known []string) (string, error) {
+	if len(known) == 1 {
+		return known[0], nil
+	}
+	if len(known) > 1 {
+		fmt.Fprintf(out, "Users: %s\n", strings.Join(known, ", "))
+	}
+	fmt.Fprint(out, "User: ")
+	line, err := bufio.NewReader(in).ReadString('\n')
+	user := strings.TrimSpace(line)
+	if user == "" {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+		return "", fmt.Errorf("no user given, set one with --%s", FlagUser)
+	}
+	return user, nil
+}
@@ -0,0 +1,62 @@
+package connect
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURI(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{Target{Engine: Postgres, Host: "pg.example.com", User: "alice", Database: "postgres"}, "postgresql://alice@pg.example.com:5432/postgres?sslmode=require"},
+		{Target{Engine: MariaDB, Host: "maria.example.com", User: "bob"}, "mysql://bob@maria.example.com:3306/?ssl-mode=REQUIRED"},
+		{Target{Engine: Mongo, ConnectionString: "mongodb+srv://m-1.mongodb.de-fra.ionos.com", User: "carol"}, "mongodb+srv://carol@m-1.mongodb.de-fra.ionos.com/?authSource=admin&tls=true"},
+		{Target{Engine: Mongo, ConnectionString: "mongodb://m-1.example.com/?tls=false", User: "carol", Database: "shop"}, "mongodb://carol@m-1.example.com/shop?authSource=admin&tls=false"},
+		{Target{Engine: InMemoryDB, Host: "redis.example.com", User: "dave"}, "rediss://dave@redis.example.com:6379"},
+		{Target{Engine: Postgres, Host: "pg.example.com", User: "a@b:c", Database: "postgres"}, "postgresql://a%40b%3Ac@pg.example.com:5432/postgres?sslmode=require"},
+	}
+	for _, tt := range tests {
+		got, err := tt.target.URI()
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.want, got)
+		}
+	}
+
+	_, err := Target{Engine: Mongo, User: "carol"}.URI()
+	assert.Error(t, err)
+}
+
+func TestArgs(t *testing.T) {
+	args, err := Target{Engine: Postgres, Host: "pg.example.com", User: "alice", Database: "postgres"}.Args()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"postgresql://alice@pg.example.com:5432/postgres?sslmode=require"}, args)
+
+	args, err = Target{Engine: MariaDB, Host: "maria.example.com", User: "bob", Database: "shop"}.Args()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"--host=maria.example.com", "--port=3306", "--user=bob", "--password", "--ssl", "shop"}, args)
+
+	args, err = Target{Engine: InMemoryDB, Host: "redis.example.com", User: "dave"}.Args()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"-h", "redis.example.com", "-p", "6379", "--tls", "--user", "dave", "--askpass"}, args)
+}
+
+func TestResolveUser(t *testing.T) {
+	var out bytes.Buffer
+	user, err := resolveUser(strings.NewReader(""), &out, []string{"alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", user)
+	assert.Empty(t, out.String())
+
+	user, err = resolveUser(strings.NewReader(" bob \n"), &out, []string{"alice", "bob"})
+	assert.NoError(t, err)
+	assert.Equal(t, "bob", user)
+	assert.Equal(t, "Users: alice, bob\nUser: ", out.String())
+
+	_, err = resolveUser(strings.NewReader(""), &out, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package replicaset
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/connect"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/inmemorydb/utils"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+func Connect() *core.Command {
+	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+		Namespace: "dbaas inmemorydb",
+		Resource:  "replicaset",
+		Verb:      "connect",
+		ShortDesc: "Connect to an In-Memory DB Replica Set with redis-cli",
+		LongDesc: `Connect to an In-Memory DB Replica Set with redis-cli, with TLS enabled. redis-cli must be installed, and the replica set must be reachable from where the command runs.
+
+By default, the command connects as the user of the replica set. redis-cli prompts for the password.
+
+Use ` + "`--print-uri`" + ` to only print the connection URI, e.g. for other clients.`,
+		Example: fmt.Sprintf("ionosctl dbaas inmemorydb replicaset connect %s\nionosctl dbaas inmemorydb replicaset connect %s --print-uri",
+			core.FlagsUsage(constants.FlagReplicasetID), core.FlagsUsage(constants.FlagReplicasetID)),
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return c.CheckRequiredFlagsSetsAndLocation(
+				[]string{constants.FlagReplicasetID},
+			)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			id := viper.GetString(core.GetFlagName(c.NS, constants.FlagReplicasetID))
+
+			rs, _, err := client.Must().InMemoryDBClient.ReplicaSetApi.ReplicasetsFindById(context.Background(), id).Execute()
+			if err != nil {
+				return err
+			}
+			if rs.Metadata.DnsName == "" {
+				return fmt.Errorf("replica set %s has no DNS name yet", id)
+			}
+
+			return connect.Run(c, connect.Target{
+				Engine: connect.InMemoryDB,
+				Host:   rs.Metadata.DnsName,
+				User:   rs.Properties.Credentials.Username,
+			}, nil)
+		},
+		InitClient: true,
+	})
+
+	cmd.AddStringFlag(constants.FlagReplicasetID, constants.FlagIdShort, "",
+		"The ID of the Replica Set you want to connect to",
+		core.WithCompletion(utils.ReplicasetIDs, constants.InMemoryDBApiRegionalURL, constants.InMemoryDBLocations),
+	)
+	connect.AddFlags(cmd, connect.InMemoryDB, false)
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+
+	return cmd
+}
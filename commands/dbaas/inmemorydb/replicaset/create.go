@@ -13,6 +13,7 @@ import (
 	"github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
 	completer2 "github.com/ionos-cloud/ionosctl/v6/commands/dbaas/completer"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/inmemorydb/utils"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -25,7 +26,8 @@ import (
 )
 
 func Create() *core.Command {
-	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+	var doc map[string]any
+	cmd := core.NewCommandWithJsonProperties(context.Background(), nil, jsonPropertiesExample, &doc, core.CommandBuilder{
 		Namespace: "dbaas inmemorydb",
 		Resource:  "replicaset",
 		Verb:      "create",
@@ -50,15 +52,34 @@ volatile-random: Random keys will be removed, but only among keys with the expir
 volatile-ttl: The key with the nearest time to live will be removed first, but only among keys with the expire field set to true.`,
 		Example: "ionosctl dbaas inmemorydb replicaset create " + core.FlagsUsage(constants.FlagLocation, constants.FlagName,
 			constants.FlagReplicas, constants.FlagCores, constants.FlagRam, constants.ArgUser, constants.ArgPassword,
-			constants.FlagDatacenterId, constants.FlagLanId, constants.FlagCidr),
+			constants.FlagDatacenterId, constants.FlagLanId, constants.FlagCidr) + "\n" +
+			"ionosctl dbaas inmemorydb replicaset get --replica-set-id <replica-set-id> --as-create-payload > replicaset.json\n" +
+			"ionosctl dbaas inmemorydb replicaset create --json-properties replicaset.json",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
-			return c.CheckRequiredFlagsAndLocation(
-				constants.FlagName, constants.FlagReplicas,
-				constants.FlagCores, constants.FlagRam,
-				constants.ArgUser, constants.ArgPassword,
-				constants.FlagDatacenterId, constants.FlagLanId, constants.FlagCidr)
+			return payload.PreRun(c, func(c *core.PreCommandConfig) error {
+				return c.CheckRequiredFlagsAndLocation(
+					constants.FlagName, constants.FlagReplicas,
+					constants.FlagCores, constants.FlagRam,
+					constants.ArgUser, constants.ArgPassword,
+					constants.FlagDatacenterId, constants.FlagLanId, constants.FlagCidr)
+			})
 		},
 		CmdRun: func(c *core.CommandConfig) error {
+			if doc != nil {
+				var rs inmemorydb.ReplicaSetCreate
+				if err := payload.Decode(doc, &rs); err != nil {
+					return err
+				}
+				id := uuidgen.Must()
+				replica, _, err := client.Must().InMemoryDBClient.ReplicaSetApi.
+					ReplicasetsPut(context.Background(), id).
+					ReplicaSetEnsure(inmemorydb.ReplicaSetEnsure{Id: id, Metadata: rs.Metadata, Properties: rs.Properties}).Execute()
+				if err != nil {
+					return err
+				}
+				return c.Printer(allCols).Print(replica)
+			}
+
 			input := inmemorydb.ReplicaSet{}
 
 			if fn := core.GetFlagName(c.NS, constants.FlagName); viper.IsSet(fn) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/inmemorydb/utils"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -18,7 +19,8 @@ func Get() *core.Command {
 		Verb:      "get",
 		Aliases:   []string{"g"},
 		ShortDesc: "Get an In-Memory DB Replica Set",
-		Example:   fmt.Sprintf("ionosctl dbaas inmemorydb replicaset get %s", core.FlagsUsage(constants.FlagReplicasetID)),
+		Example: fmt.Sprintf("ionosctl dbaas inmemorydb replicaset get %s\n", core.FlagsUsage(constants.FlagReplicasetID)) +
+			fmt.Sprintf("ionosctl dbaas inmemorydb replicaset get %s --%s > replicaset.json", core.FlagsUsage(constants.FlagReplicasetID), payload.FlagAsCreatePayload),
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			return c.CheckRequiredFlagsSetsAndLocation(
 				[]string{constants.FlagReplicasetID},
@@ -32,6 +34,10 @@ func Get() *core.Command {
 				return err
 			}
 
+			if viper.GetBool(core.GetFlagName(c.NS, payload.FlagAsCreatePayload)) {
+				return payload.Print(c, createPayload(rs))
+			}
+
 			return c.Printer(allCols).Print(rs)
 		},
 		InitClient: true,
@@ -42,6 +48,8 @@ func Get() *core.Command {
 		core.WithCompletion(utils.ReplicasetIDs, constants.InMemoryDBApiRegionalURL, constants.InMemoryDBLocations),
 	)
 
+	payload.AddFlag(cmd, "ionosctl dbaas inmemorydb replicaset create")
+
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
 
@@ -0,0 +1,51 @@
+package replicaset
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/inmemorydb/v2"
+)
+
+// createPayload converts a replica set into a request which creates a replica set like it. The storage is derived
+// by the API, and the password is never returned, so the user keeps its name and gets a placeholder password.
+func createPayload(rs inmemorydb.ReplicaSetRead) inmemorydb.ReplicaSetCreate {
+	p := rs.Properties
+	props := inmemorydb.ReplicaSet{
+		DisplayName:       p.DisplayName,
+		Version:           p.Version,
+		Replicas:          p.Replicas,
+		Resources:         inmemorydb.Resources{Cores: p.Resources.Cores, Ram: p.Resources.Ram},
+		PersistenceMode:   p.PersistenceMode,
+		EvictionPolicy:    p.EvictionPolicy,
+		MaintenanceWindow: p.MaintenanceWindow,
+		Backup:            p.Backup,
+		Credentials: inmemorydb.User{
+			Username: p.Credentials.Username,
+			Password: &inmemorydb.UserPassword{PlainTextPassword: pointer.From(payload.Password)},
+		},
+		Connections: []inmemorydb.Connection{},
+	}
+	for range p.Connections {
+		props.Connections = append(props.Connections, inmemorydb.Connection{
+			DatacenterId: payload.DatacenterId,
+			LanId:        payload.LanId,
+			Cidr:         payload.Cidr,
+		})
+	}
+	return inmemorydb.ReplicaSetCreate{Properties: props}
+}
+
+var jsonPropertiesExample = payload.Example(inmemorydb.ReplicaSetCreate{Properties: inmemorydb.ReplicaSet{
+	DisplayName:       "example",
+	Version:           "7.2",
+	Replicas:          1,
+	Resources:         inmemorydb.Resources{Cores: 1, Ram: 4},
+	PersistenceMode:   "RDB",
+	EvictionPolicy:    "allkeys-lru",
+	MaintenanceWindow: &inmemorydb.MaintenanceWindow{DayOfTheWeek: "Monday", Time: "12:00:00"},
+	Connections:       []inmemorydb.Connection{{DatacenterId: payload.DatacenterId, LanId: payload.LanId, Cidr: payload.Cidr}},
+	Credentials: inmemorydb.User{
+		Username: payload.Username,
+		Password: &inmemorydb.UserPassword{PlainTextPassword: pointer.From(payload.Password)},
+	},
+}})
@@ -0,0 +1,50 @@
+package replicaset
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/inmemorydb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePayload(t *testing.T) {
+	rs := inmemorydb.ReplicaSetRead{
+		Id: "3d2b0c26-0000-0000-0000-000000000000",
+		Properties: inmemorydb.ReplicaSet{
+			DisplayName:     "cache",
+			Version:         "7.2",
+			Replicas:        3,
+			Resources:       inmemorydb.Resources{Cores: 2, Ram: 8, Storage: pointer.From[int32](16)},
+			PersistenceMode: "RDB",
+			EvictionPolicy:  "allkeys-lru",
+			Connections:     []inmemorydb.Connection{{DatacenterId: "dc", LanId: "2", Cidr: "10.0.0.5/24"}},
+			Credentials:     inmemorydb.User{Username: "admin"},
+		},
+	}
+
+	req := createPayload(rs)
+	assert.Equal(t, "cache", req.Properties.DisplayName)
+	assert.Equal(t, inmemorydb.Resources{Cores: 2, Ram: 8}, req.Properties.Resources)
+	assert.Equal(t, []inmemorydb.Connection{{DatacenterId: payload.DatacenterId, LanId: payload.LanId, Cidr: payload.Cidr}},
+		req.Properties.Connections)
+	assert.Equal(t, "admin", req.Properties.Credentials.Username)
+
+	// The payload decodes again as the create command reads it.
+	b, err := json.Marshal(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(b), "3d2b0c26")
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	var decoded inmemorydb.ReplicaSetCreate
+	if !assert.NoError(t, payload.Decode(doc, &decoded)) {
+		return
+	}
+	if assert.NotNil(t, decoded.Properties.Credentials.Password) {
+		assert.Equal(t, payload.Password, *decoded.Properties.Credentials.Password.PlainTextPassword)
+	}
+}
@@ -0,0 +1,210 @@
+// Package logtail follows the logs of DBaaS clusters, whose APIs only return the logs of a time window.
+package logtail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFollow       = "follow"
+	FlagGrep         = "grep"
+	FlagPollInterval = "poll-interval"
+
+	DirectionForward  = "FORWARD"
+	DirectionBackward = "BACKWARD"
+)
+
+// Line is a log line of a cluster instance.
+type Line struct {
+	Instance string    `json:"instance"`
+	Time     time.Time `json:"time"`
+	Message  string    `json:"message"`
+}
+
+// FetchFunc returns at most limit log lines between start and end, scanning in the direction. A zero start
+// leaves the start to the API.
+type FetchFunc func(ctx context.Context, start, end time.Time, direction string, limit int32) ([]Line, error)
+
+type Options struct {
+	// Start is where following begins. If zero, following begins with the last Limit lines.
+	Start time.Time
+	// Limit is the number of lines fetched per request.
+	Limit int32
+	// Interval is the pause between requests, once the logs are caught up.
+	Interval time.Duration
+	// Grep only prints lines whose message matches, if set.
+	Grep *regexp.Regexp
+	// JSON prints every line as a JSON object on its own line.
+	JSON bool
+}
+
+var now = time.Now
+
+// Follow prints the log lines as they arrive, until the context is done. Every request starts at the time of the
+// newest line printed so far, so lines with the same timestamp are not lost. The overlap is de-duplicated.
+func Follow(ctx context.Context, fetch FetchFunc, w io.Writer, opts Options) error {
+	t := tail{w: w, opts: opts, cursor: opts.Start}
+	if opts.Start.IsZero() {
+		lines, err := fetch(ctx, time.Time{}, now(), DirectionBackward, opts.Limit)
+		if err != nil {
+			return err
+		}
+		if err := t.emit(lines); err != nil {
+			return err
+		}
+		if t.cursor.IsZero() {
+			t.cursor = now()
+		}
+	}
+
+	for {
+		lines, err := fetch(ctx, t.cursor, now(), DirectionForward, opts.Limit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		fresh := t.fresh(lines)
+		if err := t.emit(lines); err != nil {
+			return err
+		}
+		// A full page means more lines are waiting. If the whole page was printed already, more than a page of
+		// lines share the timestamp of the cursor, and the rest of them can only be skipped.
+		if len(lines) >= int(opts.Limit) {
+			if fresh == 0 {
+				t.cursor = t.cursor.Add(time.Nanosecond)
+				t.printed = nil
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+type tail struct {
+	w    io.Writer
+	opts Options
+	// cursor is the time of the newest line printed so far.
+	cursor time.Time
+	// printed are the lines printed with the time of the cursor. Newer requests return them again.
+	printed map[lineKey]bool
+}
+
+type lineKey struct {
+	instance string
+	time     int64
+	message  string
+}
+
+func keyOf(l Line) lineKey {
+	return lineKey{instance: l.Instance, time: l.Time.UnixNano(), message: l.Message}
+}
+
+// fresh returns how many of the lines were not printed yet.
+func (t *tail) fresh(lines []Line) int {
+	n := 0
+	for _, l := range lines {
+		if t.isNew(l) {
+			n++
+		}
+	}
+	return n
+}
+
+func (t *tail) isNew(l Line) bool {
+	return l.Time.After(t.cursor) || (l.Time.Equal(t.cursor) && !t.printed[keyOf(l)])
+}
+
+// emit prints the lines which were not printed yet, oldest first, and moves the cursor to the newest of them.
+func (t *tail) emit(lines []Line) error {
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].Time.Before(lines[j].Time) })
+	for _, l := range lines {
+		if !t.isNew(l) {
+			continue
+		}
+		if l.Time.After(t.cursor) || t.printed == nil {
+			t.cursor = l.Time
+			t.printed = map[lineKey]bool{}
+		}
+		t.printed[keyOf(l)] = true
+		if t.opts.Grep != nil && !t.opts.Grep.MatchString(l.Message) {
+			continue
+		}
+		if err := t.print(l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *tail) print(l Line) error {
+	if t.opts.JSON {
+		b, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(t.w, "%s\n", b)
+		return err
+	}
+	_, err := fmt.Fprintf(t.w, "[%s] %s\n", l.Instance, l.Message)
+	return err
+}
+
+// AddFlags adds the flags of following logs.
+func AddFlags(cmd *core.Command) {
+	cmd.AddBoolFlag(FlagFollow, "", false, "Keep polling for new log lines and print them as they arrive, until interrupted")
+	cmd.AddStringFlag(FlagGrep, "", "", "With --follow, only print log lines whose message matches this regular expression")
+	cmd.AddDurationFlag(FlagPollInterval, "", 5*time.Second, "With --follow, the pause between polls once all log lines are printed")
+}
+
+// CheckFlags rejects flags which only work with --follow, and the end flags, which conflict with it.
+func CheckFlags(c *core.PreCommandConfig, endFlags ...string) error {
+	if !viper.GetBool(core.GetFlagName(c.NS, FlagFollow)) {
+		if viper.IsSet(core.GetFlagName(c.NS, FlagGrep)) {
+			return fmt.Errorf("--%s requires --%s", FlagGrep, FlagFollow)
+		}
+		return nil
+	}
+	for _, f := range endFlags {
+		if viper.IsSet(core.GetFlagName(c.NS, f)) {
+			return fmt.Errorf("--%s cannot be used with --%s", f, FlagFollow)
+		}
+	}
+	if expr := viper.GetString(core.GetFlagName(c.NS, FlagGrep)); expr != "" {
+		if _, err := regexp.Compile(expr); err != nil {
+			return fmt.Errorf("invalid --%s: %w", FlagGrep, err)
+		}
+	}
+	return nil
+}
+
+// Run follows the logs with the options of the flags. With '-o json', every line is printed as JSON.
+func Run(c *core.CommandConfig, fetch FetchFunc, start time.Time, limit int32) error {
+	opts := Options{
+		Start:    start,
+		Limit:    limit,
+		Interval: viper.GetDuration(core.GetFlagName(c.NS, FlagPollInterval)),
+	}
+	if expr := viper.GetString(core.GetFlagName(c.NS, FlagGrep)); expr != "" {
+		opts.Grep = regexp.MustCompile(expr)
+	}
+	output := viper.GetString(constants.ArgOutput)
+	opts.JSON = output == "json" || output == "api-json"
+
+	return Follow(c.Context, fetch, c.Command.Command.OutOrStdout(), opts)
+}
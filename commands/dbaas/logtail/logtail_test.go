@@ -0,0 +1,110 @@
+package logtail
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var t0 = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// fakeLogs serves the lines of a cluster like the logs APIs, the first limit lines in the direction of the window.
+type fakeLogs struct {
+	lines    []Line
+	requests int
+	// cancel stops following after max requests.
+	cancel context.CancelFunc
+	max    int
+	// grow adds lines after every request, as if they were logged in the meantime.
+	grow func(requests int) []Line
+}
+
+func (f *fakeLogs) fetch(_ context.Context, start, end time.Time, direction string, limit int32) ([]Line, error) {
+	f.requests++
+	var window []Line
+	for _, l := range f.lines {
+		if (start.IsZero() || !l.Time.Before(start)) && !l.Time.After(end) {
+			window = append(window, l)
+		}
+	}
+	if direction == DirectionBackward {
+		for i, j := 0, len(window)-1; i < j; i, j = i+1, j-1 {
+			window[i], window[j] = window[j], window[i]
+		}
+	}
+	if len(window) > int(limit) {
+		window = window[:limit]
+	}
+	if f.grow != nil {
+		f.lines = append(f.lines, f.grow(f.requests)...)
+	}
+	if f.requests >= f.max {
+		f.cancel()
+	}
+	return window, nil
+}
+
+func line(instance string, seconds int, message string) Line {
+	return Line{Instance: instance, Time: t0.Add(time.Duration(seconds) * time.Second), Message: message}
+}
+
+func follow(t *testing.T, f *fakeLogs, opts Options) string {
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	now = func() time.Time { return t0.Add(time.Hour) }
+	defer func() { now = time.Now }()
+
+	var out bytes.Buffer
+	assert.NoError(t, Follow(ctx, f.fetch, &out, opts))
+	return out.String()
+}
+
+func TestFollowTail(t *testing.T) {
+	f := &fakeLogs{
+		lines: []Line{line("pg-0", 1, "one"), line("pg-1", 2, "two"), line("pg-0", 3, "three")},
+		max:   3,
+		grow: func(requests int) []Line {
+			if requests == 2 {
+				return []Line{line("pg-1", 3, "three too"), line("pg-0", 4, "four")}
+			}
+			return nil
+		},
+	}
+	out := follow(t, f, Options{Limit: 2})
+	assert.Equal(t, "[pg-1] two\n[pg-0] three\n[pg-1] three too\n[pg-0] four\n", out)
+}
+
+func TestFollowPages(t *testing.T) {
+	var lines []Line
+	for i := 0; i < 5; i++ {
+		lines = append(lines, line("pg-0", i, string(rune('a'+i))))
+	}
+	f := &fakeLogs{lines: lines, max: 5}
+	out := follow(t, f, Options{Start: t0, Limit: 2, Interval: time.Hour})
+	assert.Equal(t, "[pg-0] a\n[pg-0] b\n[pg-0] c\n[pg-0] d\n[pg-0] e\n", out)
+	// Full pages are requested right away. Pages overlap by the line at the cursor.
+	assert.Equal(t, 5, f.requests)
+}
+
+func TestFollowSameTimestamp(t *testing.T) {
+	f := &fakeLogs{
+		lines: []Line{line("pg-0", 1, "a"), line("pg-0", 1, "b"), line("pg-0", 1, "c"), line("pg-0", 2, "d")},
+		max:   3,
+	}
+	// More lines share a timestamp than fit a page, so c cannot be fetched and is skipped.
+	out := follow(t, f, Options{Start: t0, Limit: 2})
+	assert.Equal(t, "[pg-0] a\n[pg-0] b\n[pg-0] d\n", out)
+}
+
+func TestFollowGrepJSON(t *testing.T) {
+	f := &fakeLogs{
+		lines: []Line{line("m-0", 1, "ok"), line("m-1", 2, "ERROR: disk full")},
+		max:   1,
+	}
+	out := follow(t, f, Options{Start: t0, Limit: 10, Grep: regexp.MustCompile("ERROR"), JSON: true})
+	assert.Equal(t, `{"instance":"m-1","time":"2026-10-01T12:00:02Z","message":"ERROR: disk full"}`+"\n", out)
+}
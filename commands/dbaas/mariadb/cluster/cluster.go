@@ -32,6 +32,7 @@ func Root() *core.Command {
 	cmd.AddCommand(Update())
 	cmd.AddCommand(Get())
 	cmd.AddCommand(Delete())
+	cmd.AddCommand(Connect())
 
 	return cmd
 }
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/connect"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/mariadb/v2"
+	"github.com/spf13/viper"
+)
+
+func Connect() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "dbaas-mariadb",
+		Resource:  "cluster",
+		Verb:      "connect",
+		ShortDesc: "Connect to a MariaDB Cluster with the mariadb client",
+		LongDesc: `Connect to a MariaDB Cluster with the mariadb client, with TLS required. The mariadb client must be installed, and the cluster must be reachable from where the command runs.
+
+The user is taken from ` + "`--user`" + `, or prompted for. The client prompts for the password.
+
+Use ` + "`--print-uri`" + ` to only print the connection URI, e.g. for other clients.`,
+		Example: `ionosctl dbaas mariadb cluster connect --cluster-id <cluster-id> --user <user>
+ionosctl dbaas mariadb cluster connect --cluster-id <cluster-id> --user <user> --database <database> --print-uri`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return c.CheckRequiredFlagsAndLocation(constants.FlagClusterId)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
+
+			c.Verbose("Getting Cluster by id: %s", clusterId)
+
+			cluster, _, err := client.Must().MariaClient.ClustersApi.ClustersFindById(context.Background(), clusterId).Execute()
+			if err != nil {
+				return err
+			}
+			if cluster.Properties == nil || cluster.Properties.DnsName == nil || *cluster.Properties.DnsName == "" {
+				return fmt.Errorf("cluster %s has no DNS name yet", clusterId)
+			}
+
+			return connect.Run(c, connect.Target{Engine: connect.MariaDB, Host: *cluster.Properties.DnsName}, nil)
+		},
+		InitClient: true,
+	})
+
+	cmd.AddStringFlag(constants.FlagClusterId, constants.FlagIdShort, "", "The unique ID of the cluster",
+		core.RequiredFlagOption(),
+		core.WithCompletion(
+			func() []string {
+				return ClustersProperty(func(c mariadb.ClusterResponse) string {
+					if c.Id == nil {
+						return ""
+					}
+					return *c.Id
+				})
+			}, constants.MariaDBApiRegionalURL, constants.MariaDBLocations),
+	)
+	connect.AddFlags(cmd, connect.MariaDB, true)
+
+	cmd.Command.SilenceUsage = true
+
+	return cmd
+}
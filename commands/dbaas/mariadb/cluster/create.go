@@ -9,6 +9,7 @@ import (
 
 	cloudapiv6completer "github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -24,17 +25,34 @@ func Create() *core.Command {
 		constants.FlagName, constants.FlagVersion, constants.FlagDatacenterId, constants.FlagLanId, constants.FlagCidr,
 		constants.ArgUser, constants.ArgPassword,
 	}
-	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+	var doc map[string]any
+	cmd := core.NewCommandWithJsonProperties(context.TODO(), nil, jsonPropertiesExample, &doc, core.CommandBuilder{
 		Namespace: "dbaas-mariadb",
 		Resource:  "cluster",
 		Verb:      "create", // used in AVAILABLE COMMANDS in help
 		Aliases:   []string{"c"},
 		ShortDesc: "Create DBaaS MariaDB clusters",
-		Example:   fmt.Sprintf("i db mariadb cluster create %s", core.FlagsUsage(baseReqFlags...)),
+		Example: fmt.Sprintf("i db mariadb cluster create %s\n", core.FlagsUsage(baseReqFlags...)) +
+			"ionosctl dbaas mariadb cluster get --cluster-id <cluster-id> --as-create-payload > cluster.json\n" +
+			"ionosctl dbaas mariadb cluster create --json-properties cluster.json",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
-			return c.CheckRequiredFlagsAndLocation(baseReqFlags...)
+			return payload.PreRun(c, func(c *core.PreCommandConfig) error {
+				return c.CheckRequiredFlagsAndLocation(baseReqFlags...)
+			})
 		},
 		CmdRun: func(c *core.CommandConfig) error {
+			if doc != nil {
+				var input mariadb.CreateClusterRequest
+				if err := payload.Decode(doc, &input); err != nil {
+					return err
+				}
+				createdCluster, _, err := client.Must().MariaClient.ClustersApi.ClustersPost(context.Background()).CreateClusterRequest(input).Execute()
+				if err != nil {
+					return fmt.Errorf("failed creating cluster: %w", err)
+				}
+				return c.Printer(allCols).Print(createdCluster)
+			}
+
 			cluster := mariadb.CreateClusterProperties{}
 			if fn := core.GetFlagName(c.NS, constants.FlagName); viper.IsSet(fn) {
 				cluster.DisplayName = viper.GetString(fn)
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -17,7 +18,7 @@ func Get() *core.Command {
 		Verb:      "get",
 		Aliases:   []string{"g"},
 		ShortDesc: "Get a MariaDB Cluster by ID",
-		Example:   "ionosctl dbaas mariadb cluster get --cluster-id <cluster-id>",
+		Example:   "ionosctl dbaas mariadb cluster get --cluster-id <cluster-id>\nionosctl dbaas mariadb cluster get --cluster-id <cluster-id> --as-create-payload > cluster.json",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			return c.CheckRequiredFlagsAndLocation(constants.FlagClusterId)
 		},
@@ -31,6 +32,10 @@ func Get() *core.Command {
 				return err
 			}
 
+			if viper.GetBool(core.GetFlagName(c.NS, payload.FlagAsCreatePayload)) {
+				return payload.Print(c, createPayload(cluster))
+			}
+
 			return c.Printer(allCols).Print(cluster)
 		},
 		InitClient: true,
@@ -49,6 +54,8 @@ func Get() *core.Command {
 			}, constants.MariaDBApiRegionalURL, constants.MariaDBLocations),
 	)
 
+	payload.AddFlag(cmd, "ionosctl dbaas mariadb cluster create")
+
 	cmd.Command.SilenceUsage = true
 
 	return cmd
@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/mariadb/v2"
+)
+
+// createPayload converts a cluster into a request which creates a cluster like it.
+func createPayload(cluster mariadb.ClusterResponse) mariadb.CreateClusterRequest {
+	p := cluster.GetProperties()
+	props := mariadb.CreateClusterProperties{
+		MariadbVersion:    p.GetMariadbVersion(),
+		Instances:         p.GetInstances(),
+		Cores:             p.GetCores(),
+		Ram:               p.GetRam(),
+		StorageSize:       p.GetStorageSize(),
+		DisplayName:       p.GetDisplayName(),
+		MaintenanceWindow: p.MaintenanceWindow,
+		Backup:            p.Backup,
+		Credentials:       mariadb.DBUser{Username: payload.Username, Password: payload.Password},
+	}
+	for range p.Connections {
+		props.Connections = append(props.Connections, mariadb.Connection{
+			DatacenterId: payload.DatacenterId,
+			LanId:        payload.LanId,
+			Cidr:         payload.Cidr,
+		})
+	}
+	return mariadb.CreateClusterRequest{Properties: &props}
+}
+
+var jsonPropertiesExample = payload.Example(mariadb.CreateClusterRequest{Properties: &mariadb.CreateClusterProperties{
+	MariadbVersion:    "10.11",
+	Instances:         3,
+	Cores:             2,
+	Ram:               4,
+	StorageSize:       20,
+	DisplayName:       "example",
+	MaintenanceWindow: &mariadb.MaintenanceWindow{DayOfTheWeek: "Monday", Time: "12:00:00"},
+	Connections:       []mariadb.Connection{{DatacenterId: payload.DatacenterId, LanId: payload.LanId, Cidr: payload.Cidr}},
+	Credentials:       mariadb.DBUser{Username: payload.Username, Password: payload.Password},
+}})
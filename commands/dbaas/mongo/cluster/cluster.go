@@ -43,6 +43,7 @@ func ClusterCmd() *core.Command {
 	clusterCmd.AddCommand(ClusterGetCmd())
 	clusterCmd.AddCommand(ClusterDeleteCmd())
 	clusterCmd.AddCommand(ClusterRestoreCmd())
+	clusterCmd.AddCommand(ClusterConnectCmd())
 
 	return clusterCmd
 }
@@ -0,0 +1,71 @@
+package cluster
+
+import (
+	"context"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/connect"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/mongo/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ClusterConnectCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "dbaas-mongo",
+		Resource:  "cluster",
+		Verb:      "connect",
+		ShortDesc: "Connect to a Mongo Cluster with mongosh",
+		LongDesc: `Connect to a Mongo Cluster with mongosh, using the connection string of the cluster with TLS enabled. mongosh must be installed, and the cluster must be reachable from where the command runs.
+
+The user is taken from ` + "`--user`" + `. Otherwise, if the cluster has a single user, that user is used, else the user is prompted for. mongosh prompts for the password.
+
+Use ` + "`--print-uri`" + ` to only print the connection URI, e.g. for other clients.`,
+		Example: `ionosctl dbaas mongo cluster connect --cluster-id <cluster-id> --user <user>
+ionosctl dbaas mongo cluster connect --cluster-id <cluster-id> --user <user> --database <database> --print-uri`,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return c.Command.Command.MarkFlagRequired(constants.FlagClusterId)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
+
+			c.Verbose("Getting Cluster by id: %s", clusterId)
+
+			cluster, _, err := client.Must().MongoClient.ClustersApi.ClustersFindById(context.Background(), clusterId).Execute()
+			if err != nil {
+				return err
+			}
+
+			target := connect.Target{Engine: connect.Mongo}
+			if cluster.Properties != nil && cluster.Properties.ConnectionString != nil {
+				target.ConnectionString = *cluster.Properties.ConnectionString
+			}
+			return connect.Run(c, target, func() ([]string, error) {
+				users, _, err := client.Must().MongoClient.UsersApi.ClustersUsersGet(context.Background(), clusterId).Execute()
+				if err != nil {
+					return nil, err
+				}
+				var names []string
+				for _, u := range users.Items {
+					if u.Properties != nil {
+						names = append(names, u.Properties.Username)
+					}
+				}
+				return names, nil
+			})
+		},
+		InitClient: true,
+	})
+
+	cmd.AddStringFlag(constants.FlagClusterId, constants.FlagIdShort, "", "The unique ID of the cluster", core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(constants.FlagClusterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.MongoClusterIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	connect.AddFlags(cmd, connect.Mongo, true)
+
+	cmd.Command.SilenceUsage = true
+
+	return cmd
+}
@@ -12,6 +12,7 @@ import (
 	cloudapiv6completer "github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/mongo/completer"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/mongo/templates"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -44,9 +45,13 @@ func ClusterCreateCmd() *core.Command {
 		// Example where using --type creates a requirement for --shards
 		fmt.Sprintf("ionosctl dbaas mongo cluster create --%s enterprise --%s sharded-cluster %s",
 			constants.FlagEdition, constants.FlagType, core.FlagsUsage(enterpriseShardedRequired[1:]...)),
+		// Example which copies an existing cluster
+		"ionosctl dbaas mongo cluster get --cluster-id <cluster-id> --as-create-payload > cluster.json\n" +
+			"ionosctl dbaas mongo cluster create --json-properties cluster.json",
 	}
 
-	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+	var doc map[string]any
+	cmd := core.NewCommandWithJsonProperties(context.TODO(), nil, jsonPropertiesExample, &doc, core.CommandBuilder{
 		Namespace: "dbaas-mongo",
 		Resource:  "cluster",
 		Verb:      "create", // used in AVAILABLE COMMANDS in help
@@ -54,39 +59,52 @@ func ClusterCreateCmd() *core.Command {
 		ShortDesc: "Create DBaaS Mongo Replicaset or Sharded Clusters for your chosen edition",
 		Example:   strings.Join(examples, "\n\n"),
 		PreCmdRun: func(c *core.PreCommandConfig) error {
-			/*
-			 * For edition playground, only replica-set, =1 instance and playground template (33457e53-1f8b-4ed2-8a12-2d42355aa759, 1 core, 50 GB Storage, 2 GB RAM).
-			 * For edition business, only replica-set, >1 instance and any template.
-			 * For edition enterprise, type replica-set/sharded-cluster and
-			 *  - CPU Cores: 1-8
-			 *  - RAM Size (GB): <16 GB
-			 *  - Storage Size:  >100GB for optimal perf. max 1048.576 GB.
-			 *  - Shards: 2-32 shards. (Infer sharded-cluster if set. Else, replicaset). A sharded cluster can still have multiple replicas
-			 *  - Instances: >3
-			**/
-
-			err := validateOrInferEditionByTemplate(c) // sets FlagEdition if unset and possible to infer
-			if err != nil {
-				return fmt.Errorf("failed inferring or validating edition: %w", err)
-			}
+			return payload.PreRun(c, func(c *core.PreCommandConfig) error {
+				/*
+				 * For edition playground, only replica-set, =1 instance and playground template (33457e53-1f8b-4ed2-8a12-2d42355aa759, 1 core, 50 GB Storage, 2 GB RAM).
+				 * For edition business, only replica-set, >1 instance and any template.
+				 * For edition enterprise, type replica-set/sharded-cluster and
+				 *  - CPU Cores: 1-8
+				 *  - RAM Size (GB): <16 GB
+				 *  - Storage Size:  >100GB for optimal perf. max 1048.576 GB.
+				 *  - Shards: 2-32 shards. (Infer sharded-cluster if set. Else, replicaset). A sharded cluster can still have multiple replicas
+				 *  - Instances: >3
+				**/
+				err := validateOrInferEditionByTemplate(c) // sets FlagEdition if unset and possible to infer
+				if err != nil {
+					return fmt.Errorf("failed inferring or validating edition: %w", err)
+				}
 
-			err = inferTypeForEnterprise(c) // sets FlagType if unset and possible to infer
-			if err != nil {
-				return fmt.Errorf("failed inferring type: %w", err)
-			}
+				err = inferTypeForEnterprise(c) // sets FlagType if unset and possible to infer
+				if err != nil {
+					return fmt.Errorf("failed inferring type: %w", err)
+				}
 
-			err = validateEdition(c)
-			if err != nil {
-				return fmt.Errorf("failed validating edition specific flags: %w", err)
-			}
+				err = validateEdition(c)
+				if err != nil {
+					return fmt.Errorf("failed validating edition specific flags: %w", err)
+				}
 
-			err = inferLocationByDatacenter(c)
-			if err != nil {
-				return fmt.Errorf("failed inferring location: %w", err)
-			}
-			return nil
+				err = inferLocationByDatacenter(c)
+				if err != nil {
+					return fmt.Errorf("failed inferring location: %w", err)
+				}
+				return nil
+			})
 		},
 		CmdRun: func(c *core.CommandConfig) error {
+			if doc != nil {
+				var input mongo.CreateClusterRequest
+				if err := payload.Decode(doc, &input); err != nil {
+					return err
+				}
+				createdCluster, _, err := client.Must().MongoClient.ClustersApi.ClustersPost(context.Background()).CreateClusterRequest(input).Execute()
+				if err != nil {
+					return fmt.Errorf("failed creating cluster: %w", err)
+				}
+				return c.Printer(allCols).Print(createdCluster)
+			}
+
 			cluster := mongo.CreateClusterProperties{}
 			if fn := core.GetFlagName(c.NS, constants.FlagEdition); viper.IsSet(fn) {
 				cluster.Edition = pointer.From(viper.GetString(fn))
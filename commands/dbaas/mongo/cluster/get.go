@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/mongo/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
@@ -18,7 +19,7 @@ func ClusterGetCmd() *core.Command {
 		Verb:      "get",
 		Aliases:   []string{"g"},
 		ShortDesc: "Get a Mongo Cluster by ID",
-		Example:   "ionosctl dbaas mongo cluster get --cluster-id <cluster-id>",
+		Example:   "ionosctl dbaas mongo cluster get --cluster-id <cluster-id>\nionosctl dbaas mongo cluster get --cluster-id <cluster-id> --as-create-payload > cluster.json",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			return c.Command.Command.MarkFlagRequired(constants.FlagClusterId)
 		},
@@ -32,6 +33,10 @@ func ClusterGetCmd() *core.Command {
 				return err
 			}
 
+			if viper.GetBool(core.GetFlagName(c.NS, payload.FlagAsCreatePayload)) {
+				return payload.Print(c, createPayload(cluster))
+			}
+
 			return c.Printer(allCols).Print(cluster)
 		},
 		InitClient: true,
@@ -42,6 +47,8 @@ func ClusterGetCmd() *core.Command {
 		return completer.MongoClusterIds(), cobra.ShellCompDirectiveNoFileComp
 	})
 
+	payload.AddFlag(cmd, "ionosctl dbaas mongo cluster create")
+
 	cmd.Command.SilenceUsage = true
 
 	return cmd
@@ -0,0 +1,58 @@
+package cluster
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/mongo/v2"
+)
+
+// createPayload converts a cluster into a request which creates a cluster like it. Clusters of a template leave
+// their resources to the template.
+func createPayload(cluster mongo.ClusterResponse) mongo.CreateClusterRequest {
+	p := cluster.GetProperties()
+	props := mongo.CreateClusterProperties{
+		Type:              p.Type,
+		TemplateID:        p.TemplateID,
+		MongoDBVersion:    p.GetMongoDBVersion(),
+		Instances:         p.GetInstances(),
+		Shards:            p.Shards,
+		Location:          p.GetLocation(),
+		Backup:            p.Backup,
+		DisplayName:       p.GetDisplayName(),
+		MaintenanceWindow: p.MaintenanceWindow,
+		Edition:           p.Edition,
+	}
+	if bi := p.BiConnector; bi != nil && bi.Enabled != nil {
+		// The host and port of the BI Connector are assigned by the server.
+		props.BiConnector = &mongo.BiConnectorProperties{Enabled: bi.Enabled}
+	}
+	if p.TemplateID == nil {
+		props.Cores = p.Cores
+		props.Ram = p.Ram
+		props.StorageSize = p.StorageSize
+		props.StorageType = p.StorageType
+	}
+	for _, conn := range p.Connections {
+		props.Connections = append(props.Connections, mongo.Connection{
+			DatacenterId: payload.DatacenterId,
+			LanId:        payload.LanId,
+			CidrList:     payload.Cidrs(len(conn.CidrList)),
+		})
+	}
+	return mongo.CreateClusterRequest{Properties: &props}
+}
+
+var jsonPropertiesExample = payload.Example(mongo.CreateClusterRequest{Properties: &mongo.CreateClusterProperties{
+	Type:              pointer.From("replicaset"),
+	Edition:           pointer.From("enterprise"),
+	MongoDBVersion:    "7.0",
+	Instances:         3,
+	Location:          "de/txl",
+	DisplayName:       "example",
+	MaintenanceWindow: &mongo.MaintenanceWindow{DayOfTheWeek: "Monday", Time: "12:00:00"},
+	Connections:       []mongo.Connection{{DatacenterId: payload.DatacenterId, LanId: payload.LanId, CidrList: payload.Cidrs(3)}},
+	Cores:             pointer.From[int32](2),
+	Ram:               pointer.From[int32](4096),
+	StorageSize:       pointer.From[int32](102400),
+	StorageType:       (*mongo.StorageType)(pointer.From("SSD Standard")),
+}})
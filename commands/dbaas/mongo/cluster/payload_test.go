@@ -0,0 +1,57 @@
+package cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/mongo/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePayload(t *testing.T) {
+	cluster := mongo.ClusterResponse{
+		Id: pointer.From("a6c9a5d7-0000-0000-0000-000000000000"),
+		Properties: &mongo.ClusterProperties{
+			DisplayName:      pointer.From("prod"),
+			MongoDBVersion:   pointer.From("7.0"),
+			Location:         pointer.From("de/txl"),
+			Instances:        pointer.From[int32](3),
+			TemplateID:       pointer.From("template"),
+			Cores:            pointer.From[int32](2),
+			ConnectionString: pointer.From("mongodb+srv://m-1.example.com"),
+			BiConnector: &mongo.BiConnectorProperties{
+				Enabled: pointer.From(true),
+				Host:    pointer.From("bi-1.example.com"),
+				Port:    pointer.From("27015"),
+			},
+			Connections: []mongo.Connection{{
+				DatacenterId: "dc",
+				LanId:        "1",
+				CidrList:     []string{"10.0.0.1/24", "10.0.0.2/24", "10.0.0.3/24"},
+			}},
+		},
+	}
+
+	req := createPayload(cluster)
+	props := req.Properties
+	assert.Equal(t, "prod", props.DisplayName)
+	assert.Equal(t, int32(3), props.Instances)
+	assert.Equal(t, "template", *props.TemplateID)
+	assert.Nil(t, props.Cores, "the template decides the resources")
+	assert.Equal(t, []mongo.Connection{{
+		DatacenterId: payload.DatacenterId,
+		LanId:        payload.LanId,
+		CidrList:     []string{"${CIDR_1}", "${CIDR_2}", "${CIDR_3}"},
+	}}, props.Connections)
+	assert.Equal(t, &mongo.BiConnectorProperties{Enabled: pointer.From(true)}, props.BiConnector)
+
+	b, err := json.Marshal(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(b), "a6c9a5d7")
+	assert.NotContains(t, string(b), "connectionString")
+	assert.NotContains(t, string(b), "bi-1.example.com")
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/logtail"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/mongo/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -29,7 +30,11 @@ func LogsListCmd() *core.Command {
 		Verb:      "list",
 		Aliases:   []string{"ls"},
 		ShortDesc: "List (and optionally filter) the logs of your Mongo Cluster. Use --cols message to see the logs messages.",
-		Example:   "ionosctl dbaas mongo logs list --cluster-id CLUSTER_ID --start -24h --end -20h --limit 1 --direction FORWARD --cols message",
+		LongDesc: `List (and optionally filter) the logs of your Mongo Cluster. Use --cols message to see the logs messages.
+
+Use ` + "`--follow`" + ` to keep printing new log lines as they arrive, each prefixed with the name of its instance. Following starts with the last ` + "`--limit`" + ` lines, or at ` + "`--startDate`" + ` or ` + "`--start`" + `. Use ` + "`--grep`" + ` to only print matching lines, and ` + "`-o json`" + ` to print every line as a JSON object on its own line.`,
+		Example: `ionosctl dbaas mongo logs list --cluster-id CLUSTER_ID --start -24h --end -20h --limit 1 --direction FORWARD --cols message
+ionosctl dbaas mongo logs list --cluster-id CLUSTER_ID --follow --grep 'Slow query'`,
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			err := c.Command.Command.MarkFlagRequired(constants.FlagClusterId)
 			if err != nil {
@@ -39,22 +44,31 @@ func LogsListCmd() *core.Command {
 			c.Command.Command.MarkFlagsMutuallyExclusive(flagStart, flagStartDuration)
 			c.Command.Command.MarkFlagsMutuallyExclusive(flagEnd, flagEndDuration)
 
-			return nil
+			return logtail.CheckFlags(c, flagEnd, flagEndDuration)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
 			c.Verbose("Getting logs of Cluster %s", clusterId)
 
-			req := client.Must().MongoClient.LogsApi.ClustersLogsGet(context.Background(), clusterId)
+			var start time.Time
 			if fn := core.GetFlagName(c.NS, flagStart); viper.IsSet(fn) {
-				start, err := time.Parse(time.RFC3339, viper.GetString(fn))
+				var err error
+				start, err = time.Parse(time.RFC3339, viper.GetString(fn))
 				if err != nil {
 					return fmt.Errorf("failed parsing start time as RFC3339: %w", err)
 				}
-				req = req.Start(start)
 			}
 			if fn := core.GetFlagName(c.NS, flagStartDuration); viper.IsSet(fn) {
-				start := time.Now().Add(viper.GetDuration(fn))
+				start = time.Now().Add(viper.GetDuration(fn))
+			}
+			limit := viper.GetInt32(core.GetFlagName(c.NS, flagLimit))
+
+			if viper.GetBool(core.GetFlagName(c.NS, logtail.FlagFollow)) {
+				return logtail.Run(c, followFetch(clusterId), start, limit)
+			}
+
+			req := client.Must().MongoClient.LogsApi.ClustersLogsGet(context.Background(), clusterId).Limit(limit)
+			if !start.IsZero() {
 				req = req.Start(start)
 			}
 
@@ -109,8 +123,30 @@ func LogsListCmd() *core.Command {
 	cmd.AddSetFlag(flagDirection, "", "", []string{"BACKWARD", "FORWARD"}, "The direction in which to scan through the logs. The logs are returned in order of the direction")
 	cmd.AddIntFlag(flagLimit, "", 100, "The maximal number of log lines to return. If the limit is reached then log lines will be cut at the end (respecting the scan direction). Must be between 1 - 5000")
 
+	logtail.AddFlags(cmd)
+
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
 
 	return cmd
 }
+
+func followFetch(clusterId string) logtail.FetchFunc {
+	return func(ctx context.Context, start, end time.Time, direction string, limit int32) ([]logtail.Line, error) {
+		req := client.Must().MongoClient.LogsApi.ClustersLogsGet(ctx, clusterId).End(end).Direction(direction).Limit(limit)
+		if !start.IsZero() {
+			req = req.Start(start)
+		}
+		logs, _, err := req.Execute()
+		if err != nil {
+			return nil, err
+		}
+		var lines []logtail.Line
+		for _, instance := range logs.GetInstances() {
+			for _, msg := range instance.GetMessages() {
+				lines = append(lines, logtail.Line{Instance: instance.GetName(), Time: msg.GetTime(), Message: msg.GetMessage()})
+			}
+		}
+		return lines, nil
+	}
+}
@@ -0,0 +1,85 @@
+// Package payload converts DBaaS clusters into the documents their create commands accept with --json-properties.
+package payload
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+)
+
+const FlagAsCreatePayload = "as-create-payload"
+
+// Placeholders replace the values which must differ between clusters, or which the API never returns. They are
+// environment variable references, so a payload can be filled in with e.g. envsubst.
+const (
+	DatacenterId = "${DATACENTER_ID}"
+	LanId        = "${LAN_ID}"
+	Cidr         = "${CIDR}"
+	Username     = "${DB_USERNAME}"
+	Password     = "${DB_PASSWORD}"
+)
+
+// Cidrs returns a CIDR placeholder for each of n instances, CIDR_1, CIDR_2 and so on.
+func Cidrs(n int) []string {
+	cidrs := make([]string, n)
+	for i := range cidrs {
+		cidrs[i] = fmt.Sprintf("${CIDR_%d}", i+1)
+	}
+	return cidrs
+}
+
+// AddFlag adds --as-create-payload to the get command of a product, whose create command is named.
+func AddFlag(cmd *core.Command, createCmd string) {
+	cmd.AddBoolFlag(FlagAsCreatePayload, "", false, fmt.Sprintf(
+		"Print the cluster as a document for '%s --%s', instead of the cluster. Server-managed fields are left out, "+
+			"the connection and credentials are placeholders like %s", createCmd, constants.FlagJsonProperties, DatacenterId))
+}
+
+// Print writes the payload as indented JSON.
+func Print(c *core.CommandConfig, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Command.Command.OutOrStdout(), "%s\n", b)
+	return err
+}
+
+// Example renders a payload as the example printed by --json-properties-example.
+func Example(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	return string(b) + "\n"
+}
+
+// PreRun checks the flags of a create command with --json-properties. Printing the example needs no flags, and
+// reading the properties only needs the location of regional APIs. Without either, the flags are checked by
+// checkFlags.
+func PreRun(c *core.PreCommandConfig, checkFlags func(c *core.PreCommandConfig) error) error {
+	flags := c.Command.Command.Flags()
+	if flags.Changed(constants.FlagJsonPropertiesExample) {
+		return nil
+	}
+	if flags.Changed(constants.FlagJsonProperties) {
+		return c.CheckRequiredFlagsAndLocation()
+	}
+	return checkFlags(c)
+}
+
+// Decode decodes the document which core.NewCommandWithJsonProperties read into doc into a request of the SDK.
+// The document is encoded again and decoded with encoding/json, so the JSON tags and custom decoding of the SDK
+// types apply. Keys are matched case-insensitively.
+func Decode(doc map[string]any, v any) error {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("invalid --%s: %w", constants.FlagJsonProperties, err)
+	}
+	return nil
+}
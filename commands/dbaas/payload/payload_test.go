@@ -0,0 +1,47 @@
+package payload
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/inmemorydb/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCidrs(t *testing.T) {
+	assert.Equal(t, []string{"${CIDR_1}", "${CIDR_2}"}, Cidrs(2))
+	assert.Empty(t, Cidrs(0))
+}
+
+func TestDecode(t *testing.T) {
+	// Viper lowercases the keys of --json-properties files.
+	doc := map[string]any{
+		"properties": map[string]any{
+			"displayname": "example",
+			"replicas":    2,
+			"credentials": map[string]any{
+				"username": "admin",
+				"password": map[string]any{"hash": "abc", "algorithm": "SHA-256"},
+			},
+		},
+	}
+	var rs inmemorydb.ReplicaSetCreate
+	if !assert.NoError(t, Decode(doc, &rs)) {
+		return
+	}
+	assert.Equal(t, "example", rs.Properties.DisplayName)
+	assert.Equal(t, int32(2), rs.Properties.Replicas)
+	assert.Equal(t, "admin", rs.Properties.Credentials.Username)
+	if assert.NotNil(t, rs.Properties.Credentials.Password) && assert.NotNil(t, rs.Properties.Credentials.Password.HashedPassword) {
+		assert.Equal(t, "abc", rs.Properties.Credentials.Password.HashedPassword.Hash)
+	}
+
+	assert.Error(t, Decode(map[string]any{"properties": "invalid"}, &rs))
+}
+
+func TestExample(t *testing.T) {
+	example := Example(map[string]any{"properties": map[string]any{"lanId": LanId}})
+	var doc map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(example), &doc))
+	assert.Equal(t, map[string]any{"properties": map[string]any{"lanId": LanId}}, doc)
+}
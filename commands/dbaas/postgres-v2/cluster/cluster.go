@@ -61,6 +61,7 @@ func ClusterCmd() *core.Command {
 	clusterCmd.AddCommand(ClusterDeleteCmd())
 	clusterCmd.AddCommand(ClusterGetCmd())
 	clusterCmd.AddCommand(ClusterRestoreCmd())
+	clusterCmd.AddCommand(ClusterConnectCmd())
 
 	return clusterCmd
 }
@@ -0,0 +1,72 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/connect"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres-v2/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+func ClusterConnectCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "dbaas-postgres-v2",
+		Resource:  "cluster",
+		Verb:      "connect",
+		Aliases:   []string{"psql"},
+		ShortDesc: "Connect to a PostgreSQL Cluster with psql",
+		LongDesc: `Use this command to open a psql session to a PostgreSQL Cluster, with TLS required. psql must be installed, and the cluster must be reachable from where the command runs.
+
+By default, the command connects as the initial user of the cluster, to its initial database. psql prompts for the password, or takes it from the PGPASSWORD environment variable.
+
+Use ` + "`--print-uri`" + ` to only print the connection URI, e.g. for other clients.
+
+Required values to run command:
+
+* Cluster Id`,
+		Example: `ionosctl dbaas postgres-v2 cluster connect --cluster-id <cluster-id>
+ionosctl dbaas postgres-v2 cluster connect --cluster-id <cluster-id> --user <user> --database <database> --print-uri`,
+		PreCmdRun:  PreRunClusterId,
+		CmdRun:     RunClusterConnect,
+		InitClient: true,
+	})
+
+	cmd.AddUUIDFlag(constants.FlagClusterId, constants.FlagIdShort, "", constants.DescCluster, core.RequiredFlagOption(),
+		core.WithCompletion(completer.ClusterIds, constants.PostgresApiRegionalURL, constants.PostgresLocations),
+	)
+	connect.AddFlags(cmd, connect.Postgres, true)
+
+	cmd.Command.SilenceUsage = true
+
+	return cmd
+}
+
+func RunClusterConnect(c *core.CommandConfig) error {
+	clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
+	c.Verbose("Getting Cluster %s...", clusterId)
+
+	cluster, _, err := client.Must().PostgresClientV2.ClustersApi.ClustersFindById(context.Background(), clusterId).Execute()
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+	if cluster.Metadata.DnsName == nil || *cluster.Metadata.DnsName == "" {
+		return fmt.Errorf("cluster %s has no DNS name yet", clusterId)
+	}
+
+	target := connect.Target{
+		Engine:   connect.Postgres,
+		Host:     *cluster.Metadata.DnsName,
+		Database: "postgres",
+	}
+	if creds := cluster.Properties.Credentials; creds != nil {
+		target.User = creds.Username
+		if creds.Database != "" {
+			target.Database = creds.Database
+		}
+	}
+	return connect.Run(c, target, nil)
+}
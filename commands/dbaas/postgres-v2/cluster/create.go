@@ -8,6 +8,7 @@ import (
 	"time"
 
 	cloudapiv6completer "github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres-v2/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -29,7 +30,8 @@ func ClusterCreateCmd() *core.Command {
 	defaultMaintenanceDay := workingDaysOfWeek[r.Intn(len(workingDaysOfWeek))]
 	defaultMaintenanceTime := fmt.Sprintf("%02d:00:00", hour)
 
-	create := core.NewCommand(ctx, nil, core.CommandBuilder{
+	var doc map[string]any
+	create := core.NewCommandWithJsonProperties(ctx, nil, jsonPropertiesExample, &doc, core.CommandBuilder{
 		Namespace: "dbaas-postgres-v2",
 		Resource:  "cluster",
 		Verb:      "create",
@@ -43,10 +45,24 @@ Required values to run command:
 * Lan Id
 * CIDR (IP and subnet)
 * PostgreSQL Version
-* Credentials for the database user: Username, Password, and Database name`,
-		Example:    "ionosctl dbaas postgres-v2 cluster create --datacenter-id <datacenter-id> --lan-id <lan-id> --cidr <cidr> --db-username <username> --db-password <password> --database <database> --version <version>",
-		PreCmdRun:  PreRunClusterCreate,
-		CmdRun:     RunClusterCreate,
+* Credentials for the database user: Username, Password, and Database name
+
+The properties can also be read from a JSON file with --json-properties, e.g. one printed by 'ionosctl dbaas postgres-v2 cluster get --as-create-payload'. The other flags are ignored then.`,
+		Example: "ionosctl dbaas postgres-v2 cluster create --datacenter-id <datacenter-id> --lan-id <lan-id> --cidr <cidr> --db-username <username> --db-password <password> --database <database> --version <version>\n" +
+			"ionosctl dbaas postgres-v2 cluster create --json-properties cluster.json",
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return payload.PreRun(c, PreRunClusterCreate)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			if doc != nil {
+				var input psqlv2.ClusterCreate
+				if err := payload.Decode(doc, &input); err != nil {
+					return err
+				}
+				return createCluster(c, input)
+			}
+			return RunClusterCreate(c)
+		},
 		InitClient: true,
 	})
 	create.AddStringFlag(constants.FlagVersion, constants.FlagVersionShortPsql, "", "The PostgreSQL version of your Cluster", core.RequiredFlagOption())
@@ -129,7 +145,10 @@ func RunClusterCreate(c *core.CommandConfig) error {
 	if err != nil {
 		return err
 	}
+	return createCluster(c, input)
+}
 
+func createCluster(c *core.CommandConfig, input psqlv2.ClusterCreate) error {
 	c.Verbose("Creating Cluster...")
 
 	cluster, _, err := client.Must().PostgresClientV2.ClustersApi.ClustersPost(context.Background()).ClusterCreate(input).Execute()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres-v2/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -20,7 +21,7 @@ func ClusterGetCmd() *core.Command {
 		Verb:       "get",
 		Aliases:    []string{"g"},
 		ShortDesc:  "Get a PostgreSQL Cluster",
-		Example:    "ionosctl dbaas postgres-v2 cluster get --cluster-id <cluster-id>",
+		Example:    "ionosctl dbaas postgres-v2 cluster get --cluster-id <cluster-id>\nionosctl dbaas postgres-v2 cluster get --cluster-id <cluster-id> --as-create-payload > cluster.json",
 		LongDesc:   "Use this command to retrieve details about a PostgreSQL Cluster by using its ID.\n\nRequired values to run command:\n\n* Cluster Id",
 		PreCmdRun:  PreRunClusterId,
 		CmdRun:     RunClusterGet,
@@ -30,6 +31,7 @@ func ClusterGetCmd() *core.Command {
 	get.AddUUIDFlag(constants.FlagClusterId, constants.FlagIdShort, "", constants.DescCluster, core.RequiredFlagOption(),
 		core.WithCompletion(completer.ClusterIds, constants.PostgresApiRegionalURL, constants.PostgresLocations),
 	)
+	payload.AddFlag(get, "ionosctl dbaas postgres-v2 cluster create")
 	return get
 }
 
@@ -47,6 +49,10 @@ func RunClusterGet(c *core.CommandConfig) error {
 		return fmt.Errorf("could not get cluster: %w", err)
 	}
 
+	if viper.GetBool(core.GetFlagName(c.NS, payload.FlagAsCreatePayload)) {
+		return payload.Print(c, createPayload(cluster))
+	}
+
 	cols, _ := c.Command.Command.Flags().GetStringSlice(constants.ArgCols)
 
 	return c.Out(table.Sprint(clusterCols, cluster, cols))
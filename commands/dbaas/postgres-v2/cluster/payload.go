@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	psqlv2 "github.com/ionos-cloud/sdk-go-bundle/products/dbaas/psql/v3"
+)
+
+// createPayload converts a cluster into a request which creates a cluster like it. The user and database keep
+// their names, the password is never returned and gets a placeholder.
+func createPayload(cluster psqlv2.ClusterRead) psqlv2.ClusterCreate {
+	p := cluster.Properties
+	props := psqlv2.ClusterCreateProperties{
+		Name:              p.Name,
+		Description:       p.Description,
+		Version:           p.Version,
+		Instances:         p.Instances,
+		MaintenanceWindow: p.MaintenanceWindow,
+		ReplicationMode:   p.ReplicationMode,
+		ConnectionPooler:  p.ConnectionPooler,
+		BackupLocation:    p.BackupLocation,
+		LogsEnabled:       p.LogsEnabled,
+		MetricsEnabled:    p.MetricsEnabled,
+		Connection: psqlv2.PostgresClusterConnection{
+			DatacenterId:           payload.DatacenterId,
+			LanId:                  payload.LanId,
+			PrimaryInstanceAddress: payload.Cidr,
+		},
+		Credentials: psqlv2.PostgresUser{Username: payload.Username, Password: payload.Password},
+	}
+	if p.Credentials != nil {
+		props.Credentials.Username = p.Credentials.Username
+		props.Credentials.Database = p.Credentials.Database
+	}
+	return psqlv2.ClusterCreate{Properties: props}
+}
+
+var jsonPropertiesExample = payload.Example(psqlv2.ClusterCreate{Properties: psqlv2.ClusterCreateProperties{
+	Name:              "example",
+	Version:           pointer.From("17"),
+	Instances:         psqlv2.InstanceConfiguration{Count: 1, Cores: 2, Ram: 4, StorageSize: 20},
+	MaintenanceWindow: psqlv2.MaintenanceWindow{DayOfTheWeek: "Monday", Time: "12:00:00"},
+	ReplicationMode:   psqlv2.POSTGRESCLUSTERREPLICATIONMODE_ASYNCHRONOUS,
+	ConnectionPooler:  pointer.From("DISABLED"),
+	BackupLocation:    "eu-central-4",
+	Connection: psqlv2.PostgresClusterConnection{
+		DatacenterId:           payload.DatacenterId,
+		LanId:                  payload.LanId,
+		PrimaryInstanceAddress: payload.Cidr,
+	},
+	Credentials: psqlv2.PostgresUser{Username: payload.Username, Password: payload.Password, Database: "example"},
+}})
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	cloudapiv6completer "github.com/ionos-cloud/ionosctl/v6/commands/compute/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -70,11 +71,13 @@ func ClusterCmd() *core.Command {
 	_ = get.Command.RegisterFlagCompletionFunc(constants.FlagClusterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completer.ClustersIds(), cobra.ShellCompDirectiveNoFileComp
 	})
+	payload.AddFlag(get, "ionosctl dbaas postgres cluster create")
 
 	/*
 		Create Command
 	*/
-	create := core.NewCommand(ctx, clusterCmd, core.CommandBuilder{
+	var createDoc map[string]any
+	create := core.NewCommandWithJsonProperties(ctx, clusterCmd, clusterJSONPropertiesExample, &createDoc, core.CommandBuilder{
 		Namespace: "dbaas-postgres",
 		Resource:  "cluster",
 		Verb:      "create",
@@ -87,10 +90,23 @@ Required values to run command:
 * Datacenter Id
 * Lan Id
 * CIDR (IP and subnet)
-* Credentials for the database user: Username and Password`,
-		Example:    createClusterExample,
-		PreCmdRun:  PreRunClusterCreate,
-		CmdRun:     RunClusterCreate,
+* Credentials for the database user: Username and Password
+
+The properties can also be read from a JSON file with --json-properties, e.g. one printed by 'ionosctl dbaas postgres cluster get --as-create-payload'. The other flags are ignored then.`,
+		Example: createClusterExample,
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return payload.PreRun(c, PreRunClusterCreate)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			if createDoc != nil {
+				var input psql.CreateClusterRequest
+				if err := payload.Decode(createDoc, &input); err != nil {
+					return err
+				}
+				return createCluster(c, input)
+			}
+			return RunClusterCreate(c)
+		},
 		InitClient: true,
 	})
 	create.AddStringFlag(constants.FlagVersion, constants.FlagVersionShortPsql, "15", "The PostgreSQL version of your Cluster")
@@ -276,6 +292,8 @@ Required values to run command:
 	deleteCmd.AddBoolFlag(constants.ArgAll, constants.ArgAllShort, false, "Delete all Clusters")
 	deleteCmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Delete all Clusters after filtering based on name. It does not require an exact match. Can be used with --all flag")
 
+	clusterCmd.AddCommand(ClusterConnectCmd())
+	clusterCmd.AddCommand(ClusterRestorePlanCmd())
 	clusterCmd.AddCommand(ClusterBackupCmd())
 
 	return clusterCmd
@@ -350,6 +368,10 @@ func RunClusterGet(c *core.CommandConfig) error {
 		return fmt.Errorf("could not get cluster: %w", err)
 	}
 
+	if viper.GetBool(core.GetFlagName(c.NS, payload.FlagAsCreatePayload)) {
+		return payload.Print(c, createPayload(cluster))
+	}
+
 	return c.Printer(allClusterCols).Print(cluster)
 }
 
@@ -358,7 +380,10 @@ func RunClusterCreate(c *core.CommandConfig) error {
 	if err != nil {
 		return err
 	}
+	return createCluster(c, input)
+}
 
+func createCluster(c *core.CommandConfig, input psql.CreateClusterRequest) error {
 	c.Verbose("Creating Cluster...")
 
 	cluster, _, err := client.Must().PostgresClient.ClustersApi.ClustersPost(context.Background()).
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/connect"
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func ClusterConnectCmd() *core.Command {
+	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+		Namespace: "dbaas-postgres",
+		Resource:  "cluster",
+		Verb:      "connect",
+		Aliases:   []string{"psql"},
+		ShortDesc: "Connect to a PostgreSQL Cluster with psql",
+		LongDesc: `Use this command to open a psql session to a PostgreSQL Cluster, with TLS required. psql must be installed, and the cluster must be reachable from where the command runs.
+
+The user is taken from ` + "`--user`" + `. Otherwise, if the cluster has a single user which is not a system user, that user is used, else the user is prompted for. psql prompts for the password, or takes it from the PGPASSWORD environment variable.
+
+Use ` + "`--print-uri`" + ` to only print the connection URI, e.g. for other clients.
+
+Required values to run command:
+
+* Cluster Id`,
+		Example: `ionosctl dbaas postgres cluster connect --cluster-id <cluster-id> --user <user>
+ionosctl dbaas postgres cluster connect --cluster-id <cluster-id> --user <user> --database <database> --print-uri`,
+		PreCmdRun:  PreRunClusterId,
+		CmdRun:     RunClusterConnect,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(constants.FlagClusterId, constants.FlagIdShort, "", constants.DescCluster, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(constants.FlagClusterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ClustersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	connect.AddFlags(cmd, connect.Postgres, true)
+
+	cmd.Command.SilenceUsage = true
+
+	return cmd
+}
+
+func RunClusterConnect(c *core.CommandConfig) error {
+	clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
+	c.Verbose("Getting Cluster %s...", clusterId)
+
+	cluster, _, err := client.Must().PostgresClient.ClustersApi.ClustersFindById(context.Background(), clusterId).Execute()
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+	if cluster.Properties == nil || cluster.Properties.DnsName == nil || *cluster.Properties.DnsName == "" {
+		return fmt.Errorf("cluster %s has no DNS name yet", clusterId)
+	}
+
+	target := connect.Target{
+		Engine:   connect.Postgres,
+		Host:     *cluster.Properties.DnsName,
+		Database: "postgres",
+	}
+	return connect.Run(c, target, func() ([]string, error) {
+		users, _, err := client.Must().PostgresClient.UsersApi.UsersList(context.Background(), clusterId).Execute()
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, u := range users.Items {
+			if u.Properties.System != nil && *u.Properties.System {
+				continue
+			}
+			names = append(names, u.Properties.Username)
+		}
+		return names, nil
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/logtail"
 	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -44,7 +45,7 @@ func LogsCmd() *core.Command {
 		Aliases:    []string{"l", "ls"},
 		ShortDesc:  "List Logs for a PostgreSQL Cluster",
 		Example:    listLogsExample,
-		LongDesc:   "Use this command to retrieve the Logs of a specified PostgreSQL Cluster. By default, the result will contain all Cluster Logs. You can specify the start time, end time or a limit for sorting Cluster Logs.\n\nRequired values to run command:\n\n* Cluster Id",
+		LongDesc:   "Use this command to retrieve the Logs of a specified PostgreSQL Cluster. By default, the result will contain all Cluster Logs. You can specify the start time, end time or a limit for sorting Cluster Logs.\n\nUse `--follow` to keep printing new log lines as they arrive, each prefixed with the name of its instance. Following starts with the last `--limit` lines, or at `--start-time` or `--since`. Use `--grep` to only print matching lines, and `-o json` to print every line as a JSON object on its own line.\n\nRequired values to run command:\n\n* Cluster Id",
 		PreCmdRun:  PreRunClusterLogsList,
 		CmdRun:     RunClusterLogsList,
 		InitClient: true,
@@ -62,6 +63,7 @@ func LogsCmd() *core.Command {
 	_ = list.Command.RegisterFlagCompletionFunc(constants.FlagClusterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return completer.ClustersIds(), cobra.ShellCompDirectiveNoFileComp
 	})
+	logtail.AddFlags(list)
 
 	return clusterCmd
 }
@@ -79,6 +81,9 @@ func PreRunClusterLogsList(c *core.PreCommandConfig) error {
 			return errors.New("--until option must have suffix h(hours) or m(minutes). e.g.: --until 1h")
 		}
 	}
+	if err := logtail.CheckFlags(c, constants.FlagEndTime, constants.FlagUntil); err != nil {
+		return err
+	}
 	return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagClusterId)
 }
 
@@ -90,6 +95,10 @@ func RunClusterLogsList(c *core.CommandConfig) error {
 		return err
 	}
 
+	if viper.GetBool(core.GetFlagName(c.NS, logtail.FlagFollow)) {
+		return followClusterLogs(c, viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId)), queryParams)
+	}
+
 	c.Verbose("Getting Logs for the specified Cluster...")
 
 	req := client.Must().PostgresClient.LogsApi.
@@ -137,6 +146,30 @@ func RunClusterLogsList(c *core.CommandConfig) error {
 	return c.Printer(allClusterLogsCols).Print(rows)
 }
 
+func followClusterLogs(c *core.CommandConfig, clusterId string, queryParams *LogsQueryParams) error {
+	c.Verbose("Following Logs for the specified Cluster...")
+
+	fetch := func(ctx context.Context, start, end time.Time, direction string, limit int32) ([]logtail.Line, error) {
+		req := client.Must().PostgresClient.LogsApi.ClusterLogsGet(ctx, clusterId).End(end).Direction(direction).Limit(limit)
+		if !start.IsZero() {
+			req = req.Start(start)
+		}
+		clusterLogs, _, err := req.Execute()
+		if err != nil {
+			return nil, err
+		}
+		var lines []logtail.Line
+		for _, instance := range clusterLogs.GetInstances() {
+			for _, msg := range instance.GetMessages() {
+				lines = append(lines, logtail.Line{Instance: instance.GetName(), Time: msg.GetTime(), Message: msg.GetMessage()})
+			}
+		}
+		return lines, nil
+	}
+
+	return logtail.Run(c, fetch, queryParams.StartTime, queryParams.Limit)
+}
+
 type LogsQueryParams struct {
 	Direction          string
 	Limit              int32
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/payload"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/psql/v2"
+)
+
+// createPayload converts a cluster into a request which creates a cluster like it.
+func createPayload(cluster psql.ClusterResponse) psql.CreateClusterRequest {
+	p := cluster.GetProperties()
+	props := psql.CreateClusterProperties{
+		PostgresVersion:     p.GetPostgresVersion(),
+		Instances:           p.GetInstances(),
+		Cores:               p.GetCores(),
+		Ram:                 p.GetRam(),
+		StorageSize:         p.GetStorageSize(),
+		StorageType:         p.GetStorageType(),
+		Location:            p.GetLocation(),
+		BackupLocation:      p.BackupLocation,
+		DisplayName:         p.GetDisplayName(),
+		MaintenanceWindow:   p.MaintenanceWindow,
+		Credentials:         psql.DBUser{Username: payload.Username, Password: payload.Password},
+		SynchronizationMode: p.GetSynchronizationMode(),
+		ConnectionPooler:    p.ConnectionPooler,
+	}
+	for range p.Connections {
+		props.Connections = append(props.Connections, psql.Connection{
+			DatacenterId: payload.DatacenterId,
+			LanId:        payload.LanId,
+			Cidr:         payload.Cidr,
+		})
+	}
+	return psql.CreateClusterRequest{Properties: &props}
+}
+
+var clusterJSONPropertiesExample = payload.Example(psql.CreateClusterRequest{Properties: &psql.CreateClusterProperties{
+	PostgresVersion:     "15",
+	Instances:           1,
+	Cores:               2,
+	Ram:                 2048,
+	StorageSize:         20480,
+	StorageType:         psql.STORAGETYPE_SSD,
+	Location:            "de/fra",
+	BackupLocation:      pointer.From("de"),
+	DisplayName:         "example",
+	MaintenanceWindow:   &psql.MaintenanceWindow{DayOfTheWeek: "Monday", Time: "12:00:00"},
+	Connections:         []psql.Connection{{DatacenterId: payload.DatacenterId, LanId: payload.LanId, Cidr: payload.Cidr}},
+	Credentials:         psql.DBUser{Username: payload.Username, Password: payload.Password},
+	SynchronizationMode: psql.SYNCHRONIZATIONMODE_ASYNCHRONOUS,
+}})
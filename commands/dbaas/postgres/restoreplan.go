@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/dbaas/postgres/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/confirm"
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/psql/v2"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagTargetTime = "target-time"
+	flagClone      = "clone"
+	flagCloneName  = "clone-name"
+	flagExecute    = "execute"
+)
+
+var restorePlanCols = []table.Column{
+	{Name: "Action", JSONPath: "Action", Default: true},
+	{Name: "BackupId", JSONPath: "BackupId", Default: true},
+	{Name: "TargetTime", JSONPath: "TargetTime", Default: true},
+	{Name: "EarliestRecoveryTime", JSONPath: "EarliestRecoveryTime", Default: true},
+	{Name: "LatestRecoveryTime", JSONPath: "LatestRecoveryTime", Default: true},
+	{Name: "Target", JSONPath: "Target", Default: true},
+	{Name: "Version", JSONPath: "Version"},
+	{Name: "Location", JSONPath: "Location"},
+}
+
+func ClusterRestorePlanCmd() *core.Command {
+	cmd := core.NewCommand(context.TODO(), nil, core.CommandBuilder{
+		Namespace: "dbaas-postgres",
+		Resource:  "cluster",
+		Verb:      "restore-plan",
+		Aliases:   []string{"rp"},
+		ShortDesc: "Plan a point-in-time restore of a PostgreSQL Cluster",
+		LongDesc: `Use this command to plan a point-in-time restore of a PostgreSQL Cluster to ` + "`--target-time`" + `.
+
+The command finds the backup of the cluster which covers the target time, and shows its earliest and latest recovery times and what the restore will do. The latest recovery time of the active backup is now. The latest recovery time of an inactive backup is the earliest recovery time of the backup which replaced it. A target time outside of all backups is rejected.
+
+By default, the plan is only shown. Use ` + "`--execute`" + ` to run it. The restore overwrites the data of the cluster, unless ` + "`--clone`" + ` is set, which creates a new cluster from the backup instead. The clone has the same properties as the cluster, and is connected to the same LAN with ` + "`--cidr`" + `. Its database user is set with ` + "`--db-username`" + ` and ` + "`--db-password`" + `.
+
+Required values to run command:
+
+* Cluster Id
+* Target Time`,
+		Example: `ionosctl dbaas postgres cluster restore-plan --cluster-id <cluster-id> --target-time 2026-10-01T12:00:00Z
+ionosctl dbaas postgres cluster restore-plan --cluster-id <cluster-id> --target-time 2026-10-01T12:00:00Z --execute
+ionosctl dbaas postgres cluster restore-plan --cluster-id <cluster-id> --target-time 2026-10-01T12:00:00Z --clone --cidr 192.168.1.100/24 --db-username <username> --db-password <password> --execute`,
+		PreCmdRun:  PreRunClusterRestorePlan,
+		CmdRun:     RunClusterRestorePlan,
+		InitClient: true,
+	})
+	cmd.AddUUIDFlag(constants.FlagClusterId, constants.FlagIdShort, "", constants.DescCluster, core.RequiredFlagOption())
+	_ = cmd.Command.RegisterFlagCompletionFunc(constants.FlagClusterId, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completer.ClustersIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.AddStringFlag(flagTargetTime, "", "", "The time to restore the cluster to, in RFC3339 format. Example: 2026-10-01T12:00:00Z", core.RequiredFlagOption())
+	cmd.AddBoolFlag(flagExecute, "", false, "Run the plan. By default, the plan is only shown")
+	cmd.AddBoolFlag(flagClone, "", false, "Restore into a new cluster, instead of overwriting the cluster")
+	cmd.AddStringFlag(flagCloneName, "", "", "The name of the new cluster, with --clone. Defaults to the name of the cluster, followed by the target time")
+	cmd.AddStringFlag(constants.FlagCidr, "", "", "The IP and subnet of the new cluster in the LAN of the cluster, with --clone. Example: 192.168.1.100/24")
+	cmd.AddStringFlag(constants.FlagDbUsername, "", "", "The username of the database user of the new cluster, with --clone")
+	cmd.AddStringFlag(constants.FlagDbPassword, "", "", "The password of the database user of the new cluster, with --clone")
+
+	cmd.Command.SilenceUsage = true
+
+	return cmd
+}
+
+func PreRunClusterRestorePlan(c *core.PreCommandConfig) error {
+	if err := core.CheckRequiredFlags(c.Command, c.NS, constants.FlagClusterId, flagTargetTime); err != nil {
+		return err
+	}
+	if _, err := time.Parse(time.RFC3339, viper.GetString(core.GetFlagName(c.NS, flagTargetTime))); err != nil {
+		return fmt.Errorf("invalid --%s: %w", flagTargetTime, err)
+	}
+	if viper.GetBool(core.GetFlagName(c.NS, flagClone)) && viper.GetBool(core.GetFlagName(c.NS, flagExecute)) {
+		return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagCidr, constants.FlagDbUsername, constants.FlagDbPassword)
+	}
+	return nil
+}
+
+// recoveryWindow is the time range a backup can restore to.
+type recoveryWindow struct {
+	BackupId string
+	Earliest time.Time
+	Latest   time.Time
+	Active   bool
+	Version  string
+	Location string
+}
+
+// recoveryWindows returns the recovery windows of the backups of a cluster, oldest first. A backup covers the
+// time from its earliest recovery target time until the next backup starts, the active backup until now.
+func recoveryWindows(backups []psql.BackupResponse, now time.Time) []recoveryWindow {
+	var windows []recoveryWindow
+	for _, b := range backups {
+		if b.Id == nil || b.Properties == nil || b.Properties.EarliestRecoveryTargetTime == nil {
+			continue
+		}
+		windows = append(windows, recoveryWindow{
+			BackupId: *b.Id,
+			Earliest: b.Properties.GetEarliestRecoveryTargetTime(),
+			Active:   b.Properties.GetIsActive(),
+			Version:  b.Properties.GetVersion(),
+			Location: b.Properties.GetLocation(),
+		})
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].Earliest.Before(windows[j].Earliest) })
+
+	for i := range windows {
+		switch {
+		case windows[i].Active:
+			windows[i].Latest = now
+		case i+1 < len(windows):
+			windows[i].Latest = windows[i+1].Earliest
+		default:
+			// An inactive backup without a successor, its end is unknown.
+			windows[i].Latest = windows[i].Earliest
+		}
+	}
+	return windows
+}
+
+// pickBackup returns the newest recovery window which contains the target time.
+func pickBackup(windows []recoveryWindow, target time.Time) (recoveryWindow, error) {
+	for i := len(windows) - 1; i >= 0; i-- {
+		w := windows[i]
+		if !target.Before(w.Earliest) && !target.After(w.Latest) {
+			return w, nil
+		}
+	}
+	if len(windows) == 0 {
+		return recoveryWindow{}, errors.New("the cluster has no backups to restore from")
+	}
+	var ranges []string
+	for _, w := range windows {
+		ranges = append(ranges, fmt.Sprintf("%s - %s (backup %s)", w.Earliest.Format(time.RFC3339), w.Latest.Format(time.RFC3339), w.BackupId))
+	}
+	return recoveryWindow{}, fmt.Errorf("no backup covers %s, the cluster can be restored to:\n%s", target.Format(time.RFC3339), strings.Join(ranges, "\n"))
+}
+
+func RunClusterRestorePlan(c *core.CommandConfig) error {
+	clusterId := viper.GetString(core.GetFlagName(c.NS, constants.FlagClusterId))
+	target, err := time.Parse(time.RFC3339, viper.GetString(core.GetFlagName(c.NS, flagTargetTime)))
+	if err != nil {
+		return err
+	}
+	clone := viper.GetBool(core.GetFlagName(c.NS, flagClone))
+
+	c.Verbose("Getting Cluster %s...", clusterId)
+	cluster, _, err := client.Must().PostgresClient.ClustersApi.ClustersFindById(context.Background(), clusterId).Execute()
+	if err != nil {
+		return fmt.Errorf("could not get cluster: %w", err)
+	}
+	c.Verbose("Getting Backups of Cluster %s...", clusterId)
+	backups, _, err := client.Must().PostgresClient.BackupsApi.ClusterBackupsGet(context.Background(), clusterId).Execute()
+	if err != nil {
+		return fmt.Errorf("could not get backups: %w", err)
+	}
+
+	now := time.Now().UTC()
+	if target.After(now) {
+		return fmt.Errorf("the target time %s is in the future", target.Format(time.RFC3339))
+	}
+	window, err := pickBackup(recoveryWindows(backups.Items, now), target)
+	if err != nil {
+		return err
+	}
+
+	action, restoreTarget := "restore in place", fmt.Sprintf("cluster %s (overwritten)", clusterId)
+	cloneName := viper.GetString(core.GetFlagName(c.NS, flagCloneName))
+	if clone {
+		if cloneName == "" {
+			cloneName = fmt.Sprintf("%s-restore-%s", cluster.Properties.GetDisplayName(), target.UTC().Format("20060102T150405Z"))
+		}
+		action, restoreTarget = "restore into clone", fmt.Sprintf("new cluster %s", cloneName)
+	}
+
+	if !viper.GetBool(core.GetFlagName(c.NS, flagExecute)) {
+		return c.Printer(restorePlanCols).Print(map[string]any{
+			"Action":               action,
+			"BackupId":             window.BackupId,
+			"TargetTime":           target.UTC().Format(time.RFC3339),
+			"EarliestRecoveryTime": window.Earliest.UTC().Format(time.RFC3339),
+			"LatestRecoveryTime":   window.Latest.UTC().Format(time.RFC3339),
+			"Target":               restoreTarget,
+			"Version":              window.Version,
+			"Location":             window.Location,
+		})
+	}
+
+	restore := psql.CreateRestoreRequest{BackupId: window.BackupId}
+	restore.SetRecoveryTargetTime(target)
+
+	if clone {
+		input, err := cloneClusterRequest(c, cluster, cloneName, restore)
+		if err != nil {
+			return err
+		}
+		c.Verbose("Creating Cluster %s from Backup %s...", cloneName, window.BackupId)
+		created, _, err := client.Must().PostgresClient.ClustersApi.ClustersPost(context.Background()).CreateClusterRequest(input).Execute()
+		if err != nil {
+			return err
+		}
+		return c.Printer(allClusterCols).Print(created)
+	}
+
+	if !confirm.FAsk(c.Command.Command.InOrStdin(), fmt.Sprintf("overwrite cluster %s with backup %s at %s", clusterId, window.BackupId, target.Format(time.RFC3339)), viper.GetBool(constants.ArgForce)) {
+		return fmt.Errorf(confirm.UserDenied)
+	}
+	c.Verbose("Restoring Cluster from Backup %s...", window.BackupId)
+	_, err = client.Must().PostgresClient.RestoresApi.ClusterRestorePost(context.Background(), clusterId).CreateRestoreRequest(restore).Execute()
+	if err != nil {
+		return err
+	}
+	c.Msg("PostgreSQL Cluster restore to %s started", target.Format(time.RFC3339))
+	return nil
+}
+
+// cloneClusterRequest creates a cluster with the properties of the cluster, restored from a backup.
+func cloneClusterRequest(c *core.CommandConfig, cluster psql.ClusterResponse, name string, restore psql.CreateRestoreRequest) (psql.CreateClusterRequest, error) {
+	props := cluster.Properties
+	if props == nil || len(props.Connections) == 0 {
+		return psql.CreateClusterRequest{}, errors.New("the cluster has no connection to clone")
+	}
+
+	input := psql.CreateClusterProperties{
+		PostgresVersion: props.GetPostgresVersion(),
+		Instances:       props.GetInstances(),
+		Cores:           props.GetCores(),
+		Ram:             props.GetRam(),
+		StorageSize:     props.GetStorageSize(),
+		StorageType:     props.GetStorageType(),
+		Connections: []psql.Connection{{
+			DatacenterId: props.Connections[0].DatacenterId,
+			LanId:        props.Connections[0].LanId,
+			Cidr:         viper.GetString(core.GetFlagName(c.NS, constants.FlagCidr)),
+		}},
+		Location:    props.GetLocation(),
+		DisplayName: name,
+		Credentials: psql.DBUser{
+			Username: viper.GetString(core.GetFlagName(c.NS, constants.FlagDbUsername)),
+			Password: viper.GetString(core.GetFlagName(c.NS, constants.FlagDbPassword)),
+		},
+		SynchronizationMode: props.GetSynchronizationMode(),
+		FromBackup:          &restore,
+	}
+	if props.BackupLocation != nil {
+		input.SetBackupLocation(*props.BackupLocation)
+	}
+	if props.ConnectionPooler != nil {
+		input.SetConnectionPooler(*props.ConnectionPooler)
+	}
+
+	return psql.CreateClusterRequest{Properties: &input}, nil
+}
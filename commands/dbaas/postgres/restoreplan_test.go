@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ionos-cloud/sdk-go-bundle/products/dbaas/psql/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBackup(id string, earliest time.Time, active bool) psql.BackupResponse {
+	props := psql.ClusterBackup{}
+	props.SetEarliestRecoveryTargetTime(earliest)
+	props.SetIsActive(active)
+	return psql.BackupResponse{Id: &id, Properties: &props}
+}
+
+func TestRecoveryWindows(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	now := day(20)
+	windows := recoveryWindows([]psql.BackupResponse{
+		testBackup("new", day(10), true),
+		testBackup("old", day(1), false),
+		{Properties: &psql.ClusterBackup{}},
+	}, now)
+	if !assert.Len(t, windows, 2) {
+		return
+	}
+	assert.Equal(t, "old", windows[0].BackupId)
+	assert.Equal(t, day(10), windows[0].Latest)
+	assert.Equal(t, now, windows[1].Latest)
+
+	w, err := pickBackup(windows, day(5))
+	assert.NoError(t, err)
+	assert.Equal(t, "old", w.BackupId)
+
+	// At the boundary, the newer backup wins.
+	w, err = pickBackup(windows, day(10))
+	assert.NoError(t, err)
+	assert.Equal(t, "new", w.BackupId)
+
+	_, err = pickBackup(windows, day(1).Add(-time.Second))
+	assert.ErrorContains(t, err, "2026-10-01T00:00:00Z - 2026-10-10T00:00:00Z (backup old)")
+
+	_, err = pickBackup(nil, day(5))
+	assert.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package topic
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/wire"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/sdk-go-bundle/products/kafka/v2"
+)
+
+// addConnectFlags adds the flags needed to connect to the brokers of a cluster as a user.
+func addConnectFlags(cmd *core.Command) {
+	cmd.AddStringFlag(
+		constants.FlagClusterId, "", "", "The ID of the cluster",
+		core.RequiredFlagOption(), core.WithCompletion(
+			func() []string {
+				return completer.ClustersProperty(
+					func(read kafka.ClusterRead) string {
+						return read.Id
+					},
+				)
+			}, constants.KafkaApiRegionalURL, constants.KafkaLocations,
+		),
+	)
+	cmd.AddStringFlag(
+		constants.FlagKafkaTopicId, "", "", "The ID of the topic", core.RequiredFlagOption(),
+		core.WithCompletion(
+			func() []string {
+				return completer.Topics(cmd.Command.Flag(constants.FlagClusterId).Value.String())
+			}, constants.KafkaApiRegionalURL, constants.KafkaLocations,
+		),
+	)
+	cmd.AddStringFlag(
+		constants.FlagUserId, "", "", "The ID of the user whose certificate is used to connect to the brokers", core.RequiredFlagOption(),
+		core.WithCompletion(
+			func() []string {
+				return completer.Users(cmd.Command.Flag(constants.FlagClusterId).Value.String())
+			}, constants.KafkaApiRegionalURL, constants.KafkaLocations,
+		),
+	)
+	cmd.AddStringSliceFlag(
+		constants.FlagKafkaBrokerAddresses, "", nil,
+		"The broker addresses (host:port) to connect to. Defaults to the broker addresses of the cluster, "+
+			"set this if the brokers are reachable through other addresses, e.g. a port forwarding",
+	)
+}
+
+// connect looks up the cluster, topic and user credentials and connects to the brokers of the cluster with mutual TLS.
+// It returns the client and the name of the topic.
+func connect(ctx context.Context, cmd *core.CommandConfig) (*wire.Client, string, error) {
+	clusterID, _ := cmd.Command.Command.Flags().GetString(constants.FlagClusterId)
+	topicID, _ := cmd.Command.Command.Flags().GetString(constants.FlagKafkaTopicId)
+	userID, _ := cmd.Command.Command.Flags().GetString(constants.FlagUserId)
+	brokers, _ := cmd.Command.Command.Flags().GetStringSlice(constants.FlagKafkaBrokerAddresses)
+
+	if len(brokers) == 0 {
+		cluster, _, err := client.Must().Kafka.ClustersApi.ClustersFindById(ctx, clusterID).Execute()
+		if err != nil {
+			return nil, "", err
+		}
+		brokers = cluster.Metadata.BrokerAddresses
+		if len(brokers) == 0 {
+			return nil, "", fmt.Errorf("cluster %s has no broker addresses yet, its state is %s", clusterID, cluster.Metadata.State)
+		}
+	}
+
+	topic, _, err := client.Must().Kafka.TopicsApi.ClustersTopicsFindById(ctx, clusterID, topicID).Execute()
+	if err != nil {
+		return nil, "", err
+	}
+
+	access, _, err := client.Must().Kafka.UsersApi.ClustersUsersAccessGet(ctx, clusterID, userID).Execute()
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to get user's credentials: %w", err)
+	}
+	tlsConfig, err := userTLSConfig(access.Metadata)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cmd.Verbose("Connecting to brokers %v as user %s", brokers, access.Properties.Name)
+	c, err := wire.Dial(ctx, brokers, tlsConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	return c, topic.Properties.Name, nil
+}
+
+// userTLSConfig builds a TLS config which authenticates with the certificate of the user and trusts the CA of the cluster.
+func userTLSConfig(metadata kafka.UserAccessMetadata) (*tls.Config, error) {
+	cert, ok := metadata.GetCertificateOk()
+	if !ok || cert == nil {
+		return nil, fmt.Errorf("certificate not found in the response")
+	}
+	priv, ok := metadata.GetPrivateKeyOk()
+	if !ok || priv == nil {
+		return nil, fmt.Errorf("private key not found in the response")
+	}
+	ca, ok := metadata.GetCertificateAuthorityOk()
+	if !ok || ca == nil {
+		return nil, fmt.Errorf("CA certificate not found in the response")
+	}
+
+	keyPair, err := tls.X509KeyPair([]byte(*cert), []byte(*priv))
+	if err != nil {
+		return nil, fmt.Errorf("invalid user certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(*ca)) {
+		return nil, fmt.Errorf("invalid CA certificate")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
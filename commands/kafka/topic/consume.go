@@ -0,0 +1,211 @@
+package topic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/wire"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagFromBeginning = "from-beginning"
+	flagIdleTimeout   = "idle-timeout"
+	flagMax           = "max"
+	flagPartition     = "partition"
+	flagPrintKey      = "print-key"
+
+	// fetchWait is how long a fetch waits for new records of a partition.
+	fetchWait = 500 * time.Millisecond
+)
+
+// fetcher is the part of the Kafka client used for consuming.
+type fetcher interface {
+	Offset(ctx context.Context, topic string, partition int32, timestamp int64) (int64, error)
+	Fetch(ctx context.Context, topic string, partition int32, offset int64, maxWait time.Duration) ([]wire.Record, int64, error)
+}
+
+type consumeOptions struct {
+	fromBeginning bool
+	max           int
+	// idle stops consuming after no message arrived for this long, 0 waits forever.
+	idle time.Duration
+}
+
+// message is the JSON form of a consumed record.
+type message struct {
+	Partition int32             `json:"partition"`
+	Offset    int64             `json:"offset"`
+	Timestamp time.Time         `json:"timestamp"`
+	Key       *string           `json:"key"`
+	Value     string            `json:"value"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+func consumeCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.Background(), nil, core.CommandBuilder{
+			Verb:      "consume",
+			Namespace: "kafka",
+			Resource:  "topic",
+			ShortDesc: "Consume messages of a kafka topic",
+			LongDesc: `Consume messages of a kafka topic and print them to stdout, the value of every message on its own line.
+
+The command connects directly to the brokers of the cluster, authenticated with the certificate of the given user. The brokers must be reachable from where the command runs, e.g. from a server in the LAN of the cluster.
+
+By default, only messages produced after the command started are printed, use --from-beginning to print the existing messages too. The command stops after --max messages, or when no message arrived for --idle-timeout. No consumer group is used, so no offsets are committed.
+
+Messages compressed with snappy, lz4 or zstd cannot be decoded yet. They are skipped with a warning on stderr, gzip is supported.
+
+With '--output json', every message is printed as a JSON object on its own line, including partition, offset, timestamp, key and headers.`,
+			Aliases: []string{"c"},
+			Example: `ionosctl kafka topic consume --location de/fra --cluster-id CLUSTER_ID --topic-id TOPIC_ID --user-id USER_ID --from-beginning --max 10
+ionosctl kafka topic consume --location de/fra --cluster-id CLUSTER_ID --topic-id TOPIC_ID --user-id USER_ID --partition 0 --print-key --output json`,
+			PreCmdRun: func(cmd *core.PreCommandConfig) error {
+				return cmd.CheckRequiredFlagsAndLocation(constants.FlagClusterId, constants.FlagKafkaTopicId, constants.FlagUserId)
+			},
+			CmdRun: func(cmd *core.CommandConfig) error {
+				opts := consumeOptions{}
+				opts.fromBeginning, _ = cmd.Command.Command.Flags().GetBool(flagFromBeginning)
+				opts.max, _ = cmd.Command.Command.Flags().GetInt(flagMax)
+				opts.idle, _ = cmd.Command.Command.Flags().GetDuration(flagIdleTimeout)
+				partition, _ := cmd.Command.Command.Flags().GetInt32(flagPartition)
+				printKey, _ := cmd.Command.Command.Flags().GetBool(flagPrintKey)
+				separator, _ := cmd.Command.Command.Flags().GetString(flagKeySeparator)
+
+				ctx := cmd.Context
+				c, topic, err := connect(ctx, cmd)
+				if err != nil {
+					return err
+				}
+				defer c.Close()
+				c.OnSkipped = func(b wire.SkippedBatch) {
+					fmt.Fprintln(cmd.Command.Command.ErrOrStderr(), skippedWarning(topic, b))
+				}
+
+				all, err := c.Partitions(ctx, topic)
+				if err != nil {
+					return err
+				}
+				var partitions []int32
+				for _, p := range all {
+					if !cmd.Command.Command.Flags().Changed(flagPartition) || p.ID == partition {
+						partitions = append(partitions, p.ID)
+					}
+				}
+				if len(partitions) == 0 {
+					return fmt.Errorf("topic %s has no partition %d", topic, partition)
+				}
+
+				out := cmd.Command.Command.OutOrStdout()
+				output := viper.GetString(constants.ArgOutput)
+				n, err := consume(ctx, c, topic, partitions, opts, func(r wire.Record) error {
+					if output == "json" || output == "api-json" {
+						return writeJSONMessage(out, r)
+					}
+					if printKey {
+						_, err := fmt.Fprintf(out, "%s%s%s\n", r.Key, separator, r.Value)
+						return err
+					}
+					_, err := fmt.Fprintf(out, "%s\n", r.Value)
+					return err
+				})
+				if err != nil {
+					return err
+				}
+				cmd.Verbose("Consumed %d messages", n)
+				return nil
+			},
+			InitClient: true,
+		},
+	)
+
+	addConnectFlags(cmd)
+	cmd.AddBoolFlag(flagFromBeginning, "", false, "Start with the oldest message of every partition instead of the next new one")
+	cmd.AddIntFlag(flagMax, "", 0, "Stop after this many messages. 0 for no limit")
+	cmd.AddDurationFlag(flagIdleTimeout, "", 10*time.Second, "Stop when no message arrived for this long. 0 to wait forever")
+	cmd.AddInt32Flag(flagPartition, "", 0, "Only consume this partition. By default, all partitions are consumed")
+	cmd.AddBoolFlag(flagPrintKey, "", false, "Print the key of every message before its value, separated by the key separator")
+	cmd.AddStringFlag(flagKeySeparator, "", ":", "The separator between the key and value, with --print-key")
+
+	return cmd
+}
+
+// consume fetches the records of the partitions in turns and calls emit for each of them.
+// It returns the number of records emitted.
+func consume(ctx context.Context, f fetcher, topic string, partitions []int32, opts consumeOptions, emit func(wire.Record) error) (int, error) {
+	start := wire.LatestOffset
+	if opts.fromBeginning {
+		start = wire.EarliestOffset
+	}
+	offsets := map[int32]int64{}
+	for _, p := range partitions {
+		offset, err := f.Offset(ctx, topic, p, start)
+		if err != nil {
+			return 0, err
+		}
+		offsets[p] = offset
+	}
+
+	// Spread the wait over the partitions, so a round over all partitions takes about the same time for every topic.
+	wait := fetchWait / time.Duration(len(partitions))
+	n := 0
+	lastMessage := time.Now()
+	for {
+		for _, p := range partitions {
+			records, next, err := f.Fetch(ctx, topic, p, offsets[p], wait)
+			if err != nil {
+				return n, err
+			}
+			for _, r := range records {
+				if err := emit(r); err != nil {
+					return n, err
+				}
+				offsets[p] = r.Offset + 1
+				n++
+				if opts.max > 0 && n >= opts.max {
+					return n, nil
+				}
+			}
+			// Batches without records, e.g. transaction markers, still move the offset forward.
+			offsets[p] = max(offsets[p], next)
+			if len(records) > 0 {
+				lastMessage = time.Now()
+			}
+		}
+		if opts.idle > 0 && time.Since(lastMessage) >= opts.idle {
+			return n, nil
+		}
+	}
+}
+
+// skippedWarning is the warning for messages that are not printed, because their batch cannot be decoded.
+func skippedWarning(topic string, b wire.SkippedBatch) string {
+	return fmt.Sprintf("Warning: skipped messages %d to %d of partition %d of %s, they are compressed with %s, which is not supported, only gzip",
+		b.BaseOffset, b.LastOffset, b.Partition, topic, b.Compression)
+}
+
+func writeJSONMessage(w io.Writer, r wire.Record) error {
+	m := message{Partition: r.Partition, Offset: r.Offset, Timestamp: r.Timestamp, Value: string(r.Value)}
+	if r.Key != nil {
+		key := string(r.Key)
+		m.Key = &key
+	}
+	for _, h := range r.Headers {
+		if m.Headers == nil {
+			m.Headers = map[string]string{}
+		}
+		m.Headers[h.Key] = string(h.Value)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
@@ -0,0 +1,94 @@
+package topic
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func (f *fakeTopic) Offset(_ context.Context, _ string, partition int32, timestamp int64) (int64, error) {
+	if timestamp == wire.EarliestOffset {
+		return 0, nil
+	}
+	return int64(len(f.partitions[partition])), nil
+}
+
+func (f *fakeTopic) Fetch(_ context.Context, _ string, partition int32, offset int64, _ time.Duration) ([]wire.Record, int64, error) {
+	records := f.partitions[partition]
+	if offset >= int64(len(records)) {
+		return nil, offset, nil
+	}
+	// Return at most two records, like a broker limited by the maximum response size.
+	end := min(offset+2, int64(len(records)))
+	var fetched []wire.Record
+	for _, r := range records[offset:end] {
+		if !f.control[r.Offset] {
+			fetched = append(fetched, r)
+		}
+	}
+	return fetched, end, nil
+}
+
+func TestConsume(t *testing.T) {
+	f := &fakeTopic{partitions: make([][]wire.Record, 2)}
+	for i, v := range []string{"a", "b", "c", "d", "e"} {
+		_, _ = f.Produce(context.Background(), "events", int32(i%2), []wire.Record{{Value: []byte(v)}})
+	}
+
+	consumeValues := func(partitions []int32, opts consumeOptions) []string {
+		var values []string
+		n, err := consume(context.Background(), f, "events", partitions, opts, func(r wire.Record) error {
+			values = append(values, string(r.Value))
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, len(values), n)
+		return values
+	}
+
+	assert.Equal(t, []string{"a", "c", "b", "d", "e"}, consumeValues([]int32{0, 1}, consumeOptions{fromBeginning: true, idle: time.Millisecond}))
+	assert.Equal(t, []string{"a", "c", "b"}, consumeValues([]int32{0, 1}, consumeOptions{fromBeginning: true, max: 3}))
+	assert.Equal(t, []string{"b", "d"}, consumeValues([]int32{1}, consumeOptions{fromBeginning: true, idle: time.Millisecond}))
+	assert.Empty(t, consumeValues([]int32{0, 1}, consumeOptions{idle: time.Millisecond}))
+}
+
+func TestConsumeControlBatches(t *testing.T) {
+	f := &fakeTopic{partitions: make([][]wire.Record, 1), control: map[int64]bool{1: true, 2: true, 3: true}}
+	for _, v := range []string{"a", "", "", "", "b"} {
+		_, _ = f.Produce(context.Background(), "events", 0, []wire.Record{{Value: []byte(v)}})
+	}
+
+	var values []string
+	n, err := consume(context.Background(), f, "events", []int32{0}, consumeOptions{fromBeginning: true, idle: time.Second}, func(r wire.Record) error {
+		values = append(values, string(r.Value))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"a", "b"}, values)
+}
+
+func TestWriteJSONMessage(t *testing.T) {
+	var buf bytes.Buffer
+	ts := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	err := writeJSONMessage(&buf, wire.Record{Partition: 1, Offset: 42, Timestamp: ts, Key: []byte("k"), Value: []byte("v"),
+		Headers: []wire.Header{{Key: "source", Value: []byte("test")}}})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"partition":1,"offset":42,"timestamp":"2026-10-01T12:00:00Z","key":"k","value":"v","headers":{"source":"test"}}`+"\n", buf.String())
+	}
+
+	buf.Reset()
+	err = writeJSONMessage(&buf, wire.Record{Timestamp: ts, Value: []byte("v")})
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{"partition":0,"offset":0,"timestamp":"2026-10-01T12:00:00Z","key":null,"value":"v"}`+"\n", buf.String())
+	}
+}
+
+func TestSkippedWarning(t *testing.T) {
+	assert.Equal(t, "Warning: skipped messages 3 to 4 of partition 2 of events, they are compressed with zstd, which is not supported, only gzip",
+		skippedWarning("events", wire.SkippedBatch{Partition: 2, BaseOffset: 3, LastOffset: 4, Compression: "zstd"}))
+}
@@ -0,0 +1,193 @@
+package topic
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/wire"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+)
+
+const (
+	flagParseKey     = "parse-key"
+	flagKeySeparator = "key-separator"
+	flagHeader       = "header"
+	flagBatchSize    = "batch-size"
+)
+
+var produceCols = []table.Column{
+	{Name: "Topic", JSONPath: "topic", Default: true},
+	{Name: "Partition", JSONPath: "partition", Default: true},
+	{Name: "Messages", JSONPath: "messages", Default: true},
+	{Name: "FirstOffset", JSONPath: "firstOffset", Default: true},
+}
+
+// producer is the part of the Kafka client used for producing.
+type producer interface {
+	Produce(ctx context.Context, topic string, partition int32, records []wire.Record) (int64, error)
+}
+
+type produceOptions struct {
+	parseKey     bool
+	keySeparator string
+	headers      []wire.Header
+	batchSize    int
+}
+
+// produceResult summarizes the messages produced to a partition.
+type produceResult struct {
+	Topic       string `json:"topic"`
+	Partition   int32  `json:"partition"`
+	Messages    int    `json:"messages"`
+	FirstOffset int64  `json:"firstOffset"`
+}
+
+func produceCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.Background(), nil, core.CommandBuilder{
+			Verb:      "produce",
+			Namespace: "kafka",
+			Resource:  "topic",
+			ShortDesc: "Produce messages to a kafka topic, read from stdin",
+			LongDesc: `Produce messages to a kafka topic, one message per line of stdin. Empty lines are skipped.
+
+The command connects directly to the brokers of the cluster, authenticated with the certificate of the given user. The brokers must be reachable from where the command runs, e.g. from a server in the LAN of the cluster.
+
+With --parse-key, each line is split at the first --key-separator into the message key and value. Messages with a key are sent to the partition of the key, like the default partitioner of the Java client, the others are distributed round-robin over the partitions.
+
+The headers set with --header are added to every message. This command is meant for smoke tests, not for high throughput.`,
+			Aliases: []string{"p"},
+			Example: `echo hello | ionosctl kafka topic produce --location de/fra --cluster-id CLUSTER_ID --topic-id TOPIC_ID --user-id USER_ID
+printf 'user1:login\nuser2:logout\n' | ionosctl kafka topic produce --location de/fra --cluster-id CLUSTER_ID --topic-id TOPIC_ID --user-id USER_ID --parse-key --header source=smoke-test`,
+			PreCmdRun: func(cmd *core.PreCommandConfig) error {
+				return cmd.CheckRequiredFlagsAndLocation(constants.FlagClusterId, constants.FlagKafkaTopicId, constants.FlagUserId)
+			},
+			CmdRun: func(cmd *core.CommandConfig) error {
+				opts := produceOptions{}
+				opts.parseKey, _ = cmd.Command.Command.Flags().GetBool(flagParseKey)
+				opts.keySeparator, _ = cmd.Command.Command.Flags().GetString(flagKeySeparator)
+				opts.batchSize, _ = cmd.Command.Command.Flags().GetInt(flagBatchSize)
+				headers, _ := cmd.Command.Command.Flags().GetStringToString(flagHeader)
+				if opts.parseKey && opts.keySeparator == "" {
+					return fmt.Errorf("--%s must not be empty", flagKeySeparator)
+				}
+				if opts.batchSize < 1 {
+					return fmt.Errorf("--%s must be at least 1", flagBatchSize)
+				}
+				keys := make([]string, 0, len(headers))
+				for k := range headers {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				for _, k := range keys {
+					opts.headers = append(opts.headers, wire.Header{Key: k, Value: []byte(headers[k])})
+				}
+
+				ctx := cmd.Context
+				c, topic, err := connect(ctx, cmd)
+				if err != nil {
+					return err
+				}
+				defer c.Close()
+
+				partitions, err := c.Partitions(ctx, topic)
+				if err != nil {
+					return err
+				}
+
+				results, err := produceLines(ctx, c, topic, len(partitions), cmd.Command.Command.InOrStdin(), opts)
+				if err != nil {
+					return err
+				}
+				return cmd.Printer(produceCols).Print(results)
+			},
+			InitClient: true,
+		},
+	)
+
+	addConnectFlags(cmd)
+	cmd.AddBoolFlag(flagParseKey, "", false, "Split each line into the message key and value at the first key separator")
+	cmd.AddStringFlag(flagKeySeparator, "", ":", "The separator between the key and value of a line, with --parse-key")
+	cmd.AddStringToStringFlag(flagHeader, "", nil, "Headers to add to every message, e.g. --header source=test --header env=dev")
+	cmd.AddIntFlag(flagBatchSize, "", 500, "The maximum number of messages sent to a partition in one request")
+
+	return cmd
+}
+
+// produceLines produces every non-empty line of r as a message and returns a summary per partition, ordered by partition.
+func produceLines(ctx context.Context, p producer, topic string, partitions int, r io.Reader, opts produceOptions) ([]produceResult, error) {
+	pending := map[int32][]wire.Record{}
+	results := map[int32]*produceResult{}
+
+	flush := func(partition int32) error {
+		records := pending[partition]
+		if len(records) == 0 {
+			return nil
+		}
+		offset, err := p.Produce(ctx, topic, partition, records)
+		if err != nil {
+			return err
+		}
+		res, ok := results[partition]
+		if !ok {
+			res = &produceResult{Topic: topic, Partition: partition, FirstOffset: offset}
+			results[partition] = res
+		}
+		res.Messages += len(records)
+		pending[partition] = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	next := int32(0)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+
+		record := wire.Record{Timestamp: time.Now(), Value: []byte(text), Headers: opts.headers}
+		var partition int32
+		if opts.parseKey {
+			key, value, ok := strings.Cut(text, opts.keySeparator)
+			if !ok {
+				return nil, fmt.Errorf("line %d has no key separator %q", line, opts.keySeparator)
+			}
+			record.Key, record.Value = []byte(key), []byte(value)
+			partition = wire.PartitionForKey(record.Key, partitions)
+		} else {
+			partition = next
+			next = (next + 1) % int32(partitions)
+		}
+
+		pending[partition] = append(pending[partition], record)
+		if len(pending[partition]) >= opts.batchSize {
+			if err := flush(partition); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed reading stdin: %w", err)
+	}
+	for partition := range pending {
+		if err := flush(partition); err != nil {
+			return nil, err
+		}
+	}
+
+	summary := make([]produceResult, 0, len(results))
+	for _, res := range results {
+		summary = append(summary, *res)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Partition < summary[j].Partition })
+	return summary, nil
+}
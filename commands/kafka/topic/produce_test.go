@@ -0,0 +1,74 @@
+package topic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/kafka/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTopic stores produced records in memory, per partition.
+type fakeTopic struct {
+	partitions [][]wire.Record
+	// control holds the offsets of transaction markers, which are fetched as batches without records.
+	control  map[int64]bool
+	requests int
+}
+
+func (f *fakeTopic) Produce(_ context.Context, _ string, partition int32, records []wire.Record) (int64, error) {
+	f.requests++
+	offset := int64(len(f.partitions[partition]))
+	for i, r := range records {
+		r.Partition, r.Offset = partition, offset+int64(i)
+		f.partitions[partition] = append(f.partitions[partition], r)
+	}
+	return offset, nil
+}
+
+func TestProduceLines(t *testing.T) {
+	f := &fakeTopic{partitions: make([][]wire.Record, 3)}
+	f.partitions[2] = []wire.Record{{}}
+	headers := []wire.Header{{Key: "source", Value: []byte("test")}}
+
+	input := "one\r\ntwo\n\nthree\nfour\nfive"
+	results, err := produceLines(context.Background(), f, "events", 3, strings.NewReader(input), produceOptions{headers: headers, batchSize: 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []produceResult{
+		{Topic: "events", Partition: 0, Messages: 2, FirstOffset: 0},
+		{Topic: "events", Partition: 1, Messages: 2, FirstOffset: 0},
+		{Topic: "events", Partition: 2, Messages: 1, FirstOffset: 1},
+	}, results)
+	assert.Equal(t, 5, f.requests)
+	assert.Equal(t, "one", string(f.partitions[0][0].Value))
+	assert.Equal(t, "four", string(f.partitions[0][1].Value))
+	assert.Nil(t, f.partitions[0][0].Key)
+	assert.Equal(t, headers, f.partitions[1][0].Headers)
+}
+
+func TestProduceLinesWithKeys(t *testing.T) {
+	f := &fakeTopic{partitions: make([][]wire.Record, 4)}
+	opts := produceOptions{parseKey: true, keySeparator: ":", batchSize: 500}
+
+	results, err := produceLines(context.Background(), f, "events", 4, strings.NewReader("abc:1\nabc:2:x\n:3\n"), opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, f.requests)
+	p := wire.PartitionForKey([]byte("abc"), 4)
+	if assert.Len(t, f.partitions[p], 2) {
+		assert.Equal(t, "abc", string(f.partitions[p][1].Key))
+		assert.Equal(t, "2:x", string(f.partitions[p][1].Value))
+	}
+	total := 0
+	for _, r := range results {
+		total += r.Messages
+	}
+	assert.Equal(t, 3, total)
+
+	_, err = produceLines(context.Background(), f, "events", 4, strings.NewReader("abc:1\nno key\n"), opts)
+	assert.ErrorContains(t, err, `line 2 has no key separator ":"`)
+}
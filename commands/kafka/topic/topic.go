@@ -29,9 +29,11 @@ func Command() *core.Command {
 	cmd.AddColsFlag(allCols)
 
 	cmd.AddCommand(createCmd())
+	cmd.AddCommand(consumeCmd())
 	cmd.AddCommand(deleteCmd())
 	cmd.AddCommand(getCmd())
 	cmd.AddCommand(listCmd())
+	cmd.AddCommand(produceCmd())
 
 	return cmd
 }
@@ -0,0 +1,174 @@
+package user
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/keystore"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatPEM          = "pem"
+	formatProperties   = "properties"
+	formatLibrdkafka   = "librdkafka"
+	formatJavaKeystore = "java-keystore"
+	formatSpring       = "spring"
+
+	keystoreTypePKCS12 = "PKCS12"
+	keystoreTypeJKS    = "JKS"
+)
+
+var clientConfigFormats = []string{formatPEM, formatProperties, formatLibrdkafka, formatJavaKeystore, formatSpring}
+
+// credentials are the parsed certificate, key and CA of a user.
+type credentials struct {
+	key   crypto.PrivateKey
+	chain []*x509.Certificate
+	cas   []*x509.Certificate
+}
+
+func parseCredentials(cert, key, ca string) (credentials, error) {
+	pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return credentials{}, fmt.Errorf("invalid user certificate: %w", err)
+	}
+	creds := credentials{key: pair.PrivateKey}
+	for _, der := range pair.Certificate {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return credentials{}, fmt.Errorf("invalid user certificate: %w", err)
+		}
+		creds.chain = append(creds.chain, c)
+	}
+
+	rest := []byte(ca)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return credentials{}, fmt.Errorf("invalid CA certificate: %w", err)
+		}
+		creds.cas = append(creds.cas, c)
+	}
+	if len(creds.cas) == 0 {
+		return credentials{}, fmt.Errorf("no certificate found in the CA certificate")
+	}
+	return creds, nil
+}
+
+// javaStores are the Java keystore and truststore of a user, and how clients open them.
+type javaStores struct {
+	Type           string
+	Password       string
+	KeystorePath   string
+	TruststorePath string
+	Keystore       []byte
+	Truststore     []byte
+}
+
+// newJavaStores encodes the key and certificates of the user as a keystore, and the CA certificates as a truststore,
+// both of the type and protected by the password. The files are named after the user.
+func newJavaStores(creds credentials, storeType, name, password, dir string) (javaStores, error) {
+	encodeKeystore, encodeTruststore, ext := keystore.PKCS12, keystore.PKCS12Truststore, "p12"
+	if storeType == keystoreTypeJKS {
+		encodeKeystore, encodeTruststore, ext = keystore.JKS, keystore.JKSTruststore, "jks"
+	}
+
+	ks, err := encodeKeystore(creds.key, creds.chain, name, password)
+	if err != nil {
+		return javaStores{}, fmt.Errorf("failed to create keystore: %w", err)
+	}
+	ts, err := encodeTruststore(creds.cas, "ca", password)
+	if err != nil {
+		return javaStores{}, fmt.Errorf("failed to create truststore: %w", err)
+	}
+	return javaStores{
+		Type:           storeType,
+		Password:       password,
+		KeystorePath:   filepath.Join(dir, fmt.Sprintf("%s.keystore.%s", name, ext)),
+		TruststorePath: filepath.Join(dir, fmt.Sprintf("%s.truststore.%s", name, ext)),
+		Keystore:       ks,
+		Truststore:     ts,
+	}, nil
+}
+
+// renderProperties renders the configuration of the Java client, e.g. for kafka-console-producer.sh --producer.config.
+func renderProperties(bootstrap []string, stores javaStores) string {
+	escape := strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace
+	var b strings.Builder
+	fmt.Fprintf(&b, "bootstrap.servers=%s\n", strings.Join(bootstrap, ","))
+	fmt.Fprintln(&b, "security.protocol=SSL")
+	fmt.Fprintf(&b, "ssl.keystore.type=%s\n", stores.Type)
+	fmt.Fprintf(&b, "ssl.keystore.location=%s\n", escape(filepath.ToSlash(stores.KeystorePath)))
+	fmt.Fprintf(&b, "ssl.keystore.password=%s\n", escape(stores.Password))
+	fmt.Fprintf(&b, "ssl.key.password=%s\n", escape(stores.Password))
+	fmt.Fprintf(&b, "ssl.truststore.type=%s\n", stores.Type)
+	fmt.Fprintf(&b, "ssl.truststore.location=%s\n", escape(filepath.ToSlash(stores.TruststorePath)))
+	fmt.Fprintf(&b, "ssl.truststore.password=%s\n", escape(stores.Password))
+	return b.String()
+}
+
+// renderLibrdkafka renders the configuration of librdkafka based clients, e.g. kcat -F or confluent-kafka-python.
+func renderLibrdkafka(bootstrap []string, caPath, certPath, keyPath string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "bootstrap.servers=%s\n", strings.Join(bootstrap, ","))
+	fmt.Fprintln(&b, "security.protocol=ssl")
+	fmt.Fprintf(&b, "ssl.ca.location=%s\n", caPath)
+	fmt.Fprintf(&b, "ssl.certificate.location=%s\n", certPath)
+	fmt.Fprintf(&b, "ssl.key.location=%s\n", keyPath)
+	return b.String()
+}
+
+type springConfig struct {
+	Spring struct {
+		Kafka struct {
+			BootstrapServers []string `yaml:"bootstrap-servers"`
+			Security         struct {
+				Protocol string `yaml:"protocol"`
+			} `yaml:"security"`
+			SSL struct {
+				KeyStoreLocation   string `yaml:"key-store-location"`
+				KeyStorePassword   string `yaml:"key-store-password"`
+				KeyStoreType       string `yaml:"key-store-type"`
+				KeyPassword        string `yaml:"key-password"`
+				TrustStoreLocation string `yaml:"trust-store-location"`
+				TrustStorePassword string `yaml:"trust-store-password"`
+				TrustStoreType     string `yaml:"trust-store-type"`
+			} `yaml:"ssl"`
+		} `yaml:"kafka"`
+	} `yaml:"spring"`
+}
+
+// renderSpring renders the spring.kafka properties of Spring Boot as YAML.
+func renderSpring(bootstrap []string, stores javaStores) (string, error) {
+	var c springConfig
+	k := &c.Spring.Kafka
+	k.BootstrapServers = bootstrap
+	k.Security.Protocol = "SSL"
+	k.SSL.KeyStoreLocation = "file:" + filepath.ToSlash(stores.KeystorePath)
+	k.SSL.KeyStorePassword = stores.Password
+	k.SSL.KeyStoreType = stores.Type
+	k.SSL.KeyPassword = stores.Password
+	k.SSL.TrustStoreLocation = "file:" + filepath.ToSlash(stores.TruststorePath)
+	k.SSL.TrustStorePassword = stores.Password
+	k.SSL.TrustStoreType = stores.Type
+
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,133 @@
+package user
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCertificate(t *testing.T, name string) (certPEM, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestParseCredentials(t *testing.T) {
+	cert, key := testCertificate(t, "alice")
+	ca1, _ := testCertificate(t, "ca1")
+	ca2, _ := testCertificate(t, "ca2")
+
+	creds, err := parseCredentials(cert, key, ca1+ca2)
+	if assert.NoError(t, err) {
+		assert.Len(t, creds.chain, 1)
+		assert.Equal(t, "alice", creds.chain[0].Subject.CommonName)
+		if assert.Len(t, creds.cas, 2) {
+			assert.Equal(t, "ca2", creds.cas[1].Subject.CommonName)
+		}
+	}
+
+	_, otherKey := testCertificate(t, "bob")
+	_, err = parseCredentials(cert, otherKey, ca1)
+	assert.ErrorContains(t, err, "invalid user certificate")
+
+	_, err = parseCredentials(cert, key, "")
+	assert.ErrorContains(t, err, "no certificate found in the CA certificate")
+}
+
+func TestNewJavaStores(t *testing.T) {
+	cert, key := testCertificate(t, "alice")
+	ca, _ := testCertificate(t, "ca")
+	creds, err := parseCredentials(cert, key, ca)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dir := t.TempDir()
+	stores, err := newJavaStores(creds, keystoreTypePKCS12, "alice", "s3cret", dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(dir, "alice.keystore.p12"), stores.KeystorePath)
+		assert.Equal(t, filepath.Join(dir, "alice.truststore.p12"), stores.TruststorePath)
+		assert.NotEmpty(t, stores.Keystore)
+		assert.NotEmpty(t, stores.Truststore)
+	}
+
+	stores, err = newJavaStores(creds, keystoreTypeJKS, "alice", "s3cret", dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(dir, "alice.truststore.jks"), stores.TruststorePath)
+		assert.Equal(t, []byte{0xfe, 0xed, 0xfe, 0xed}, stores.Keystore[:4])
+	}
+}
+
+func TestRenderClientConfigs(t *testing.T) {
+	bootstrap := []string{"10.0.0.1:9093", "10.0.0.2:9093"}
+	stores := javaStores{
+		Type:           keystoreTypePKCS12,
+		Password:       `pa\ss`,
+		KeystorePath:   "/home/dev/kafka/alice.keystore.p12",
+		TruststorePath: "/home/dev/kafka/alice.truststore.p12",
+	}
+
+	assert.Equal(t, `bootstrap.servers=10.0.0.1:9093,10.0.0.2:9093
+security.protocol=SSL
+ssl.keystore.type=PKCS12
+ssl.keystore.location=/home/dev/kafka/alice.keystore.p12
+ssl.keystore.password=pa\\ss
+ssl.key.password=pa\\ss
+ssl.truststore.type=PKCS12
+ssl.truststore.location=/home/dev/kafka/alice.truststore.p12
+ssl.truststore.password=pa\\ss
+`, renderProperties(bootstrap, stores))
+
+	assert.Equal(t, `bootstrap.servers=10.0.0.1:9093,10.0.0.2:9093
+security.protocol=ssl
+ssl.ca.location=/k/alice-ca.pem
+ssl.certificate.location=/k/alice-cert.pem
+ssl.key.location=/k/alice-key.pem
+`, renderLibrdkafka(bootstrap, "/k/alice-ca.pem", "/k/alice-cert.pem", "/k/alice-key.pem"))
+
+	spring, err := renderSpring(bootstrap, stores)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `spring:
+    kafka:
+        bootstrap-servers:
+            - 10.0.0.1:9093
+            - 10.0.0.2:9093
+        security:
+            protocol: SSL
+        ssl:
+            key-store-location: file:/home/dev/kafka/alice.keystore.p12
+            key-store-password: pa\ss
+            key-store-type: PKCS12
+            key-password: pa\ss
+            trust-store-location: file:/home/dev/kafka/alice.truststore.p12
+            trust-store-password: pa\ss
+            trust-store-type: PKCS12
+`, spring)
+	}
+}
@@ -15,6 +15,12 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	flagFormat           = "format"
+	flagKeystoreType     = "keystore-type"
+	flagKeystorePassword = "keystore-password"
+)
+
 func GetAccess() *core.Command {
 	cmd := core.NewCommand(
 		context.Background(), nil, core.CommandBuilder{
@@ -30,9 +36,19 @@ By default, the command writes three PEM files to the specified output directory
 
 You can also use '--output json' to print the full JSON response from the API to stdout instead of writing files.
 
+Use --format to write a complete client configuration instead, with the broker addresses of the cluster and the SSL settings:
+ - librdkafka: the PEM files and <username>-librdkafka.conf, for kcat -F and librdkafka based clients
+ - java-keystore: <username>.keystore.p12 with the certificate and private key, and <username>.truststore.p12 with the CA certificate
+ - properties: the keystores and <username>.properties, for the Java client, e.g. kafka-console-producer.sh --producer.config
+ - spring: the keystores and <username>-application.yaml with the spring.kafka properties of Spring Boot
+
+The keystores are PKCS12 files, or JKS files with '--keystore-type JKS'. --keystore-password sets the password of the keystores and of the private key. It is written in clear text to the configuration files.
+The configuration files refer to the other files by their absolute path.
+
 IMPORTANT: Keep these credentials secure. The private key should never be shared or exposed publicly.`,
 			Aliases: []string{"g", "get", "access"},
-			Example: "ionosctl kafka user get-access " + core.FlagsUsage(constants.FlagLocation, constants.FlagClusterId, constants.FlagUserId),
+			Example: "ionosctl kafka user get-access " + core.FlagsUsage(constants.FlagLocation, constants.FlagClusterId, constants.FlagUserId) + "\n" +
+				"ionosctl kafka user get-access " + core.FlagsUsage(constants.FlagLocation, constants.FlagClusterId, constants.FlagUserId) + " --format properties --keystore-password PASSWORD --output-dir ./kafka",
 			PreCmdRun: func(cmd *core.PreCommandConfig) error {
 				return cmd.CheckRequiredFlagsAndLocation(constants.FlagClusterId, constants.FlagUserId)
 			},
@@ -60,8 +76,9 @@ IMPORTANT: Keep these credentials secure. The private key should never be shared
 					return fmt.Errorf("CA certificate not found in the response")
 				}
 
+				format, _ := cmd.Command.Command.Flags().GetString(flagFormat)
 				output := viper.GetString(constants.ArgOutput)
-				if output == "json" || output == "api-json" {
+				if format == formatPEM && (output == "json" || output == "api-json") {
 					out, err := json.MarshalIndent(userAccess, "", "  ")
 					if err != nil {
 						return fmt.Errorf("failed to marshal credentials for stdout: %w", err)
@@ -70,37 +87,112 @@ IMPORTANT: Keep these credentials secure. The private key should never be shared
 					return nil
 				}
 
-				// Default path: write 3 PEM files with 0600 perms
+				storeType, _ := cmd.Command.Command.Flags().GetString(flagKeystoreType)
+				password, _ := cmd.Command.Command.Flags().GetString(flagKeystorePassword)
+				javaFormat := format == formatProperties || format == formatJavaKeystore || format == formatSpring
+				if javaFormat && password == "" {
+					return fmt.Errorf("--%s is required for --%s %s", flagKeystorePassword, flagFormat, format)
+				}
+
+				// All files are written with 0600 perms
 				outputDir, _ := cmd.Command.Command.Flags().GetString("output-dir")
 				if outputDir == "" {
 					outputDir = "."
 				}
+				if format != formatPEM {
+					// Configuration files refer to the other files, which must work from any directory.
+					if outputDir, err = filepath.Abs(outputDir); err != nil {
+						return err
+					}
+				}
 
 				// Ensure output directory exists
 				if err := os.MkdirAll(outputDir, 0o700); err != nil {
 					return fmt.Errorf("unable to create output directory %s: %w", outputDir, err)
 				}
 
-				base := fmt.Sprintf("%s", userAccess.Properties.Name)
-				certFile := filepath.Join(outputDir, fmt.Sprintf("%s-cert.pem", base))
-				keyFile := filepath.Join(outputDir, fmt.Sprintf("%s-key.pem", base))
-				caFile := filepath.Join(outputDir, fmt.Sprintf("%s-ca.pem", base))
-
-				if err := writeFile(certFile, *cert); err != nil {
-					return err
+				var bootstrap []string
+				if format == formatProperties || format == formatLibrdkafka || format == formatSpring {
+					cluster, _, err := client.Must().Kafka.ClustersApi.ClustersFindById(context.Background(), clusterID).Execute()
+					if err != nil {
+						return err
+					}
+					bootstrap = cluster.Metadata.BrokerAddresses
+					if len(bootstrap) == 0 {
+						return fmt.Errorf("cluster %s has no broker addresses yet, its state is %s", clusterID, cluster.Metadata.State)
+					}
 				}
-				if err := writeFile(keyFile, *priv); err != nil {
-					return err
+
+				base := fmt.Sprintf("%s", userAccess.Properties.Name)
+				var written []string
+				write := func(path string, content []byte) error {
+					if err := writeBytes(path, content); err != nil {
+						return err
+					}
+					written = append(written, path)
+					return nil
 				}
-				if err := writeFile(caFile, *ca); err != nil {
-					return err
+
+				if !javaFormat {
+					certFile := filepath.Join(outputDir, fmt.Sprintf("%s-cert.pem", base))
+					keyFile := filepath.Join(outputDir, fmt.Sprintf("%s-key.pem", base))
+					caFile := filepath.Join(outputDir, fmt.Sprintf("%s-ca.pem", base))
+
+					if err := writeFile(certFile, *cert); err != nil {
+						return err
+					}
+					if err := writeFile(keyFile, *priv); err != nil {
+						return err
+					}
+					if err := writeFile(caFile, *ca); err != nil {
+						return err
+					}
+					written = append(written, caFile, certFile, keyFile)
+
+					if format == formatLibrdkafka {
+						conf := renderLibrdkafka(bootstrap, caFile, certFile, keyFile)
+						if err := write(filepath.Join(outputDir, base+"-librdkafka.conf"), []byte(conf)); err != nil {
+							return err
+						}
+					}
+				} else {
+					creds, err := parseCredentials(*cert, *priv, *ca)
+					if err != nil {
+						return err
+					}
+					stores, err := newJavaStores(creds, storeType, base, password, outputDir)
+					if err != nil {
+						return err
+					}
+					if err := write(stores.KeystorePath, stores.Keystore); err != nil {
+						return err
+					}
+					if err := write(stores.TruststorePath, stores.Truststore); err != nil {
+						return err
+					}
+
+					switch format {
+					case formatProperties:
+						conf := renderProperties(bootstrap, stores)
+						if err := write(filepath.Join(outputDir, base+".properties"), []byte(conf)); err != nil {
+							return err
+						}
+					case formatSpring:
+						conf, err := renderSpring(bootstrap, stores)
+						if err != nil {
+							return err
+						}
+						if err := write(filepath.Join(outputDir, base+"-application.yaml"), []byte(conf)); err != nil {
+							return err
+						}
+					}
 				}
 
 				// Print summary
 				fmt.Fprintln(cmd.Command.Command.OutOrStdout(), "Wrote:")
-				fmt.Fprintf(cmd.Command.Command.OutOrStdout(), " - %s\n", caFile)
-				fmt.Fprintf(cmd.Command.Command.OutOrStdout(), " - %s\n", certFile)
-				fmt.Fprintf(cmd.Command.Command.OutOrStdout(), " - %s", keyFile)
+				for _, f := range written {
+					fmt.Fprintf(cmd.Command.Command.OutOrStdout(), " - %s\n", f)
+				}
 
 				return nil
 			},
@@ -130,6 +222,11 @@ IMPORTANT: Keep these credentials secure. The private key should never be shared
 	)
 
 	cmd.AddStringFlag("output-dir", "", ".", "Directory to save the user's credential PEM files")
+	cmd.AddSetFlag(flagFormat, "", formatPEM, clientConfigFormats, "The files to write. "+
+		"'pem' writes the PEM files only, 'librdkafka' adds a librdkafka configuration referring to them. "+
+		"'java-keystore' writes a keystore and truststore, 'properties' and 'spring' add a Java client or Spring Boot configuration referring to them")
+	cmd.AddSetFlag(flagKeystoreType, "", keystoreTypePKCS12, []string{keystoreTypePKCS12, keystoreTypeJKS}, "The type of the keystore and truststore")
+	cmd.AddStringFlag(flagKeystorePassword, "", "", "The password of the keystore, truststore and private key. Required for the formats with keystores")
 
 	return cmd
 }
@@ -139,7 +236,11 @@ func writeFile(path, content string) error {
 	if len(content) > 0 && content[len(content)-1] != '\n' {
 		content = content + "\n"
 	}
-	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+	return writeBytes(path, []byte(content))
+}
+
+func writeBytes(path string, content []byte) error {
+	if err := os.WriteFile(path, content, 0o600); err != nil {
 		return fmt.Errorf("failed to write %s: %w", path, err)
 	}
 	return nil
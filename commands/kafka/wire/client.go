@@ -0,0 +1,514 @@
+package wire
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// API keys and the versions used. These are the oldest versions which support record batches
+// with headers, and are supported by all Kafka versions since 0.11, including 4.x.
+const (
+	apiProduce     int16 = 0
+	apiFetch       int16 = 1
+	apiListOffsets int16 = 2
+	apiMetadata    int16 = 3
+
+	versionProduce     int16 = 3
+	versionFetch       int16 = 4
+	versionListOffsets int16 = 1
+	versionMetadata    int16 = 1
+
+	clientID = "ionosctl"
+
+	// maxResponseSize protects against reading garbage as a huge size, e.g. when talking to something else than Kafka.
+	maxResponseSize = 256 << 20
+)
+
+// Special timestamps of ListOffsets.
+const (
+	LatestOffset   int64 = -1
+	EarliestOffset int64 = -2
+)
+
+// Error is an error code returned by the broker.
+type Error int16
+
+var errorNames = map[Error]string{
+	1:  "OFFSET_OUT_OF_RANGE",
+	2:  "CORRUPT_MESSAGE",
+	3:  "UNKNOWN_TOPIC_OR_PARTITION",
+	5:  "LEADER_NOT_AVAILABLE",
+	6:  "NOT_LEADER_OR_FOLLOWER",
+	7:  "REQUEST_TIMED_OUT",
+	10: "MESSAGE_TOO_LARGE",
+	19: "NOT_ENOUGH_REPLICAS",
+	20: "NOT_ENOUGH_REPLICAS_AFTER_APPEND",
+	29: "TOPIC_AUTHORIZATION_FAILED",
+	31: "CLUSTER_AUTHORIZATION_FAILED",
+	35: "UNSUPPORTED_VERSION",
+	87: "INVALID_RECORD",
+}
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e]; ok {
+		return fmt.Sprintf("kafka error %d: %s", int16(e), name)
+	}
+	return fmt.Sprintf("kafka error %d", int16(e))
+}
+
+func errorCode(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return Error(code)
+}
+
+// Partition is the metadata of a topic partition.
+type Partition struct {
+	ID     int32
+	Leader int32
+}
+
+// conn is a connection to a single broker. Requests are sent one at a time.
+type conn struct {
+	mu            sync.Mutex
+	nc            net.Conn
+	correlationID int32
+}
+
+func (c *conn) roundTrip(ctx context.Context, apiKey, version int16, body []byte) (*decoder, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.nc.SetDeadline(deadline)
+	} else {
+		_ = c.nc.SetDeadline(time.Time{})
+	}
+	// Expire the deadline to unblock reads and writes if the context is canceled.
+	stop := context.AfterFunc(ctx, func() { _ = c.nc.SetDeadline(time.Unix(1, 0)) })
+	defer stop()
+
+	c.correlationID++
+	var e encoder
+	e.int32(0) // size, filled in below
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(c.correlationID)
+	e.string(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+
+	if _, err := c.nc.Write(e.buf); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(c.nc, size[:]); err != nil {
+		return nil, contextError(ctx, err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d from %s, is this a Kafka broker?", n, c.nc.RemoteAddr())
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(c.nc, resp); err != nil {
+		return nil, contextError(ctx, err)
+	}
+
+	d := &decoder{b: resp}
+	if id := d.int32(); id != c.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d, expected %d", id, c.correlationID)
+	}
+	return d, nil
+}
+
+func contextError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// Client talks to the brokers of a cluster. It connects to the leaders of partitions on demand.
+type Client struct {
+	// OnSkipped is called for the record batches that Fetch skips, because their compression is not
+	// supported. Optional.
+	OnSkipped func(SkippedBatch)
+
+	tls    *tls.Config
+	dialer net.Dialer
+
+	mu      sync.Mutex
+	brokers map[int32]string
+	conns   map[string]*conn
+	leaders map[string]map[int32]int32
+}
+
+// Dial connects to the first reachable bootstrap broker. Without a TLS config, plaintext is used.
+func Dial(ctx context.Context, bootstrap []string, tlsConfig *tls.Config) (*Client, error) {
+	c := &Client{
+		tls:     tlsConfig,
+		dialer:  net.Dialer{Timeout: 10 * time.Second},
+		brokers: map[int32]string{},
+		conns:   map[string]*conn{},
+		leaders: map[string]map[int32]int32{},
+	}
+
+	var errs []error
+	for _, addr := range bootstrap {
+		if _, err := c.connect(ctx, addr); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// Bootstrap brokers are only used for metadata, under an id which is not a real broker.
+		c.brokers[-1] = addr
+		return c, nil
+	}
+	return nil, fmt.Errorf("no broker reachable: %w", errors.Join(errs...))
+}
+
+func (c *Client) connect(ctx context.Context, addr string) (*conn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cn, ok := c.conns[addr]; ok {
+		return cn, nil
+	}
+
+	nc, err := c.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if c.tls != nil {
+		cfg := c.tls.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName, _, _ = net.SplitHostPort(addr)
+		}
+		tc := tls.Client(nc, cfg)
+		if err := tc.HandshakeContext(ctx); err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("TLS handshake with %s failed: %w", addr, err)
+		}
+		nc = tc
+	}
+
+	cn := &conn{nc: nc}
+	c.conns[addr] = cn
+	return cn, nil
+}
+
+// Close closes all connections.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var errs []error
+	for addr, cn := range c.conns {
+		errs = append(errs, cn.nc.Close())
+		delete(c.conns, addr)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *Client) broker(ctx context.Context, id int32) (*conn, error) {
+	c.mu.Lock()
+	addr, ok := c.brokers[id]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown broker %d", id)
+	}
+	return c.connect(ctx, addr)
+}
+
+// Partitions returns the partitions of a topic and caches their leaders.
+func (c *Client) Partitions(ctx context.Context, topic string) ([]Partition, error) {
+	cn, err := c.broker(ctx, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	var e encoder
+	e.arrayLen(1)
+	e.string(topic)
+	d, err := cn.roundTrip(ctx, apiMetadata, versionMetadata, e.buf)
+	if err != nil {
+		return nil, fmt.Errorf("metadata request failed: %w", err)
+	}
+
+	brokers := map[int32]string{}
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller id
+
+	var partitions []Partition
+	var topicErr error
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		code := d.int16()
+		name := d.string()
+		d.bool() // is internal
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int16() // partition error, e.g. a leader which is not available yet
+			p := Partition{ID: d.int32(), Leader: d.int32()}
+			for k, r := 0, d.arrayLen(); k < r; k++ {
+				d.int32() // replicas
+			}
+			for k, r := 0, d.arrayLen(); k < r; k++ {
+				d.int32() // in sync replicas
+			}
+			if name == topic {
+				partitions = append(partitions, p)
+			}
+		}
+		if name == topic {
+			topicErr = errorCode(code)
+		}
+	}
+	if d.err != nil {
+		return nil, fmt.Errorf("invalid metadata response: %w", d.err)
+	}
+	if topicErr != nil {
+		return nil, fmt.Errorf("topic %s: %w", topic, topicErr)
+	}
+	if len(partitions) == 0 {
+		return nil, fmt.Errorf("topic %s not found", topic)
+	}
+
+	c.mu.Lock()
+	for id, addr := range brokers {
+		c.brokers[id] = addr
+	}
+	leaders := map[int32]int32{}
+	for _, p := range partitions {
+		leaders[p.ID] = p.Leader
+	}
+	c.leaders[topic] = leaders
+	c.mu.Unlock()
+	return partitions, nil
+}
+
+func (c *Client) leader(ctx context.Context, topic string, partition int32) (*conn, error) {
+	c.mu.Lock()
+	leaders, ok := c.leaders[topic]
+	c.mu.Unlock()
+	if !ok {
+		if _, err := c.Partitions(ctx, topic); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		leaders = c.leaders[topic]
+		c.mu.Unlock()
+	}
+	id, ok := leaders[partition]
+	if !ok {
+		return nil, fmt.Errorf("topic %s has no partition %d", topic, partition)
+	}
+	if id < 0 {
+		return nil, fmt.Errorf("partition %d of topic %s has no leader", partition, topic)
+	}
+	return c.broker(ctx, id)
+}
+
+// Produce appends the records to a partition and waits for all in-sync replicas. It returns the offset of the first record.
+func (c *Client) Produce(ctx context.Context, topic string, partition int32, records []Record) (int64, error) {
+	if len(records) == 0 {
+		return 0, errors.New("no records to produce")
+	}
+	cn, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	var e encoder
+	e.nullString() // transactional id
+	e.int16(-1)    // acks from all in-sync replicas
+	e.int32(30000) // timeout in milliseconds
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.bytes(encodeRecordBatch(records))
+
+	d, err := cn.roundTrip(ctx, apiProduce, versionProduce, e.buf)
+	if err != nil {
+		return 0, fmt.Errorf("produce request failed: %w", err)
+	}
+	var offset int64
+	var produceErr error
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int32() // partition
+			produceErr = errorCode(d.int16())
+			offset = d.int64()
+			d.int64() // log append time
+		}
+	}
+	if d.err != nil {
+		return 0, fmt.Errorf("invalid produce response: %w", d.err)
+	}
+	if produceErr != nil {
+		return 0, fmt.Errorf("producing to partition %d of %s failed: %w", partition, topic, produceErr)
+	}
+	return offset, nil
+}
+
+// Offset returns the offset of the first record of a partition for EarliestOffset, or the offset
+// after the last record for LatestOffset.
+func (c *Client) Offset(ctx context.Context, topic string, partition int32, timestamp int64) (int64, error) {
+	cn, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return 0, err
+	}
+
+	var e encoder
+	e.int32(-1) // replica id of consumers
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int64(timestamp)
+
+	d, err := cn.roundTrip(ctx, apiListOffsets, versionListOffsets, e.buf)
+	if err != nil {
+		return 0, fmt.Errorf("list offsets request failed: %w", err)
+	}
+	var offset int64
+	var listErr error
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int32() // partition
+			listErr = errorCode(d.int16())
+			d.int64() // timestamp
+			offset = d.int64()
+		}
+	}
+	if d.err != nil {
+		return 0, fmt.Errorf("invalid list offsets response: %w", d.err)
+	}
+	if listErr != nil {
+		return 0, fmt.Errorf("listing offsets of partition %d of %s failed: %w", partition, topic, listErr)
+	}
+	return offset, nil
+}
+
+// Fetch returns the records of a partition from the offset on, waiting up to maxWait for new records,
+// and the offset to fetch next. Records before the offset are dropped, as brokers return whole batches.
+// The next offset also moves past batches without records, e.g. the control batches of transactions,
+// and past batches with an unsupported compression, which are reported to OnSkipped.
+func (c *Client) Fetch(ctx context.Context, topic string, partition int32, offset int64, maxWait time.Duration) ([]Record, int64, error) {
+	cn, err := c.leader(ctx, topic, partition)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var e encoder
+	e.int32(-1) // replica id of consumers
+	e.int32(int32(maxWait.Milliseconds()))
+	e.int32(1)       // min bytes
+	e.int32(4 << 20) // max bytes
+	e.int8(0)        // read uncommitted
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int64(offset)
+	e.int32(1 << 20) // partition max bytes
+
+	d, err := cn.roundTrip(ctx, apiFetch, versionFetch, e.buf)
+	if err != nil {
+		return nil, 0, fmt.Errorf("fetch request failed: %w", err)
+	}
+	d.int32() // throttle time
+	var data []byte
+	var fetchErr error
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			d.int32() // partition
+			fetchErr = errorCode(d.int16())
+			d.int64() // high watermark
+			d.int64() // last stable offset
+			for k, a := 0, d.arrayLen(); k < a; k++ {
+				d.int64() // aborted producer id
+				d.int64() // aborted first offset
+			}
+			data = d.bytes()
+		}
+	}
+	if d.err != nil {
+		return nil, 0, fmt.Errorf("invalid fetch response: %w", d.err)
+	}
+	if fetchErr != nil {
+		return nil, 0, fmt.Errorf("fetching partition %d of %s failed: %w", partition, topic, fetchErr)
+	}
+
+	records, skipped, next, err := decodeRecordBatches(partition, data)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, b := range skipped {
+		if b.LastOffset >= offset && c.OnSkipped != nil {
+			c.OnSkipped(b)
+		}
+	}
+	i := 0
+	for i < len(records) && records[i].Offset < offset {
+		i++
+	}
+	return records[i:], max(next, offset), nil
+}
+
+// PartitionForKey returns the partition of a key, like the default partitioner of the Java client.
+func PartitionForKey(key []byte, numPartitions int) int32 {
+	return int32((murmur2(key) & 0x7fffffff) % uint32(numPartitions))
+}
+
+// murmur2 is the variant of MurmurHash2 used by Kafka.
+func murmur2(data []byte) uint32 {
+	const (
+		seed uint32 = 0x9747b28c
+		m    uint32 = 0x5bd1e995
+		r           = 24
+	)
+	length := len(data)
+	h := seed ^ uint32(length)
+	for i := 0; i+4 <= length; i += 4 {
+		k := binary.LittleEndian.Uint32(data[i:])
+		k *= m
+		k ^= k >> r
+		k *= m
+		h *= m
+		h ^= k
+	}
+
+	tail := data[length&^3:]
+	switch len(tail) {
+	case 3:
+		h ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		h ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		h ^= uint32(tail[0])
+		h *= m
+	}
+
+	h ^= h >> 13
+	h *= m
+	h ^= h >> 15
+	return h
+}
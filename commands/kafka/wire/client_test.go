@@ -0,0 +1,374 @@
+package wire
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker is a single Kafka broker, which stores the records of one topic in memory.
+type fakeBroker struct {
+	t     *testing.T
+	ln    net.Listener
+	topic string
+
+	mu         sync.Mutex
+	partitions [][]Record
+}
+
+func newFakeBroker(t *testing.T, topic string, partitions int) *fakeBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{t: t, ln: ln, topic: topic, partitions: make([][]Record, partitions)}
+	t.Cleanup(func() { _ = ln.Close() })
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) addr() string { return b.ln.Addr().String() }
+
+func (b *fakeBroker) serve() {
+	for {
+		nc, err := b.ln.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(nc)
+	}
+}
+
+func (b *fakeBroker) handle(nc net.Conn) {
+	defer nc.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(nc, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(nc, req); err != nil {
+			return
+		}
+		d := &decoder{b: req}
+		apiKey, version, correlationID := d.int16(), d.int16(), d.int32()
+		d.string() // client id
+
+		var e encoder
+		e.int32(0)
+		e.int32(correlationID)
+		switch {
+		case apiKey == apiMetadata && version == versionMetadata:
+			b.metadata(d, &e)
+		case apiKey == apiProduce && version == versionProduce:
+			b.produce(d, &e)
+		case apiKey == apiListOffsets && version == versionListOffsets:
+			b.listOffsets(d, &e)
+		case apiKey == apiFetch && version == versionFetch:
+			b.fetch(d, &e)
+		default:
+			b.t.Errorf("unexpected request %d version %d", apiKey, version)
+			return
+		}
+		binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+		if _, err := nc.Write(e.buf); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) metadata(d *decoder, e *encoder) {
+	host, port, _ := net.SplitHostPort(b.addr())
+	p, _ := strconv.Atoi(port)
+	e.arrayLen(1)
+	e.int32(7)
+	e.string(host)
+	e.int32(int32(p))
+	e.nullString()
+	e.int32(7)
+
+	d.arrayLen()
+	name := d.string()
+	e.arrayLen(1)
+	if name != b.topic {
+		e.int16(3)
+		e.string(name)
+		e.int8(0)
+		e.arrayLen(0)
+		return
+	}
+	e.int16(0)
+	e.string(name)
+	e.int8(0)
+	e.arrayLen(len(b.partitions))
+	for i := range b.partitions {
+		e.int16(0)
+		e.int32(int32(i))
+		e.int32(7)
+		e.arrayLen(1)
+		e.int32(7)
+		e.arrayLen(1)
+		e.int32(7)
+	}
+}
+
+func (b *fakeBroker) produce(d *decoder, e *encoder) {
+	d.string()
+	d.int16()
+	d.int32()
+	d.arrayLen()
+	topic := d.string()
+	d.arrayLen()
+	partition := d.int32()
+	records, _, _, err := decodeRecordBatches(partition, d.bytes())
+	if err != nil {
+		b.t.Error(err)
+	}
+
+	b.mu.Lock()
+	base := int64(len(b.partitions[partition]))
+	for i := range records {
+		records[i].Offset = base + int64(i)
+	}
+	b.partitions[partition] = append(b.partitions[partition], records...)
+	b.mu.Unlock()
+
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int16(0)
+	e.int64(base)
+	e.int64(-1)
+	e.int32(0)
+}
+
+func (b *fakeBroker) listOffsets(d *decoder, e *encoder) {
+	d.int32()
+	d.arrayLen()
+	topic := d.string()
+	d.arrayLen()
+	partition := d.int32()
+	offset := int64(0)
+	if d.int64() == LatestOffset {
+		b.mu.Lock()
+		offset = int64(len(b.partitions[partition]))
+		b.mu.Unlock()
+	}
+
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int16(0)
+	e.int64(-1)
+	e.int64(offset)
+}
+
+// fetch returns the records from the batch boundary before the offset on, in batches of two records,
+// the last of them gzip compressed and truncated like a real broker would.
+func (b *fakeBroker) fetch(d *decoder, e *encoder) {
+	d.int32()
+	d.int32()
+	d.int32()
+	d.int32()
+	d.int8()
+	d.arrayLen()
+	topic := d.string()
+	d.arrayLen()
+	partition := d.int32()
+	offset := d.int64()
+
+	b.mu.Lock()
+	records := b.partitions[partition]
+	b.mu.Unlock()
+
+	var data []byte
+	for start := offset &^ 1; start < int64(len(records)); start += 2 {
+		batch := encodeRecordBatch(records[start:min(start+2, int64(len(records)))])
+		binary.BigEndian.PutUint64(batch, uint64(start))
+		data = append(data, batch...)
+	}
+	if len(data) > 0 {
+		data = append(data, gzipBatch(b.t, records[:1])...)
+		data = append(data, encodeRecordBatch(records[:1])[:20]...)
+	}
+
+	e.int32(0)
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int16(0)
+	e.int64(int64(len(records)))
+	e.int64(int64(len(records)))
+	e.arrayLen(0)
+	e.bytes(data)
+}
+
+// gzipBatch encodes the records as a gzip compressed batch, at an offset after all records of the test.
+func gzipBatch(t *testing.T, records []Record) []byte {
+	plain := encodeRecordBatch(records)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(plain[recordBatchOverhead:])
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var e encoder
+	e.buf = append(e.buf, plain[:recordBatchOverhead]...)
+	e.buf = append(e.buf, buf.Bytes()...)
+	binary.BigEndian.PutUint64(e.buf, 1000)
+	binary.BigEndian.PutUint32(e.buf[8:], uint32(len(e.buf)-batchLengthOffset))
+	binary.BigEndian.PutUint16(e.buf[21:], compressionGzip)
+	binary.BigEndian.PutUint32(e.buf[17:], crc32.Checksum(e.buf[21:], castagnoli))
+	return e.buf
+}
+
+func TestProduceFetch(t *testing.T) {
+	broker := newFakeBroker(t, "events", 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := Dial(ctx, []string{"127.0.0.1:1"}, nil)
+	assert.ErrorContains(t, err, "no broker reachable")
+
+	c, err := Dial(ctx, []string{"127.0.0.1:1", broker.addr()}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+
+	partitions, err := c.Partitions(ctx, "events")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []Partition{{ID: 0, Leader: 7}, {ID: 1, Leader: 7}}, partitions)
+
+	_, err = c.Partitions(ctx, "missing")
+	assert.ErrorContains(t, err, "topic missing: kafka error 3: UNKNOWN_TOPIC_OR_PARTITION")
+
+	now := time.UnixMilli(time.Now().UnixMilli())
+	records := []Record{
+		{Timestamp: now, Key: []byte("k1"), Value: []byte("first"), Headers: []Header{{Key: "source", Value: []byte("test")}}},
+		{Timestamp: now.Add(time.Second), Value: []byte("second")},
+		{Timestamp: now.Add(2 * time.Second), Key: []byte{}, Value: []byte{}},
+	}
+	offset, err := c.Produce(ctx, "events", 1, records)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(0), offset)
+	offset, err = c.Produce(ctx, "events", 1, records[:1])
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), offset)
+
+	latest, err := c.Offset(ctx, "events", 1, LatestOffset)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(4), latest)
+	}
+
+	// Records before the requested offset are dropped, and the compressed batch is decoded.
+	got, next, err := c.Fetch(ctx, "events", 1, 1, time.Second)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(1001), next)
+	var offsets []int64
+	for _, r := range got {
+		offsets = append(offsets, r.Offset)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 1000}, offsets)
+	assert.Nil(t, got[0].Key)
+	assert.Equal(t, "second", string(got[0].Value))
+	assert.Equal(t, now.Add(time.Second), got[0].Timestamp)
+	assert.Equal(t, []byte{}, got[1].Key)
+	assert.Equal(t, "k1", string(got[2].Key))
+	assert.Equal(t, []Header{{Key: "source", Value: []byte("test")}}, got[2].Headers)
+	assert.Equal(t, "first", string(got[3].Value))
+
+	got, next, err = c.Fetch(ctx, "events", 0, 0, time.Second)
+	if assert.NoError(t, err) {
+		assert.Empty(t, got)
+		assert.Equal(t, int64(0), next)
+	}
+
+	_, err = c.Produce(ctx, "events", 5, records)
+	assert.ErrorContains(t, err, "topic events has no partition 5")
+}
+
+func TestDecodeControlBatch(t *testing.T) {
+	// A commit marker of a transaction at offset 5, followed by a batch of two records at offset 6.
+	control := encodeRecordBatch([]Record{{Key: []byte{0, 0, 0, 1}, Value: []byte{0, 0, 0, 0, 0, 0}}})
+	binary.BigEndian.PutUint64(control, 5)
+	binary.BigEndian.PutUint16(control[21:], controlBatchFlag)
+	binary.BigEndian.PutUint32(control[17:], crc32.Checksum(control[21:], castagnoli))
+
+	records, _, next, err := decodeRecordBatches(0, control)
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+	assert.Equal(t, int64(6), next)
+
+	batch := encodeRecordBatch([]Record{{Value: []byte("a")}, {Value: []byte("b")}})
+	binary.BigEndian.PutUint64(batch, 6)
+	records, _, next, err = decodeRecordBatches(0, append(control, batch...))
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, int64(7), records[1].Offset)
+	assert.Equal(t, int64(8), next)
+}
+
+func TestDecodeCorruptBatch(t *testing.T) {
+	batch := encodeRecordBatch([]Record{{Value: []byte("value")}})
+	batch[len(batch)-2] ^= 0xff
+	_, _, _, err := decodeRecordBatches(0, batch)
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
+
+func TestDecodeUnsupportedCompression(t *testing.T) {
+	// A batch of two records at offset 3 compressed with zstd, followed by a plain batch at offset 5.
+	compressed := encodeRecordBatch([]Record{{Value: []byte("a")}, {Value: []byte("b")}})
+	binary.BigEndian.PutUint64(compressed, 3)
+	binary.BigEndian.PutUint16(compressed[21:], 4)
+	binary.BigEndian.PutUint32(compressed[17:], crc32.Checksum(compressed[21:], castagnoli))
+
+	batch := encodeRecordBatch([]Record{{Value: []byte("c")}})
+	binary.BigEndian.PutUint64(batch, 5)
+	records, skipped, next, err := decodeRecordBatches(2, append(compressed, batch...))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []SkippedBatch{{Partition: 2, BaseOffset: 3, LastOffset: 4, Compression: "zstd"}}, skipped)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "c", string(records[0].Value))
+	}
+	assert.Equal(t, int64(6), next)
+}
+
+func TestPartitionForKey(t *testing.T) {
+	// Test vectors of the Java client.
+	for key, want := range map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	} {
+		assert.Equal(t, want, int32(murmur2([]byte(key))), key)
+	}
+	assert.Equal(t, int32(479470107%3), PartitionForKey([]byte("abc"), 3))
+}
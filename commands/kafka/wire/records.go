@@ -0,0 +1,204 @@
+package wire
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// Header is a record header. Kafka allows duplicate keys, so headers are a list.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Record is a message of a topic partition. A nil Key is a null key.
+type Record struct {
+	Partition int32
+	Offset    int64
+	Timestamp time.Time
+	Key       []byte
+	Value     []byte
+	Headers   []Header
+}
+
+const (
+	recordBatchMagic = 2
+	// recordBatchOverhead is the size of the batch header up to and including the record count.
+	recordBatchOverhead = 61
+	// batchLengthOffset is the size of the fields before the batch length is counted, baseOffset and batchLength.
+	batchLengthOffset = 12
+
+	compressionMask   = 0x07
+	compressionGzip   = 1
+	controlBatchFlag  = 0x20
+	timestampTypeFlag = 0x08
+)
+
+var compressionNames = map[int16]string{1: "gzip", 2: "snappy", 3: "lz4", 4: "zstd"}
+
+// SkippedBatch is a record batch whose records cannot be decoded, because it is compressed with a codec
+// other than gzip.
+type SkippedBatch struct {
+	Partition   int32
+	BaseOffset  int64
+	LastOffset  int64
+	Compression string
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// encodeRecordBatch encodes the records as an uncompressed record batch (magic 2). Offsets and
+// timestamps are relative to the first record, the broker assigns the final offsets.
+func encodeRecordBatch(records []Record) []byte {
+	base := records[0].Timestamp
+	maxTimestamp := base
+
+	var body encoder
+	for i, r := range records {
+		if r.Timestamp.After(maxTimestamp) {
+			maxTimestamp = r.Timestamp
+		}
+		var rec encoder
+		rec.int8(0) // attributes
+		rec.varint(r.Timestamp.Sub(base).Milliseconds())
+		rec.varint(int64(i))
+		rec.varBytes(r.Key)
+		rec.varBytes(r.Value)
+		rec.varint(int64(len(r.Headers)))
+		for _, h := range r.Headers {
+			rec.varBytes([]byte(h.Key))
+			rec.varBytes(h.Value)
+		}
+		body.varint(int64(len(rec.buf)))
+		body.buf = append(body.buf, rec.buf...)
+	}
+
+	var e encoder
+	e.int64(0)                                                              // baseOffset
+	e.int32(int32(recordBatchOverhead - batchLengthOffset + len(body.buf))) // batchLength
+	e.int32(-1)                                                             // partitionLeaderEpoch
+	e.int8(recordBatchMagic)
+	crcAt := len(e.buf)
+	e.int32(0) // crc, filled in below
+	e.int16(0) // attributes
+	e.int32(int32(len(records) - 1))
+	e.int64(base.UnixMilli())
+	e.int64(maxTimestamp.UnixMilli())
+	e.int64(-1) // producerId
+	e.int16(-1) // producerEpoch
+	e.int32(-1) // baseSequence
+	e.arrayLen(len(records))
+	e.buf = append(e.buf, body.buf...)
+
+	crc := crc32.Checksum(e.buf[crcAt+4:], castagnoli)
+	e.buf[crcAt], e.buf[crcAt+1], e.buf[crcAt+2], e.buf[crcAt+3] = byte(crc>>24), byte(crc>>16), byte(crc>>8), byte(crc)
+	return e.buf
+}
+
+// decodeRecordBatches decodes the record batches of a fetch response. Brokers may return a
+// partial batch at the end, which is skipped. Control batches of transactions are skipped too,
+// and batches with an unsupported compression, which are returned separately.
+// It also returns the offset after the last complete batch, 0 without one, so a consumer moves
+// past batches without records.
+func decodeRecordBatches(partition int32, data []byte) ([]Record, []SkippedBatch, int64, error) {
+	var records []Record
+	var skipped []SkippedBatch
+	var next int64
+	d := &decoder{b: data}
+	for d.remaining() >= batchLengthOffset {
+		baseOffset := d.int64()
+		length := int(d.int32())
+		if length > d.remaining() {
+			break
+		}
+		batch := &decoder{b: d.take(length)}
+		batch.int32() // partitionLeaderEpoch
+		if magic := batch.int8(); magic != recordBatchMagic {
+			return nil, nil, 0, fmt.Errorf("unsupported record batch version %d at offset %d", magic, baseOffset)
+		}
+		crc := uint32(batch.int32())
+		if crc32.Checksum(batch.b[batch.off:], castagnoli) != crc {
+			return nil, nil, 0, fmt.Errorf("corrupt record batch at offset %d: checksum mismatch", baseOffset)
+		}
+		attributes := batch.int16()
+		lastOffsetDelta := batch.int32()
+		baseTimestamp := batch.int64()
+		maxTimestamp := batch.int64()
+		batch.take(14) // producerId, producerEpoch, baseSequence
+		count := batch.arrayLen()
+		if batch.err != nil {
+			return nil, nil, 0, batch.err
+		}
+		next = max(next, baseOffset+int64(lastOffsetDelta)+1)
+		if attributes&controlBatchFlag != 0 {
+			continue
+		}
+
+		body := batch
+		if c := attributes & compressionMask; c != 0 {
+			if c != compressionGzip {
+				skipped = append(skipped, SkippedBatch{
+					Partition:   partition,
+					BaseOffset:  baseOffset,
+					LastOffset:  baseOffset + int64(lastOffsetDelta),
+					Compression: compressionNames[c],
+				})
+				continue
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(batch.b[batch.off:]))
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("record batch at offset %d: %w", baseOffset, err)
+			}
+			raw, err := io.ReadAll(zr)
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("record batch at offset %d: %w", baseOffset, err)
+			}
+			body = &decoder{b: raw}
+		}
+
+		for i := 0; i < count; i++ {
+			r, err := decodeRecord(body, partition, baseOffset, baseTimestamp)
+			if err != nil {
+				return nil, nil, 0, fmt.Errorf("record batch at offset %d: %w", baseOffset, err)
+			}
+			// With log append time, the broker sets the timestamp of the whole batch.
+			if attributes&timestampTypeFlag != 0 {
+				r.Timestamp = time.UnixMilli(maxTimestamp)
+			}
+			records = append(records, r)
+		}
+	}
+	return records, skipped, next, d.err
+}
+
+func decodeRecord(d *decoder, partition int32, baseOffset, baseTimestamp int64) (Record, error) {
+	length := d.varint()
+	if d.err != nil {
+		return Record{}, d.err
+	}
+	rd := &decoder{b: d.take(int(length))}
+	if d.err != nil {
+		return Record{}, d.err
+	}
+
+	rd.int8() // attributes
+	r := Record{Partition: partition}
+	r.Timestamp = time.UnixMilli(baseTimestamp + rd.varint())
+	r.Offset = baseOffset + rd.varint()
+	r.Key = rd.varBytes()
+	r.Value = rd.varBytes()
+	n := rd.varint()
+	if n < 0 || n > int64(rd.remaining()) {
+		return Record{}, errors.New("invalid header count")
+	}
+	for i := int64(0); i < n; i++ {
+		key := rd.varBytes()
+		r.Headers = append(r.Headers, Header{Key: string(key), Value: rd.varBytes()})
+	}
+	return r, rd.err
+}
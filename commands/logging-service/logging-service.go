@@ -4,6 +4,7 @@ import (
 	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/central"
 	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/logs"
 	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/pipeline"
+	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/ship"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/sdk-go-bundle/shared/fileconfiguration"
@@ -23,6 +24,7 @@ func Root() *core.Command {
 	cmd.AddCommand(pipeline.PipelineCmd())
 	cmd.AddCommand(logs.LogsCmd())
 	cmd.AddCommand(central.CentralCommand())
+	cmd.AddCommand(ship.ShipCmd())
 
 	return core.WithRegionalConfigOverride(cmd, []string{fileconfiguration.Logging}, constants.LoggingApiRegionalURL, constants.LoggingLocations)
 }
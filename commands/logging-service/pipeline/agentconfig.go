@@ -0,0 +1,255 @@
+package pipeline
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/ship"
+	"github.com/ionos-cloud/sdk-go-bundle/products/logging/v2"
+)
+
+// Formats of export-agent-config, with the agent they configure.
+var agentConfigFormats = []string{"fluent-bit", "vector", "otel-collector"}
+
+// keyEnv is the environment variable the configuration reads the pipeline key from, if no key is given.
+const keyEnv = "IONOS_LOGGING_PIPELINE_KEY"
+
+// agentLog is a log of the pipeline, with the endpoint its protocol sends to.
+type agentLog struct {
+	Tag      string
+	Source   string
+	Protocol string
+	Host     string
+	Port     string
+}
+
+// agentConfig is everything an agent needs to send the logs of a pipeline.
+type agentConfig struct {
+	Pipeline string
+	Logs     []agentLog
+	// Key is the shared key of the pipeline. If empty, the configuration reads it from keyEnv.
+	Key string
+}
+
+func newAgentConfig(p logging.PipelineRead, key string) (agentConfig, error) {
+	if len(p.Properties.Logs) == 0 {
+		return agentConfig{}, fmt.Errorf("pipeline %s has no logs", p.Id)
+	}
+
+	a := agentConfig{Pipeline: p.Properties.Name, Key: key}
+	for _, l := range p.Properties.Logs {
+		host, port, err := ship.Endpoint(p, l.Protocol)
+		if err != nil {
+			return agentConfig{}, fmt.Errorf("log %s: %w", l.Tag, err)
+		}
+		a.Logs = append(a.Logs, agentLog{Tag: l.Tag, Source: l.Source, Protocol: l.Protocol, Host: host, Port: port})
+	}
+	return a, nil
+}
+
+func renderAgentConfig(format string, a agentConfig) (string, error) {
+	switch format {
+	case "fluent-bit":
+		return renderFluentBit(a), nil
+	case "vector":
+		return renderVector(a)
+	case "otel-collector":
+		return renderOtelCollector(a)
+	}
+	return "", fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(agentConfigFormats, ", "))
+}
+
+// unsupportedError explains which log of the pipeline the agent cannot send.
+func unsupportedError(agent, tag, protocol, alternative string) error {
+	return fmt.Errorf("%s cannot send log %s over %s, use --format %s or change the protocol of the log with 'ionosctl logging-service logs update'",
+		agent, tag, strings.ToUpper(protocol), alternative)
+}
+
+// keyRef returns the key, or a reference to keyEnv in the syntax of the agent.
+func (a agentConfig) keyRef(envSyntax string) string {
+	if a.Key != "" {
+		return a.Key
+	}
+	return fmt.Sprintf(envSyntax, keyEnv)
+}
+
+func (a agentConfig) header(b *strings.Builder, agent, path string) {
+	fmt.Fprintf(b, "# %s configuration for the IONOS Cloud logging pipeline %s, e.g. %s\n", agent, a.Pipeline, path)
+	if a.Key == "" {
+		fmt.Fprintf(b, "# The key of the pipeline is read from the environment variable %s.\n", keyEnv)
+	}
+}
+
+// genericPath is the file pattern tailed for logs with the source generic.
+func genericPath(tag string) string {
+	return "/var/log/" + tag + "/*.log"
+}
+
+func renderFluentBit(a agentConfig) string {
+	b := &strings.Builder{}
+	a.header(b, "Fluent Bit", "/etc/fluent-bit/fluent-bit.conf")
+	b.WriteString("[SERVICE]\n")
+	b.WriteString("    Flush         1\n")
+	b.WriteString("    Log_Level     info\n")
+	b.WriteString("    Parsers_File  parsers.conf\n")
+
+	key := a.keyRef("${%s}")
+	for _, l := range a.Logs {
+		fmt.Fprintf(b, "\n# Log %s: source %s, protocol %s\n", l.Tag, l.Source, l.Protocol)
+		match := l.Tag
+		switch l.Source {
+		case "kubernetes":
+			match = l.Tag + ".*"
+			b.WriteString("[INPUT]\n")
+			b.WriteString("    Name              tail\n")
+			fmt.Fprintf(b, "    Tag               %s\n", match)
+			b.WriteString("    Path              /var/log/containers/*.log\n")
+			b.WriteString("    multiline.parser  docker, cri\n")
+			b.WriteString("    Mem_Buf_Limit     5MB\n")
+			b.WriteString("    Skip_Long_Lines   On\n")
+			b.WriteString("\n[FILTER]\n")
+			b.WriteString("    Name              kubernetes\n")
+			fmt.Fprintf(b, "    Match             %s\n", match)
+			fmt.Fprintf(b, "    Kube_Tag_Prefix   %s.var.log.containers.\n", l.Tag)
+			b.WriteString("    Merge_Log         On\n")
+		case "docker":
+			b.WriteString("[INPUT]\n")
+			b.WriteString("    Name              tail\n")
+			fmt.Fprintf(b, "    Tag               %s\n", l.Tag)
+			b.WriteString("    Path              /var/lib/docker/containers/*/*.log\n")
+			b.WriteString("    Parser            docker\n")
+			b.WriteString("    Skip_Long_Lines   On\n")
+		case "systemd":
+			b.WriteString("[INPUT]\n")
+			b.WriteString("    Name              systemd\n")
+			fmt.Fprintf(b, "    Tag               %s\n", l.Tag)
+			b.WriteString("    Read_From_Tail    On\n")
+		default:
+			b.WriteString("# Adjust Path to the files of the application.\n")
+			b.WriteString("[INPUT]\n")
+			b.WriteString("    Name              tail\n")
+			fmt.Fprintf(b, "    Tag               %s\n", l.Tag)
+			fmt.Fprintf(b, "    Path              %s\n", genericPath(l.Tag))
+			b.WriteString("    Skip_Long_Lines   On\n")
+		}
+
+		b.WriteString("\n[OUTPUT]\n")
+		if l.Protocol == ship.ProtocolTCP {
+			b.WriteString("    Name              forward\n")
+			fmt.Fprintf(b, "    Match             %s\n", match)
+			fmt.Fprintf(b, "    Host              %s\n", l.Host)
+			fmt.Fprintf(b, "    Port              %s\n", l.Port)
+			fmt.Fprintf(b, "    Tag               %s\n", l.Tag)
+			fmt.Fprintf(b, "    Shared_Key        %s\n", key)
+			fmt.Fprintf(b, "    Self_Hostname     ${HOSTNAME}\n")
+		} else {
+			b.WriteString("    Name              http\n")
+			fmt.Fprintf(b, "    Match             %s\n", match)
+			fmt.Fprintf(b, "    Host              %s\n", l.Host)
+			fmt.Fprintf(b, "    Port              %s\n", l.Port)
+			fmt.Fprintf(b, "    URI               /%s\n", l.Tag)
+			b.WriteString("    Format            json\n")
+			fmt.Fprintf(b, "    Header            APIKEY %s\n", key)
+		}
+		b.WriteString("    tls               On\n")
+		b.WriteString("    tls.verify        On\n")
+	}
+	return b.String()
+}
+
+func renderVector(a agentConfig) (string, error) {
+	for _, l := range a.Logs {
+		if l.Protocol != ship.ProtocolHTTP {
+			return "", unsupportedError("Vector", l.Tag, l.Protocol, "fluent-bit or otel-collector")
+		}
+	}
+
+	b := &strings.Builder{}
+	a.header(b, "Vector", "/etc/vector/vector.yaml")
+	b.WriteString("sources:\n")
+	for _, l := range a.Logs {
+		fmt.Fprintf(b, "  %s:\n", l.Tag)
+		switch l.Source {
+		case "kubernetes":
+			b.WriteString("    type: kubernetes_logs\n")
+		case "docker":
+			b.WriteString("    type: docker_logs\n")
+		case "systemd":
+			b.WriteString("    type: journald\n")
+		default:
+			b.WriteString("    type: file\n")
+			b.WriteString("    # Adjust include to the files of the application.\n")
+			b.WriteString("    include:\n")
+			fmt.Fprintf(b, "      - %s\n", genericPath(l.Tag))
+		}
+	}
+
+	b.WriteString("\nsinks:\n")
+	for _, l := range a.Logs {
+		fmt.Fprintf(b, "  %s_ionos:\n", l.Tag)
+		b.WriteString("    type: http\n")
+		fmt.Fprintf(b, "    inputs:\n      - %s\n", l.Tag)
+		fmt.Fprintf(b, "    uri: %s\n", strconv.Quote(fmt.Sprintf("https://%s:%s/%s", l.Host, l.Port, l.Tag)))
+		b.WriteString("    method: post\n")
+		b.WriteString("    encoding:\n      codec: json\n")
+		b.WriteString("    request:\n      headers:\n")
+		fmt.Fprintf(b, "        APIKEY: %s\n", strconv.Quote(a.keyRef("${%s}")))
+		b.WriteString("    tls:\n      verify_certificate: true\n")
+	}
+	return b.String(), nil
+}
+
+func renderOtelCollector(a agentConfig) (string, error) {
+	for _, l := range a.Logs {
+		if l.Protocol != ship.ProtocolTCP {
+			return "", unsupportedError("The OpenTelemetry Collector", l.Tag, l.Protocol, "fluent-bit or vector")
+		}
+	}
+
+	b := &strings.Builder{}
+	a.header(b, "OpenTelemetry Collector (contrib)", "/etc/otelcol-contrib/config.yaml")
+	receivers := make([]string, len(a.Logs))
+	b.WriteString("receivers:\n")
+	for i, l := range a.Logs {
+		switch l.Source {
+		case "kubernetes":
+			receivers[i] = "filelog/" + l.Tag
+			fmt.Fprintf(b, "  %s:\n", receivers[i])
+			b.WriteString("    include:\n      - /var/log/pods/*/*/*.log\n")
+			b.WriteString("    include_file_path: true\n")
+			b.WriteString("    operators:\n      - type: container\n")
+		case "docker":
+			receivers[i] = "filelog/" + l.Tag
+			fmt.Fprintf(b, "  %s:\n", receivers[i])
+			b.WriteString("    include:\n      - /var/lib/docker/containers/*/*.log\n")
+			b.WriteString("    operators:\n      - type: container\n        format: docker\n")
+		case "systemd":
+			receivers[i] = "journald/" + l.Tag
+			fmt.Fprintf(b, "  %s: {}\n", receivers[i])
+		default:
+			receivers[i] = "filelog/" + l.Tag
+			fmt.Fprintf(b, "  %s:\n", receivers[i])
+			b.WriteString("    # Adjust include to the files of the application.\n")
+			fmt.Fprintf(b, "    include:\n      - %s\n", genericPath(l.Tag))
+		}
+	}
+
+	b.WriteString("\nexporters:\n")
+	for _, l := range a.Logs {
+		fmt.Fprintf(b, "  fluentforward/%s:\n", l.Tag)
+		fmt.Fprintf(b, "    endpoint:\n      tcp_addr: %s\n", strconv.Quote(l.Host+":"+l.Port))
+		fmt.Fprintf(b, "    tag: %s\n", strconv.Quote(l.Tag))
+		fmt.Fprintf(b, "    shared_key: %s\n", strconv.Quote(a.keyRef("${env:%s}")))
+		b.WriteString("    require_ack: true\n")
+		b.WriteString("    tls:\n      insecure: false\n")
+	}
+
+	b.WriteString("\nservice:\n  pipelines:\n")
+	for i, l := range a.Logs {
+		fmt.Fprintf(b, "    logs/%s:\n", l.Tag)
+		fmt.Fprintf(b, "      receivers: [%s]\n", receivers[i])
+		fmt.Fprintf(b, "      exporters: [fluentforward/%s]\n", l.Tag)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/logging/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPipeline(logs ...logging.PipelineNoAddrLogs) logging.PipelineRead {
+	return logging.PipelineRead{Id: "abc", Properties: logging.Pipeline{
+		Name:        "prod",
+		Logs:        logs,
+		TcpAddress:  pointer.From("tcp-abc.logging.de-txl.ionos.com"),
+		HttpAddress: pointer.From("https://http-abc.logging.de-txl.ionos.com"),
+	}}
+}
+
+func TestNewAgentConfig(t *testing.T) {
+	a, err := newAgentConfig(testPipeline(
+		logging.PipelineNoAddrLogs{Tag: "k8s", Source: "kubernetes", Protocol: "tcp"},
+		logging.PipelineNoAddrLogs{Tag: "app", Source: "generic", Protocol: "http"},
+	), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []agentLog{
+		{Tag: "k8s", Source: "kubernetes", Protocol: "tcp", Host: "tcp-abc.logging.de-txl.ionos.com", Port: "9000"},
+		{Tag: "app", Source: "generic", Protocol: "http", Host: "http-abc.logging.de-txl.ionos.com", Port: "443"},
+	}, a.Logs)
+
+	_, err = newAgentConfig(testPipeline(), "")
+	assert.Error(t, err)
+
+	p := testPipeline(logging.PipelineNoAddrLogs{Tag: "k8s", Source: "kubernetes", Protocol: "tcp"})
+	p.Properties.TcpAddress = nil
+	_, err = newAgentConfig(p, "")
+	assert.ErrorContains(t, err, "has no TCP address")
+}
+
+func TestRenderFluentBit(t *testing.T) {
+	a, _ := newAgentConfig(testPipeline(
+		logging.PipelineNoAddrLogs{Tag: "k8s", Source: "kubernetes", Protocol: "tcp"},
+		logging.PipelineNoAddrLogs{Tag: "app", Source: "generic", Protocol: "http"},
+	), "")
+	conf, err := renderAgentConfig("fluent-bit", a)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, conf, "# The key of the pipeline is read from the environment variable IONOS_LOGGING_PIPELINE_KEY.\n")
+	assert.Contains(t, conf, "    Name              kubernetes\n    Match             k8s.*\n    Kube_Tag_Prefix   k8s.var.log.containers.\n")
+	assert.Contains(t, conf, "    Name              forward\n"+
+		"    Match             k8s.*\n"+
+		"    Host              tcp-abc.logging.de-txl.ionos.com\n"+
+		"    Port              9000\n"+
+		"    Tag               k8s\n"+
+		"    Shared_Key        ${IONOS_LOGGING_PIPELINE_KEY}\n")
+	assert.Contains(t, conf, "    Path              /var/log/app/*.log\n")
+	assert.Contains(t, conf, "    Name              http\n"+
+		"    Match             app\n"+
+		"    Host              http-abc.logging.de-txl.ionos.com\n"+
+		"    Port              443\n"+
+		"    URI               /app\n"+
+		"    Format            json\n"+
+		"    Header            APIKEY ${IONOS_LOGGING_PIPELINE_KEY}\n"+
+		"    tls               On\n")
+}
+
+func TestRenderVector(t *testing.T) {
+	a, _ := newAgentConfig(testPipeline(
+		logging.PipelineNoAddrLogs{Tag: "journal", Source: "systemd", Protocol: "http"},
+	), "s3cret")
+	conf, err := renderAgentConfig("vector", a)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, conf, "IONOS_LOGGING_PIPELINE_KEY")
+	assert.Contains(t, conf, "sources:\n  journal:\n    type: journald\n")
+	assert.Contains(t, conf, "    uri: \"https://http-abc.logging.de-txl.ionos.com:443/journal\"\n")
+	assert.Contains(t, conf, "        APIKEY: \"s3cret\"\n")
+
+	a, _ = newAgentConfig(testPipeline(logging.PipelineNoAddrLogs{Tag: "k8s", Source: "kubernetes", Protocol: "tcp"}), "")
+	_, err = renderAgentConfig("vector", a)
+	assert.ErrorContains(t, err, "Vector cannot send log k8s over TCP")
+}
+
+func TestRenderOtelCollector(t *testing.T) {
+	a, _ := newAgentConfig(testPipeline(
+		logging.PipelineNoAddrLogs{Tag: "docker", Source: "docker", Protocol: "tcp"},
+		logging.PipelineNoAddrLogs{Tag: "journal", Source: "systemd", Protocol: "tcp"},
+	), "")
+	conf, err := renderAgentConfig("otel-collector", a)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, conf, "  filelog/docker:\n    include:\n      - /var/lib/docker/containers/*/*.log\n")
+	assert.Contains(t, conf, "  journald/journal: {}\n")
+	assert.Contains(t, conf, "  fluentforward/journal:\n"+
+		"    endpoint:\n      tcp_addr: \"tcp-abc.logging.de-txl.ionos.com:9000\"\n"+
+		"    tag: \"journal\"\n"+
+		"    shared_key: \"${env:IONOS_LOGGING_PIPELINE_KEY}\"\n")
+	assert.Contains(t, conf, "    logs/journal:\n      receivers: [journald/journal]\n      exporters: [fluentforward/journal]\n")
+
+	a, _ = newAgentConfig(testPipeline(logging.PipelineNoAddrLogs{Tag: "app", Source: "generic", Protocol: "http"}), "")
+	_, err = renderAgentConfig("otel-collector", a)
+	assert.ErrorContains(t, err, "cannot send log app over HTTP")
+
+	_, err = renderAgentConfig("logstash", a)
+	assert.Error(t, err)
+}
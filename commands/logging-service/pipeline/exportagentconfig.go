@@ -0,0 +1,111 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/completer"
+	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/ship"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/confirm"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFormat = "format"
+	FlagNewKey = "new-key"
+)
+
+func PipelineExportAgentConfigCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.Background(), nil, core.CommandBuilder{
+			Namespace: "logging-service",
+			Resource:  "pipeline",
+			Verb:      "export-agent-config",
+			Aliases:   []string{"export", "eac"},
+			ShortDesc: "Generate the configuration of a log agent that sends logs to a logging pipeline",
+			LongDesc: "Generate the configuration of a log agent, with an input for the source and an output to the TCP or HTTP endpoint " +
+				"of every log of the pipeline. The outputs use TLS and authenticate with the key of the pipeline.\n\n" +
+				"Formats:\n" +
+				"  fluent-bit:     fluent-bit.conf, for logs over TCP and HTTP\n" +
+				"  vector:         vector.yaml, for logs over HTTP\n" +
+				"  otel-collector: config.yaml of the OpenTelemetry Collector contrib distribution, for logs over TCP\n\n" +
+				"An error is returned if the agent cannot send a log of the pipeline. " +
+				"Without --" + ship.FlagKey + " or --" + FlagNewKey + ", the configuration reads the key from the environment variable " + keyEnv + ".",
+			Example: "ionosctl logging-service pipeline export-agent-config " +
+				core.FlagsUsage(constants.FlagLoggingPipelineId, FlagFormat) + " > /etc/fluent-bit/fluent-bit.conf",
+			PreCmdRun: preRunExportAgentConfigCmd,
+			CmdRun:    runExportAgentConfigCmd,
+		},
+	)
+	cmd.AddStringFlag(
+		constants.FlagLoggingPipelineId, constants.FlagIdShort, "",
+		"The ID of the logging pipeline", core.RequiredFlagOption(),
+		core.WithCompletion(completer.LoggingServicePipelineIds, constants.LoggingApiRegionalURL, constants.LoggingLocations),
+	)
+	cmd.AddSetFlag(FlagFormat, "", "", agentConfigFormats, "Format of the configuration, for the log agent", core.RequiredFlagOption())
+	cmd.AddStringFlag(ship.FlagKey, "", "", "The key of the pipeline to write into the configuration")
+	cmd.AddBoolFlag(FlagNewKey, "", false,
+		"Generate a new key for the pipeline and write it into the configuration. The previous key of the pipeline stops working")
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+
+	return cmd
+}
+
+func preRunExportAgentConfigCmd(c *core.PreCommandConfig) error {
+	c.Command.Command.MarkFlagsMutuallyExclusive(ship.FlagKey, FlagNewKey)
+	return c.CheckRequiredFlagsAndLocation(constants.FlagLoggingPipelineId, FlagFormat)
+}
+
+func runExportAgentConfigCmd(c *core.CommandConfig) error {
+	pipelineId := viper.GetString(core.GetFlagName(c.NS, constants.FlagLoggingPipelineId))
+
+	pipeline, _, err := client.Must().LoggingServiceClient.PipelinesApi.PipelinesFindById(
+		context.Background(), pipelineId,
+	).Execute()
+	if err != nil {
+		return fmt.Errorf("failed getting pipeline by id %s: %w", pipelineId, err)
+	}
+
+	key := viper.GetString(core.GetFlagName(c.NS, ship.FlagKey))
+	a, err := newAgentConfig(pipeline, key)
+	if err != nil {
+		return err
+	}
+	// Rendered before a new key is generated, so the key of the pipeline is not replaced for nothing.
+	conf, err := renderAgentConfig(viper.GetString(core.GetFlagName(c.NS, FlagFormat)), a)
+	if err != nil {
+		return err
+	}
+
+	if viper.GetBool(core.GetFlagName(c.NS, FlagNewKey)) {
+		if !confirm.FAsk(c.Command.Command.InOrStdin(), "generate a new key, the previous key of the pipeline stops working",
+			viper.GetBool(constants.ArgForce)) {
+			return fmt.Errorf(confirm.UserDenied)
+		}
+
+		k, _, err := client.Must().LoggingServiceClient.KeyApi.PipelinesKeyPost(
+			context.Background(), pipelineId,
+		).Body(
+			map[string]interface{}{}, // explicit empty body due to 'Error: body is required and must be specified'
+		).Execute()
+		if err != nil {
+			return fmt.Errorf("failed generating a new key: %w", err)
+		}
+		a.Key = k.Key
+		if conf, err = renderAgentConfig(viper.GetString(core.GetFlagName(c.NS, FlagFormat)), a); err != nil {
+			return err
+		}
+	}
+	if a.Key == "" {
+		fmt.Fprintf(c.Command.Command.ErrOrStderr(),
+			"WARNING: the key of the pipeline is read from the environment variable %s, set it on the agent or use --%s\n", keyEnv, ship.FlagKey)
+	}
+
+	_, err = fmt.Fprint(c.Command.Command.OutOrStdout(), conf)
+	return err
+}
@@ -36,6 +36,7 @@ func PipelineCmd() *core.Command {
 	cmd.AddCommand(PipelineCreateCmd())
 	cmd.AddCommand(PipelineUpdateCmd())
 	cmd.AddCommand(PipelineKeyCmd())
+	cmd.AddCommand(PipelineExportAgentConfigCmd())
 	return cmd
 }
 
@@ -0,0 +1,111 @@
+package ship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Record is a log line with the time it was read.
+type Record struct {
+	Time time.Time
+	Line string
+}
+
+// Sender delivers a batch of records of a tag to a pipeline.
+type Sender interface {
+	Send(ctx context.Context, tag string, records []Record) error
+	Close() error
+}
+
+type Options struct {
+	Tag string
+	// BatchSize is the largest number of records sent at once.
+	BatchSize int
+	// FlushInterval is the longest time a record waits for its batch to fill up.
+	FlushInterval time.Duration
+	// Retries is how often a failed batch is sent again, waiting Backoff, then twice as long every time.
+	Retries int
+	Backoff time.Duration
+}
+
+const maxBackoff = 30 * time.Second
+
+// Ship sends the records of the channel in batches until the channel is closed, then sends the rest. It returns the
+// number of records sent, and stops with an error once a batch could not be sent after all retries. If the
+// context is done, the records already read are still sent, as long as the sender succeeds on its first try.
+func Ship(ctx context.Context, records <-chan Record, s Sender, opts Options) (int, error) {
+	sent := 0
+	var batch []Record
+	flush := func(ctx context.Context, retries int) error {
+		for len(batch) > 0 {
+			n := min(len(batch), opts.BatchSize)
+			if err := sendWithRetry(ctx, s, opts, batch[:n], retries); err != nil {
+				return fmt.Errorf("failed sending %d records: %w", n, err)
+			}
+			sent += n
+			batch = batch[n:]
+		}
+		batch = nil
+		return nil
+	}
+
+	timer := time.NewTimer(opts.FlushInterval)
+	timer.Stop()
+	for {
+		select {
+		case r, ok := <-records:
+			if !ok {
+				return sent, flush(ctx, opts.Retries)
+			}
+			if len(batch) == 0 {
+				timer.Reset(opts.FlushInterval)
+			}
+			batch = append(batch, r)
+			if len(batch) < opts.BatchSize {
+				continue
+			}
+		case <-timer.C:
+		case <-ctx.Done():
+			// Drain what was read already, with a fresh context, so an interrupt does not lose it.
+		drain:
+			for {
+				select {
+				case r, ok := <-records:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, r)
+				default:
+					break drain
+				}
+			}
+			return sent, flush(context.Background(), 0)
+		}
+		timer.Stop()
+		if err := flush(ctx, opts.Retries); err != nil {
+			return sent, err
+		}
+	}
+}
+
+func sendWithRetry(ctx context.Context, s Sender, opts Options, batch []Record, retries int) error {
+	backoff := opts.Backoff
+	for attempt := 0; ; attempt++ {
+		err := s.Send(ctx, opts.Tag, batch)
+		if err == nil {
+			return nil
+		}
+		var status *statusError
+		if attempt >= retries || (errors.As(err, &status) && !status.retryable()) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
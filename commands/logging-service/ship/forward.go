@@ -0,0 +1,184 @@
+package ship
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// forwardSender sends records with the Fluent forward protocol, authenticated with the shared key of the pipeline.
+// The connection is opened on the first send, and again after a failed send.
+type forwardSender struct {
+	addr     string
+	tls      *tls.Config
+	key      string
+	hostname string
+	timeout  time.Duration
+
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newForwardSender(addr string, tlsConfig *tls.Config, key string) *forwardSender {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "ionosctl"
+	}
+	return &forwardSender{addr: addr, tls: tlsConfig, key: key, hostname: hostname, timeout: 30 * time.Second}
+}
+
+// Send sends the records as a single Forward mode message and waits for the server to acknowledge it.
+func (s *forwardSender) Send(ctx context.Context, tag string, records []Record) error {
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	if err := s.send(tag, records); err != nil {
+		s.Close()
+		return err
+	}
+	return nil
+}
+
+func (s *forwardSender) send(tag string, records []Record) error {
+	chunk, err := randomString()
+	if err != nil {
+		return err
+	}
+
+	msg := appendArrayHeader(nil, 3)
+	msg = appendString(msg, tag)
+	msg = appendArrayHeader(msg, len(records))
+	for _, r := range records {
+		msg = appendArrayHeader(msg, 2)
+		msg = appendEventTime(msg, r.Time)
+		msg = appendMapHeader(msg, 1)
+		msg = appendString(msg, "log")
+		msg = appendString(msg, r.Line)
+	}
+	msg = appendMapHeader(msg, 1)
+	msg = appendString(msg, "chunk")
+	msg = appendString(msg, chunk)
+
+	_ = s.conn.SetDeadline(time.Now().Add(s.timeout))
+	if _, err := s.conn.Write(msg); err != nil {
+		return err
+	}
+	resp, err := decode(s.r)
+	if err != nil {
+		return fmt.Errorf("failed reading the acknowledgement: %w", err)
+	}
+	if ack, _ := resp.(map[string]any); ack == nil || ack["ack"] != chunk {
+		return fmt.Errorf("unexpected acknowledgement %v", resp)
+	}
+	return nil
+}
+
+// connect opens the connection and, with a shared key, answers the HELO of the server.
+func (s *forwardSender) connect(ctx context.Context) error {
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if s.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: s.tls}).DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	s.conn, s.r = conn, bufio.NewReader(conn)
+	if s.key == "" {
+		return nil
+	}
+	if err := s.handshake(); err != nil {
+		s.Close()
+		return fmt.Errorf("failed authenticating with %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+func (s *forwardSender) handshake() error {
+	_ = s.conn.SetDeadline(time.Now().Add(s.timeout))
+	helo, err := decode(s.r)
+	if err != nil {
+		return fmt.Errorf("failed reading HELO: %w", err)
+	}
+	fields, _ := helo.([]any)
+	if len(fields) != 2 || fields[0] != "HELO" {
+		return fmt.Errorf("unexpected HELO %v", helo)
+	}
+	options, _ := fields[1].(map[string]any)
+	nonce, _ := options["nonce"].(string)
+	if _, ok := options["auth"].(string); ok && options["auth"] != "" {
+		return errors.New("the server requires a user name and password, which are not supported")
+	}
+
+	salt, err := randomString()
+	if err != nil {
+		return err
+	}
+	ping := appendArrayHeader(nil, 6)
+	ping = appendString(ping, "PING")
+	ping = appendString(ping, s.hostname)
+	ping = appendString(ping, salt)
+	ping = appendString(ping, digest(salt, s.hostname, nonce, s.key))
+	ping = appendString(ping, "")
+	ping = appendString(ping, "")
+	if _, err := s.conn.Write(ping); err != nil {
+		return err
+	}
+
+	pong, err := decode(s.r)
+	if err != nil {
+		return fmt.Errorf("failed reading PONG: %w", err)
+	}
+	fields, _ = pong.([]any)
+	if len(fields) != 5 || fields[0] != "PONG" {
+		return fmt.Errorf("unexpected PONG %v", pong)
+	}
+	if ok, _ := fields[1].(bool); !ok {
+		return fmt.Errorf("rejected: %v", fields[2])
+	}
+	serverHostname, _ := fields[3].(string)
+	if fields[4] != digest(salt, serverHostname, nonce, s.key) {
+		return errors.New("the server does not know the shared key")
+	}
+	return nil
+}
+
+func (s *forwardSender) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.r = nil, nil
+	return err
+}
+
+// digest is the hex SHA-512 of the parts, which proves the knowledge of the shared key without sending it.
+func digest(parts ...string) string {
+	h := sha512.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,85 @@
+package ship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// httpSender posts records as the JSON array of the Fluent Bit http output to the endpoint, under the path of the
+// tag. The pipeline key is sent in the APIKEY header.
+type httpSender struct {
+	client   *http.Client
+	endpoint string
+	key      string
+}
+
+type httpRecord struct {
+	Date float64 `json:"date"`
+	Log  string  `json:"log"`
+}
+
+func newHTTPSender(endpoint, key string) *httpSender {
+	return &httpSender{client: &http.Client{Timeout: 30 * time.Second}, endpoint: endpoint, key: key}
+}
+
+func (s *httpSender) Send(ctx context.Context, tag string, records []Record) error {
+	body := make([]httpRecord, len(records))
+	for i, r := range records {
+		body[i] = httpRecord{Date: float64(r.Time.UnixNano()) / float64(time.Second), Log: r.Line}
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	u, err := url.JoinPath(s.endpoint, url.PathEscape(tag))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.key != "" {
+		req.Header.Set("APIKEY", s.key)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{code: resp.StatusCode, msg: string(bytes.TrimSpace(msg))}
+	}
+	return nil
+}
+
+func (s *httpSender) Close() error {
+	return nil
+}
+
+// statusError is an HTTP error response. Client errors other than 429 are not retried.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	if e.msg == "" {
+		return fmt.Sprintf("%d %s", e.code, http.StatusText(e.code))
+	}
+	return fmt.Sprintf("%d %s: %s", e.code, http.StatusText(e.code), e.msg)
+}
+
+func (e *statusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
@@ -0,0 +1,204 @@
+package ship
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// The Fluent forward protocol is MessagePack over TCP. Only the types the protocol uses are encoded and decoded.
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xda)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdb)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xdc)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdd)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+func appendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xde)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdf)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+// appendEventTime appends t as the EventTime extension of the forward protocol, which keeps nanoseconds.
+func appendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// decode reads a single value. Strings and binaries are decoded as strings, maps as map[string]any and
+// EventTimes as time.Time.
+func decode(r *bufio.Reader) (any, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xe0 == 0xa0:
+		return readString(r, int(c&0x1f))
+	case c&0xf0 == 0x90:
+		return readArray(r, int(c&0x0f))
+	case c&0xf0 == 0x80:
+		return readMap(r, int(c&0x0f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xd9:
+		n, err := readUint(r, 1)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xc5, 0xda:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xc6, 0xdb:
+		n, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return readString(r, int(n))
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		n, err := readUint(r, 1<<(c-0xcc))
+		return int64(n), err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := readUint(r, size)
+		if err != nil {
+			return nil, err
+		}
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, nil
+	case 0xd7:
+		typ, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if typ != 0x00 {
+			return nil, fmt.Errorf("unsupported MessagePack extension %d", int8(typ))
+		}
+		sec, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		nsec, err := readUint(r, 4)
+		return time.Unix(int64(sec), int64(nsec)), err
+	case 0xdc:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readArray(r, int(n))
+	case 0xdd:
+		n, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return readArray(r, int(n))
+	case 0xde:
+		n, err := readUint(r, 2)
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, int(n))
+	case 0xdf:
+		n, err := readUint(r, 4)
+		if err != nil {
+			return nil, err
+		}
+		return readMap(r, int(n))
+	}
+	return nil, fmt.Errorf("unsupported MessagePack type 0x%02x", c)
+}
+
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, b := range buf {
+		n = n<<8 | uint64(b)
+	}
+	return n, nil
+}
+
+func readString(r *bufio.Reader, n int) (string, error) {
+	buf := make([]byte, n)
+	_, err := io.ReadFull(r, buf)
+	return string(buf), err
+}
+
+func readArray(r *bufio.Reader, n int) ([]any, error) {
+	a := make([]any, n)
+	for i := range a {
+		v, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		a[i] = v
+	}
+	return a, nil
+}
+
+func readMap(r *bufio.Reader, n int) (map[string]any, error) {
+	m := make(map[string]any, n)
+	for i := 0; i < n; i++ {
+		k, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := decode(r)
+		if err != nil {
+			return nil, err
+		}
+		m[fmt.Sprint(k)] = v
+	}
+	return m, nil
+}
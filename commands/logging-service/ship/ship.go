@@ -0,0 +1,272 @@
+// Package ship forwards log files to a logging pipeline, without installing a log agent like Fluent Bit.
+package ship
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/logging-service/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/sdk-go-bundle/products/logging/v2"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagTag           = "tag"
+	FlagFile          = "file"
+	FlagFollow        = "follow"
+	FlagKey           = "key"
+	FlagProtocol      = "protocol"
+	FlagEndpoint      = "endpoint"
+	FlagBatchSize     = "batch-size"
+	FlagFlushInterval = "flush-interval"
+	FlagRetries       = "retries"
+
+	ProtocolHTTP = "http"
+	ProtocolTCP  = "tcp"
+
+	// The ports of the ingestion endpoints, if the addresses of the pipeline have none.
+	defaultTCPPort  = "9000"
+	defaultHTTPPort = "443"
+
+	pollInterval = 250 * time.Millisecond
+)
+
+func ShipCmd() *core.Command {
+	cmd := core.NewCommand(
+		context.Background(), nil, core.CommandBuilder{
+			Namespace: "logging-service",
+			Resource:  "logging-service",
+			Verb:      "ship",
+			ShortDesc: "Send log files or stdin to a logging pipeline",
+			LongDesc: `Send the lines of log files, or of stdin, to a logging pipeline, without installing a log agent like Fluent Bit.
+
+The lines are sent with the tag of one of the logs of the pipeline, with the protocol of that log: the Fluent forward protocol over TLS for 'tcp' logs, or HTTPS for 'http' logs. The pipeline key authenticates the sender. It is printed when the pipeline is created, or by 'ionosctl logging-service pipeline key', which invalidates the previous key.
+
+Lines are sent in batches of up to --batch-size lines, at least every --flush-interval. A batch which fails is sent again up to --retries times, with a growing pause. Without --follow, the command ends once the files are sent. With --follow, it keeps sending the lines appended to the files, also across log rotation, until it is interrupted. The lines read so far are sent before it exits.
+
+--endpoint sends to another address than the pipeline's, e.g. a local stub. Use 'tcp://HOST:PORT' for the forward protocol without TLS, and 'http://HOST:PORT' for HTTP without TLS. With both --endpoint and --protocol, the pipeline is not looked up.`,
+			Example: `ionosctl logging-service ship --pipeline-id ID --key KEY --tag app --file /var/log/app.log --follow
+journalctl -f -u app | ionosctl logging-service ship --pipeline-id ID --key KEY --tag app
+ionosctl logging-service ship --endpoint tcp://localhost:24224 --protocol tcp --tag app --file app.log`,
+			PreCmdRun: preRunShipCmd,
+			CmdRun:    runShipCmd,
+		},
+	)
+	cmd.AddStringFlag(
+		constants.FlagLoggingPipelineId, constants.FlagIdShort, "", "The ID of the logging pipeline to send the logs to",
+		core.WithCompletion(completer.LoggingServicePipelineIds, constants.LoggingApiRegionalURL, constants.LoggingLocations),
+	)
+	cmd.AddStringFlag(FlagTag, "", "", "The tag of the pipeline log to send the lines as", core.RequiredFlagOption())
+	cmd.AddStringSliceFlag(FlagFile, "", nil, "The files to send. '-' is stdin, which is also read if no file is set")
+	cmd.AddBoolFlag(FlagFollow, "", false, "Keep sending the lines appended to the files, until interrupted")
+	cmd.AddStringFlag(FlagKey, "", "", "The key of the pipeline")
+	cmd.AddSetFlag(FlagProtocol, "", "", []string{ProtocolHTTP, ProtocolTCP}, "The protocol to send with. By default, the protocol of the pipeline log with the tag")
+	cmd.AddStringFlag(FlagEndpoint, "", "", "The address to send to, instead of the address of the pipeline")
+	cmd.AddIntFlag(FlagBatchSize, "", 500, "The largest number of lines sent at once")
+	cmd.AddDurationFlag(FlagFlushInterval, "", time.Second, "The longest time a line waits for its batch to fill up")
+	cmd.AddIntFlag(FlagRetries, "", 5, "How often a batch which failed is sent again")
+
+	return cmd
+}
+
+func preRunShipCmd(c *core.PreCommandConfig) error {
+	if err := core.CheckRequiredFlags(c.Command, c.NS, FlagTag); err != nil {
+		return err
+	}
+	standalone := viper.IsSet(core.GetFlagName(c.NS, FlagEndpoint)) && viper.IsSet(core.GetFlagName(c.NS, FlagProtocol))
+	if !standalone {
+		if err := c.CheckRequiredFlagsAndLocation(constants.FlagLoggingPipelineId, FlagKey); err != nil {
+			return err
+		}
+	}
+	for _, file := range viper.GetStringSlice(core.GetFlagName(c.NS, FlagFile)) {
+		if file == "-" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			return err
+		}
+	}
+	if viper.GetInt(core.GetFlagName(c.NS, FlagBatchSize)) < 1 {
+		return fmt.Errorf("--%s must be at least 1", FlagBatchSize)
+	}
+	if viper.GetDuration(core.GetFlagName(c.NS, FlagFlushInterval)) <= 0 {
+		return fmt.Errorf("--%s must be positive", FlagFlushInterval)
+	}
+	if viper.GetInt(core.GetFlagName(c.NS, FlagRetries)) < 0 {
+		return fmt.Errorf("--%s must not be negative", FlagRetries)
+	}
+	return nil
+}
+
+func runShipCmd(c *core.CommandConfig) error {
+	tag := viper.GetString(core.GetFlagName(c.NS, FlagTag))
+	protocol := viper.GetString(core.GetFlagName(c.NS, FlagProtocol))
+	endpoint := viper.GetString(core.GetFlagName(c.NS, FlagEndpoint))
+	key := viper.GetString(core.GetFlagName(c.NS, FlagKey))
+
+	if endpoint == "" || protocol == "" {
+		pipelineId := viper.GetString(core.GetFlagName(c.NS, constants.FlagLoggingPipelineId))
+		pipeline, _, err := client.Must().LoggingServiceClient.PipelinesApi.PipelinesFindById(
+			context.Background(), pipelineId,
+		).Execute()
+		if err != nil {
+			return err
+		}
+		pipelineProtocol, pipelineEndpoint, err := pipelineTarget(pipeline, tag)
+		if err != nil {
+			return err
+		}
+		if protocol == "" {
+			protocol = pipelineProtocol
+		}
+		if endpoint == "" {
+			endpoint = pipelineEndpoint
+		}
+	}
+
+	sender, err := newSender(protocol, endpoint, key)
+	if err != nil {
+		return err
+	}
+	defer sender.Close()
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	files := viper.GetStringSlice(core.GetFlagName(c.NS, FlagFile))
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	follow := viper.GetBool(core.GetFlagName(c.NS, FlagFollow))
+	batchSize := viper.GetInt(core.GetFlagName(c.NS, FlagBatchSize))
+
+	records := make(chan Record, batchSize)
+	readErrs := make(chan error, len(files))
+	var wg sync.WaitGroup
+	for _, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var err error
+			if file == "-" {
+				err = readLines(ctx, c.Stdin, records)
+			} else {
+				err = tailFile(ctx, file, follow, pollInterval, records)
+			}
+			if err != nil {
+				readErrs <- fmt.Errorf("failed reading %s: %w", file, err)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(records)
+	}()
+
+	c.Verbose("Sending to %s with %s", endpoint, protocol)
+	sent, err := Ship(ctx, records, sender, Options{
+		Tag:           tag,
+		BatchSize:     batchSize,
+		FlushInterval: viper.GetDuration(core.GetFlagName(c.NS, FlagFlushInterval)),
+		Retries:       viper.GetInt(core.GetFlagName(c.NS, FlagRetries)),
+		Backoff:       time.Second,
+	})
+	c.Verbose("Sent %d lines", sent)
+	if err != nil {
+		return err
+	}
+	select {
+	case err := <-readErrs:
+		return err
+	default:
+		return nil
+	}
+}
+
+// pipelineTarget returns the protocol of the log of the pipeline with the tag, and the address of its endpoint.
+func pipelineTarget(pipeline logging.PipelineRead, tag string) (protocol, endpoint string, err error) {
+	var tags []string
+	for _, l := range pipeline.Properties.Logs {
+		if l.Tag == tag {
+			protocol = l.Protocol
+			break
+		}
+		tags = append(tags, l.Tag)
+	}
+	if protocol == "" {
+		return "", "", fmt.Errorf("pipeline %s has no log with tag %q, its tags are: %s", pipeline.Id, tag, strings.Join(tags, ", "))
+	}
+
+	host, port, err := Endpoint(pipeline, protocol)
+	if err != nil {
+		return "", "", err
+	}
+	if protocol == ProtocolHTTP {
+		return protocol, "https://" + net.JoinHostPort(host, port), nil
+	}
+	return protocol, net.JoinHostPort(host, port), nil
+}
+
+// Endpoint returns the host and port of the ingestion endpoint of the pipeline for the protocol of a log.
+func Endpoint(pipeline logging.PipelineRead, protocol string) (host, port string, err error) {
+	var address *string
+	var defaultPort string
+	switch protocol {
+	case ProtocolTCP:
+		address, defaultPort = pipeline.Properties.TcpAddress, defaultTCPPort
+	case ProtocolHTTP:
+		address, defaultPort = pipeline.Properties.HttpAddress, defaultHTTPPort
+	default:
+		return "", "", fmt.Errorf("unsupported protocol %q", protocol)
+	}
+	if address == nil || *address == "" {
+		return "", "", fmt.Errorf("pipeline %s has no %s address yet", pipeline.Id, strings.ToUpper(protocol))
+	}
+
+	hostPort := *address
+	if u, err := url.Parse(hostPort); err == nil && u.Host != "" {
+		hostPort = u.Host
+	}
+	if host, port, err := net.SplitHostPort(hostPort); err == nil {
+		return host, port, nil
+	}
+	return hostPort, defaultPort, nil
+}
+
+// newSender returns the sender of the protocol. TCP endpoints use TLS, unless they have the scheme 'tcp://'.
+func newSender(protocol, endpoint, key string) (Sender, error) {
+	switch protocol {
+	case ProtocolHTTP:
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, fmt.Errorf("invalid HTTP endpoint %q, expected http:// or https://", endpoint)
+		}
+		return newHTTPSender(endpoint, key), nil
+	case ProtocolTCP:
+		var tlsConfig *tls.Config
+		address, plain := strings.CutPrefix(endpoint, "tcp://")
+		if !plain {
+			address = strings.TrimPrefix(address, "tls://")
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, fmt.Errorf("invalid TCP endpoint %q, expected HOST:PORT: %w", endpoint, err)
+			}
+			tlsConfig = &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+		}
+		return newForwardSender(address, tlsConfig, key), nil
+	}
+	return nil, fmt.Errorf("unsupported protocol %q", protocol)
+}
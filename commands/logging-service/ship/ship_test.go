@@ -0,0 +1,350 @@
+package ship
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ionos-cloud/sdk-go-bundle/products/logging/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMsgpack(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	at := time.Unix(1700000000, 123)
+	b := appendArrayHeader(nil, 4)
+	b = appendString(b, "short")
+	b = appendString(b, long)
+	b = appendEventTime(b, at)
+	b = appendMapHeader(b, 1)
+	b = appendString(b, "n")
+	b = append(b, 0xd0, 0xfe) // int8 -2
+
+	v, err := decode(bufio.NewReader(strings.NewReader(string(b))))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []any{"short", long, at, map[string]any{"n": int64(-2)}}, v)
+}
+
+// forwardStub is a Fluent forward server which requires the shared key, like a pipeline's TCP endpoint.
+type forwardStub struct {
+	key      string
+	mu       sync.Mutex
+	tags     []string
+	received []string
+}
+
+func (s *forwardStub) serve(t *testing.T, l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(t, conn)
+	}
+}
+
+func (s *forwardStub) handle(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	const nonce = "nonce"
+	helo := appendArrayHeader(nil, 2)
+	helo = appendString(helo, "HELO")
+	helo = appendMapHeader(helo, 2)
+	helo = appendString(helo, "nonce")
+	helo = appendString(helo, nonce)
+	helo = appendString(helo, "auth")
+	helo = appendString(helo, "")
+	conn.Write(helo)
+
+	v, err := decode(r)
+	if err != nil {
+		return
+	}
+	ping := v.([]any)
+	salt, hostname := ping[2].(string), ping[1].(string)
+	ok := ping[3] == digest(salt, hostname, nonce, s.key)
+	pong := appendArrayHeader(nil, 5)
+	pong = appendString(pong, "PONG")
+	if ok {
+		pong = append(pong, 0xc3)
+	} else {
+		pong = append(pong, 0xc2)
+	}
+	pong = appendString(pong, "shared key mismatch")
+	pong = appendString(pong, "stub")
+	pong = appendString(pong, digest(salt, "stub", nonce, s.key))
+	conn.Write(pong)
+	if !ok {
+		return
+	}
+
+	for {
+		v, err := decode(r)
+		if err != nil {
+			return
+		}
+		msg := v.([]any)
+		s.mu.Lock()
+		s.tags = append(s.tags, msg[0].(string))
+		for _, entry := range msg[1].([]any) {
+			record := entry.([]any)[1].(map[string]any)
+			s.received = append(s.received, record["log"].(string))
+		}
+		s.mu.Unlock()
+
+		ack := appendMapHeader(nil, 1)
+		ack = appendString(ack, "ack")
+		ack = appendString(ack, msg[2].(map[string]any)["chunk"].(string))
+		conn.Write(ack)
+	}
+}
+
+func TestForwardSender(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+	stub := &forwardStub{key: "secret"}
+	go stub.serve(t, l)
+
+	s := newForwardSender(l.Addr().String(), nil, "secret")
+	defer s.Close()
+	records := []Record{{Time: time.Now(), Line: "one"}, {Time: time.Now(), Line: "two"}}
+	assert.NoError(t, s.Send(context.Background(), "app", records))
+	assert.NoError(t, s.Send(context.Background(), "app", records[:1]))
+	assert.Equal(t, []string{"app", "app"}, stub.tags)
+	assert.Equal(t, []string{"one", "two", "one"}, stub.received)
+
+	wrong := newForwardSender(l.Addr().String(), nil, "wrong")
+	err = wrong.Send(context.Background(), "app", records)
+	assert.ErrorContains(t, err, "shared key mismatch")
+}
+
+func TestHTTPSender(t *testing.T) {
+	var paths, keys []string
+	var bodies [][]httpRecord
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []httpRecord
+		json.NewDecoder(r.Body).Decode(&body)
+		paths = append(paths, r.URL.Path)
+		keys = append(keys, r.Header.Get("APIKEY"))
+		bodies = append(bodies, body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := newHTTPSender(srv.URL, "secret")
+	at := time.Unix(1700000000, 500000000)
+	assert.NoError(t, s.Send(context.Background(), "app", []Record{{Time: at, Line: "one"}}))
+	assert.Equal(t, []string{"/app"}, paths)
+	assert.Equal(t, []string{"secret"}, keys)
+	assert.Equal(t, [][]httpRecord{{{Date: 1700000000.5, Log: "one"}}}, bodies)
+
+	status = http.StatusUnauthorized
+	err := s.Send(context.Background(), "app", []Record{{Time: at, Line: "one"}})
+	var statusErr *statusError
+	if assert.ErrorAs(t, err, &statusErr) {
+		assert.False(t, statusErr.retryable())
+	}
+}
+
+type fakeSender struct {
+	failures int
+	batches  [][]string
+	attempts int
+}
+
+func (s *fakeSender) Send(_ context.Context, _ string, records []Record) error {
+	s.attempts++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("unavailable")
+	}
+	var lines []string
+	for _, r := range records {
+		lines = append(lines, r.Line)
+	}
+	s.batches = append(s.batches, lines)
+	return nil
+}
+
+func (s *fakeSender) Close() error { return nil }
+
+func feed(lines ...string) <-chan Record {
+	records := make(chan Record, len(lines))
+	for _, l := range lines {
+		records <- Record{Line: l}
+	}
+	close(records)
+	return records
+}
+
+func TestShip(t *testing.T) {
+	opts := Options{Tag: "app", BatchSize: 2, FlushInterval: time.Hour, Retries: 2, Backoff: time.Millisecond}
+
+	s := &fakeSender{failures: 2}
+	sent, err := Ship(context.Background(), feed("a", "b", "c", "d", "e"), s, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, sent)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, s.batches)
+	assert.Equal(t, 5, s.attempts)
+
+	s = &fakeSender{failures: 3}
+	sent, err = Ship(context.Background(), feed("a", "b", "c"), s, opts)
+	assert.ErrorContains(t, err, "failed sending 2 records: unavailable")
+	assert.Equal(t, 0, sent)
+}
+
+func TestShipFlushInterval(t *testing.T) {
+	records := make(chan Record)
+	s := &fakeSender{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Ship(context.Background(), records, s, Options{BatchSize: 100, FlushInterval: 10 * time.Millisecond})
+	}()
+	records <- Record{Line: "a"}
+	time.Sleep(100 * time.Millisecond)
+	records <- Record{Line: "b"}
+	close(records)
+	<-done
+	assert.Equal(t, [][]string{{"a"}, {"b"}}, s.batches)
+}
+
+func TestTailFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("one\n\ntwo\npart"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Record, 100)
+	errs := make(chan error, 1)
+	go func() { errs <- tailFile(ctx, path, true, 5*time.Millisecond, out) }()
+
+	next := func() string {
+		select {
+		case r := <-out:
+			return r.Line
+		case <-time.After(2 * time.Second):
+			return "<timeout>"
+		}
+	}
+	appendTo := func(s string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if assert.NoError(t, err) {
+			f.WriteString(s)
+			f.Close()
+		}
+	}
+
+	assert.Equal(t, "one", next())
+	assert.Equal(t, "two", next())
+	appendTo("ial\nthree\n")
+	assert.Equal(t, "partial", next())
+	assert.Equal(t, "three", next())
+
+	// Truncated
+	assert.NoError(t, os.WriteFile(path, []byte("a\n"), 0o600))
+	assert.Equal(t, "a", next())
+
+	// Rotated
+	appendTo("b\n")
+	assert.Equal(t, "b", next())
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, os.WriteFile(path, []byte("new\n"), 0o600))
+	assert.Equal(t, "new", next())
+
+	cancel()
+	assert.NoError(t, <-errs)
+}
+
+func TestTailFileRotatedBeforeRead(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("one\n"), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out := make(chan Record, 100)
+	errs := make(chan error, 1)
+	// The poll interval is long enough to append and rotate before the old file is read again.
+	go func() { errs <- tailFile(ctx, path, true, 200*time.Millisecond, out) }()
+
+	var lines []string
+	next := func() {
+		select {
+		case r := <-out:
+			lines = append(lines, r.Line)
+		case <-time.After(2 * time.Second):
+			lines = append(lines, "<timeout>")
+		}
+	}
+	next()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if assert.NoError(t, err) {
+		f.WriteString("two\nthree")
+		f.Close()
+	}
+	assert.NoError(t, os.Rename(path, path+".1"))
+	assert.NoError(t, os.WriteFile(path, []byte("new\n"), 0o600))
+	for range 3 {
+		next()
+	}
+	assert.Equal(t, []string{"one", "two", "three", "new"}, lines)
+
+	cancel()
+	assert.NoError(t, <-errs)
+}
+
+func TestReadLines(t *testing.T) {
+	out := make(chan Record, 10)
+	assert.NoError(t, readLines(context.Background(), strings.NewReader("a\r\nb\n\nc"), out))
+	close(out)
+	var lines []string
+	for r := range out {
+		lines = append(lines, r.Line)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, lines)
+}
+
+func TestPipelineTarget(t *testing.T) {
+	tcpAddr, httpAddr := "tcp-abc.logging.de-txl.ionos.com", "https://http-abc.logging.de-txl.ionos.com"
+	pipeline := logging.PipelineRead{Id: "p", Properties: logging.Pipeline{
+		Logs: []logging.PipelineNoAddrLogs{
+			{Tag: "app", Protocol: ProtocolTCP},
+			{Tag: "web", Protocol: ProtocolHTTP},
+		},
+		TcpAddress:  &tcpAddr,
+		HttpAddress: &httpAddr,
+	}}
+
+	protocol, endpoint, err := pipelineTarget(pipeline, "app")
+	assert.NoError(t, err)
+	assert.Equal(t, ProtocolTCP, protocol)
+	assert.Equal(t, "tcp-abc.logging.de-txl.ionos.com:9000", endpoint)
+
+	protocol, endpoint, err = pipelineTarget(pipeline, "web")
+	assert.NoError(t, err)
+	assert.Equal(t, ProtocolHTTP, protocol)
+	assert.Equal(t, "https://http-abc.logging.de-txl.ionos.com:443", endpoint)
+
+	_, _, err = pipelineTarget(pipeline, "db")
+	assert.ErrorContains(t, err, "its tags are: app, web")
+}
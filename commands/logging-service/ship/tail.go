@@ -0,0 +1,125 @@
+package ship
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var now = time.Now
+
+// readLines sends the lines of r until it ends. Empty lines are skipped.
+func readLines(ctx context.Context, r io.Reader, out chan<- Record) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if !emit(ctx, out, line) {
+			return nil
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// tailFile sends the lines of the file at path. With follow, it keeps polling for lines appended to the file, like
+// 'tail -F': a file which shrinks is read again from its start, and a file which is replaced, e.g. by log
+// rotation, is read from the start of the new file, once the rest of the old file is read.
+func tailFile(ctx context.Context, path string, follow bool, poll time.Duration, out chan<- Record) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { f.Close() }()
+
+	br := bufio.NewReader(f)
+	var offset int64
+	var partial string
+	for {
+		line, err := br.ReadString('\n')
+		offset += int64(len(line))
+		if err == nil {
+			if !emit(ctx, out, partial+line) {
+				return nil
+			}
+			partial = ""
+			continue
+		}
+		if !errors.Is(err, io.EOF) {
+			return err
+		}
+		// The last line may still be written.
+		partial += line
+		if !follow {
+			emit(ctx, out, partial)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			emit(ctx, out, partial)
+			return nil
+		case <-time.After(poll):
+		}
+
+		current, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		latest, err := os.Stat(path)
+		switch {
+		case err != nil && os.IsNotExist(err):
+			// Rotated, and the new file is not created yet.
+		case err != nil:
+			return err
+		case !os.SameFile(current, latest):
+			// Rotated. Lines may have been written to the old file since it was read last, before it was moved.
+			next, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			rest, err := io.ReadAll(br)
+			if err != nil {
+				next.Close()
+				return err
+			}
+			for _, line := range strings.SplitAfter(partial+string(rest), "\n") {
+				if !emit(ctx, out, line) {
+					next.Close()
+					return nil
+				}
+			}
+			f.Close()
+			f, br, offset, partial = next, bufio.NewReader(next), 0, ""
+		case latest.Size() < offset:
+			// Truncated.
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			emit(ctx, out, partial)
+			br.Reset(f)
+			offset, partial = 0, ""
+		}
+	}
+}
+
+// emit sends the line, without its line break, unless it is empty. It returns false once the context is done.
+func emit(ctx context.Context, out chan<- Record, line string) bool {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return ctx.Err() == nil
+	}
+	select {
+	case out <- Record{Time: now(), Line: line}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -3,6 +3,7 @@ package cors
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
@@ -15,6 +16,7 @@ var allCols = []table.Column{
 	{Name: "ExposeHeaders", JSONPath: "ExposeHeaders"},
 	{Name: "MaxAgeSeconds", JSONPath: "MaxAgeSeconds"},
 	{Name: "ID", JSONPath: "ID"},
+	{Name: "Explanation", JSONPath: "Explanation"},
 }
 
 type corsRuleInfo struct {
@@ -24,6 +26,7 @@ type corsRuleInfo struct {
 	ExposeHeaders  string `json:"ExposeHeaders"`
 	MaxAgeSeconds  string `json:"MaxAgeSeconds"`
 	ID             string `json:"ID"`
+	Explanation    string `json:"Explanation"`
 }
 
 func CorsCmd() *core.Command {
@@ -46,6 +49,7 @@ func CorsCmd() *core.Command {
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(PutCmd())
 	cmd.AddCommand(DeleteCmd())
+	cmd.AddCommand(bucketconfig.ValidateCmd("cors", bucketconfig.CORS))
 
 	return cmd
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -40,6 +41,7 @@ func GetCmd() *core.Command {
 					AllowedMethods: strings.Join(r.GetAllowedMethods(), ", "),
 					AllowedHeaders: strings.Join(r.GetAllowedHeaders(), ", "),
 					ExposeHeaders:  strings.Join(r.GetExposeHeaders(), ", "),
+					Explanation:    bucketconfig.ExplainCORSRule(r),
 				}
 
 				if r.HasMaxAgeSeconds() {
@@ -2,13 +2,11 @@ package cors
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 
-	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -35,37 +33,36 @@ func PutCmd() *core.Command {
 		Aliases:   []string{"p"},
 		ShortDesc: "Create or replace the CORS configuration for a bucket",
 		LongDesc: "Create or replace the CORS configuration for a bucket. " +
-			"The configuration must be provided as a path to a JSON file via --json-properties. " +
-			"Use --json-properties-example to see an example CORS configuration.",
+			"The configuration must be provided as a path to a JSON file via --json-properties, or built from a template with --from-template. " +
+			"It is validated locally before it is sent. " +
+			"Use --json-properties-example to see an example CORS configuration." + bucketconfig.TemplateHelp(bucketconfig.CORS),
 		Example: "ionosctl object-storage bucket cors put --name my-bucket --json-properties cors.json\n" +
+			"ionosctl object-storage bucket cors put --name my-bucket --from-template static-website --template-param origins=https://example.com\n" +
 			"ionosctl object-storage bucket cors put --json-properties-example",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
 				return nil
 			}
-			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, constants.FlagJsonProperties)
+			return bucketconfig.RequiredDocumentFlags(c, constants.FlagName)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
-				fmt.Fprintln(c.Command.Command.OutOrStdout(), corsExample)
+				example, err := bucketconfig.Example(c, bucketconfig.CORS, corsExample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(c.Command.Command.OutOrStdout(), example)
 				return nil
 			}
 
 			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
-			input := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
-
-			data, err := os.ReadFile(input)
+			corsReq, err := bucketconfig.Load(c, bucketconfig.CORS, name)
 			if err != nil {
-				return fmt.Errorf("reading CORS input: %w", err)
-			}
-
-			var corsReq objectstorage.PutBucketCorsRequest
-			if err := json.Unmarshal(data, &corsReq); err != nil {
-				return fmt.Errorf("parsing CORS JSON: %w", err)
+				return err
 			}
 
 			_, err = client.MustObjectStorage().ObjectStorageClient.CORSApi.PutBucketCors(c.Context, name).
-				PutBucketCorsRequest(corsReq).
+				PutBucketCorsRequest(*corsReq).
 				Execute()
 			if err != nil {
 				return err
@@ -80,7 +77,8 @@ func PutCmd() *core.Command {
 	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
 		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
 	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", "Path to a JSON file containing the CORS configuration")
-	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example CORS configuration JSON and exit")
+	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example CORS configuration JSON, or the rendered --from-template, and exit")
+	bucketconfig.AddTemplateFlags(cmd, bucketconfig.CORS)
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
@@ -3,6 +3,7 @@ package encryption
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
@@ -10,10 +11,12 @@ import (
 
 var allCols = []table.Column{
 	{Name: "SSEAlgorithm", JSONPath: "SSEAlgorithm", Default: true},
+	{Name: "Explanation", JSONPath: "Explanation"},
 }
 
 type encryptionRuleInfo struct {
 	SSEAlgorithm string `json:"SSEAlgorithm"`
+	Explanation  string `json:"Explanation"`
 }
 
 func EncryptionCmd() *core.Command {
@@ -37,6 +40,7 @@ func EncryptionCmd() *core.Command {
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(PutCmd())
 	cmd.AddCommand(DeleteCmd())
+	cmd.AddCommand(bucketconfig.ValidateCmd("encryption", bucketconfig.Encryption))
 
 	return cmd
 }
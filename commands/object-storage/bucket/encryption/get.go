@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -37,6 +38,7 @@ func GetCmd() *core.Command {
 					def := r.GetApplyServerSideEncryptionByDefault()
 					rules = append(rules, encryptionRuleInfo{
 						SSEAlgorithm: string(def.GetSSEAlgorithm()),
+						Explanation:  bucketconfig.ExplainEncryptionRule(r),
 					})
 				}
 			}
@@ -2,13 +2,11 @@ package encryption
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 
-	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -33,37 +31,36 @@ func PutCmd() *core.Command {
 		Aliases:   []string{"p"},
 		ShortDesc: "Create or replace the default encryption configuration for a bucket",
 		LongDesc: "Create or replace the default encryption configuration for a bucket. " +
-			"The configuration must be provided as a path to a JSON file via --json-properties. " +
-			"Use --json-properties-example to see an example encryption configuration.",
+			"The configuration must be provided as a path to a JSON file via --json-properties, or built from a template with --from-template. " +
+			"It is validated locally before it is sent. " +
+			"Use --json-properties-example to see an example encryption configuration." + bucketconfig.TemplateHelp(bucketconfig.Encryption),
 		Example: "ionosctl object-storage bucket encryption put --name my-bucket --json-properties encryption.json\n" +
+			"ionosctl object-storage bucket encryption put --name my-bucket --from-template sse-s3\n" +
 			"ionosctl object-storage bucket encryption put --json-properties-example",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
 				return nil
 			}
-			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, constants.FlagJsonProperties)
+			return bucketconfig.RequiredDocumentFlags(c, constants.FlagName)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
-				fmt.Fprintln(c.Command.Command.OutOrStdout(), encryptionExample)
+				example, err := bucketconfig.Example(c, bucketconfig.Encryption, encryptionExample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(c.Command.Command.OutOrStdout(), example)
 				return nil
 			}
 
 			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
-			input := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
-
-			data, err := os.ReadFile(input)
+			encReq, err := bucketconfig.Load(c, bucketconfig.Encryption, name)
 			if err != nil {
-				return fmt.Errorf("reading encryption input: %w", err)
-			}
-
-			var encReq objectstorage.PutBucketEncryptionRequest
-			if err := json.Unmarshal(data, &encReq); err != nil {
-				return fmt.Errorf("parsing encryption JSON: %w", err)
+				return err
 			}
 
 			_, err = client.MustObjectStorage().ObjectStorageClient.EncryptionApi.PutBucketEncryption(c.Context, name).
-				PutBucketEncryptionRequest(encReq).
+				PutBucketEncryptionRequest(*encReq).
 				Execute()
 			if err != nil {
 				return err
@@ -78,7 +75,8 @@ func PutCmd() *core.Command {
 	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
 		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
 	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", "Path to a JSON file containing the encryption configuration")
-	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example encryption configuration JSON and exit")
+	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example encryption configuration JSON, or the rendered --from-template, and exit")
+	bucketconfig.AddTemplateFlags(cmd, bucketconfig.Encryption)
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -34,9 +35,10 @@ func GetCmd() *core.Command {
 			var rules []ruleInfo
 			for _, r := range result.GetRules() {
 				info := ruleInfo{
-					ID:     r.GetID(),
-					Prefix: r.GetPrefix(),
-					Status: string(r.GetStatus()),
+					ID:          r.GetID(),
+					Prefix:      r.GetPrefix(),
+					Status:      string(r.GetStatus()),
+					Explanation: bucketconfig.ExplainLifecycleRule(r),
 				}
 
 				if exp := r.Expiration; exp != nil {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
@@ -19,6 +20,7 @@ var allCols = []table.Column{
 	{Name: "ExpiredObjectDeleteMarker", JSONPath: "ExpiredObjectDeleteMarker"},
 	{Name: "NoncurrentDays", JSONPath: "NoncurrentDays"},
 	{Name: "AbortDays", JSONPath: "AbortDays"},
+	{Name: "Explanation", JSONPath: "Explanation"},
 }
 
 type ruleInfo struct {
@@ -30,6 +32,7 @@ type ruleInfo struct {
 	ExpiredObjectDeleteMarker string `json:"ExpiredObjectDeleteMarker"`
 	NoncurrentDays            string `json:"NoncurrentDays"`
 	AbortDays                 string `json:"AbortDays"`
+	Explanation               string `json:"Explanation"`
 }
 
 func int32PtrToStr(v *int32) string {
@@ -67,6 +70,7 @@ func LifecycleCmd() *core.Command {
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(PutCmd())
 	cmd.AddCommand(DeleteCmd())
+	cmd.AddCommand(bucketconfig.ValidateCmd("lifecycle", bucketconfig.Lifecycle))
 
 	return cmd
 }
@@ -4,14 +4,12 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/base64"
-	"encoding/json"
 	"encoding/xml"
 	"fmt"
-	"os"
 
-	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -47,36 +45,35 @@ func PutCmd() *core.Command {
 		Aliases:   []string{"p"},
 		ShortDesc: "Create or replace the lifecycle configuration for a bucket",
 		LongDesc: "Create or replace the lifecycle configuration for a bucket. " +
-			"The configuration must be provided as a path to a JSON file via --json-properties. " +
-			"Use --json-properties-example to see an example lifecycle configuration.",
+			"The configuration must be provided as a path to a JSON file via --json-properties, or built from a template with --from-template. " +
+			"It is validated locally before it is sent. " +
+			"Use --json-properties-example to see an example lifecycle configuration." + bucketconfig.TemplateHelp(bucketconfig.Lifecycle),
 		Example: "ionosctl object-storage bucket lifecycle put --name my-bucket --json-properties lifecycle.json\n" +
+			"ionosctl object-storage bucket lifecycle put --name my-bucket --from-template expire-after-days --template-param days=30,prefix=logs/\n" +
 			"ionosctl object-storage bucket lifecycle put --json-properties-example",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
 				return nil
 			}
-			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, constants.FlagJsonProperties)
+			return bucketconfig.RequiredDocumentFlags(c, constants.FlagName)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
-				fmt.Fprintln(c.Command.Command.OutOrStdout(), lifecycleExample)
+				example, err := bucketconfig.Example(c, bucketconfig.Lifecycle, lifecycleExample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(c.Command.Command.OutOrStdout(), example)
 				return nil
 			}
 
 			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
-			input := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
-
-			data, err := os.ReadFile(input)
+			req, err := bucketconfig.Load(c, bucketconfig.Lifecycle, name)
 			if err != nil {
-				return fmt.Errorf("reading lifecycle input: %w", err)
-			}
-
-			var req objectstorage.PutBucketLifecycleRequest
-			if err := json.Unmarshal(data, &req); err != nil {
-				return fmt.Errorf("parsing lifecycle JSON: %w", err)
+				return err
 			}
 
-			xmlBytes, err := xml.Marshal(req)
+			xmlBytes, err := xml.Marshal(*req)
 			if err != nil {
 				return fmt.Errorf("serializing lifecycle configuration: %w", err)
 			}
@@ -85,7 +82,7 @@ func PutCmd() *core.Command {
 
 			_, err = client.MustObjectStorage().ObjectStorageClient.LifecycleApi.PutBucketLifecycle(c.Context, name).
 				ContentMD5(contentMD5).
-				PutBucketLifecycleRequest(req).
+				PutBucketLifecycleRequest(*req).
 				Execute()
 			if err != nil {
 				return err
@@ -100,7 +97,8 @@ func PutCmd() *core.Command {
 	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
 		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
 	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", "Path to a JSON file containing the lifecycle configuration")
-	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example lifecycle configuration JSON and exit")
+	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example lifecycle configuration JSON, or the rendered --from-template, and exit")
+	bucketconfig.AddTemplateFlags(cmd, bucketconfig.Lifecycle)
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -15,12 +16,13 @@ import (
 )
 
 type statementInfo struct {
-	Sid       string `json:"Sid"`
-	Effect    string `json:"Effect"`
-	Action    string `json:"Action"`
-	Resource  string `json:"Resource"`
-	Principal string `json:"Principal"`
-	Condition string `json:"Condition"`
+	Sid         string `json:"Sid"`
+	Effect      string `json:"Effect"`
+	Action      string `json:"Action"`
+	Resource    string `json:"Resource"`
+	Principal   string `json:"Principal"`
+	Condition   string `json:"Condition"`
+	Explanation string `json:"Explanation"`
 }
 
 func GetCmd() *core.Command {
@@ -45,9 +47,10 @@ func GetCmd() *core.Command {
 			var statements []statementInfo
 			for _, s := range result.GetStatement() {
 				si := statementInfo{
-					Sid:    s.GetSid(),
-					Effect: s.GetEffect(),
-					Action: strings.Join(s.GetAction(), ", "),
+					Sid:         s.GetSid(),
+					Effect:      s.GetEffect(),
+					Action:      strings.Join(s.GetAction(), ", "),
+					Explanation: bucketconfig.ExplainPolicyStatement(s),
 				}
 
 				si.Resource = strings.Join(s.GetResource(), ", ")
@@ -3,6 +3,7 @@ package policy
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
@@ -15,6 +16,7 @@ var allCols = []table.Column{
 	{Name: "Resource", JSONPath: "Resource", Default: true},
 	{Name: "Principal", JSONPath: "Principal", Default: true},
 	{Name: "Condition", JSONPath: "Condition"},
+	{Name: "Explanation", JSONPath: "Explanation"},
 }
 
 var statusCols = []table.Column{
@@ -43,6 +45,7 @@ func PolicyCmd() *core.Command {
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(PutCmd())
 	cmd.AddCommand(DeleteCmd())
+	cmd.AddCommand(bucketconfig.ValidateCmd("policy", bucketconfig.Policy))
 	cmd.AddCommand(StatusCmd())
 
 	return cmd
@@ -2,13 +2,11 @@ package policy
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 
-	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -41,37 +39,36 @@ func PutCmd() *core.Command {
 		Aliases:   []string{"p"},
 		ShortDesc: "Create or replace the bucket policy",
 		LongDesc: "Create or replace the bucket policy. " +
-			"The policy must be provided as a path to a JSON file via --json-properties. " +
-			"Use --json-properties-example to see an example policy.",
+			"The policy must be provided as a path to a JSON file via --json-properties, or built from a template with --from-template. " +
+			"It is validated locally before it is sent. " +
+			"Use --json-properties-example to see an example policy." + bucketconfig.TemplateHelp(bucketconfig.Policy),
 		Example: "ionosctl object-storage bucket policy put --name my-bucket --json-properties policy.json\n" +
+			"ionosctl object-storage bucket policy put --name my-bucket --from-template public-read\n" +
 			"ionosctl object-storage bucket policy put --json-properties-example",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
 				return nil
 			}
-			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, constants.FlagJsonProperties)
+			return bucketconfig.RequiredDocumentFlags(c, constants.FlagName)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
-				fmt.Fprintln(c.Command.Command.OutOrStdout(), policyExample)
+				example, err := bucketconfig.Example(c, bucketconfig.Policy, policyExample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(c.Command.Command.OutOrStdout(), example)
 				return nil
 			}
 
 			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
-			input := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
-
-			data, err := readInput(input)
+			bp, err := bucketconfig.Load(c, bucketconfig.Policy, name)
 			if err != nil {
-				return fmt.Errorf("reading policy input: %w", err)
-			}
-
-			var bp objectstorage.BucketPolicy
-			if err := json.Unmarshal(data, &bp); err != nil {
-				return fmt.Errorf("parsing policy JSON: %w", err)
+				return err
 			}
 
 			_, err = client.MustObjectStorage().ObjectStorageClient.PolicyApi.PutBucketPolicy(c.Context, name).
-				BucketPolicy(bp).
+				BucketPolicy(*bp).
 				Execute()
 			if err != nil {
 				return err
@@ -86,14 +83,10 @@ func PutCmd() *core.Command {
 	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
 		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
 	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", "Path to a JSON file containing the bucket policy")
-	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example bucket policy JSON and exit")
+	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example bucket policy JSON, or the rendered --from-template, and exit")
+	bucketconfig.AddTemplateFlags(cmd, bucketconfig.Policy)
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
 	return cmd
 }
-
-// readInput reads the policy JSON from a file path.
-func readInput(path string) ([]byte, error) {
-	return os.ReadFile(path)
-}
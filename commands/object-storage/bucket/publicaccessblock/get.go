@@ -3,8 +3,10 @@ package publicaccessblock
 import (
 	"context"
 
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -36,6 +38,12 @@ func GetCmd() *core.Command {
 				IgnorePublicAcls:      derefBool(result.IgnorePublicAcls),
 				BlockPublicPolicy:     derefBool(result.BlockPublicPolicy),
 				RestrictPublicBuckets: derefBool(result.RestrictPublicBuckets),
+				Explanation: bucketconfig.ExplainPublicAccessBlock(objectstorage.BlockPublicAccessPayload{
+					BlockPublicAcls:       result.BlockPublicAcls,
+					IgnorePublicAcls:      result.IgnorePublicAcls,
+					BlockPublicPolicy:     result.BlockPublicPolicy,
+					RestrictPublicBuckets: result.RestrictPublicBuckets,
+				}),
 			}
 
 			cols, _ := c.Command.Command.Flags().GetStringSlice(constants.ArgCols)
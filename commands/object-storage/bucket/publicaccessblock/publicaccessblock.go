@@ -3,6 +3,7 @@ package publicaccessblock
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
@@ -13,13 +14,15 @@ var allCols = []table.Column{
 	{Name: "IgnorePublicAcls", JSONPath: "IgnorePublicAcls", Default: true},
 	{Name: "BlockPublicPolicy", JSONPath: "BlockPublicPolicy", Default: true},
 	{Name: "RestrictPublicBuckets", JSONPath: "RestrictPublicBuckets", Default: true},
+	{Name: "Explanation", JSONPath: "Explanation"},
 }
 
 type publicAccessBlockInfo struct {
-	BlockPublicAcls       bool `json:"BlockPublicAcls"`
-	IgnorePublicAcls      bool `json:"IgnorePublicAcls"`
-	BlockPublicPolicy     bool `json:"BlockPublicPolicy"`
-	RestrictPublicBuckets bool `json:"RestrictPublicBuckets"`
+	BlockPublicAcls       bool   `json:"BlockPublicAcls"`
+	IgnorePublicAcls      bool   `json:"IgnorePublicAcls"`
+	BlockPublicPolicy     bool   `json:"BlockPublicPolicy"`
+	RestrictPublicBuckets bool   `json:"RestrictPublicBuckets"`
+	Explanation           string `json:"Explanation"`
 }
 
 func PublicAccessBlockCmd() *core.Command {
@@ -43,6 +46,7 @@ func PublicAccessBlockCmd() *core.Command {
 	cmd.AddCommand(GetCmd())
 	cmd.AddCommand(PutCmd())
 	cmd.AddCommand(DeleteCmd())
+	cmd.AddCommand(bucketconfig.ValidateCmd("public-access-block", bucketconfig.PublicAccessBlock))
 
 	return cmd
 }
@@ -2,13 +2,11 @@ package publicaccessblock
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os"
 
-	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
 	"github.com/spf13/viper"
 
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/bucketconfig"
 	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
@@ -30,37 +28,36 @@ func PutCmd() *core.Command {
 		Aliases:   []string{"p"},
 		ShortDesc: "Create or replace the public access block configuration for a bucket",
 		LongDesc: "Create or replace the public access block configuration for a bucket. " +
-			"The configuration must be provided as a path to a JSON file via --json-properties. " +
-			"Use --json-properties-example to see an example public access block configuration.",
+			"The configuration must be provided as a path to a JSON file via --json-properties, or built from a template with --from-template. " +
+			"It is validated locally before it is sent. " +
+			"Use --json-properties-example to see an example public access block configuration." + bucketconfig.TemplateHelp(bucketconfig.PublicAccessBlock),
 		Example: "ionosctl object-storage bucket public-access-block put --name my-bucket --json-properties config.json\n" +
+			"ionosctl object-storage bucket public-access-block put --name my-bucket --from-template block-all\n" +
 			"ionosctl object-storage bucket public-access-block put --json-properties-example",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
 				return nil
 			}
-			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, constants.FlagJsonProperties)
+			return bucketconfig.RequiredDocumentFlags(c, constants.FlagName)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			if viper.GetBool(core.GetFlagName(c.NS, constants.FlagJsonPropertiesExample)) {
-				fmt.Fprintln(c.Command.Command.OutOrStdout(), publicAccessBlockExample)
+				example, err := bucketconfig.Example(c, bucketconfig.PublicAccessBlock, publicAccessBlockExample)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(c.Command.Command.OutOrStdout(), example)
 				return nil
 			}
 
 			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
-			input := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
-
-			data, err := os.ReadFile(input)
+			req, err := bucketconfig.Load(c, bucketconfig.PublicAccessBlock, name)
 			if err != nil {
-				return fmt.Errorf("reading public access block input: %w", err)
-			}
-
-			var req objectstorage.BlockPublicAccessPayload
-			if err := json.Unmarshal(data, &req); err != nil {
-				return fmt.Errorf("parsing public access block JSON: %w", err)
+				return err
 			}
 
 			_, err = client.MustObjectStorage().ObjectStorageClient.PublicAccessBlockApi.PutPublicAccessBlock(c.Context, name).
-				BlockPublicAccessPayload(req).
+				BlockPublicAccessPayload(*req).
 				Execute()
 			if err != nil {
 				return err
@@ -75,7 +72,8 @@ func PutCmd() *core.Command {
 	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
 		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
 	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", "Path to a JSON file containing the public access block configuration")
-	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example public access block configuration JSON and exit")
+	cmd.AddBoolFlag(constants.FlagJsonPropertiesExample, "", false, "Print an example public access block configuration JSON, or the rendered --from-template, and exit")
+	bucketconfig.AddTemplateFlags(cmd, bucketconfig.PublicAccessBlock)
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
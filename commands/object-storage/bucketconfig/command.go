@@ -0,0 +1,152 @@
+package bucketconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+)
+
+const (
+	FlagFromTemplate  = "from-template"
+	FlagTemplateParam = "template-param"
+
+	// examplePlaceholderBucket is used when rendering a template as example without --name.
+	examplePlaceholderBucket = "BUCKET_NAME"
+)
+
+var explainCols = []table.Column{
+	{Name: "Rule", JSONPath: "Rule", Default: true},
+	{Name: "Explanation", JSONPath: "Explanation", Default: true},
+}
+
+type explainInfo struct {
+	Rule        int    `json:"Rule"`
+	Explanation string `json:"Explanation"`
+}
+
+// AddTemplateFlags adds --from-template and --template-param for the templates of d.
+func AddTemplateFlags[T any](cmd *core.Command, d Document[T]) {
+	cmd.AddSetFlag(FlagFromTemplate, "", "", d.TemplateNames(),
+		fmt.Sprintf("Use a built-in %s instead of --%s", d.Kind, constants.FlagJsonProperties))
+	cmd.AddStringToStringFlag(FlagTemplateParam, "", map[string]string{},
+		"Template parameters as name=value pairs, e.g. --template-param days=30,prefix=logs/")
+}
+
+// TemplateHelp is appended to the long description of commands accepting --from-template.
+func TemplateHelp[T any](d Document[T]) string {
+	return fmt.Sprintf("\n\nInstead of a file, one of the following templates can be used with --%s. "+
+		"Their parameters are set with --%s name=value:\n%s", FlagFromTemplate, FlagTemplateParam, d.Usage())
+}
+
+// RequiredDocumentFlags checks that the document is given either as file or as template.
+func RequiredDocumentFlags(c *core.PreCommandConfig, other ...string) error {
+	return core.CheckRequiredFlagsSets(c.Command, c.NS,
+		append([]string{constants.FlagJsonProperties}, other...),
+		append([]string{FlagFromTemplate}, other...),
+	)
+}
+
+// Load returns the document given with --from-template or --json-properties, validated
+// for the given bucket. The returned error lists every problem found in the document.
+func Load[T any](c *core.CommandConfig, d Document[T], bucket string) (*T, error) {
+	if name := viper.GetString(core.GetFlagName(c.NS, FlagFromTemplate)); name != "" {
+		return d.Render(name, bucket, viper.GetStringMapString(core.GetFlagName(c.NS, FlagTemplateParam)))
+	}
+
+	path := viper.GetString(core.GetFlagName(c.NS, constants.FlagJsonProperties))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s input: %w", d.Kind, err)
+	}
+	return d.Validate(data, bucket)
+}
+
+// Example returns the rendered --from-template if it is set, otherwise fallback.
+// It backs --json-properties-example, so templates can be inspected before applying them.
+func Example[T any](c *core.CommandConfig, d Document[T], fallback string) (string, error) {
+	name := viper.GetString(core.GetFlagName(c.NS, FlagFromTemplate))
+	if name == "" {
+		return fallback, nil
+	}
+
+	bucket := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
+	if bucket == "" {
+		bucket = examplePlaceholderBucket
+	}
+	doc, err := d.Render(name, bucket, viper.GetStringMapString(core.GetFlagName(c.NS, FlagTemplateParam)))
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// ValidateCmd builds the `validate` sub-command of a bucket configuration resource.
+func ValidateCmd[T any](resource string, d Document[T]) *core.Command {
+	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+		Namespace: "object-storage",
+		Resource:  resource,
+		Verb:      "validate",
+		Aliases:   []string{"v"},
+		ShortDesc: fmt.Sprintf("Validate a %s locally", d.Kind),
+		LongDesc: fmt.Sprintf("Validate a %[1]s without sending it to the API. "+
+			"If the document is valid, its effective rules are explained in plain language, "+
+			"otherwise every problem found is listed and the command fails. "+
+			"Set --name to also check that resources refer to that bucket.", d.Kind) + TemplateHelp(d),
+		Example: fmt.Sprintf("ionosctl object-storage bucket %[1]s validate --json-properties %[1]s.json\n"+
+			"ionosctl object-storage bucket %[1]s validate --name my-bucket --from-template %[2]s", resource, d.Templates[0].Name),
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return RequiredDocumentFlags(c)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			bucket := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
+
+			// Templates which embed the bucket name can be validated with a placeholder.
+			if bucket == "" && viper.GetString(core.GetFlagName(c.NS, FlagFromTemplate)) != "" {
+				bucket = examplePlaceholderBucket
+			}
+
+			doc, err := Load(c, d, bucket)
+			if err != nil {
+				return err
+			}
+
+			var rules []explainInfo
+			for i, line := range d.Explain(doc) {
+				rules = append(rules, explainInfo{Rule: i + 1, Explanation: line})
+			}
+
+			cols, _ := c.Command.Command.Flags().GetStringSlice(constants.ArgCols)
+			return c.Out(table.Sprint(explainCols, rules, cols))
+		},
+		InitClient: false,
+	})
+
+	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket the document is meant for (optional)",
+		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
+	cmd.AddStringFlag(constants.FlagJsonProperties, "", "", fmt.Sprintf("Path to a JSON file containing the %s", d.Kind))
+	AddTemplateFlags(cmd, d)
+
+	cmd.Command.Flags().StringSlice(constants.ArgCols, nil, table.ColsMessage(explainCols))
+	_ = cmd.Command.RegisterFlagCompletionFunc(constants.ArgCols,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return table.AllCols(explainCols), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+	return cmd
+}
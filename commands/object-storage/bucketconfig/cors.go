@@ -0,0 +1,146 @@
+package bucketconfig
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
+)
+
+const maxCORSRules = 100
+
+var corsMethods = []string{http.MethodGet, http.MethodPut, http.MethodHead, http.MethodPost, http.MethodDelete}
+
+// CORS is the document accepted by `bucket cors put`.
+var CORS = Document[objectstorage.PutBucketCorsRequest]{
+	Kind: "CORS configuration",
+	Templates: []Template[objectstorage.PutBucketCorsRequest]{
+		{
+			Name:        "static-website",
+			Description: "Allow browsers to read objects, e.g. for fonts, scripts or media of a static website",
+			Params: []Param{
+				{Name: "origins", Description: "Space separated list of allowed origins", Default: "*"},
+				{Name: "max-age", Description: "Seconds browsers may cache the preflight response", Default: "3600"},
+			},
+			build: func(_ string, p params) (objectstorage.PutBucketCorsRequest, error) {
+				maxAge, err := p.positiveInt("max-age")
+				if err != nil {
+					return objectstorage.PutBucketCorsRequest{}, err
+				}
+				return objectstorage.PutBucketCorsRequest{
+					CORSRules: []objectstorage.CORSRule{{
+						AllowedOrigins: p.list("origins"),
+						AllowedMethods: []string{http.MethodGet, http.MethodHead},
+						AllowedHeaders: []string{"*"},
+						ExposeHeaders:  []string{"ETag", "Content-Length", "Content-Type"},
+						MaxAgeSeconds:  &maxAge,
+					}},
+				}, nil
+			},
+		},
+		{
+			Name:        "browser-upload",
+			Description: "Allow browsers on the given origins to read and upload objects, e.g. with presigned URLs",
+			Params: []Param{
+				{Name: "origins", Description: "Space separated list of allowed origins", Required: true},
+				{Name: "max-age", Description: "Seconds browsers may cache the preflight response", Default: "3600"},
+			},
+			build: func(_ string, p params) (objectstorage.PutBucketCorsRequest, error) {
+				maxAge, err := p.positiveInt("max-age")
+				if err != nil {
+					return objectstorage.PutBucketCorsRequest{}, err
+				}
+				return objectstorage.PutBucketCorsRequest{
+					CORSRules: []objectstorage.CORSRule{{
+						AllowedOrigins: p.list("origins"),
+						AllowedMethods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost},
+						AllowedHeaders: []string{"*"},
+						ExposeHeaders:  []string{"ETag"},
+						MaxAgeSeconds:  &maxAge,
+					}},
+				}, nil
+			},
+		},
+	},
+	check:   checkCORS,
+	explain: explainCORS,
+}
+
+func checkCORS(doc *objectstorage.PutBucketCorsRequest, _ string) []string {
+	var p problems
+	if len(doc.CORSRules) == 0 {
+		p.add("CORSRules", "at least one rule is required")
+	}
+	if len(doc.CORSRules) > maxCORSRules {
+		p.add("CORSRules", "at most %d rules are allowed, got %d", maxCORSRules, len(doc.CORSRules))
+	}
+
+	for i, r := range doc.CORSRules {
+		path := fmt.Sprintf("CORSRules[%d]", i)
+
+		if len(r.AllowedOrigins) == 0 {
+			p.add(path+".AllowedOrigins", "at least one origin is required")
+		}
+		for j, o := range r.AllowedOrigins {
+			if strings.Count(o, "*") > 1 {
+				p.add(fmt.Sprintf("%s.AllowedOrigins[%d]", path, j), "%q may contain at most one * wildcard", o)
+			}
+		}
+
+		if len(r.AllowedMethods) == 0 {
+			p.add(path+".AllowedMethods", "at least one method is required")
+		}
+		for j, m := range r.AllowedMethods {
+			if !slices.Contains(corsMethods, m) {
+				p.add(fmt.Sprintf("%s.AllowedMethods[%d]", path, j), "%q is not supported, must be one of %s", m, strings.Join(corsMethods, ", "))
+			}
+		}
+
+		for j, h := range r.AllowedHeaders {
+			if strings.Count(h, "*") > 1 {
+				p.add(fmt.Sprintf("%s.AllowedHeaders[%d]", path, j), "%q may contain at most one * wildcard", h)
+			}
+		}
+
+		if r.MaxAgeSeconds != nil && *r.MaxAgeSeconds < 0 {
+			p.add(path+".MaxAgeSeconds", "must not be negative")
+		}
+	}
+	return p
+}
+
+func explainCORS(doc *objectstorage.PutBucketCorsRequest) []string {
+	lines := make([]string, len(doc.CORSRules))
+	for i, r := range doc.CORSRules {
+		lines[i] = ExplainCORSRule(r)
+	}
+	return lines
+}
+
+// ExplainCORSRule describes which cross-origin requests a CORS rule allows.
+func ExplainCORSRule(r objectstorage.CORSRule) string {
+	origins := "any origin"
+	if !slices.Contains(r.AllowedOrigins, "*") {
+		origins = strings.Join(r.AllowedOrigins, ", ")
+	}
+
+	headers := "no extra request headers"
+	switch {
+	case slices.Contains(r.AllowedHeaders, "*"):
+		headers = "any request header"
+	case len(r.AllowedHeaders) > 0:
+		headers = "request headers " + strings.Join(r.AllowedHeaders, ", ")
+	}
+
+	s := fmt.Sprintf("Browsers on %s may send %s requests with %s", origins, strings.Join(r.AllowedMethods, ", "), headers)
+	if len(r.ExposeHeaders) > 0 {
+		s += "; scripts can read response headers " + strings.Join(r.ExposeHeaders, ", ")
+	}
+	if r.MaxAgeSeconds != nil {
+		s += "; preflight responses are cached for " + strconv.Itoa(int(*r.MaxAgeSeconds)) + "s"
+	}
+	return s
+}
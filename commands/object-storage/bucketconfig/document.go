@@ -0,0 +1,219 @@
+// Package bucketconfig holds the templates, local validation and human-readable
+// explanations for the JSON documents accepted by the bucket sub-resource commands
+// (lifecycle, cors, policy, encryption and public-access-block).
+package bucketconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Document describes one kind of bucket configuration document, decoded into T.
+type Document[T any] struct {
+	// Kind is the human-readable name of the document, e.g. "lifecycle configuration".
+	Kind      string
+	Templates []Template[T]
+
+	// check reports schema and semantic problems of a decoded document. bucket may be empty.
+	check func(doc *T, bucket string) []string
+	// explain describes the effective rules of a valid document, one line per rule.
+	explain func(doc *T) []string
+}
+
+// Template is a named, parameterized document of a common configuration.
+type Template[T any] struct {
+	Name        string
+	Description string
+	Params      []Param
+	// NeedsBucket is set for templates which embed the bucket name, e.g. in policy resources.
+	NeedsBucket bool
+
+	build func(bucket string, p params) (T, error)
+}
+
+// Param is a value which can be passed to a template with --template-param name=value.
+type Param struct {
+	Name        string
+	Description string
+	// Default is used if the parameter is not given.
+	Default  string
+	Required bool
+}
+
+// ValidationError lists every problem found in a document.
+type ValidationError struct {
+	Kind     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s is invalid:\n  - %s", e.Kind, strings.Join(e.Problems, "\n  - "))
+}
+
+// Parse strictly decodes a JSON document. Unknown fields are rejected, which catches
+// misspelled keys that the API would otherwise silently ignore.
+func (d Document[T]) Parse(data []byte) (*T, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var doc T
+	if err := dec.Decode(&doc); err != nil {
+		return nil, &ValidationError{Kind: d.Kind, Problems: []string{err.Error()}}
+	}
+	if dec.More() {
+		return nil, &ValidationError{Kind: d.Kind, Problems: []string{"unexpected data after the JSON document"}}
+	}
+	return &doc, nil
+}
+
+// Validate parses data and checks it against the rules of the API. bucket may be empty,
+// in which case checks that depend on the bucket name are skipped.
+func (d Document[T]) Validate(data []byte, bucket string) (*T, error) {
+	doc, err := d.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return doc, d.Check(doc, bucket)
+}
+
+// Check validates an already decoded document.
+func (d Document[T]) Check(doc *T, bucket string) error {
+	if problems := d.check(doc, bucket); len(problems) > 0 {
+		return &ValidationError{Kind: d.Kind, Problems: problems}
+	}
+	return nil
+}
+
+// Explain describes the effective rules of doc in plain language.
+func (d Document[T]) Explain(doc *T) []string {
+	return d.explain(doc)
+}
+
+// TemplateNames returns the names of all templates, for flag completion and help texts.
+func (d Document[T]) TemplateNames() []string {
+	names := make([]string, len(d.Templates))
+	for i, t := range d.Templates {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Render builds the named template with the given parameters and validates the result.
+func (d Document[T]) Render(name, bucket string, values map[string]string) (*T, error) {
+	var tmpl *Template[T]
+	for i := range d.Templates {
+		if d.Templates[i].Name == name {
+			tmpl = &d.Templates[i]
+		}
+	}
+	if tmpl == nil {
+		return nil, fmt.Errorf("unknown %s template %q. Can be one of: %s", d.Kind, name, strings.Join(d.TemplateNames(), ", "))
+	}
+	if tmpl.NeedsBucket && bucket == "" {
+		return nil, fmt.Errorf("template %q needs the bucket name, set it with --name", name)
+	}
+
+	p, err := tmpl.resolve(values)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := tmpl.build(bucket, p)
+	if err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	if err := d.Check(&doc, bucket); err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	return &doc, nil
+}
+
+// Usage describes the templates and their parameters, for long help texts.
+func (d Document[T]) Usage() string {
+	var sb strings.Builder
+	for _, t := range d.Templates {
+		fmt.Fprintf(&sb, "  %s: %s\n", t.Name, t.Description)
+		for _, p := range t.Params {
+			def := fmt.Sprintf("default %q", p.Default)
+			if p.Required {
+				def = "required"
+			}
+			fmt.Fprintf(&sb, "      %s: %s (%s)\n", p.Name, p.Description, def)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// params are the resolved template parameters.
+type params map[string]string
+
+func (t Template[T]) resolve(values map[string]string) (params, error) {
+	p := params{}
+	known := map[string]bool{}
+	var missing []string
+	for _, param := range t.Params {
+		known[param.Name] = true
+		if v, ok := values[param.Name]; ok && v != "" {
+			p[param.Name] = v
+			continue
+		}
+		if param.Required {
+			missing = append(missing, param.Name)
+			continue
+		}
+		p[param.Name] = param.Default
+	}
+
+	var unknown []string
+	for k := range values {
+		if !known[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("template %q does not accept parameter(s) %s", t.Name, strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("template %q requires parameter(s) %s, set them with --template-param name=value", t.Name, strings.Join(missing, ", "))
+	}
+	return p, nil
+}
+
+// positiveInt parses a template parameter which must be a positive number, e.g. a number of days.
+func (p params) positiveInt(name string) (int32, error) {
+	v, err := strconv.ParseInt(p[name], 10, 32)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("parameter %s must be a positive integer, got %q", name, p[name])
+	}
+	return int32(v), nil
+}
+
+// list splits a space separated template parameter. Commas can't be used as separator
+// because --template-param itself is a comma separated list of name=value pairs.
+func (p params) list(name string) []string {
+	return strings.Fields(p[name])
+}
+
+// problems collects validation messages with a path prefix, e.g. "Rules[0].Status: ...".
+type problems []string
+
+func (p *problems) add(path, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	*p = append(*p, msg)
+}
+
+func plural(n int32, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,132 @@
+package bucketconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse_RejectsUnknownFields(t *testing.T) {
+	_, err := Lifecycle.Parse([]byte(`{"Rules": [{"ID": "a", "Stauts": "Enabled"}]}`))
+
+	var verr *ValidationError
+	if !assert.True(t, errors.As(err, &verr)) {
+		return
+	}
+	assert.Equal(t, "lifecycle configuration", verr.Kind)
+	assert.Contains(t, verr.Error(), `unknown field "Stauts"`)
+}
+
+func TestParse_RejectsTrailingData(t *testing.T) {
+	_, err := CORS.Parse([]byte(`{"CORSRules": []} {}`))
+	assert.ErrorContains(t, err, "unexpected data after the JSON document")
+}
+
+func TestRender_Params(t *testing.T) {
+	doc, err := Lifecycle.Render("expire-after-days", "", map[string]string{"days": "90", "prefix": "logs/"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	if !assert.Len(t, doc.Rules, 1) {
+		return
+	}
+	assert.Equal(t, "logs/", doc.Rules[0].Prefix)
+	assert.Equal(t, int32(90), *doc.Rules[0].Expiration.Days)
+	assert.Equal(t, "expire-after-90-days", *doc.Rules[0].ID)
+}
+
+func TestRender_Defaults(t *testing.T) {
+	doc, err := Lifecycle.Render("abort-incomplete-multipart", "", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int32(7), *doc.Rules[0].AbortIncompleteMultipartUpload.DaysAfterInitiation)
+	assert.Empty(t, doc.Rules[0].Prefix)
+}
+
+func TestRender_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		bucket   string
+		values   map[string]string
+		want     string
+	}{
+		{"unknown template", "expire-yesterday", "", nil, `unknown lifecycle configuration template "expire-yesterday"`},
+		{"missing param", "expire-after-days", "", nil, "requires parameter(s) days"},
+		{"unknown param", "expire-after-days", "", map[string]string{"days": "1", "dys": "2"}, "does not accept parameter(s) dys"},
+		{"invalid number", "expire-after-days", "", map[string]string{"days": "-3"}, `parameter days must be a positive integer, got "-3"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Lifecycle.Render(tt.template, tt.bucket, tt.values)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err := Policy.Render("public-read", "", nil)
+	assert.ErrorContains(t, err, "needs the bucket name")
+}
+
+func TestTemplates_AreValid(t *testing.T) {
+	required := map[string]string{"days": "30", "origins": "https://example.com", "cidrs": "203.0.113.0/24"}
+
+	render := func(t *testing.T, params []Param, render func(values map[string]string) (any, error)) {
+		values := map[string]string{}
+		for _, p := range params {
+			if p.Required {
+				values[p.Name] = required[p.Name]
+			}
+		}
+		doc, err := render(values)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// Rendered templates are printed by --json-properties-example and must parse again.
+		_, err = json.Marshal(doc)
+		assert.NoError(t, err)
+	}
+
+	for _, tmpl := range Lifecycle.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			render(t, tmpl.Params, func(v map[string]string) (any, error) { return Lifecycle.Render(tmpl.Name, "b", v) })
+		})
+	}
+	for _, tmpl := range CORS.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			render(t, tmpl.Params, func(v map[string]string) (any, error) { return CORS.Render(tmpl.Name, "b", v) })
+		})
+	}
+	for _, tmpl := range Policy.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			render(t, tmpl.Params, func(v map[string]string) (any, error) { return Policy.Render(tmpl.Name, "b", v) })
+		})
+	}
+	for _, tmpl := range Encryption.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			render(t, tmpl.Params, func(v map[string]string) (any, error) { return Encryption.Render(tmpl.Name, "b", v) })
+		})
+	}
+	for _, tmpl := range PublicAccessBlock.Templates {
+		t.Run(tmpl.Name, func(t *testing.T) {
+			render(t, tmpl.Params, func(v map[string]string) (any, error) { return PublicAccessBlock.Render(tmpl.Name, "b", v) })
+		})
+	}
+}
+
+func TestRender_RoundTrip(t *testing.T) {
+	doc, err := Policy.Render("read-only-from-ips", "my-bucket", map[string]string{"cidrs": "203.0.113.0/24 198.51.100.7/32"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	data, err := json.Marshal(doc)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotContains(t, string(data), "XMLName")
+
+	_, err = Policy.Validate(data, "my-bucket")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,59 @@
+package bucketconfig
+
+import (
+	"fmt"
+
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
+)
+
+// Encryption is the document accepted by `bucket encryption put`.
+var Encryption = Document[objectstorage.PutBucketEncryptionRequest]{
+	Kind: "encryption configuration",
+	Templates: []Template[objectstorage.PutBucketEncryptionRequest]{
+		{
+			Name:        "sse-s3",
+			Description: "Encrypt new objects at rest with keys managed by Object Storage (AES256)",
+			build: func(_ string, _ params) (objectstorage.PutBucketEncryptionRequest, error) {
+				return objectstorage.PutBucketEncryptionRequest{
+					Rules: []objectstorage.ServerSideEncryptionRule{{
+						ApplyServerSideEncryptionByDefault: &objectstorage.ServerSideEncryptionByDefault{
+							SSEAlgorithm: objectstorage.SERVERSIDEENCRYPTION_AES256,
+						},
+					}},
+				}, nil
+			},
+		},
+	},
+	check:   checkEncryption,
+	explain: explainEncryption,
+}
+
+func checkEncryption(doc *objectstorage.PutBucketEncryptionRequest, _ string) []string {
+	var p problems
+	if len(doc.Rules) != 1 {
+		p.add("Rules", "exactly one rule is required, got %d", len(doc.Rules))
+	}
+	for i, r := range doc.Rules {
+		if r.ApplyServerSideEncryptionByDefault == nil {
+			p.add(fmt.Sprintf("Rules[%d].ApplyServerSideEncryptionByDefault", i), "is required")
+		}
+	}
+	return p
+}
+
+func explainEncryption(doc *objectstorage.PutBucketEncryptionRequest) []string {
+	lines := make([]string, len(doc.Rules))
+	for i, r := range doc.Rules {
+		lines[i] = ExplainEncryptionRule(r)
+	}
+	return lines
+}
+
+// ExplainEncryptionRule describes how new objects are encrypted by default.
+func ExplainEncryptionRule(r objectstorage.ServerSideEncryptionRule) string {
+	if r.ApplyServerSideEncryptionByDefault == nil {
+		return "New objects are not encrypted by default"
+	}
+	return fmt.Sprintf("New objects uploaded without encryption headers are encrypted at rest with %s",
+		r.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+}
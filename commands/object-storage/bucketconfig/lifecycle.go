@@ -0,0 +1,194 @@
+package bucketconfig
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
+)
+
+const maxLifecycleRules = 1000
+
+// Lifecycle is the document accepted by `bucket lifecycle put`.
+var Lifecycle = Document[objectstorage.PutBucketLifecycleRequest]{
+	Kind: "lifecycle configuration",
+	Templates: []Template[objectstorage.PutBucketLifecycleRequest]{
+		{
+			Name:        "expire-after-days",
+			Description: "Delete objects a number of days after they were created",
+			Params: []Param{
+				{Name: "days", Description: "Days after creation", Required: true},
+				{Name: "prefix", Description: "Only apply to keys starting with this prefix"},
+			},
+			build: func(_ string, p params) (objectstorage.PutBucketLifecycleRequest, error) {
+				days, err := p.positiveInt("days")
+				if err != nil {
+					return objectstorage.PutBucketLifecycleRequest{}, err
+				}
+				return lifecycleRule(fmt.Sprintf("expire-after-%d-days", days), p["prefix"], objectstorage.Rule{
+					Expiration: &objectstorage.LifecycleExpiration{Days: &days},
+				}), nil
+			},
+		},
+		{
+			Name:        "abort-incomplete-multipart",
+			Description: "Abort multipart uploads which were not completed in time, freeing the storage of their parts",
+			Params: []Param{
+				{Name: "days", Description: "Days after the upload was initiated", Default: "7"},
+				{Name: "prefix", Description: "Only apply to keys starting with this prefix"},
+			},
+			build: func(_ string, p params) (objectstorage.PutBucketLifecycleRequest, error) {
+				days, err := p.positiveInt("days")
+				if err != nil {
+					return objectstorage.PutBucketLifecycleRequest{}, err
+				}
+				return lifecycleRule(fmt.Sprintf("abort-incomplete-multipart-after-%d-days", days), p["prefix"], objectstorage.Rule{
+					AbortIncompleteMultipartUpload: &objectstorage.AbortIncompleteMultipartUpload{DaysAfterInitiation: &days},
+				}), nil
+			},
+		},
+		{
+			Name:        "expire-noncurrent-versions",
+			Description: "Permanently delete previous object versions of a versioned bucket",
+			Params: []Param{
+				{Name: "days", Description: "Days after a version became noncurrent", Default: "30"},
+				{Name: "prefix", Description: "Only apply to keys starting with this prefix"},
+			},
+			build: func(_ string, p params) (objectstorage.PutBucketLifecycleRequest, error) {
+				days, err := p.positiveInt("days")
+				if err != nil {
+					return objectstorage.PutBucketLifecycleRequest{}, err
+				}
+				return lifecycleRule(fmt.Sprintf("expire-noncurrent-after-%d-days", days), p["prefix"], objectstorage.Rule{
+					NoncurrentVersionExpiration: &objectstorage.NoncurrentVersionExpiration{NoncurrentDays: &days},
+				}), nil
+			},
+		},
+	},
+	check:   checkLifecycle,
+	explain: explainLifecycle,
+}
+
+func lifecycleRule(id, prefix string, rule objectstorage.Rule) objectstorage.PutBucketLifecycleRequest {
+	rule.ID = &id
+	rule.Prefix = prefix
+	rule.Status = objectstorage.EXPIRATIONSTATUS_ENABLED
+	return objectstorage.PutBucketLifecycleRequest{Rules: []objectstorage.Rule{rule}}
+}
+
+func checkLifecycle(doc *objectstorage.PutBucketLifecycleRequest, _ string) []string {
+	var p problems
+	if len(doc.Rules) == 0 {
+		p.add("Rules", "at least one rule is required")
+	}
+	if len(doc.Rules) > maxLifecycleRules {
+		p.add("Rules", "at most %d rules are allowed, got %d", maxLifecycleRules, len(doc.Rules))
+	}
+
+	ids := map[string]int{}
+	for i, r := range doc.Rules {
+		path := fmt.Sprintf("Rules[%d]", i)
+
+		if r.ID != nil {
+			if len(*r.ID) > 255 {
+				p.add(path+".ID", "must not be longer than 255 characters")
+			}
+			if first, ok := ids[*r.ID]; ok {
+				p.add(path+".ID", "%q is already used by Rules[%d]", *r.ID, first)
+			} else {
+				ids[*r.ID] = i
+			}
+		}
+
+		if r.Status == "" {
+			p.add(path+".Status", "is required, must be Enabled or Disabled")
+		}
+		if r.Filter != nil && r.Prefix != "" {
+			p.add(path, "set either Prefix or Filter.Prefix, not both")
+		}
+		if r.Expiration == nil && r.NoncurrentVersionExpiration == nil && r.AbortIncompleteMultipartUpload == nil {
+			p.add(path, "at least one of Expiration, NoncurrentVersionExpiration or AbortIncompleteMultipartUpload is required")
+		}
+
+		if exp := r.Expiration; exp != nil {
+			set := 0
+			if exp.Days != nil {
+				set++
+				if *exp.Days <= 0 {
+					p.add(path+".Expiration.Days", "must be a positive integer")
+				}
+			}
+			if exp.Date != nil {
+				set++
+				if d, err := time.Parse(time.RFC3339, *exp.Date); err != nil {
+					p.add(path+".Expiration.Date", "must be an ISO 8601 date such as 2026-01-01T00:00:00Z")
+				} else if d.UTC().Truncate(24*time.Hour) != d.UTC() {
+					p.add(path+".Expiration.Date", "must be at midnight UTC")
+				}
+			}
+			if exp.ExpiredObjectDeleteMarker != nil {
+				set++
+			}
+			if set != 1 {
+				p.add(path+".Expiration", "exactly one of Days, Date or ExpiredObjectDeleteMarker must be set")
+			}
+		}
+
+		if nve := r.NoncurrentVersionExpiration; nve != nil && (nve.NoncurrentDays == nil || *nve.NoncurrentDays <= 0) {
+			p.add(path+".NoncurrentVersionExpiration.NoncurrentDays", "is required and must be a positive integer")
+		}
+		if abort := r.AbortIncompleteMultipartUpload; abort != nil && (abort.DaysAfterInitiation == nil || *abort.DaysAfterInitiation <= 0) {
+			p.add(path+".AbortIncompleteMultipartUpload.DaysAfterInitiation", "is required and must be a positive integer")
+		}
+	}
+	return p
+}
+
+func explainLifecycle(doc *objectstorage.PutBucketLifecycleRequest) []string {
+	lines := make([]string, len(doc.Rules))
+	for i, r := range doc.Rules {
+		lines[i] = ExplainLifecycleRule(r)
+	}
+	return lines
+}
+
+// ExplainLifecycleRule describes what a lifecycle rule does, e.g.
+// "Objects under "logs/": delete 90 days after creation".
+func ExplainLifecycleRule(r objectstorage.Rule) string {
+	prefix := r.Prefix
+	if r.Filter != nil && r.Filter.Prefix != "" {
+		prefix = r.Filter.Prefix
+	}
+	scope := "All objects"
+	if prefix != "" {
+		scope = fmt.Sprintf("Objects under %q", prefix)
+	}
+
+	if r.Status == objectstorage.EXPIRATIONSTATUS_DISABLED {
+		return fmt.Sprintf("%s: nothing, the rule is disabled", scope)
+	}
+
+	var actions []string
+	if exp := r.Expiration; exp != nil {
+		switch {
+		case exp.Days != nil:
+			actions = append(actions, fmt.Sprintf("delete %s after creation", plural(*exp.Days, "day")))
+		case exp.Date != nil:
+			actions = append(actions, fmt.Sprintf("delete on %s", *exp.Date))
+		case exp.ExpiredObjectDeleteMarker != nil && *exp.ExpiredObjectDeleteMarker:
+			actions = append(actions, "remove delete markers which have no remaining versions")
+		}
+	}
+	if nve := r.NoncurrentVersionExpiration; nve != nil && nve.NoncurrentDays != nil {
+		actions = append(actions, fmt.Sprintf("permanently delete previous versions %s after they become noncurrent", plural(*nve.NoncurrentDays, "day")))
+	}
+	if abort := r.AbortIncompleteMultipartUpload; abort != nil && abort.DaysAfterInitiation != nil {
+		actions = append(actions, fmt.Sprintf("abort multipart uploads not completed within %s", plural(*abort.DaysAfterInitiation, "day")))
+	}
+	if len(actions) == 0 {
+		return fmt.Sprintf("%s: nothing, the rule has no actions", scope)
+	}
+
+	return fmt.Sprintf("%s: %s", scope, strings.Join(actions, "; "))
+}
@@ -0,0 +1,226 @@
+package bucketconfig
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
+)
+
+// The "s3:" action prefix and "arn:aws:s3:::" resource format are required by the
+// S3-compatible API, not references to AWS services.
+const (
+	policyActionPrefix   = "s3:"
+	policyResourcePrefix = "arn:aws:s3:::"
+)
+
+var policyVersions = []string{"2012-10-17", "2008-10-17"}
+
+// Policy is the document accepted by `bucket policy put`.
+var Policy = Document[objectstorage.BucketPolicy]{
+	Kind: "bucket policy",
+	Templates: []Template[objectstorage.BucketPolicy]{
+		{
+			Name:        "public-read",
+			Description: "Allow anyone to download objects, without listing the bucket",
+			NeedsBucket: true,
+			Params: []Param{
+				{Name: "prefix", Description: "Only allow reading keys starting with this prefix"},
+			},
+			build: func(bucket string, p params) (objectstorage.BucketPolicy, error) {
+				return policyDocument(objectstorage.BucketPolicyStatement{
+					Sid:       strPtr("PublicRead"),
+					Effect:    "Allow",
+					Principal: &objectstorage.Principal{AWS: []string{"*"}},
+					Action:    []string{"s3:GetObject"},
+					Resource:  []string{policyResourcePrefix + bucket + "/" + p["prefix"] + "*"},
+				}), nil
+			},
+		},
+		{
+			Name:        "read-only-from-ips",
+			Description: "Allow clients from the given networks to list the bucket and download objects",
+			NeedsBucket: true,
+			Params: []Param{
+				{Name: "cidrs", Description: "Space separated list of allowed IP ranges, e.g. 203.0.113.0/24", Required: true},
+			},
+			build: func(bucket string, p params) (objectstorage.BucketPolicy, error) {
+				return policyDocument(objectstorage.BucketPolicyStatement{
+					Sid:       strPtr("ReadOnlyFromIPs"),
+					Effect:    "Allow",
+					Principal: &objectstorage.Principal{AWS: []string{"*"}},
+					Action:    []string{"s3:GetObject", "s3:ListBucket"},
+					Resource:  []string{policyResourcePrefix + bucket, policyResourcePrefix + bucket + "/*"},
+					Condition: &objectstorage.BucketPolicyCondition{
+						IpAddress: &objectstorage.BucketPolicyConditionIpAddress{AwsSourceIp: p.list("cidrs")},
+					},
+				}), nil
+			},
+		},
+	},
+	check:   checkPolicy,
+	explain: explainPolicy,
+}
+
+func policyDocument(statements ...objectstorage.BucketPolicyStatement) objectstorage.BucketPolicy {
+	return objectstorage.BucketPolicy{
+		Version:   strPtr(policyVersions[0]),
+		Statement: statements,
+	}
+}
+
+func checkPolicy(doc *objectstorage.BucketPolicy, bucket string) []string {
+	var p problems
+	if doc.Version != nil && !slices.Contains(policyVersions, *doc.Version) {
+		p.add("Version", "%q is not supported, must be one of %s", *doc.Version, strings.Join(policyVersions, ", "))
+	}
+	if len(doc.Statement) == 0 {
+		p.add("Statement", "at least one statement is required")
+	}
+
+	sids := map[string]int{}
+	for i, s := range doc.Statement {
+		path := fmt.Sprintf("Statement[%d]", i)
+
+		if s.Sid != nil && *s.Sid != "" {
+			if first, ok := sids[*s.Sid]; ok {
+				p.add(path+".Sid", "%q is already used by Statement[%d]", *s.Sid, first)
+			} else {
+				sids[*s.Sid] = i
+			}
+		}
+
+		if s.Effect != "Allow" && s.Effect != "Deny" {
+			p.add(path+".Effect", "must be Allow or Deny, got %q", s.Effect)
+		}
+
+		if s.Principal == nil || len(s.Principal.AWS) == 0 {
+			p.add(path+".Principal", "is required, use {\"AWS\": [\"*\"]} for anyone")
+		}
+
+		if len(s.Action) == 0 {
+			p.add(path+".Action", "at least one action is required")
+		}
+		for j, a := range s.Action {
+			if !strings.HasPrefix(a, policyActionPrefix) {
+				p.add(fmt.Sprintf("%s.Action[%d]", path, j), "%q must start with %q", a, policyActionPrefix)
+			}
+		}
+
+		if len(s.Resource) == 0 {
+			p.add(path+".Resource", "at least one resource is required")
+		}
+		for j, r := range s.Resource {
+			rpath := fmt.Sprintf("%s.Resource[%d]", path, j)
+			if !strings.HasPrefix(r, policyResourcePrefix) {
+				p.add(rpath, "%q must start with %q", r, policyResourcePrefix)
+				continue
+			}
+			if b, _, _ := strings.Cut(strings.TrimPrefix(r, policyResourcePrefix), "/"); bucket != "" && b != bucket {
+				p.add(rpath, "%q refers to bucket %q, but the policy is for bucket %q", r, b, bucket)
+			}
+		}
+
+		objectActions, bucketActions := classifyActions(s.Action)
+		objectResources, bucketResources := classifyResources(s.Resource)
+		if len(objectActions) > 0 && len(s.Resource) > 0 && !objectResources {
+			p.add(path, "%s apply to objects, but no resource matches objects. Use %s<bucket>/*",
+				strings.Join(objectActions, ", "), policyResourcePrefix)
+		}
+		if len(bucketActions) > 0 && len(s.Resource) > 0 && !bucketResources {
+			p.add(path, "%s apply to the bucket, but no resource matches the bucket itself. Use %s<bucket>",
+				strings.Join(bucketActions, ", "), policyResourcePrefix)
+		}
+	}
+	return p
+}
+
+// classifyActions splits actions into object-level and bucket-level ones. Wildcards are not classified.
+func classifyActions(actions []string) (object, bucket []string) {
+	for _, a := range actions {
+		name := strings.TrimPrefix(a, policyActionPrefix)
+		switch {
+		case strings.Contains(name, "*"):
+		case strings.Contains(name, "Bucket") || name == "ListAllMyBuckets":
+			bucket = append(bucket, a)
+		case strings.Contains(name, "Object") || strings.Contains(name, "Multipart"):
+			object = append(object, a)
+		}
+	}
+	return object, bucket
+}
+
+// classifyResources reports whether any resource matches objects and whether any matches a bucket.
+func classifyResources(resources []string) (object, bucket bool) {
+	for _, r := range resources {
+		if strings.Contains(strings.TrimPrefix(r, policyResourcePrefix), "/") {
+			object = true
+		} else {
+			bucket = true
+		}
+	}
+	return object, bucket
+}
+
+func explainPolicy(doc *objectstorage.BucketPolicy) []string {
+	lines := make([]string, len(doc.Statement))
+	for i, s := range doc.Statement {
+		lines[i] = ExplainPolicyStatement(s)
+	}
+	return lines
+}
+
+// ExplainPolicyStatement describes who a policy statement allows or denies to do what, e.g.
+// "Allow anyone to s3:GetObject on my-bucket/*".
+func ExplainPolicyStatement(s objectstorage.BucketPolicyStatement) string {
+	who := "nobody"
+	if s.Principal != nil && len(s.Principal.AWS) > 0 {
+		who = strings.Join(s.Principal.AWS, ", ")
+		if slices.Contains(s.Principal.AWS, "*") {
+			who = "anyone"
+		}
+	}
+
+	resources := make([]string, len(s.Resource))
+	for i, r := range s.Resource {
+		resources[i] = strings.TrimPrefix(r, policyResourcePrefix)
+	}
+
+	line := fmt.Sprintf("%s %s to %s on %s", s.Effect, who, strings.Join(s.Action, ", "), strings.Join(resources, ", "))
+
+	if c := s.Condition; c != nil {
+		var conds []string
+		if c.IpAddress != nil && len(c.IpAddress.AwsSourceIp) > 0 {
+			conds = append(conds, "the client IP is in "+strings.Join(c.IpAddress.AwsSourceIp, ", "))
+		}
+		if c.NotIpAddress != nil && len(c.NotIpAddress.AwsSourceIp) > 0 {
+			conds = append(conds, "the client IP is not in "+strings.Join(c.NotIpAddress.AwsSourceIp, ", "))
+		}
+		if d := conditionTime(c.DateGreaterThan); d != "" {
+			conds = append(conds, "after "+d)
+		}
+		if d := conditionTime(c.DateLessThan); d != "" {
+			conds = append(conds, "before "+d)
+		}
+		if len(conds) > 0 {
+			line += ", only if " + strings.Join(conds, " and ")
+		}
+	}
+	return line
+}
+
+func conditionTime(d *objectstorage.BucketPolicyConditionDate) string {
+	switch {
+	case d == nil:
+		return ""
+	case d.AwsCurrentTime != nil:
+		return d.AwsCurrentTime.Time.UTC().Format(time.RFC3339)
+	case d.AwsEpochTime != nil:
+		return time.Unix(int64(*d.AwsEpochTime), 0).UTC().Format(time.RFC3339)
+	}
+	return ""
+}
+
+func strPtr(s string) *string { return &s }
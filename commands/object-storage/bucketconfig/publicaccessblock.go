@@ -0,0 +1,76 @@
+package bucketconfig
+
+import (
+	"strings"
+
+	objectstorage "github.com/ionos-cloud/sdk-go-bundle/products/objectstorage/v2"
+)
+
+// PublicAccessBlock is the document accepted by `bucket public-access-block put`.
+var PublicAccessBlock = Document[objectstorage.BlockPublicAccessPayload]{
+	Kind: "public access block configuration",
+	Templates: []Template[objectstorage.BlockPublicAccessPayload]{
+		{
+			Name:        "block-all",
+			Description: "Block every form of public access through ACLs and bucket policies",
+			build: func(_ string, _ params) (objectstorage.BlockPublicAccessPayload, error) {
+				return publicAccessBlock(true, true, true, true), nil
+			},
+		},
+		{
+			Name:        "block-new",
+			Description: "Reject new public ACLs and policies, but keep existing public access working",
+			build: func(_ string, _ params) (objectstorage.BlockPublicAccessPayload, error) {
+				return publicAccessBlock(true, false, true, false), nil
+			},
+		},
+	},
+	check:   checkPublicAccessBlock,
+	explain: explainPublicAccessBlock,
+}
+
+func publicAccessBlock(blockAcls, ignoreAcls, blockPolicy, restrictBuckets bool) objectstorage.BlockPublicAccessPayload {
+	return objectstorage.BlockPublicAccessPayload{
+		BlockPublicAcls:       &blockAcls,
+		IgnorePublicAcls:      &ignoreAcls,
+		BlockPublicPolicy:     &blockPolicy,
+		RestrictPublicBuckets: &restrictBuckets,
+	}
+}
+
+func checkPublicAccessBlock(doc *objectstorage.BlockPublicAccessPayload, _ string) []string {
+	var p problems
+	if doc.BlockPublicAcls == nil && doc.IgnorePublicAcls == nil && doc.BlockPublicPolicy == nil && doc.RestrictPublicBuckets == nil {
+		p.add("", "at least one of BlockPublicAcls, IgnorePublicAcls, BlockPublicPolicy or RestrictPublicBuckets must be set")
+	}
+	return p
+}
+
+func explainPublicAccessBlock(doc *objectstorage.BlockPublicAccessPayload) []string {
+	return []string{ExplainPublicAccessBlock(*doc)}
+}
+
+// ExplainPublicAccessBlock describes which kinds of public access are blocked.
+func ExplainPublicAccessBlock(doc objectstorage.BlockPublicAccessPayload) string {
+	var blocked []string
+	if isTrue(doc.BlockPublicAcls) {
+		blocked = append(blocked, "new public ACLs are rejected")
+	}
+	if isTrue(doc.IgnorePublicAcls) {
+		blocked = append(blocked, "existing public ACLs are ignored")
+	}
+	if isTrue(doc.BlockPublicPolicy) {
+		blocked = append(blocked, "public bucket policies are rejected")
+	}
+	if isTrue(doc.RestrictPublicBuckets) {
+		blocked = append(blocked, "access granted by an existing public policy is restricted to the bucket owner")
+	}
+	if len(blocked) == 0 {
+		return "Public access is not blocked"
+	}
+	return strings.ToUpper(blocked[0][:1]) + strings.Join(blocked, "; ")[1:]
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
@@ -0,0 +1,96 @@
+package bucketconfig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func problemsOf(t *testing.T, err error) []string {
+	t.Helper()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	return verr.Problems
+}
+
+func TestValidateLifecycle(t *testing.T) {
+	_, err := Lifecycle.Validate([]byte(`{"Rules": [
+		{"ID": "a", "Status": "Enabled", "Prefix": "logs/", "Expiration": {"Days": 30}},
+		{"ID": "a", "Prefix": "tmp/", "Filter": {"Prefix": "tmp/"}, "Expiration": {"Days": 1, "Date": "2026-01-01T12:00:00Z"}},
+		{"ID": "c", "Status": "Enabled"}
+	]}`), "")
+
+	assert.ElementsMatch(t, []string{
+		`Rules[1].ID: "a" is already used by Rules[0]`,
+		"Rules[1].Status: is required, must be Enabled or Disabled",
+		"Rules[1]: set either Prefix or Filter.Prefix, not both",
+		"Rules[1].Expiration.Date: must be at midnight UTC",
+		"Rules[1].Expiration: exactly one of Days, Date or ExpiredObjectDeleteMarker must be set",
+		"Rules[2]: at least one of Expiration, NoncurrentVersionExpiration or AbortIncompleteMultipartUpload is required",
+	}, problemsOf(t, err))
+}
+
+func TestValidateCORS(t *testing.T) {
+	_, err := CORS.Validate([]byte(`{"CORSRules": [
+		{"AllowedOrigins": ["https://*.*.example.com"], "AllowedMethods": ["GET", "PATCH"], "MaxAgeSeconds": -1}
+	]}`), "")
+
+	assert.ElementsMatch(t, []string{
+		`CORSRules[0].AllowedOrigins[0]: "https://*.*.example.com" may contain at most one * wildcard`,
+		`CORSRules[0].AllowedMethods[1]: "PATCH" is not supported, must be one of GET, PUT, HEAD, POST, DELETE`,
+		"CORSRules[0].MaxAgeSeconds: must not be negative",
+	}, problemsOf(t, err))
+}
+
+func TestValidatePolicy(t *testing.T) {
+	_, err := Policy.Validate([]byte(`{"Version": "2012-10-17", "Statement": [
+		{"Sid": "a", "Effect": "Allow", "Principal": {"AWS": ["*"]}, "Action": ["s3:GetObject"], "Resource": ["arn:aws:s3:::my-bucket"]},
+		{"Sid": "a", "Effect": "allow", "Action": ["GetObject"], "Resource": ["arn:aws:s3:::other-bucket/*"]}
+	]}`), "my-bucket")
+
+	assert.ElementsMatch(t, []string{
+		"Statement[0]: s3:GetObject apply to objects, but no resource matches objects. Use arn:aws:s3:::<bucket>/*",
+		`Statement[1].Sid: "a" is already used by Statement[0]`,
+		`Statement[1].Effect: must be Allow or Deny, got "allow"`,
+		`Statement[1].Principal: is required, use {"AWS": ["*"]} for anyone`,
+		`Statement[1].Action[0]: "GetObject" must start with "s3:"`,
+		`Statement[1].Resource[0]: "arn:aws:s3:::other-bucket/*" refers to bucket "other-bucket", but the policy is for bucket "my-bucket"`,
+	}, problemsOf(t, err))
+
+	// Without a bucket name, resources of any bucket are accepted.
+	_, err = Policy.Validate([]byte(`{"Statement": [
+		{"Effect": "Deny", "Principal": {"AWS": ["*"]}, "Action": ["s3:*"], "Resource": ["arn:aws:s3:::other-bucket/*"]}
+	]}`), "")
+	assert.NoError(t, err)
+}
+
+func TestValidateEncryption(t *testing.T) {
+	_, err := Encryption.Validate([]byte(`{"Rules": []}`), "")
+	assert.Equal(t, []string{"Rules: exactly one rule is required, got 0"}, problemsOf(t, err))
+}
+
+func TestExplain(t *testing.T) {
+	lifecycle, err := Lifecycle.Render("expire-after-days", "", map[string]string{"days": "1", "prefix": "tmp/"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{`Objects under "tmp/": delete 1 day after creation`}, Lifecycle.Explain(lifecycle))
+
+	cors, err := CORS.Render("static-website", "", nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"Browsers on any origin may send GET, HEAD requests with any request header; " +
+		"scripts can read response headers ETag, Content-Length, Content-Type; preflight responses are cached for 3600s"},
+		CORS.Explain(cors))
+
+	policy, err := Policy.Render("read-only-from-ips", "my-bucket", map[string]string{"cidrs": "203.0.113.0/24"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"Allow anyone to s3:GetObject, s3:ListBucket on my-bucket, my-bucket/*, " +
+		"only if the client IP is in 203.0.113.0/24"}, Policy.Explain(policy))
+}
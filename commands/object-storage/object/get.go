@@ -22,7 +22,8 @@ func GetCmd() *core.Command {
 		Verb:      "get",
 		Aliases:   []string{"g"},
 		ShortDesc: "Download an object to a file",
-		Example:   "ionosctl object-storage object get --name my-bucket --key photos/image.jpg\nionosctl object-storage object get --name my-bucket --key photos/image.jpg --destination ./local-image.jpg",
+		LongDesc:  "Download an object to a local file. Use --destination - to stream the object to stdout without creating a temporary file.",
+		Example:   "ionosctl object-storage object get --name my-bucket --key photos/image.jpg\nionosctl object-storage object get --name my-bucket --key photos/image.jpg --destination ./local-image.jpg\nionosctl object-storage object get --name my-bucket --key backups/site.tar --destination - | tar x",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, flagKey)
 		},
@@ -32,6 +33,20 @@ func GetCmd() *core.Command {
 			destination := viper.GetString(core.GetFlagName(c.NS, flagDestination))
 			versionId := viper.GetString(core.GetFlagName(c.NS, flagVersionId))
 
+			if destination == streamPath {
+				p, err := newPresignRequest(name, key)
+				if err != nil {
+					return err
+				}
+				stream := newObjectStream(p, client.MustObjectStorage().ObjectStorageClient.GetConfig().HTTPClient)
+				n, err := stream.Download(c.Context, c.Command.Command.OutOrStdout(), versionId)
+				if err != nil {
+					return err
+				}
+				c.Verbose("Streamed %d bytes of object %q to stdout", n, key)
+				return nil
+			}
+
 			if destination == "" {
 				destination = filepath.Base(key)
 			}
@@ -72,7 +87,7 @@ func GetCmd() *core.Command {
 		core.WithCompletion(func() []string {
 			return completer.ObjectKeys(viper.GetString(core.GetFlagName(cmd.NS, constants.FlagName)))
 		}, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
-	cmd.AddStringFlag(flagDestination, "d", "", "Local file path for download (defaults to the basename of the key)")
+	cmd.AddStringFlag(flagDestination, "d", "", "Local file path for download (defaults to the basename of the key). Use - to write to stdout")
 	cmd.AddStringFlag(flagVersionId, "", "", "Version ID of the object to download")
 
 	cmd.Command.SilenceUsage = true
@@ -59,6 +59,7 @@ func ObjectCommand() *core.Command {
 	cmd.AddCommand(DeleteCmd())
 	cmd.AddCommand(HeadCmd())
 	cmd.AddCommand(CopyCmd())
+	cmd.AddCommand(PresignCmd())
 	cmd.AddCommand(retention.Root())
 	cmd.AddCommand(legalhold.Root())
 	cmd.AddCommand(objecttagging.ObjectTaggingCmd())
@@ -0,0 +1,196 @@
+package object
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	awsv4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/aws/aws-sdk-go/private/protocol/rest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/object-storage/completer"
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/ionos-cloud/ionosctl/v6/internal/printer/table"
+)
+
+const (
+	flagExpires = "expires"
+	flagMethod  = "method"
+
+	// maxPresignExpiry is the longest validity accepted for SigV4 presigned URLs.
+	maxPresignExpiry = 7 * 24 * time.Hour
+)
+
+var presignCols = []table.Column{
+	{Name: "URL", JSONPath: "URL", Default: true},
+	{Name: "Method", JSONPath: "Method", Default: true},
+	{Name: "Expires", JSONPath: "Expires", Default: true},
+}
+
+type presignInfo struct {
+	URL     string `json:"URL"`
+	Method  string `json:"Method"`
+	Expires string `json:"Expires"`
+}
+
+func PresignCmd() *core.Command {
+	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+		Namespace: "object-storage",
+		Resource:  "object",
+		Verb:      "presign",
+		Aliases:   []string{"ps"},
+		ShortDesc: "Generate a presigned URL for an object",
+		LongDesc: "Generate a temporary URL which grants access to a single object without changing the bucket policy. " +
+			"Use --method GET to share a download link, or --method PUT to allow uploading to the given key. " +
+			"The URL is signed locally with your Object Storage credentials and is valid for at most 7 days.\n\n" +
+			"Anyone holding the URL can perform the request until it expires.",
+		Example: "ionosctl object-storage object presign --name my-bucket --key reports/2026.pdf --expires 1h\n" +
+			"ionosctl object-storage object presign --name my-bucket --key uploads/build.tar.gz --method PUT --expires 30m --cols URL --no-headers\n" +
+			"curl --upload-file build.tar.gz \"$(ionosctl object-storage object presign --name my-bucket --key uploads/build.tar.gz --method PUT --cols URL --no-headers)\"",
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			if err := core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, flagKey); err != nil {
+				return err
+			}
+
+			expires := viper.GetDuration(core.GetFlagName(c.NS, flagExpires))
+			if expires <= 0 || expires > maxPresignExpiry {
+				return fmt.Errorf("--%s must be greater than 0 and at most %s, got %s", flagExpires, maxPresignExpiry, expires)
+			}
+
+			if viper.GetString(core.GetFlagName(c.NS, flagVersionId)) != "" &&
+				viper.GetString(core.GetFlagName(c.NS, flagMethod)) != http.MethodGet {
+				return fmt.Errorf("--%s can only be used with --%s %s", flagVersionId, flagMethod, http.MethodGet)
+			}
+			return nil
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			name := viper.GetString(core.GetFlagName(c.NS, constants.FlagName))
+			key := viper.GetString(core.GetFlagName(c.NS, flagKey))
+			method := viper.GetString(core.GetFlagName(c.NS, flagMethod))
+			versionId := viper.GetString(core.GetFlagName(c.NS, flagVersionId))
+			expires := viper.GetDuration(core.GetFlagName(c.NS, flagExpires))
+
+			p, err := newPresignRequest(name, key)
+			if err != nil {
+				return err
+			}
+			p.Method = method
+			p.Expires = expires
+			if versionId != "" {
+				p.Query = url.Values{"versionId": []string{versionId}}
+			}
+
+			now := time.Now().UTC()
+			signed, err := presignObjectURL(p, now)
+			if err != nil {
+				return err
+			}
+
+			info := presignInfo{
+				URL:     signed,
+				Method:  method,
+				Expires: now.Add(expires).Format(time.RFC3339),
+			}
+
+			cols, _ := c.Command.Command.Flags().GetStringSlice(constants.ArgCols)
+			return c.Out(table.Sprint(presignCols, info, cols))
+		},
+		InitClient: false,
+	})
+
+	cmd.AddStringFlag(constants.FlagName, constants.FlagNameShort, "", "Name of the bucket", core.RequiredFlagOption(),
+		core.WithCompletion(completer.BucketNames, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
+	cmd.AddStringFlag(flagKey, flagKeyShort, "", "Object key to generate the URL for", core.RequiredFlagOption(),
+		core.WithCompletion(func() []string {
+			return completer.ObjectKeys(viper.GetString(core.GetFlagName(cmd.NS, constants.FlagName)))
+		}, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
+	cmd.AddDurationFlag(flagExpires, "", time.Hour, "How long the URL stays valid (e.g. 15m, 1h, 24h). Maximum 168h")
+	cmd.AddSetFlag(flagMethod, "", http.MethodGet, []string{http.MethodGet, http.MethodPut}, "HTTP method the URL is signed for")
+	cmd.AddStringFlag(flagVersionId, "", "", "Version ID of the object (GET only)")
+
+	cmd.Command.Flags().StringSlice(constants.ArgCols, nil, table.ColsMessage(presignCols))
+	_ = cmd.Command.RegisterFlagCompletionFunc(constants.ArgCols,
+		func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return table.AllCols(presignCols), cobra.ShellCompDirectiveNoFileComp
+		})
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+	return cmd
+}
+
+type presignRequest struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Method    string
+	Bucket    string
+	Key       string
+	Query     url.Values
+	Expires   time.Duration
+}
+
+// newPresignRequest fills in the endpoint, region and credentials of the current
+// Object Storage client for the given object.
+func newPresignRequest(bucket, key string) (presignRequest, error) {
+	accessKey, secretKey, _, _, err := client.ResolveObjectStorageCredentials()
+	if err != nil {
+		return presignRequest{}, err
+	}
+
+	cl := client.MustObjectStorage()
+	region := cl.Region
+	if region == "" {
+		region = constants.ObjectStorageLocations[0]
+	}
+
+	return presignRequest{
+		Endpoint:  cl.URLOverride,
+		Region:    region,
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Bucket:    bucket,
+		Key:       key,
+	}, nil
+}
+
+// presignObjectURL builds a path-style object URL and signs it with SigV4 query parameters.
+func presignObjectURL(p presignRequest, signTime time.Time) (string, error) {
+	u, err := url.Parse(p.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing endpoint %q: %w", p.Endpoint, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("endpoint %q must be an absolute URL", p.Endpoint)
+	}
+
+	// S3 expects each path segment to be escaped exactly once, so the escaped form is
+	// provided explicitly and the signer is told not to escape it again.
+	base := strings.TrimSuffix(u.Path, "/")
+	u.Path = base + "/" + p.Bucket + "/" + p.Key
+	u.RawPath = rest.EscapePath(base+"/"+p.Bucket+"/"+p.Key, false)
+	u.RawQuery = p.Query.Encode()
+
+	req, err := http.NewRequest(p.Method, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	signer := awsv4.NewSigner(credentials.NewStaticCredentials(p.AccessKey, p.SecretKey, ""), func(s *awsv4.Signer) {
+		s.DisableURIPathEscaping = true
+	})
+	if _, err := signer.Presign(req, nil, "s3", p.Region, p.Expires, signTime); err != nil {
+		return "", fmt.Errorf("signing URL: %w", err)
+	}
+
+	return req.URL.String(), nil
+}
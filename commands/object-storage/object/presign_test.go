@@ -0,0 +1,93 @@
+package object
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSignTime = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func testPresignRequest() presignRequest {
+	return presignRequest{
+		Endpoint:  "https://s3.eu-central-3.ionoscloud.com",
+		Region:    "eu-central-3",
+		AccessKey: "AKIAEXAMPLE",
+		SecretKey: "secret",
+		Method:    http.MethodGet,
+		Bucket:    "my-bucket",
+		Key:       "reports/q3 summary+final.pdf",
+		Expires:   time.Hour,
+	}
+}
+
+func TestPresignObjectURL(t *testing.T) {
+	signed, err := presignObjectURL(testPresignRequest(), testSignTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	u, err := url.Parse(signed)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "s3.eu-central-3.ionoscloud.com", u.Host)
+	assert.Equal(t, "/my-bucket/reports/q3%20summary%2Bfinal.pdf", u.EscapedPath())
+
+	q := u.Query()
+	assert.Equal(t, "AWS4-HMAC-SHA256", q.Get("X-Amz-Algorithm"))
+	assert.Equal(t, "AKIAEXAMPLE/20261001/eu-central-3/s3/aws4_request", q.Get("X-Amz-Credential"))
+	assert.Equal(t, "20261001T120000Z", q.Get("X-Amz-Date"))
+	assert.Equal(t, "3600", q.Get("X-Amz-Expires"))
+	assert.Equal(t, "host", q.Get("X-Amz-SignedHeaders"))
+	assert.Len(t, q.Get("X-Amz-Signature"), 64)
+}
+
+func TestPresignObjectURL_Deterministic(t *testing.T) {
+	a, err := presignObjectURL(testPresignRequest(), testSignTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b, err := presignObjectURL(testPresignRequest(), testSignTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, a, b)
+
+	put := testPresignRequest()
+	put.Method = http.MethodPut
+	c, err := presignObjectURL(put, testSignTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, a, c, "signature must depend on the HTTP method")
+}
+
+func TestPresignObjectURL_Query(t *testing.T) {
+	p := testPresignRequest()
+	p.Query = url.Values{"versionId": []string{"v42"}}
+
+	signed, err := presignObjectURL(p, testSignTime)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	u, err := url.Parse(signed)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "v42", u.Query().Get("versionId"))
+	assert.NotEmpty(t, u.Query().Get("X-Amz-Signature"))
+}
+
+func TestPresignObjectURL_InvalidEndpoint(t *testing.T) {
+	p := testPresignRequest()
+	p.Endpoint = "s3.example.com"
+
+	_, err := presignObjectURL(p, testSignTime)
+	assert.Error(t, err)
+}
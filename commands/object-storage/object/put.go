@@ -22,7 +22,11 @@ func PutCmd() *core.Command {
 		Verb:      "put",
 		Aliases:   []string{"p"},
 		ShortDesc: "Upload a file as an object",
-		Example:   "ionosctl object-storage object put --name my-bucket --key photos/image.jpg --source ./image.jpg",
+		LongDesc: "Upload a local file as an object. Use --source - to read the object from stdin. " +
+			"Input from stdin is streamed without a temporary file; if it is larger than 16 MiB it is sent as a multipart upload, " +
+			"so its length does not need to be known in advance.",
+		Example: "ionosctl object-storage object put --name my-bucket --key photos/image.jpg --source ./image.jpg\n" +
+			"pg_dump mydb | ionosctl object-storage object put --name my-bucket --key backups/mydb.sql --source -",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
 			return core.CheckRequiredFlags(c.Command, c.NS, constants.FlagName, flagKey, flagSource)
 		},
@@ -32,6 +36,26 @@ func PutCmd() *core.Command {
 			source := viper.GetString(core.GetFlagName(c.NS, flagSource))
 			contentType := viper.GetString(core.GetFlagName(c.NS, flagContentType))
 
+			if source == streamPath {
+				if contentType == "" {
+					contentType = "application/octet-stream"
+				}
+
+				p, err := newPresignRequest(name, key)
+				if err != nil {
+					return err
+				}
+				stream := newObjectStream(p, client.MustObjectStorage().ObjectStorageClient.GetConfig().HTTPClient)
+				n, err := stream.Upload(c.Context, c.Command.Command.InOrStdin(), contentType)
+				if err != nil {
+					return err
+				}
+				c.Verbose("Streamed %d bytes from stdin", n)
+
+				fmt.Fprintf(c.Command.Command.OutOrStdout(), "Object %q uploaded to bucket %q\n", key, name)
+				return nil
+			}
+
 			file, err := os.Open(source)
 			if err != nil {
 				return fmt.Errorf("opening source file %q: %w", source, err)
@@ -65,8 +89,8 @@ func PutCmd() *core.Command {
 		core.WithCompletion(func() []string {
 			return completer.ObjectKeys(viper.GetString(core.GetFlagName(cmd.NS, constants.FlagName)))
 		}, constants.ObjectStorageApiRegionalURL, constants.ObjectStorageLocations))
-	cmd.AddStringFlag(flagSource, flagSourceShort, "", "Path to the local file to upload", core.RequiredFlagOption())
-	cmd.AddStringFlag(flagContentType, "", "", "MIME type of the object (auto-detected from file extension if omitted)")
+	cmd.AddStringFlag(flagSource, flagSourceShort, "", "Path to the local file to upload. Use - to read from stdin", core.RequiredFlagOption())
+	cmd.AddStringFlag(flagContentType, "", "", "MIME type of the object (auto-detected from file extension if omitted, application/octet-stream for stdin)")
 
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
@@ -0,0 +1,264 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	// streamPath is the --source / --destination value selecting stdin / stdout.
+	streamPath = "-"
+
+	// streamPartSize is the part size used when uploading input of unknown length.
+	// Together with the limit of 10000 parts this allows streaming objects of up to ~156 GiB.
+	streamPartSize = 16 << 20
+	maxStreamParts = 10000
+
+	// streamURLExpiry is the validity of the presigned URLs used for each streamed request.
+	streamURLExpiry = 15 * time.Minute
+)
+
+// objectStream transfers object data between the Object Storage API and an
+// io.Reader / io.Writer without staging it in a temporary file. The SDK always
+// buffers object bodies, so each request is sent on a presigned URL instead.
+type objectStream struct {
+	object   presignRequest
+	client   *http.Client
+	partSize int
+	now      func() time.Time
+}
+
+func newObjectStream(object presignRequest, httpClient *http.Client) *objectStream {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &objectStream{
+		object:   object,
+		client:   httpClient,
+		partSize: streamPartSize,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Download writes the object to w and returns the number of bytes written.
+func (s *objectStream) Download(ctx context.Context, w io.Writer, versionId string) (int64, error) {
+	query := url.Values{}
+	if versionId != "" {
+		query.Set("versionId", versionId)
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, query, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("streaming object %q: %w", s.object.Key, err)
+	}
+	return n, nil
+}
+
+// Upload reads r until EOF and stores it as the object. Input which fits into a
+// single part is sent with one PUT, anything larger is sent as a multipart upload
+// so the total length does not need to be known in advance.
+func (s *objectStream) Upload(ctx context.Context, r io.Reader, contentType string) (int64, error) {
+	buf := make([]byte, s.partSize)
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return int64(n), s.putObject(ctx, buf[:n], contentType)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading input: %w", err)
+	}
+
+	// Input of exactly one part still fits into a single PUT.
+	var next [1]byte
+	if _, err = io.ReadFull(r, next[:]); err == io.EOF {
+		return int64(n), s.putObject(ctx, buf, contentType)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading input: %w", err)
+	}
+
+	return s.multipartUpload(ctx, buf, io.MultiReader(bytes.NewReader(next[:]), r), contentType)
+}
+
+func (s *objectStream) putObject(ctx context.Context, data []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, nil, data, http.Header{"Content-Type": []string{contentType}})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+type initiateMultipartUploadResult struct {
+	UploadId string `xml:"UploadId"`
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// multipartUpload uploads the already read first part followed by the rest of r.
+// The upload is aborted if any part fails, so no orphaned parts are left behind.
+func (s *objectStream) multipartUpload(ctx context.Context, first []byte, r io.Reader, contentType string) (total int64, err error) {
+	resp, err := s.do(ctx, http.MethodPost, url.Values{"uploads": []string{""}}, nil,
+		http.Header{"Content-Type": []string{contentType}})
+	if err != nil {
+		return 0, fmt.Errorf("starting multipart upload: %w", err)
+	}
+	var initiated initiateMultipartUploadResult
+	err = xml.NewDecoder(resp.Body).Decode(&initiated)
+	resp.Body.Close()
+	if err != nil || initiated.UploadId == "" {
+		return 0, fmt.Errorf("starting multipart upload: no upload ID in response: %v", err)
+	}
+	uploadId := initiated.UploadId
+
+	defer func() {
+		if err != nil {
+			// Abort even if ctx was cancelled, otherwise the parts keep being billed.
+			if abortResp, abortErr := s.do(context.WithoutCancel(ctx), http.MethodDelete,
+				url.Values{"uploadId": []string{uploadId}}, nil, nil); abortErr == nil {
+				abortResp.Body.Close()
+			}
+		}
+	}()
+
+	var complete completeMultipartUpload
+	part := first
+	for partNumber := 1; len(part) > 0; partNumber++ {
+		if partNumber > maxStreamParts {
+			return total, fmt.Errorf("input exceeds the maximum of %d parts of %d bytes", maxStreamParts, s.partSize)
+		}
+
+		etag, err := s.uploadPart(ctx, uploadId, partNumber, part)
+		if err != nil {
+			return total, err
+		}
+		complete.Parts = append(complete.Parts, completedPart{PartNumber: partNumber, ETag: etag})
+		total += int64(len(part))
+
+		n, err := io.ReadFull(r, first)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return total, fmt.Errorf("reading input: %w", err)
+		}
+		part = first[:n]
+	}
+
+	body, err := xml.Marshal(complete)
+	if err != nil {
+		return total, err
+	}
+	resp, err = s.do(ctx, http.MethodPost, url.Values{"uploadId": []string{uploadId}}, body,
+		http.Header{"Content-Type": []string{"application/xml"}})
+	if err != nil {
+		return total, fmt.Errorf("completing multipart upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// CompleteMultipartUpload may report a failure with a 200 status code.
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return total, fmt.Errorf("completing multipart upload: %w", err)
+	}
+	if apiErr := parseObjectStorageError(respBody); apiErr != nil {
+		return total, fmt.Errorf("completing multipart upload: %w", apiErr)
+	}
+
+	return total, nil
+}
+
+func (s *objectStream) uploadPart(ctx context.Context, uploadId string, partNumber int, data []byte) (string, error) {
+	query := url.Values{
+		"partNumber": []string{strconv.Itoa(partNumber)},
+		"uploadId":   []string{uploadId},
+	}
+	resp, err := s.do(ctx, http.MethodPut, query, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("uploading part %d: %w", partNumber, err)
+	}
+	resp.Body.Close()
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", fmt.Errorf("uploading part %d: no ETag in response", partNumber)
+	}
+	return etag, nil
+}
+
+// do sends a presigned request for the object and returns the response if its status is 2xx.
+func (s *objectStream) do(ctx context.Context, method string, query url.Values, body []byte, header http.Header) (*http.Response, error) {
+	p := s.object
+	p.Method = method
+	p.Query = query
+	p.Expires = streamURLExpiry
+
+	signed, err := presignObjectURL(p, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, signed, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(resp.Body)
+		if apiErr := parseObjectStorageError(respBody); apiErr != nil {
+			return nil, fmt.Errorf("%s %s: %w", method, s.object.Key, apiErr)
+		}
+		return nil, fmt.Errorf("%s %s: unexpected status %s", method, s.object.Key, resp.Status)
+	}
+	return resp, nil
+}
+
+type objectStorageError struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func (e *objectStorageError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// parseObjectStorageError returns the error contained in an S3 <Error> document, or nil.
+func parseObjectStorageError(body []byte) error {
+	var apiErr objectStorageError
+	if err := xml.Unmarshal(body, &apiErr); err != nil || apiErr.Code == "" {
+		return nil
+	}
+	return &apiErr
+}
@@ -0,0 +1,209 @@
+package object
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeObjectStorage is a minimal in-memory stand-in for the object and multipart
+// endpoints used by objectStream. It does not verify signatures.
+type fakeObjectStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	uploads map[string]map[int][]byte
+	aborted []string
+	failOn  string
+}
+
+func newFakeObjectStorage() *fakeObjectStorage {
+	return &fakeObjectStorage{
+		objects: map[string][]byte{},
+		uploads: map[string]map[int][]byte{},
+	}
+}
+
+func (f *fakeObjectStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Query().Get("X-Amz-Signature") == "" {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+
+	path := r.URL.Path
+	q := r.URL.Query()
+	body, _ := io.ReadAll(r.Body)
+
+	if f.failOn != "" && strings.Contains(r.URL.RawQuery, f.failOn) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `<Error><Code>InternalError</Code><Message>injected failure</Message></Error>`)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := fmt.Sprintf("upload-%d", len(f.uploads)+1)
+		f.uploads[id] = map[int][]byte{}
+		fmt.Fprintf(w, `<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>`, id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		var n int
+		fmt.Sscan(q.Get("partNumber"), &n)
+		f.uploads[q.Get("uploadId")][n] = body
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%d"`, n))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var complete completeMultipartUpload
+		if err := xml.Unmarshal(body, &complete); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		parts := f.uploads[q.Get("uploadId")]
+		var data []byte
+		for i, p := range complete.Parts {
+			if p.PartNumber != i+1 || p.ETag != fmt.Sprintf(`"etag-%d"`, p.PartNumber) {
+				fmt.Fprint(w, `<Error><Code>InvalidPart</Code></Error>`)
+				return
+			}
+			data = append(data, parts[p.PartNumber]...)
+		}
+		f.objects[path] = data
+		fmt.Fprint(w, `<CompleteMultipartUploadResult/>`)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		f.aborted = append(f.aborted, q.Get("uploadId"))
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		f.objects[path] = body
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func newTestStream(t *testing.T, fake *fakeObjectStorage, partSize int) *objectStream {
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	p := testPresignRequest()
+	p.Endpoint = srv.URL
+	p.Key = "backups/db.sql"
+
+	s := newObjectStream(p, srv.Client())
+	s.partSize = partSize
+	return s
+}
+
+func TestObjectStream_UploadSinglePart(t *testing.T) {
+	fake := newFakeObjectStorage()
+	s := newTestStream(t, fake, 16)
+
+	n, err := s.Upload(context.Background(), strings.NewReader("small payload"), "text/plain")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 13, n)
+	assert.Equal(t, "small payload", string(fake.objects["/my-bucket/backups/db.sql"]))
+	assert.Empty(t, fake.uploads, "small input must not start a multipart upload")
+}
+
+func TestObjectStream_UploadMultipart(t *testing.T) {
+	fake := newFakeObjectStorage()
+	s := newTestStream(t, fake, 8)
+
+	input := bytes.Repeat([]byte("0123456789"), 5) // 50 bytes -> 7 parts of up to 8 bytes
+
+	// io.MultiReader returns short reads, which must not produce short parts.
+	n, err := s.Upload(context.Background(), io.MultiReader(bytes.NewReader(input[:3]), bytes.NewReader(input[3:])), "application/sql")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, len(input), n)
+	assert.Equal(t, input, fake.objects["/my-bucket/backups/db.sql"])
+
+	var sizes []int
+	for _, part := range fake.uploads["upload-1"] {
+		sizes = append(sizes, len(part))
+	}
+	sort.Ints(sizes)
+	assert.Equal(t, []int{2, 8, 8, 8, 8, 8, 8}, sizes)
+	assert.Empty(t, fake.aborted)
+}
+
+func TestObjectStream_UploadExactPartSize(t *testing.T) {
+	fake := newFakeObjectStorage()
+	s := newTestStream(t, fake, 8)
+
+	_, err := s.Upload(context.Background(), strings.NewReader("12345678"), "text/plain")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "12345678", string(fake.objects["/my-bucket/backups/db.sql"]))
+	assert.Empty(t, fake.uploads, "input of exactly one part must not start a multipart upload")
+}
+
+func TestObjectStream_UploadPartSizePlusOne(t *testing.T) {
+	fake := newFakeObjectStorage()
+	s := newTestStream(t, fake, 8)
+
+	n, err := s.Upload(context.Background(), strings.NewReader("123456789"), "text/plain")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 9, n)
+	assert.Equal(t, "123456789", string(fake.objects["/my-bucket/backups/db.sql"]))
+	assert.Len(t, fake.uploads["upload-1"], 2)
+}
+
+func TestObjectStream_UploadAbortsOnFailure(t *testing.T) {
+	fake := newFakeObjectStorage()
+	fake.failOn = "partNumber=2"
+	s := newTestStream(t, fake, 4)
+
+	_, err := s.Upload(context.Background(), strings.NewReader("aaaabbbbcccc"), "text/plain")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "uploading part 2")
+		assert.Contains(t, err.Error(), "injected failure")
+	}
+	assert.Equal(t, []string{"upload-1"}, fake.aborted)
+	assert.NotContains(t, fake.objects, "/my-bucket/backups/db.sql")
+}
+
+func TestObjectStream_Download(t *testing.T) {
+	fake := newFakeObjectStorage()
+	fake.objects["/my-bucket/backups/db.sql"] = []byte("SELECT 1;")
+	s := newTestStream(t, fake, 8)
+
+	var out bytes.Buffer
+	n, err := s.Download(context.Background(), &out, "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 9, n)
+	assert.Equal(t, "SELECT 1;", out.String())
+}
+
+func TestObjectStream_DownloadNotFound(t *testing.T) {
+	s := newTestStream(t, newFakeObjectStorage(), 8)
+
+	_, err := s.Download(context.Background(), io.Discard, "")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "NoSuchKey")
+	}
+}
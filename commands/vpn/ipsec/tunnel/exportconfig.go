@@ -0,0 +1,94 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ionos-cloud/ionosctl/v6/commands/vpn/ipsec/completer"
+
+	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
+	"github.com/ionos-cloud/ionosctl/v6/internal/core"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagFormat = "format"
+	FlagPSK    = "psk"
+)
+
+func ExportConfig() *core.Command {
+	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
+		Namespace: "vpn",
+		Resource:  "ipsec tunnel",
+		Verb:      "export-config",
+		Aliases:   []string{"export", "ec"},
+		ShortDesc: "Generate the configuration of the remote side of a tunnel",
+		LongDesc: "Generate the configuration of the device on the remote side of a tunnel, from the IKE and ESP settings, " +
+			"the pre-shared key and the networks of the tunnel. The remote device proposes exactly the algorithms configured for the tunnel, " +
+			"so that both sides agree on the proposals.\n\n" +
+			"Formats:\n" +
+			"  strongswan: swanctl.conf, for strongSwan 5.8 or later\n" +
+			"  libreswan:  ipsec.conf connection and secrets\n" +
+			"  vyos:       'set' commands for VyOS 1.4 or later\n" +
+			"  pfsense:    settings to enter in VPN > IPsec > Tunnels\n\n" +
+			"An error is returned if the device does not support an algorithm of the tunnel. " +
+			"If the API does not return the pre-shared key, it can be set with --" + FlagPSK + ", otherwise a placeholder is written.",
+		Example: "ionosctl vpn ipsec tunnel export-config " + core.FlagsUsage(constants.FlagGatewayID, constants.FlagTunnelID, FlagFormat) + " > /etc/swanctl/conf.d/ionos.conf",
+		PreCmdRun: func(c *core.PreCommandConfig) error {
+			return c.CheckRequiredFlagsAndLocation(constants.FlagGatewayID, constants.FlagTunnelID, FlagFormat)
+		},
+		CmdRun: func(c *core.CommandConfig) error {
+			gatewayId := viper.GetString(core.GetFlagName(c.NS, constants.FlagGatewayID))
+			id := viper.GetString(core.GetFlagName(c.NS, constants.FlagTunnelID))
+
+			g, _, err := client.Must().VPNClient.IPSecGatewaysApi.IpsecgatewaysFindById(context.Background(), gatewayId).Execute()
+			if err != nil {
+				return fmt.Errorf("failed getting gateway by id %s: %w", gatewayId, err)
+			}
+			t, _, err := client.Must().VPNClient.IPSecTunnelsApi.IpsecgatewaysTunnelsFindById(context.Background(), gatewayId, id).Execute()
+			if err != nil {
+				return fmt.Errorf("failed getting tunnel by id %s: %w", id, err)
+			}
+
+			remote, err := newRemoteSide(g, t)
+			if err != nil {
+				return err
+			}
+			if fn := core.GetFlagName(c.NS, FlagPSK); viper.IsSet(fn) {
+				remote.PSK = viper.GetString(fn)
+			}
+			if remote.PSK == pskPlaceholder {
+				fmt.Fprintf(c.Command.Command.ErrOrStderr(),
+					"WARNING: the pre-shared key of the tunnel is not available, replace %s or set --%s\n", pskPlaceholder, FlagPSK)
+			}
+
+			conf, err := renderRemoteConfig(viper.GetString(core.GetFlagName(c.NS, FlagFormat)), remote)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(c.Command.Command.OutOrStdout(), conf)
+			return err
+		},
+		InitClient: true,
+	})
+
+	cmd.AddStringFlag(constants.FlagGatewayID, "", "", "The ID of the IPSec Gateway",
+		core.RequiredFlagOption(),
+		core.WithCompletion(completer.GatewayIDs, constants.VPNApiRegionalURL, constants.VPNLocations),
+	)
+	cmd.AddStringFlag(constants.FlagTunnelID, constants.FlagIdShort, "", "The ID of the IPSec Tunnel",
+		core.RequiredFlagOption(),
+		core.WithCompletion(func() []string {
+			gatewayID := viper.GetString(core.GetFlagName(cmd.NS, constants.FlagGatewayID))
+			return completer.TunnelIDs(gatewayID)
+		}, constants.VPNApiRegionalURL, constants.VPNLocations),
+	)
+	cmd.AddSetFlag(FlagFormat, "", "", remoteConfigFormats, "Format of the configuration, for the device on the remote side", core.RequiredFlagOption())
+	cmd.AddStringFlag(FlagPSK, "", "", "Pre-shared key to write, if the API does not return the key of the tunnel")
+
+	cmd.Command.SilenceUsage = true
+	cmd.Command.Flags().SortFlags = false
+
+	return cmd
+}
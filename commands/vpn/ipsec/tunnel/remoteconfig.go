@@ -0,0 +1,387 @@
+package tunnel
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ionos-cloud/sdk-go-bundle/products/vpn/v2"
+)
+
+// Formats of export-config, with the device they configure.
+var remoteConfigFormats = []string{"strongswan", "libreswan", "vyos", "pfsense"}
+
+// pskPlaceholder is written if the API does not return the pre-shared key of the tunnel.
+const pskPlaceholder = "<PRE-SHARED-KEY>"
+
+// cipher is an encryption algorithm of the API, e.g. AES256-GCM-16.
+type cipher struct {
+	// Mode is one of cbc, ctr, gcm or ccm.
+	Mode    string
+	KeyBits int
+	// ICVBytes is the length of the integrity check value of AEAD modes.
+	ICVBytes int
+}
+
+var cipherPattern = regexp.MustCompile(`^AES(128|256)(?:-(CTR|GCM|CCM))?(?:-(12|16))?$`)
+
+func parseCipher(s string) (cipher, error) {
+	m := cipherPattern.FindStringSubmatch(strings.ToUpper(s))
+	if m == nil || (m[2] == "GCM" || m[2] == "CCM") != (m[3] != "") {
+		return cipher{}, fmt.Errorf("unknown encryption algorithm %q", s)
+	}
+	c := cipher{Mode: "cbc"}
+	c.KeyBits, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		c.Mode = strings.ToLower(m[2])
+	}
+	if m[3] != "" {
+		c.ICVBytes, _ = strconv.Atoi(m[3])
+	}
+	return c, nil
+}
+
+// aead reports whether the cipher also provides integrity, so no separate integrity algorithm is negotiated.
+func (c cipher) aead() bool {
+	return c.Mode == "gcm" || c.Mode == "ccm"
+}
+
+// proposal is the IKE or ESP proposal of a tunnel. The IONOS gateway accepts exactly one, so the
+// remote side has to propose the same algorithms.
+type proposal struct {
+	Cipher cipher
+	// Integrity is one of SHA256, SHA384, SHA512 or AES-XCBC. For AEAD ciphers it is the PRF of IKE.
+	Integrity string
+	DHGroup   int
+	Lifetime  int32
+}
+
+var dhGroupPattern = regexp.MustCompile(`^(\d+)-`)
+
+func parseProposal(phase string, dh, enc, integrity *string, lifetime *int32) (proposal, error) {
+	if dh == nil || enc == nil || integrity == nil || lifetime == nil {
+		return proposal{}, fmt.Errorf("the %s settings of the tunnel are incomplete", phase)
+	}
+
+	c, err := parseCipher(*enc)
+	if err != nil {
+		return proposal{}, fmt.Errorf("%s: %w", phase, err)
+	}
+	p := proposal{Cipher: c, Integrity: strings.ToUpper(*integrity), Lifetime: *lifetime}
+	if !slices.Contains([]string{"SHA256", "SHA384", "SHA512", "AES-XCBC"}, p.Integrity) {
+		return proposal{}, fmt.Errorf("%s: unknown integrity algorithm %q", phase, *integrity)
+	}
+	m := dhGroupPattern.FindStringSubmatch(*dh)
+	if m == nil {
+		return proposal{}, fmt.Errorf("%s: unknown Diffie-Hellman group %q", phase, *dh)
+	}
+	p.DHGroup, _ = strconv.Atoi(m[1])
+	if _, ok := strongswanDHGroups[p.DHGroup]; !ok {
+		return proposal{}, fmt.Errorf("%s: unknown Diffie-Hellman group %q", phase, *dh)
+	}
+	return p, nil
+}
+
+// remoteSide is the configuration of the customer's end of a tunnel. "Local" is the customer's
+// side and "remote" the IONOS gateway, as seen by the device being configured.
+type remoteSide struct {
+	Name string
+	// GatewayIP is the public IP of the IONOS gateway.
+	GatewayIP string
+	// LocalAddress is the remote host of the tunnel, i.e. the public address of the device.
+	LocalAddress string
+	PSK          string
+	LocalCIDRs   []string
+	RemoteCIDRs  []string
+	IKE, ESP     proposal
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+func newRemoteSide(gateway vpn.IPSecGatewayRead, tunnel vpn.IPSecTunnelRead) (remoteSide, error) {
+	t := tunnel.Properties
+	r := remoteSide{
+		Name:         strings.Trim(invalidNameChars.ReplaceAllString("ionos-"+t.Name, "-"), "-"),
+		GatewayIP:    gateway.Properties.GatewayIP,
+		LocalAddress: t.RemoteHost,
+		PSK:          pskPlaceholder,
+		LocalCIDRs:   t.PeerNetworkCIDRs,
+		RemoteCIDRs:  t.CloudNetworkCIDRs,
+	}
+	if !strings.EqualFold(t.Auth.Method, "PSK") {
+		return remoteSide{}, fmt.Errorf("authentication method %s is not supported, only PSK", t.Auth.Method)
+	}
+	if t.Auth.Psk != nil && t.Auth.Psk.Key != "" {
+		r.PSK = t.Auth.Psk.Key
+	}
+
+	var err error
+	if r.IKE, err = parseProposal("IKE", t.Ike.DiffieHellmanGroup, t.Ike.EncryptionAlgorithm, t.Ike.IntegrityAlgorithm, t.Ike.Lifetime); err != nil {
+		return remoteSide{}, err
+	}
+	if r.ESP, err = parseProposal("ESP", t.Esp.DiffieHellmanGroup, t.Esp.EncryptionAlgorithm, t.Esp.IntegrityAlgorithm, t.Esp.Lifetime); err != nil {
+		return remoteSide{}, err
+	}
+	return r, nil
+}
+
+// renderRemoteConfig renders the configuration of the device in the given format.
+func renderRemoteConfig(format string, r remoteSide) (string, error) {
+	switch format {
+	case "strongswan":
+		return renderStrongswan(r), nil
+	case "libreswan":
+		return renderLibreswan(r)
+	case "vyos":
+		return renderVyOS(r), nil
+	case "pfsense":
+		return renderPfSense(r)
+	}
+	return "", fmt.Errorf("unknown format %q, must be one of %s", format, strings.Join(remoteConfigFormats, ", "))
+}
+
+// unsupportedError explains which setting of the tunnel the device does not support.
+func unsupportedError(device, phase, setting string) error {
+	return fmt.Errorf("%s does not support the %s setting %s of the tunnel, update the tunnel with 'ionosctl vpn ipsec tunnel update' to use another one", device, phase, setting)
+}
+
+var strongswanDHGroups = map[int]string{
+	15: "modp3072", 16: "modp4096", 19: "ecp256", 20: "ecp384", 21: "ecp521", 28: "ecp256bp", 29: "ecp384bp", 30: "ecp512bp",
+}
+
+func strongswanCipher(c cipher) string {
+	switch c.Mode {
+	case "ctr":
+		return fmt.Sprintf("aes%dctr", c.KeyBits)
+	case "gcm", "ccm":
+		return fmt.Sprintf("aes%d%s%d", c.KeyBits, c.Mode, c.ICVBytes)
+	}
+	return fmt.Sprintf("aes%d", c.KeyBits)
+}
+
+func strongswanIntegrity(integrity string) string {
+	return strings.ReplaceAll(strings.ToLower(integrity), "-", "")
+}
+
+// strongswanProposal renders a proposal in the strongSwan syntax, which is also used by VyOS.
+// AEAD ciphers have no integrity algorithm, but IKE needs a PRF instead.
+func strongswanProposal(p proposal, ike bool) string {
+	parts := []string{strongswanCipher(p.Cipher)}
+	switch {
+	case !p.Cipher.aead():
+		parts = append(parts, strongswanIntegrity(p.Integrity))
+	case ike:
+		parts = append(parts, "prf"+strongswanIntegrity(p.Integrity))
+	}
+	return strings.Join(append(parts, strongswanDHGroups[p.DHGroup]), "-")
+}
+
+func renderStrongswan(r remoteSide) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# swanctl.conf for the IONOS Cloud IPSec tunnel %s, e.g. /etc/swanctl/conf.d/%s.conf\n", r.Name, r.Name)
+	sb.WriteString("connections {\n")
+	fmt.Fprintf(&sb, "    %s {\n", r.Name)
+	sb.WriteString("        version = 2\n")
+	fmt.Fprintf(&sb, "        remote_addrs = %s\n", r.GatewayIP)
+	fmt.Fprintf(&sb, "        proposals = %s\n", strongswanProposal(r.IKE, true))
+	fmt.Fprintf(&sb, "        rekey_time = %ds\n", r.IKE.Lifetime)
+	sb.WriteString("        dpd_delay = 30s\n")
+	sb.WriteString("        local {\n            auth = psk\n")
+	fmt.Fprintf(&sb, "            id = %s\n        }\n", r.LocalAddress)
+	sb.WriteString("        remote {\n            auth = psk\n")
+	fmt.Fprintf(&sb, "            id = %s\n        }\n", r.GatewayIP)
+	sb.WriteString("        children {\n")
+	fmt.Fprintf(&sb, "            %s {\n", r.Name)
+	fmt.Fprintf(&sb, "                local_ts = %s\n", strings.Join(r.LocalCIDRs, ","))
+	fmt.Fprintf(&sb, "                remote_ts = %s\n", strings.Join(r.RemoteCIDRs, ","))
+	fmt.Fprintf(&sb, "                esp_proposals = %s\n", strongswanProposal(r.ESP, false))
+	fmt.Fprintf(&sb, "                rekey_time = %ds\n", r.ESP.Lifetime)
+	sb.WriteString("                dpd_action = restart\n")
+	sb.WriteString("                start_action = start\n")
+	sb.WriteString("            }\n        }\n    }\n}\n\n")
+	sb.WriteString("secrets {\n")
+	fmt.Fprintf(&sb, "    ike-%s {\n", r.Name)
+	fmt.Fprintf(&sb, "        id-local = %s\n", r.LocalAddress)
+	fmt.Fprintf(&sb, "        id-remote = %s\n", r.GatewayIP)
+	fmt.Fprintf(&sb, "        secret = %q\n", r.PSK)
+	sb.WriteString("    }\n}\n")
+	return sb.String()
+}
+
+var libreswanDHGroups = map[int]string{15: "modp3072", 16: "modp4096", 19: "dh19", 20: "dh20", 21: "dh21"}
+
+func libreswanProposal(p proposal, phase string) (string, error) {
+	var enc string
+	switch {
+	case p.Cipher.Mode == "cbc":
+		enc = fmt.Sprintf("aes%d", p.Cipher.KeyBits)
+	case p.Cipher.Mode == "ctr":
+		enc = fmt.Sprintf("aes_ctr%d", p.Cipher.KeyBits)
+	case p.Cipher.Mode == "ccm" && phase == "IKE":
+		return "", unsupportedError("libreswan", phase, fmt.Sprintf("AES%d-CCM-%d", p.Cipher.KeyBits, p.Cipher.ICVBytes))
+	default:
+		// aes_gcm_b and aes_ccm_b have a 12 byte ICV, _c a 16 byte one.
+		enc = fmt.Sprintf("aes_%s_%c%d", p.Cipher.Mode, map[int]rune{12: 'b', 16: 'c'}[p.Cipher.ICVBytes], p.Cipher.KeyBits)
+	}
+
+	dh, ok := libreswanDHGroups[p.DHGroup]
+	if !ok {
+		return "", unsupportedError("libreswan", phase, fmt.Sprintf("Diffie-Hellman group %d", p.DHGroup))
+	}
+
+	integrity := map[string]string{"SHA256": "sha2_256", "SHA384": "sha2_384", "SHA512": "sha2_512", "AES-XCBC": "aes_xcbc"}[p.Integrity]
+	if p.Cipher.aead() && phase == "ESP" {
+		return enc + ";" + dh, nil
+	}
+	// For AEAD ciphers in IKE, the second algorithm is the PRF.
+	return enc + "-" + integrity + ";" + dh, nil
+}
+
+func libreswanSubnets(side string, cidrs []string) string {
+	if len(cidrs) == 1 {
+		return fmt.Sprintf("    %ssubnet=%s\n", side, cidrs[0])
+	}
+	return fmt.Sprintf("    %ssubnets={%s}\n", side, strings.Join(cidrs, " "))
+}
+
+func renderLibreswan(r remoteSide) (string, error) {
+	ike, err := libreswanProposal(r.IKE, "IKE")
+	if err != nil {
+		return "", err
+	}
+	esp, err := libreswanProposal(r.ESP, "ESP")
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# /etc/ipsec.d/%s.conf for the IONOS Cloud IPSec tunnel %s\n", r.Name, r.Name)
+	fmt.Fprintf(&sb, "conn %s\n", r.Name)
+	sb.WriteString("    ikev2=insist\n")
+	sb.WriteString("    authby=secret\n")
+	sb.WriteString("    left=%defaultroute\n")
+	fmt.Fprintf(&sb, "    leftid=%s\n", r.LocalAddress)
+	sb.WriteString(libreswanSubnets("left", r.LocalCIDRs))
+	fmt.Fprintf(&sb, "    right=%s\n", r.GatewayIP)
+	fmt.Fprintf(&sb, "    rightid=%s\n", r.GatewayIP)
+	sb.WriteString(libreswanSubnets("right", r.RemoteCIDRs))
+	fmt.Fprintf(&sb, "    ike=%s\n", ike)
+	fmt.Fprintf(&sb, "    esp=%s\n", esp)
+	sb.WriteString("    pfs=yes\n")
+	fmt.Fprintf(&sb, "    ikelifetime=%ds\n", r.IKE.Lifetime)
+	fmt.Fprintf(&sb, "    salifetime=%ds\n", r.ESP.Lifetime)
+	sb.WriteString("    dpddelay=30\n")
+	sb.WriteString("    auto=start\n\n")
+	fmt.Fprintf(&sb, "# /etc/ipsec.d/%s.secrets\n", r.Name)
+	fmt.Fprintf(&sb, "%s %s : PSK %q\n", r.LocalAddress, r.GatewayIP, r.PSK)
+	return sb.String(), nil
+}
+
+// vyosCipher uses the bit lengths of the ICV, as offered by the VyOS completion.
+func vyosCipher(c cipher) string {
+	if c.aead() {
+		return fmt.Sprintf("aes%d%s%d", c.KeyBits, c.Mode, c.ICVBytes*8)
+	}
+	return strongswanCipher(c)
+}
+
+func renderVyOS(r remoteSide) string {
+	lines := []string{
+		fmt.Sprintf("# VyOS 1.4 configuration for the IONOS Cloud IPSec tunnel %s, paste in configure mode", r.Name),
+		fmt.Sprintf("set vpn ipsec ike-group %s key-exchange 'ikev2'", r.Name),
+		fmt.Sprintf("set vpn ipsec ike-group %s lifetime '%d'", r.Name, r.IKE.Lifetime),
+		fmt.Sprintf("set vpn ipsec ike-group %s dead-peer-detection action 'restart'", r.Name),
+		fmt.Sprintf("set vpn ipsec ike-group %s dead-peer-detection interval '30'", r.Name),
+		fmt.Sprintf("set vpn ipsec ike-group %s proposal 1 dh-group '%d'", r.Name, r.IKE.DHGroup),
+		fmt.Sprintf("set vpn ipsec ike-group %s proposal 1 encryption '%s'", r.Name, vyosCipher(r.IKE.Cipher)),
+		fmt.Sprintf("set vpn ipsec ike-group %s proposal 1 hash '%s'", r.Name, strongswanIntegrity(r.IKE.Integrity)),
+		fmt.Sprintf("set vpn ipsec esp-group %s lifetime '%d'", r.Name, r.ESP.Lifetime),
+		fmt.Sprintf("set vpn ipsec esp-group %s pfs 'dh-group%d'", r.Name, r.ESP.DHGroup),
+		fmt.Sprintf("set vpn ipsec esp-group %s proposal 1 encryption '%s'", r.Name, vyosCipher(r.ESP.Cipher)),
+		fmt.Sprintf("set vpn ipsec esp-group %s proposal 1 hash '%s'", r.Name, strongswanIntegrity(r.ESP.Integrity)),
+		fmt.Sprintf("set vpn ipsec authentication psk %s id '%s'", r.Name, r.LocalAddress),
+		fmt.Sprintf("set vpn ipsec authentication psk %s id '%s'", r.Name, r.GatewayIP),
+		fmt.Sprintf("set vpn ipsec authentication psk %s secret '%s'", r.Name, r.PSK),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s authentication mode 'pre-shared-secret'", r.Name),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s authentication local-id '%s'", r.Name, r.LocalAddress),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s authentication remote-id '%s'", r.Name, r.GatewayIP),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s connection-type 'initiate'", r.Name),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s ike-group '%s'", r.Name, r.Name),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s default-esp-group '%s'", r.Name, r.Name),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s local-address 'any'", r.Name),
+		fmt.Sprintf("set vpn ipsec site-to-site peer %s remote-address '%s'", r.Name, r.GatewayIP),
+	}
+	for _, cidr := range r.LocalCIDRs {
+		lines = append(lines, fmt.Sprintf("set vpn ipsec site-to-site peer %s tunnel 0 local prefix '%s'", r.Name, cidr))
+	}
+	for _, cidr := range r.RemoteCIDRs {
+		lines = append(lines, fmt.Sprintf("set vpn ipsec site-to-site peer %s tunnel 0 remote prefix '%s'", r.Name, cidr))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+var pfSenseDHGroups = map[int]string{
+	15: "15 (3072 bit)", 16: "16 (4096 bit)", 19: "19 (nist ecp256)", 20: "20 (nist ecp384)", 21: "21 (nist ecp521)",
+	28: "28 (brainpool ecp256r1)", 29: "29 (brainpool ecp384r1)", 30: "30 (brainpool ecp512r1)",
+}
+
+func pfSenseCipher(c cipher, phase string) (string, error) {
+	switch c.Mode {
+	case "cbc":
+		return fmt.Sprintf("AES, key length %d bits", c.KeyBits), nil
+	case "gcm":
+		return fmt.Sprintf("AES%d-GCM, key length %d bits, ICV %d bits", c.KeyBits, c.KeyBits, c.ICVBytes*8), nil
+	}
+	return "", unsupportedError("pfSense", phase, fmt.Sprintf("encryption algorithm AES%d-%s", c.KeyBits, strings.ToUpper(c.Mode)))
+}
+
+// renderPfSense lists the settings to enter in VPN > IPsec > Tunnels, as pfSense has no importable
+// configuration format for single tunnels.
+func renderPfSense(r remoteSide) (string, error) {
+	ike, err := pfSenseCipher(r.IKE.Cipher, "IKE")
+	if err != nil {
+		return "", err
+	}
+	esp, err := pfSenseCipher(r.ESP.Cipher, "ESP")
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# pfSense settings for the IONOS Cloud IPSec tunnel %s, in VPN > IPsec > Tunnels\n\n", r.Name)
+	sb.WriteString("Phase 1\n")
+	fmt.Fprintf(&sb, "  Description:             %s\n", r.Name)
+	sb.WriteString("  Key Exchange version:    IKEv2\n")
+	sb.WriteString("  Internet Protocol:       IPv4\n")
+	fmt.Fprintf(&sb, "  Remote Gateway:          %s\n", r.GatewayIP)
+	sb.WriteString("  Authentication Method:   Mutual PSK\n")
+	fmt.Fprintf(&sb, "  My identifier:           IP address %s\n", r.LocalAddress)
+	fmt.Fprintf(&sb, "  Peer identifier:         IP address %s\n", r.GatewayIP)
+	fmt.Fprintf(&sb, "  Pre-Shared Key:          %s\n", r.PSK)
+	fmt.Fprintf(&sb, "  Encryption Algorithm:    %s\n", ike)
+	fmt.Fprintf(&sb, "  Hash:                    %s\n", r.IKE.Integrity)
+	fmt.Fprintf(&sb, "  DH Group:                %s\n", pfSenseDHGroups[r.IKE.DHGroup])
+	fmt.Fprintf(&sb, "  Life Time:               %d\n", r.IKE.Lifetime)
+	sb.WriteString("  Dead Peer Detection:     enabled\n")
+
+	n := 1
+	for _, local := range r.LocalCIDRs {
+		for _, remote := range r.RemoteCIDRs {
+			fmt.Fprintf(&sb, "\nPhase 2 (%d)\n", n)
+			sb.WriteString("  Mode:                    Tunnel IPv4\n")
+			fmt.Fprintf(&sb, "  Local Network:           Network %s\n", local)
+			fmt.Fprintf(&sb, "  Remote Network:          %s\n", remote)
+			sb.WriteString("  Protocol:                ESP\n")
+			fmt.Fprintf(&sb, "  Encryption Algorithms:   %s\n", esp)
+			if !r.ESP.Cipher.aead() {
+				fmt.Fprintf(&sb, "  Hash Algorithms:         %s\n", r.ESP.Integrity)
+			}
+			fmt.Fprintf(&sb, "  PFS key group:           %s\n", pfSenseDHGroups[r.ESP.DHGroup])
+			fmt.Fprintf(&sb, "  Life Time:               %d\n", r.ESP.Lifetime)
+			n++
+		}
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,145 @@
+package tunnel
+
+import (
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/vpn/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testTunnel(ikeEnc, espEnc, dh string) (vpn.IPSecGatewayRead, vpn.IPSecTunnelRead) {
+	gateway := vpn.IPSecGatewayRead{Properties: vpn.IPSecGateway{GatewayIP: "203.0.113.10"}}
+	tunnel := vpn.IPSecTunnelRead{Properties: vpn.IPSecTunnel{
+		Name:       "office berlin",
+		RemoteHost: "198.51.100.7",
+		Auth:       vpn.IPSecTunnelAuth{Method: "PSK", Psk: &vpn.IPSecPSK{Key: "s3cret"}},
+		Ike: vpn.IKEEncryption{
+			DiffieHellmanGroup:  pointer.From(dh),
+			EncryptionAlgorithm: pointer.From(ikeEnc),
+			IntegrityAlgorithm:  pointer.From("SHA384"),
+			Lifetime:            pointer.From(int32(86400)),
+		},
+		Esp: vpn.ESPEncryption{
+			DiffieHellmanGroup:  pointer.From(dh),
+			EncryptionAlgorithm: pointer.From(espEnc),
+			IntegrityAlgorithm:  pointer.From("SHA256"),
+			Lifetime:            pointer.From(int32(3600)),
+		},
+		CloudNetworkCIDRs: []string{"10.0.0.0/16"},
+		PeerNetworkCIDRs:  []string{"192.168.10.0/24", "192.168.20.0/24"},
+	}}
+	return gateway, tunnel
+}
+
+func TestParseCipher(t *testing.T) {
+	for in, want := range map[string]cipher{
+		"AES256":        {Mode: "cbc", KeyBits: 256},
+		"AES128-CTR":    {Mode: "ctr", KeyBits: 128},
+		"AES256-GCM-16": {Mode: "gcm", KeyBits: 256, ICVBytes: 16},
+		"aes128-ccm-12": {Mode: "ccm", KeyBits: 128, ICVBytes: 12},
+	} {
+		got, err := parseCipher(in)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, want, got, in)
+		}
+	}
+	for _, in := range []string{"AES192", "AES256-GCM", "AES256-CTR-16", "3DES"} {
+		_, err := parseCipher(in)
+		assert.ErrorContains(t, err, "unknown encryption algorithm", in)
+	}
+}
+
+func TestStrongswanProposal(t *testing.T) {
+	gateway, tunnel := testTunnel("AES256-GCM-16", "AES128", "19-ECP256")
+	r, err := newRemoteSide(gateway, tunnel)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "ionos-office-berlin", r.Name)
+	assert.Equal(t, "aes256gcm16-prfsha384-ecp256", strongswanProposal(r.IKE, true))
+	assert.Equal(t, "aes128-sha256-ecp256", strongswanProposal(r.ESP, false))
+
+	r.ESP.Cipher = cipher{Mode: "ccm", KeyBits: 256, ICVBytes: 12}
+	assert.Equal(t, "aes256ccm12-ecp256", strongswanProposal(r.ESP, false))
+}
+
+func TestRenderRemoteConfig(t *testing.T) {
+	gateway, tunnel := testTunnel("AES256", "AES256-GCM-12", "16-MODP4096")
+	r, err := newRemoteSide(gateway, tunnel)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	conf, err := renderRemoteConfig("strongswan", r)
+	if assert.NoError(t, err) {
+		assert.Contains(t, conf, "        proposals = aes256-sha384-modp4096\n")
+		assert.Contains(t, conf, "                esp_proposals = aes256gcm12-modp4096\n")
+		assert.Contains(t, conf, "                local_ts = 192.168.10.0/24,192.168.20.0/24\n")
+		assert.Contains(t, conf, "        rekey_time = 86400s\n")
+		assert.Contains(t, conf, `        secret = "s3cret"`)
+	}
+
+	conf, err = renderRemoteConfig("libreswan", r)
+	if assert.NoError(t, err) {
+		assert.Contains(t, conf, "    ike=aes256-sha2_384;modp4096\n")
+		assert.Contains(t, conf, "    esp=aes_gcm_b256;modp4096\n")
+		assert.Contains(t, conf, "    leftsubnets={192.168.10.0/24 192.168.20.0/24}\n")
+		assert.Contains(t, conf, "    rightsubnet=10.0.0.0/16\n")
+		assert.Contains(t, conf, `198.51.100.7 203.0.113.10 : PSK "s3cret"`)
+	}
+
+	conf, err = renderRemoteConfig("vyos", r)
+	if assert.NoError(t, err) {
+		assert.Contains(t, conf, "set vpn ipsec ike-group ionos-office-berlin proposal 1 encryption 'aes256'\n")
+		assert.Contains(t, conf, "set vpn ipsec esp-group ionos-office-berlin proposal 1 encryption 'aes256gcm96'\n")
+		assert.Contains(t, conf, "set vpn ipsec esp-group ionos-office-berlin pfs 'dh-group16'\n")
+		assert.Contains(t, conf, "set vpn ipsec site-to-site peer ionos-office-berlin tunnel 0 local prefix '192.168.20.0/24'\n")
+	}
+
+	conf, err = renderRemoteConfig("pfsense", r)
+	if assert.NoError(t, err) {
+		assert.Contains(t, conf, "  Encryption Algorithm:    AES, key length 256 bits\n")
+		assert.Contains(t, conf, "  Encryption Algorithms:   AES256-GCM, key length 256 bits, ICV 96 bits\n")
+		assert.Contains(t, conf, "  DH Group:                16 (4096 bit)\n")
+		assert.Contains(t, conf, "Phase 2 (2)\n")
+		assert.NotContains(t, conf, "Hash Algorithms")
+	}
+}
+
+func TestRenderRemoteConfigUnsupported(t *testing.T) {
+	gateway, tunnel := testTunnel("AES128-CCM-12", "AES128-CTR", "28-ECP256BP")
+	r, err := newRemoteSide(gateway, tunnel)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = renderRemoteConfig("libreswan", r)
+	assert.ErrorContains(t, err, "libreswan does not support the IKE setting AES128-CCM-12 of the tunnel")
+	r.IKE.Cipher = cipher{Mode: "cbc", KeyBits: 128}
+	_, err = renderRemoteConfig("libreswan", r)
+	assert.ErrorContains(t, err, "libreswan does not support the IKE setting Diffie-Hellman group 28")
+
+	_, err = renderRemoteConfig("pfsense", r)
+	assert.ErrorContains(t, err, "pfSense does not support the ESP setting encryption algorithm AES128-CTR")
+
+	_, err = renderRemoteConfig("strongswan", r)
+	assert.NoError(t, err)
+}
+
+func TestNewRemoteSide(t *testing.T) {
+	gateway, tunnel := testTunnel("AES256", "AES256", "19-ECP256")
+	tunnel.Properties.Auth.Psk = nil
+	r, err := newRemoteSide(gateway, tunnel)
+	if assert.NoError(t, err) {
+		assert.Equal(t, pskPlaceholder, r.PSK)
+	}
+
+	tunnel.Properties.Esp.Lifetime = nil
+	_, err = newRemoteSide(gateway, tunnel)
+	assert.ErrorContains(t, err, "the ESP settings of the tunnel are incomplete")
+
+	gateway, tunnel = testTunnel("AES256", "AES256", "14-MODP2048")
+	_, err = newRemoteSide(gateway, tunnel)
+	assert.ErrorContains(t, err, `IKE: unknown Diffie-Hellman group "14-MODP2048"`)
+}
@@ -51,6 +51,7 @@ func Root() *core.Command {
 	cmd.AddCommand(Get())
 	cmd.AddCommand(Delete())
 	cmd.AddCommand(Update())
+	cmd.AddCommand(ExportConfig())
 
 	return cmd
 }
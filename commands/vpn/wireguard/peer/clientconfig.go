@@ -0,0 +1,115 @@
+package peer
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ionos-cloud/sdk-go-bundle/products/vpn/v2"
+	"golang.org/x/crypto/curve25519"
+)
+
+// defaultListenPort is the port of a gateway without an explicit listen port.
+const defaultListenPort = 51820
+
+// generateKeyPair creates a WireGuard key pair, base64 encoded like the output of 'wg genkey' and 'wg pubkey'.
+func generateKeyPair() (privateKey, publicKey string, err error) {
+	key := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(key); err != nil {
+		return "", "", fmt.Errorf("failed generating private key: %w", err)
+	}
+	// Clamp the scalar, as 'wg genkey' does.
+	key[0] &= 248
+	key[31] = (key[31] & 127) | 64
+
+	pub, err := curve25519.X25519(key, curve25519.Basepoint)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), base64.StdEncoding.EncodeToString(pub), nil
+}
+
+// clientConfig is the wg-quick configuration of a peer connecting to a gateway.
+type clientConfig struct {
+	PrivateKey string
+	// Addresses of the peer inside the tunnel, i.e. the allowed IPs of the peer on the gateway.
+	Addresses []string
+	DNS       []string
+
+	GatewayPublicKey string
+	Endpoint         string
+	// AllowedIPs are routed through the tunnel.
+	AllowedIPs          []string
+	PersistentKeepalive int
+}
+
+// newClientConfig fills the gateway side of the configuration from the gateway. Unless allowedIPs are given,
+// the tunnel and the LANs the gateway is connected to are routed through the tunnel.
+func newClientConfig(privateKey string, addresses []string, gateway vpn.WireguardGatewayRead, allowedIPs []string) clientConfig {
+	port := defaultListenPort
+	if gateway.Properties.ListenPort != nil {
+		port = int(*gateway.Properties.ListenPort)
+	}
+
+	if len(allowedIPs) == 0 {
+		for _, cidr := range []*string{gateway.Properties.InterfaceIPv4CIDR, gateway.Properties.InterfaceIPv6CIDR} {
+			if cidr != nil && *cidr != "" {
+				allowedIPs = append(allowedIPs, *cidr)
+			}
+		}
+		for _, conn := range gateway.Properties.Connections {
+			if conn.Ipv4CIDR != "" {
+				allowedIPs = append(allowedIPs, conn.Ipv4CIDR)
+			}
+			if conn.Ipv6CIDR != nil && *conn.Ipv6CIDR != "" {
+				allowedIPs = append(allowedIPs, *conn.Ipv6CIDR)
+			}
+		}
+	}
+
+	return clientConfig{
+		PrivateKey:       privateKey,
+		Addresses:        addresses,
+		GatewayPublicKey: gateway.Metadata.PublicKey,
+		Endpoint:         net.JoinHostPort(gateway.Properties.GatewayIP, strconv.Itoa(port)),
+		AllowedIPs:       allowedIPs,
+	}
+}
+
+func (c clientConfig) String() string {
+	var sb strings.Builder
+	sb.WriteString("[Interface]\n")
+	fmt.Fprintf(&sb, "PrivateKey = %s\n", c.PrivateKey)
+	fmt.Fprintf(&sb, "Address = %s\n", strings.Join(c.Addresses, ", "))
+	if len(c.DNS) > 0 {
+		fmt.Fprintf(&sb, "DNS = %s\n", strings.Join(c.DNS, ", "))
+	}
+
+	sb.WriteString("\n[Peer]\n")
+	fmt.Fprintf(&sb, "PublicKey = %s\n", c.GatewayPublicKey)
+	fmt.Fprintf(&sb, "Endpoint = %s\n", c.Endpoint)
+	fmt.Fprintf(&sb, "AllowedIPs = %s\n", strings.Join(c.AllowedIPs, ", "))
+	if c.PersistentKeepalive > 0 {
+		fmt.Fprintf(&sb, "PersistentKeepalive = %d\n", c.PersistentKeepalive)
+	}
+	return sb.String()
+}
+
+var invalidInterfaceChars = regexp.MustCompile(`[^a-zA-Z0-9_=+.-]+`)
+
+// defaultConfigFile derives the config file name from the peer name. wg-quick names the interface
+// after the file, which allows at most 15 characters from a limited set.
+func defaultConfigFile(peerName string) string {
+	name := strings.Trim(invalidInterfaceChars.ReplaceAllString(peerName, "-"), "-")
+	if len(name) > 15 {
+		name = name[:15]
+	}
+	if name == "" {
+		name = "wg0"
+	}
+	return name + ".conf"
+}
@@ -0,0 +1,79 @@
+package peer
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/sdk-go-bundle/products/vpn/v2"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/curve25519"
+)
+
+func TestGenerateKeyPair(t *testing.T) {
+	private, public, err := generateKeyPair()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(private)
+	if !assert.NoError(t, err) || !assert.Len(t, key, 32) {
+		return
+	}
+	assert.Zero(t, key[0]&7)
+	assert.Equal(t, byte(64), key[31]&192)
+
+	pub, _ := curve25519.X25519(key, curve25519.Basepoint)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(pub), public)
+
+	other, _, _ := generateKeyPair()
+	assert.NotEqual(t, private, other)
+}
+
+func TestClientConfig(t *testing.T) {
+	gateway := vpn.WireguardGatewayRead{
+		Metadata: vpn.WireguardGatewayMetadata{PublicKey: "gatewayPublicKey="},
+		Properties: vpn.WireguardGateway{
+			GatewayIP:         "203.0.113.10",
+			InterfaceIPv4CIDR: pointer.From("10.7.222.1/24"),
+			Connections: []vpn.Connection{
+				{Ipv4CIDR: "192.168.1.0/24", Ipv6CIDR: pointer.From("2001:db8::/64")},
+			},
+		},
+	}
+
+	conf := newClientConfig("privateKey=", []string{"10.7.222.2/32"}, gateway, nil)
+	conf.DNS = []string{"192.168.1.53"}
+	conf.PersistentKeepalive = 25
+	assert.Equal(t, `[Interface]
+PrivateKey = privateKey=
+Address = 10.7.222.2/32
+DNS = 192.168.1.53
+
+[Peer]
+PublicKey = gatewayPublicKey=
+Endpoint = 203.0.113.10:51820
+AllowedIPs = 10.7.222.1/24, 192.168.1.0/24, 2001:db8::/64
+PersistentKeepalive = 25
+`, conf.String())
+
+	gateway.Properties.GatewayIP = "2001:db8::10"
+	gateway.Properties.ListenPort = pointer.From(int32(51000))
+	conf = newClientConfig("privateKey=", []string{"10.7.222.2/32"}, gateway, []string{"0.0.0.0/0", "::/0"})
+	assert.Equal(t, `[Interface]
+PrivateKey = privateKey=
+Address = 10.7.222.2/32
+
+[Peer]
+PublicKey = gatewayPublicKey=
+Endpoint = [2001:db8::10]:51000
+AllowedIPs = 0.0.0.0/0, ::/0
+`, conf.String())
+}
+
+func TestDefaultConfigFile(t *testing.T) {
+	assert.Equal(t, "laptop.conf", defaultConfigFile("laptop"))
+	assert.Equal(t, "Anna-s-Phone.conf", defaultConfigFile("Anna's Phone"))
+	assert.Equal(t, "a-very-long-pee.conf", defaultConfigFile("a very long peer name"))
+	assert.Equal(t, "wg0.conf", defaultConfigFile("???"))
+}
@@ -2,6 +2,10 @@ package peer
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
 
 	"github.com/ionos-cloud/ionosctl/v6/commands/vpn/wireguard/completer"
 
@@ -9,11 +13,21 @@ import (
 	"github.com/ionos-cloud/ionosctl/v6/internal/constants"
 	"github.com/ionos-cloud/ionosctl/v6/internal/core"
 	"github.com/ionos-cloud/ionosctl/v6/pkg/pointer"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/qrcode"
 	"github.com/ionos-cloud/sdk-go-bundle/products/vpn/v2"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+const (
+	FlagGenerateKeys        = "generate-keys"
+	FlagConfigFile          = "config-file"
+	FlagDNS                 = "dns"
+	FlagClientAllowedIPs    = "client-allowed-ips"
+	FlagPersistentKeepalive = "persistent-keepalive"
+	FlagQR                  = "qr"
+)
+
 func Create() *core.Command {
 	cmd := core.NewCommand(context.Background(), nil, core.CommandBuilder{
 		Namespace: "vpn",
@@ -21,12 +35,24 @@ func Create() *core.Command {
 		Verb:      "create",
 		Aliases:   []string{"c", "post"},
 		ShortDesc: "Create a WireGuard Peer",
-		LongDesc:  "Create WireGuard Peers. There is a limit to the total number of peers. Please refer to product documentation",
-		Example:   "ionosctl vpn wireguard peer create " + core.FlagsUsage(constants.FlagGatewayID, constants.FlagName, constants.FlagIps, constants.FlagPublicKey, constants.FlagHost),
+		LongDesc: "Create WireGuard Peers. There is a limit to the total number of peers. Please refer to product documentation\n\n" +
+			"With --" + FlagGenerateKeys + ", the key pair of the peer is generated locally instead of passing --" + constants.FlagPublicKey + ". " +
+			"Only the public key is sent to the API. The private key is written to a wg-quick configuration file, " +
+			"together with the endpoint and public key of the gateway, which can be used with 'wg-quick up' directly. " +
+			"--" + constants.FlagHost + " is optional in this case, for peers without a fixed address such as laptops and phones. " +
+			"Use --" + FlagQR + " to print the configuration as a QR code for the WireGuard mobile apps.",
+		Example: "ionosctl vpn wireguard peer create " + core.FlagsUsage(constants.FlagGatewayID, constants.FlagName, constants.FlagIps, constants.FlagPublicKey, constants.FlagHost) + "\n" +
+			"ionosctl vpn wireguard peer create " + core.FlagsUsage(constants.FlagGatewayID, constants.FlagName, constants.FlagIps, FlagGenerateKeys) + " --dns 10.0.0.1 --qr",
 		PreCmdRun: func(c *core.PreCommandConfig) error {
-			return c.CheckRequiredFlagsAndLocation(
-				constants.FlagGatewayID, constants.FlagName, constants.FlagIps, constants.FlagPublicKey, constants.FlagHost,
-			)
+			if !viper.GetBool(core.GetFlagName(c.NS, FlagGenerateKeys)) {
+				return c.CheckRequiredFlagsAndLocation(
+					constants.FlagGatewayID, constants.FlagName, constants.FlagIps, constants.FlagPublicKey, constants.FlagHost,
+				)
+			}
+			if viper.IsSet(core.GetFlagName(c.NS, constants.FlagPublicKey)) {
+				return fmt.Errorf("--%s and --%s are mutually exclusive", FlagGenerateKeys, constants.FlagPublicKey)
+			}
+			return c.CheckRequiredFlagsAndLocation(constants.FlagGatewayID, constants.FlagName, constants.FlagIps)
 		},
 		CmdRun: func(c *core.CommandConfig) error {
 			input := vpn.WireguardPeer{}
@@ -47,17 +73,20 @@ func Create() *core.Command {
 				input.PublicKey = viper.GetString(fn)
 			}
 
-			input.Endpoint = &vpn.WireguardEndpoint{}
 			if fn := core.GetFlagName(c.NS, constants.FlagHost); viper.IsSet(fn) {
-				input.Endpoint.Host = viper.GetString(fn)
+				input.Endpoint = &vpn.WireguardEndpoint{Host: viper.GetString(fn)}
+				if fn := core.GetFlagName(c.NS, constants.FlagPort); viper.IsSet(fn) {
+					input.Endpoint.Port = pointer.From(viper.GetInt32(fn))
+				}
 			}
 
-			if fn := core.GetFlagName(c.NS, constants.FlagPort); viper.IsSet(fn) {
-				input.Endpoint.Port = pointer.From(viper.GetInt32(fn))
+			gatewayID := viper.GetString(core.GetFlagName(c.NS, constants.FlagGatewayID))
+			if viper.GetBool(core.GetFlagName(c.NS, FlagGenerateKeys)) {
+				return createWithGeneratedKeys(c, gatewayID, input)
 			}
 
 			peer, _, err := client.Must().VPNClient.WireguardPeersApi.
-				WireguardgatewaysPeersPost(context.Background(), viper.GetString(core.GetFlagName(c.NS, constants.FlagGatewayID))).
+				WireguardgatewaysPeersPost(context.Background(), gatewayID).
 				WireguardPeerCreate(vpn.WireguardPeerCreate{Properties: input}).Execute()
 			if err != nil {
 				return err
@@ -78,12 +107,79 @@ func Create() *core.Command {
 	cmd.Command.RegisterFlagCompletionFunc(constants.FlagIps, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"::/0"}, cobra.ShellCompDirectiveNoFileComp
 	})
-	cmd.AddStringFlag(constants.FlagPublicKey, "", "", "Public key of the connecting peer", core.RequiredFlagOption())
-	cmd.AddStringFlag(constants.FlagHost, "", "", "Hostname or IPV4 address that the WireGuard Server will connect to", core.RequiredFlagOption())
+	cmd.AddStringFlag(constants.FlagPublicKey, "", "", "Public key of the connecting peer. Required unless --"+FlagGenerateKeys+" is set")
+	cmd.AddStringFlag(constants.FlagHost, "", "", "Hostname or IPV4 address that the WireGuard Server will connect to. Required unless --"+FlagGenerateKeys+" is set")
 	cmd.AddIntFlag(constants.FlagPort, "", 51820, "Port that the WireGuard Server will connect to")
 
+	cmd.AddBoolFlag(FlagGenerateKeys, "", false, "Generate the key pair of the peer locally and write a wg-quick configuration file for it")
+	cmd.AddStringFlag(FlagConfigFile, "", "", "Path of the configuration file written with --"+FlagGenerateKeys+". Defaults to the peer name with the .conf extension. An existing file is not overwritten")
+	cmd.AddStringSliceFlag(FlagDNS, "", []string{}, "DNS servers for the configuration file written with --"+FlagGenerateKeys)
+	cmd.AddStringSliceFlag(FlagClientAllowedIPs, "", []string{}, "Subnets the peer routes through the tunnel, for the configuration file written with --"+FlagGenerateKeys+". Defaults to the interface subnets of the gateway and the subnets of its LAN connections. Specify \"0.0.0.0/0\" to route all traffic")
+	cmd.AddIntFlag(FlagPersistentKeepalive, "", 25, "Interval in seconds of keepalive packets sent by the peer, for the configuration file written with --"+FlagGenerateKeys+". Keeps connections through NAT open. Set to 0 to disable")
+	cmd.AddBoolFlag(FlagQR, "", false, "Also print the configuration file written with --"+FlagGenerateKeys+" as a QR code, for the WireGuard mobile apps")
+
 	cmd.Command.SilenceUsage = true
 	cmd.Command.Flags().SortFlags = false
 
 	return cmd
 }
+
+// createWithGeneratedKeys creates the peer with a new public key and writes the configuration file
+// with the matching private key. The gateway is read and the file is created before the peer, so
+// that the private key can not get lost if either fails.
+func createWithGeneratedKeys(c *core.CommandConfig, gatewayID string, input vpn.WireguardPeer) error {
+	gateway, _, err := client.Must().VPNClient.WireguardGatewaysApi.WireguardgatewaysFindById(context.Background(), gatewayID).Execute()
+	if err != nil {
+		return fmt.Errorf("failed getting gateway by id %s: %w", gatewayID, err)
+	}
+
+	privateKey, publicKey, err := generateKeyPair()
+	if err != nil {
+		return err
+	}
+	input.PublicKey = publicKey
+
+	conf := newClientConfig(privateKey, input.AllowedIPs, gateway,
+		viper.GetStringSlice(core.GetFlagName(c.NS, FlagClientAllowedIPs)))
+	conf.DNS = viper.GetStringSlice(core.GetFlagName(c.NS, FlagDNS))
+	conf.PersistentKeepalive = viper.GetInt(core.GetFlagName(c.NS, FlagPersistentKeepalive))
+
+	path := viper.GetString(core.GetFlagName(c.NS, FlagConfigFile))
+	if path == "" {
+		path = defaultConfigFile(input.Name)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("configuration file %s already exists, choose another one with --%s", path, FlagConfigFile)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	peer, _, err := client.Must().VPNClient.WireguardPeersApi.
+		WireguardgatewaysPeersPost(context.Background(), gatewayID).
+		WireguardPeerCreate(vpn.WireguardPeerCreate{Properties: input}).Execute()
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+
+	if _, err := f.WriteString(conf.String()); err != nil {
+		return fmt.Errorf("peer %s was created, but writing its configuration failed. Delete the peer and create it again: %w", peer.Id, err)
+	}
+	fmt.Fprintf(c.Command.Command.ErrOrStderr(), "Wrote the configuration of peer %s to %s\n", input.Name, path)
+
+	if viper.GetBool(core.GetFlagName(c.NS, FlagQR)) {
+		code, err := qrcode.Encode([]byte(conf.String()), qrcode.Low)
+		if err != nil {
+			return err
+		}
+		if err := code.WriteTerminal(c.Command.Command.ErrOrStderr()); err != nil {
+			return err
+		}
+	}
+
+	return c.Printer(allCols).Print(peer)
+}
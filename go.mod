@@ -4,6 +4,7 @@ go 1.25.10
 
 require (
 	github.com/Jeffail/gabs/v2 v2.7.0
+	github.com/aws/aws-sdk-go v1.55.8
 	github.com/cheggaaa/pb/v3 v3.1.7
 	github.com/cilium/fake v0.7.0
 	github.com/dustin/go-humanize v1.0.1
@@ -31,7 +32,6 @@ require (
 	github.com/ionos-cloud/sdk-go/v6 v6.3.11
 	github.com/ionoscloudsdk/comptplus v1.1.4
 	github.com/jmespath/go-jmespath v0.4.0
-	github.com/kardianos/ftps v1.0.4
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/spf13/cobra v1.10.2
 	github.com/spf13/pflag v1.0.10
@@ -46,7 +46,6 @@ require (
 
 require (
 	github.com/VividCortex/ewma v1.2.0 // indirect
-	github.com/aws/aws-sdk-go v1.55.8 // indirect
 	github.com/cenkalti/backoff/v5 v5.0.3 // indirect
 	github.com/clipperhouse/uax29/v2 v2.7.0 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.7 // indirect
@@ -165,6 +165,9 @@ Within each layer, a token takes precedence over a username and password combina
 	FlagStorageType           = "storage-type"
 	FlagStorageSize           = "storage-size"
 	FlagServerType            = "server-type"
+	FlagCount                 = "count"
+	FlagNameTemplate          = "name-template"
+	FlagUserDataFile          = "user-data-file"
 
 	FlagClusterId         = "cluster-id"
 	FlagUserId            = "user-id"
@@ -387,6 +390,7 @@ const (
 const (
 	DatacenterId              = "Datacenter ID: %v"
 	ApplicationLoadBalancerId = "Application Load Balancer ID: %v"
+	NetworkLoadBalancerId     = "Network Load Balancer ID: %v"
 	TargetGroupId             = "Target Group ID: %v"
 	ClusterId                 = "Cluster ID: %v"
 	ForwardingRuleId          = "Forwarding Rule ID: %v"
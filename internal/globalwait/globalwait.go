@@ -393,14 +393,7 @@ func (w *Waiter) WaitForAvailable(wr io.Writer, token, username, password string
 		return nil
 	}
 
-	timeoutSec := viper.GetInt(constants.ArgTimeout)
-	if timeoutSec <= 0 {
-		fmt.Fprintf(wr, "Warning: --timeout %d is not supported, using default %ds\n", timeoutSec, constants.DefaultTimeoutSeconds)
-		timeoutSec = constants.DefaultTimeoutSeconds
-	}
-	timeout := time.Duration(timeoutSec) * time.Second
-
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout(wr))
 	defer cancel()
 
 	if n := w.getCaptureCount(); n > 1 {
@@ -447,6 +440,77 @@ func (w *Waiter) WaitForAvailable(wr io.Writer, token, username, password string
 	return nil
 }
 
+// WaitForHrefs polls each of the hrefs, and their parents, until they reach a terminal ready state.
+// The hrefs are polled concurrently under a single progress bar. Used by commands that create several
+// resources in one run (e.g. server create --count), as the captured state only tracks one resource.
+func (w *Waiter) WaitForHrefs(wr io.Writer, hrefs []string, token, username, password string) error {
+	if len(hrefs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), waitTimeout(wr))
+	defer cancel()
+
+	p := w.newPoller(token, username, password)
+
+	var bar *pb.ProgressBar
+	if !isStructuredOutput() {
+		bar = pb.New(len(hrefs))
+		bar.SetWriter(wr)
+		bar.SetTemplateString(ProgressTpl + ` {{ counters . }}`)
+		bar.Start()
+		defer bar.Finish()
+	}
+
+	errs := make([]error, len(hrefs))
+	var wg sync.WaitGroup
+	for i, href := range hrefs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, url := range resourceAndParentURLs(href) {
+				if errs[i] = p.poll(ctx, buildFullURL(url), false); errs[i] != nil {
+					return
+				}
+			}
+			if bar != nil {
+				bar.Increment()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var failed []error
+	for _, err := range errs {
+		var pf *provisioningFailure
+		if errors.As(err, &pf) {
+			fmt.Fprintf(wr, "Warning: %v\n", pf)
+			continue
+		}
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	if bar != nil {
+		if len(failed) > 0 {
+			bar.SetTemplateString(ProgressTpl + " FAILED")
+		} else {
+			bar.SetTemplateString(ProgressTpl + " DONE")
+		}
+	}
+	return errors.Join(failed...)
+}
+
+// waitTimeout returns the --timeout for polling, or the default if it is not supported.
+func waitTimeout(wr io.Writer) time.Duration {
+	timeoutSec := viper.GetInt(constants.ArgTimeout)
+	if timeoutSec <= 0 {
+		fmt.Fprintf(wr, "Warning: --timeout %d is not supported, using default %ds\n", timeoutSec, constants.DefaultTimeoutSeconds)
+		timeoutSec = constants.DefaultTimeoutSeconds
+	}
+	return time.Duration(timeoutSec) * time.Second
+}
+
 // WrapTransport wraps an http.Client's Transport so that every response URL
 // is captured for --wait polling. This makes delete/detach commands work
 // across all SDK clients without per-command changes.
@@ -526,6 +590,11 @@ func WaitForAvailable(w io.Writer, token, username, password string) error {
 	return defaultWaiter.WaitForAvailable(w, token, username, password)
 }
 
+// WaitForHrefs polls the hrefs concurrently, using the transport of the default Waiter.
+func WaitForHrefs(w io.Writer, hrefs []string, token, username, password string) error {
+	return defaultWaiter.WaitForHrefs(w, hrefs, token, username, password)
+}
+
 // WrapTransport wraps an http.Client's Transport on the default Waiter.
 func WrapTransport(hc *http.Client) { defaultWaiter.WrapTransport(hc) }
 
@@ -2206,3 +2206,57 @@ func TestDelegate_WrapTransport(t *testing.T) {
 	assert.Equal(t, defaultWaiter, ct.waiter, "should wire to defaultWaiter")
 	defaultWaiter.Reset()
 }
+
+func TestWaitForHrefs_PollsAll(t *testing.T) {
+	var mu sync.Mutex
+	polled := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polled[r.URL.Path]++
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]any{"metadata": map[string]any{"state": "AVAILABLE"}})
+	}))
+	defer server.Close()
+	fastpollURL(t)
+
+	viper.Set(constants.ArgTimeout, 5)
+	viper.Set(constants.ArgOutput, "text")
+	defer func() {
+		viper.Set(constants.ArgTimeout, 0)
+		viper.Set(constants.ArgOutput, "")
+	}()
+
+	dc := server.URL + "/datacenters/aaaaaaaa-1111-2222-3333-444444444444"
+	hrefs := []string{
+		dc + "/servers/bbbbbbbb-1111-2222-3333-444444444444",
+		dc + "/servers/cccccccc-1111-2222-3333-444444444444",
+	}
+	var buf bytes.Buffer
+	err := (&Waiter{}).WaitForHrefs(&buf, hrefs, "", "", "")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "DONE")
+	assert.Equal(t, 1, polled["/datacenters/aaaaaaaa-1111-2222-3333-444444444444/servers/bbbbbbbb-1111-2222-3333-444444444444"])
+	assert.Equal(t, 1, polled["/datacenters/aaaaaaaa-1111-2222-3333-444444444444/servers/cccccccc-1111-2222-3333-444444444444"])
+	assert.Equal(t, 2, polled["/datacenters/aaaaaaaa-1111-2222-3333-444444444444"])
+}
+
+func TestWaitForHrefs_FailedResourceWarns(t *testing.T) {
+	server := stateServer("FAILED")
+	defer server.Close()
+	fastpollURL(t)
+
+	viper.Set(constants.ArgTimeout, 5)
+	defer viper.Set(constants.ArgTimeout, 0)
+
+	var buf bytes.Buffer
+	err := (&Waiter{}).WaitForHrefs(&buf, []string{server.URL + "/datacenters/aaaaaaaa-1111-2222-3333-444444444444"}, "", "", "")
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Warning:")
+}
+
+func TestWaitForHrefs_NoHrefs(t *testing.T) {
+	var buf bytes.Buffer
+	err := (&Waiter{}).WaitForHrefs(&buf, nil, "", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,319 @@
+// Package ftps implements the subset of FTP over explicit TLS (RFC 4217) needed to upload images:
+// listing a directory, resuming an upload with SIZE and REST (RFC 3659), and asking the server for
+// the hash of a file with HASH (draft-bryan-ftpext-hash).
+package ftps
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is returned by Size if the file does not exist on the server.
+var ErrNotFound = errors.New("file not found")
+
+// ErrHashUnsupported is returned by Hash if the server does not advertise the HASH command.
+var ErrHashUnsupported = errors.New("server does not support the HASH command")
+
+// DialOptions for the FTPS client.
+type DialOptions struct {
+	Host     string
+	Port     int // Defaults to 21
+	Username string
+	Password string
+
+	TLSConfig *tls.Config
+}
+
+// Client is a connection to an FTPS server. It is not safe for concurrent use.
+type Client struct {
+	conn     net.Conn
+	tc       *textproto.Conn
+	opt      DialOptions
+	features map[string]string
+}
+
+// Dial connects to the server, upgrades the connection to TLS with AUTH TLS, and logs in.
+func Dial(ctx context.Context, opt DialOptions) (*Client, error) {
+	if opt.Port <= 0 {
+		opt.Port = 21
+	}
+	tlsConfig := &tls.Config{}
+	if opt.TLSConfig != nil {
+		tlsConfig = opt.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = opt.Host
+	}
+	// Servers often require the data connections to resume the TLS session of the control connection.
+	if tlsConfig.ClientSessionCache == nil {
+		tlsConfig.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+	opt.TLSConfig = tlsConfig
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(opt.Host, strconv.Itoa(opt.Port)))
+	if err != nil {
+		return nil, fmt.Errorf("ftps: dial failed: %w", err)
+	}
+	c := &Client{conn: conn, tc: textproto.NewConn(conn), opt: opt}
+	if err := c.setup(ctx); err != nil {
+		c.tc.Close()
+		return nil, fmt.Errorf("ftps: connection setup failed: %w", err)
+	}
+	return c, nil
+}
+
+func (c *Client) setup(ctx context.Context) error {
+	if _, err := c.read(220); err != nil {
+		return err
+	}
+	if _, err := c.cmd(234, "AUTH TLS"); err != nil {
+		return err
+	}
+	secure := tls.Client(c.conn, c.opt.TLSConfig)
+	if err := secure.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	c.conn = secure
+	c.tc = textproto.NewConn(secure)
+
+	code, msg, err := c.send("USER %s", c.opt.Username)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case 230:
+	case 331:
+		if _, err := c.cmd(230, "PASS %s", c.opt.Password); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("USER failed: %d %s", code, msg)
+	}
+	for _, cmd := range []string{"TYPE I", "PBSZ 0", "PROT P"} {
+		if _, err := c.cmd(200, "%s", cmd); err != nil {
+			return err
+		}
+	}
+
+	// FEAT is optional, servers without it simply have no features.
+	c.features = map[string]string{}
+	if code, msg, err := c.send("FEAT"); err == nil && code == 211 {
+		// Features are listed one per line, indented by a space (RFC 2389).
+		for _, line := range strings.Split(msg, "\n") {
+			if !strings.HasPrefix(line, " ") {
+				continue
+			}
+			name, params, _ := strings.Cut(strings.TrimSpace(line), " ")
+			c.features[strings.ToUpper(name)] = params
+		}
+	}
+	return nil
+}
+
+// send sends a command and returns the code and message of the reply.
+func (c *Client) send(format string, args ...any) (int, string, error) {
+	if _, err := c.tc.Cmd(format, args...); err != nil {
+		return 0, "", err
+	}
+	code, msg, err := c.tc.ReadResponse(0)
+	if err != nil {
+		return 0, "", err
+	}
+	return code, msg, nil
+}
+
+// cmd sends a command and fails unless the reply has the expected code. A one digit code only
+// checks the class of the reply, e.g. 2 for any success.
+func (c *Client) cmd(expectCode int, format string, args ...any) (string, error) {
+	if _, err := c.tc.Cmd(format, args...); err != nil {
+		return "", err
+	}
+	return c.read(expectCode)
+}
+
+func (c *Client) read(expectCode int) (string, error) {
+	code, msg, err := c.tc.ReadResponse(expectCode)
+	if err != nil {
+		return "", fmt.Errorf("unexpected reply %d %s: %w", code, msg, err)
+	}
+	return msg, nil
+}
+
+// Feature returns the parameters of a feature advertised by the server with FEAT.
+func (c *Client) Feature(name string) (string, bool) {
+	params, ok := c.features[strings.ToUpper(name)]
+	return params, ok
+}
+
+// Chdir changes the working directory.
+func (c *Client) Chdir(dir string) error {
+	if _, err := c.cmd(250, "CWD %s", dir); err != nil {
+		return fmt.Errorf("ftps: CWD %s failed: %w", dir, err)
+	}
+	return nil
+}
+
+// List returns the names of the files in the working directory.
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	data, err := c.data(ctx, "NLST")
+	if err != nil {
+		return nil, fmt.Errorf("ftps: NLST failed: %w", err)
+	}
+	var names []string
+	scanner := bufio.NewScanner(data)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			names = append(names, name)
+		}
+	}
+	data.Close()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ftps: NLST failed: %w", err)
+	}
+	if _, err := c.read(2); err != nil {
+		return nil, fmt.Errorf("ftps: NLST failed: %w", err)
+	}
+	return names, nil
+}
+
+// Size returns the size of the file in bytes, or ErrNotFound if it does not exist.
+func (c *Client) Size(name string) (int64, error) {
+	code, msg, err := c.send("SIZE %s", name)
+	if err != nil {
+		return 0, fmt.Errorf("ftps: SIZE %s failed: %w", name, err)
+	}
+	switch code {
+	case 213:
+		size, err := strconv.ParseInt(strings.TrimSpace(msg), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("ftps: SIZE %s returned %q: %w", name, msg, err)
+		}
+		return size, nil
+	case 550:
+		return 0, ErrNotFound
+	default:
+		return 0, fmt.Errorf("ftps: SIZE %s failed: %d %s", name, code, msg)
+	}
+}
+
+// Store uploads r to the file in the working directory. If offset is greater than 0, the server
+// writes r from the offset on with REST, so an interrupted upload can be resumed.
+func (c *Client) Store(ctx context.Context, name string, offset int64, r io.Reader) error {
+	if offset > 0 {
+		if _, err := c.cmd(350, "REST %d", offset); err != nil {
+			return fmt.Errorf("ftps: REST %d failed: %w", offset, err)
+		}
+	}
+	data, err := c.data(ctx, "STOR %s", name)
+	if err != nil {
+		return fmt.Errorf("ftps: STOR %s failed: %w", name, err)
+	}
+	if _, err := io.Copy(data, r); err != nil {
+		data.Close()
+		return fmt.Errorf("ftps: STOR %s failed: %w", name, err)
+	}
+	if err := data.Close(); err != nil {
+		return fmt.Errorf("ftps: STOR %s failed: %w", name, err)
+	}
+	if _, err := c.read(2); err != nil {
+		return fmt.Errorf("ftps: STOR %s failed: %w", name, err)
+	}
+	return nil
+}
+
+// Hash returns the hex encoded SHA-256 of the file, computed by the server, or ErrHashUnsupported.
+func (c *Client) Hash(name string) (string, error) {
+	algorithms, ok := c.Feature("HASH")
+	if !ok || !strings.Contains(strings.ToUpper(algorithms), "SHA-256") {
+		return "", ErrHashUnsupported
+	}
+	if !strings.Contains(algorithms, "SHA-256*") {
+		if _, err := c.cmd(200, "OPTS HASH SHA-256"); err != nil {
+			return "", fmt.Errorf("ftps: OPTS HASH SHA-256 failed: %w", err)
+		}
+	}
+	msg, err := c.cmd(213, "HASH %s", name)
+	if err != nil {
+		return "", fmt.Errorf("ftps: HASH %s failed: %w", name, err)
+	}
+	// The reply is: <algorithm> <start>-<end> <hash> <name>
+	fields := strings.Fields(msg)
+	if len(fields) < 3 || !strings.EqualFold(fields[0], "SHA-256") {
+		return "", fmt.Errorf("ftps: HASH %s returned %q", name, msg)
+	}
+	return strings.ToLower(fields[2]), nil
+}
+
+// Close logs out and closes the connection.
+func (c *Client) Close() error {
+	_, err := c.cmd(221, "QUIT")
+	return errors.Join(err, c.tc.Close())
+}
+
+// data opens a passive data connection, sends the command, and returns the TLS data connection.
+// The connection is closed when the context is done.
+func (c *Client) data(ctx context.Context, format string, args ...any) (io.ReadWriteCloser, error) {
+	msg, err := c.cmd(227, "PASV")
+	if err != nil {
+		return nil, err
+	}
+	port, err := parsePasv(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The address of the reply is ignored, as servers behind NAT often reply with a private one.
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.opt.Host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, fmt.Errorf("dialing data connection failed: %w", err)
+	}
+	if _, err := c.cmd(1, format, args...); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	secure := tls.Client(conn, c.opt.TLSConfig)
+	if err := secure.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("data connection TLS handshake failed: %w", err)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	return &dataConn{Conn: secure, stop: stop}, nil
+}
+
+type dataConn struct {
+	*tls.Conn
+	stop func() bool
+}
+
+func (d *dataConn) Close() error {
+	d.stop()
+	return d.Conn.Close()
+}
+
+// parsePasv returns the port of a reply to PASV: Entering Passive Mode (h1,h2,h3,h4,p1,p2).
+func parsePasv(msg string) (int, error) {
+	start, end := strings.Index(msg, "("), strings.LastIndex(msg, ")")
+	if start < 0 || end < start {
+		return 0, fmt.Errorf("invalid PASV reply %q", msg)
+	}
+	parts := strings.Split(msg[start+1:end], ",")
+	if len(parts) != 6 {
+		return 0, fmt.Errorf("invalid PASV reply %q", msg)
+	}
+	p1, err1 := strconv.Atoi(strings.TrimSpace(parts[4]))
+	p2, err2 := strconv.Atoi(strings.TrimSpace(parts[5]))
+	if err1 != nil || err2 != nil || p1 < 0 || p1 > 255 || p2 < 0 || p2 > 255 {
+		return 0, fmt.Errorf("invalid PASV reply %q", msg)
+	}
+	return p1*256 + p2, nil
+}
@@ -0,0 +1,97 @@
+package ftps_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"testing"
+
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ftps"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ftps/ftpstest"
+	"github.com/stretchr/testify/assert"
+)
+
+func dial(t *testing.T, s *ftpstest.Server) *ftps.Client {
+	t.Helper()
+	c, err := ftps.Dial(context.Background(), ftps.DialOptions{
+		Host: s.Host, Port: s.Port, Username: "user", Password: "pass",
+		TLSConfig: &tls.Config{RootCAs: s.RootCAs},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return c
+}
+
+func TestStoreAndList(t *testing.T) {
+	s := ftpstest.NewServer(t, "hdd-images")
+	s.SetFile("hdd-images/old.qcow2", []byte("old"))
+	c := dial(t, s)
+	ctx := context.Background()
+
+	assert.Error(t, c.Chdir("iso-images"))
+	assert.NoError(t, c.Chdir("hdd-images"))
+
+	data := bytes.Repeat([]byte("image"), 1000)
+	assert.NoError(t, c.Store(ctx, "disk.qcow2", 0, bytes.NewReader(data)))
+
+	names, err := c.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"disk.qcow2", "old.qcow2"}, names)
+
+	size, err := c.Size("disk.qcow2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(data)), size)
+	_, err = c.Size("missing.qcow2")
+	assert.ErrorIs(t, err, ftps.ErrNotFound)
+
+	assert.NoError(t, c.Close())
+	stored, _ := s.File("hdd-images/disk.qcow2")
+	assert.Equal(t, data, stored)
+}
+
+func TestStoreResume(t *testing.T) {
+	s := ftpstest.NewServer(t, "hdd-images")
+	c := dial(t, s)
+	ctx := context.Background()
+	assert.NoError(t, c.Chdir("hdd-images"))
+
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+	s.InterruptNextUpload(4096)
+	assert.Error(t, c.Store(ctx, "disk.qcow2", 0, bytes.NewReader(data)))
+
+	// The control connection is still usable after the data connection dropped.
+	size, err := c.Size("disk.qcow2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), size)
+
+	assert.NoError(t, c.Store(ctx, "disk.qcow2", size, bytes.NewReader(data[size:])))
+	stored, _ := s.File("hdd-images/disk.qcow2")
+	assert.Equal(t, data, stored)
+}
+
+func TestHash(t *testing.T) {
+	s := ftpstest.NewServer(t, "hdd-images")
+	data := []byte("image")
+	s.SetFile("hdd-images/disk.qcow2", data)
+	sum := sha256.Sum256(data)
+
+	c := dial(t, s)
+	assert.NoError(t, c.Chdir("hdd-images"))
+	hash, err := c.Hash("disk.qcow2")
+	assert.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+
+	s.DisableHash()
+	c = dial(t, s)
+	_, err = c.Hash("disk.qcow2")
+	assert.ErrorIs(t, err, ftps.ErrHashUnsupported)
+}
+
+func TestDialUntrusted(t *testing.T) {
+	s := ftpstest.NewServer(t)
+	_, err := ftps.Dial(context.Background(), ftps.DialOptions{Host: s.Host, Port: s.Port})
+	assert.Error(t, err)
+}
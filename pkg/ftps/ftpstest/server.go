@@ -0,0 +1,347 @@
+// Package ftpstest provides an in-memory FTPS server for tests, as a stand-in for the IONOS FTP servers.
+package ftpstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/textproto"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Server is an FTPS server with explicit TLS that keeps its files in memory. It supports the commands
+// used by the ftps package: AUTH TLS, USER, PASS, TYPE, PBSZ, PROT, FEAT, CWD, PASV, NLST, SIZE, REST,
+// STOR, OPTS HASH, HASH and QUIT.
+type Server struct {
+	Host string
+	Port int
+	// RootCAs trusts the self-signed certificate of the server.
+	RootCAs *x509.CertPool
+
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	mu            sync.Mutex
+	dirs          map[string]bool
+	files         map[string][]byte
+	hash          bool
+	interruptNext int64
+}
+
+// NewServer starts a server on the loopback interface with the given directories. It is closed
+// when the test ends.
+func NewServer(t testing.TB, dirs ...string) *Server {
+	t.Helper()
+	cert, pool, err := selfSigned()
+	if err != nil {
+		t.Fatalf("ftpstest: generating certificate: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ftpstest: listening: %v", err)
+	}
+	s := &Server{
+		Host:      "127.0.0.1",
+		Port:      l.Addr().(*net.TCPAddr).Port,
+		RootCAs:   pool,
+		listener:  l,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+		dirs:      map[string]bool{},
+		files:     map[string][]byte{},
+		hash:      true,
+	}
+	for _, d := range dirs {
+		s.dirs[strings.Trim(d, "/")] = true
+	}
+	go s.serve()
+	t.Cleanup(func() { l.Close() })
+	return s
+}
+
+// DisableHash stops advertising and answering the HASH command.
+func (s *Server) DisableHash() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hash = false
+}
+
+// InterruptNextUpload closes the data connection of the next STOR after n bytes, like a dropped connection.
+func (s *Server) InterruptNextUpload(n int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.interruptNext = n
+}
+
+// File returns the contents of the file at the path, e.g. hdd-images/disk.qcow2.
+func (s *Server) File(p string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[p]
+	return append([]byte(nil), data...), ok
+}
+
+// SetFile creates or replaces the file at the path.
+func (s *Server) SetFile(p string, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[p] = append([]byte(nil), data...)
+}
+
+func (s *Server) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+type session struct {
+	conn    net.Conn
+	tc      *textproto.Conn
+	dir     string
+	rest    int64
+	passive net.Listener
+}
+
+func (s *Server) handle(conn net.Conn) {
+	sess := &session{conn: conn, tc: textproto.NewConn(conn)}
+	defer func() {
+		if sess.passive != nil {
+			sess.passive.Close()
+		}
+		sess.tc.Close()
+	}()
+
+	sess.reply(220, "ftpstest ready")
+	for {
+		line, err := sess.tc.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd, arg, _ := strings.Cut(line, " ")
+		if !s.command(sess, strings.ToUpper(cmd), arg) {
+			return
+		}
+	}
+}
+
+// command handles one command, and returns false if the session ends.
+func (s *Server) command(sess *session, cmd, arg string) bool {
+	switch cmd {
+	case "AUTH":
+		sess.reply(234, "AUTH TLS successful")
+		secure := tls.Server(sess.conn, s.tlsConfig)
+		if err := secure.Handshake(); err != nil {
+			return false
+		}
+		sess.conn = secure
+		sess.tc = textproto.NewConn(secure)
+	case "USER":
+		sess.reply(331, "password required")
+	case "PASS":
+		sess.reply(230, "logged in")
+	case "TYPE", "PBSZ", "PROT":
+		sess.reply(200, "ok")
+	case "FEAT":
+		features := " SIZE\r\n REST STREAM\r\n"
+		s.mu.Lock()
+		if s.hash {
+			features += " HASH SHA-256;SHA-1\r\n"
+		}
+		s.mu.Unlock()
+		sess.tc.PrintfLine("211-Features:\r\n%s211 End", features)
+	case "CWD":
+		dir := strings.Trim(path.Join(sess.dir, arg), "/")
+		s.mu.Lock()
+		ok := s.dirs[dir]
+		s.mu.Unlock()
+		if !ok {
+			sess.reply(550, "no such directory")
+			break
+		}
+		sess.dir = dir
+		sess.reply(250, "directory changed")
+	case "PASV":
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			sess.reply(425, "cannot open data connection")
+			break
+		}
+		sess.passive = l
+		port := l.Addr().(*net.TCPAddr).Port
+		sess.reply(227, fmt.Sprintf("Entering Passive Mode (127,0,0,1,%d,%d)", port/256, port%256))
+	case "NLST":
+		s.mu.Lock()
+		var names []string
+		for p := range s.files {
+			if path.Dir(p) == sess.dir {
+				names = append(names, path.Base(p))
+			}
+		}
+		s.mu.Unlock()
+		sort.Strings(names)
+		data, ok := s.open(sess)
+		if !ok {
+			break
+		}
+		for _, n := range names {
+			fmt.Fprintf(data, "%s\r\n", n)
+		}
+		data.Close()
+		sess.reply(226, "transfer complete")
+	case "SIZE":
+		s.mu.Lock()
+		data, ok := s.files[path.Join(sess.dir, arg)]
+		s.mu.Unlock()
+		if !ok {
+			sess.reply(550, "no such file")
+			break
+		}
+		sess.reply(213, strconv.Itoa(len(data)))
+	case "REST":
+		offset, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || offset < 0 {
+			sess.reply(501, "invalid offset")
+			break
+		}
+		sess.rest = offset
+		sess.reply(350, "restarting")
+	case "STOR":
+		s.store(sess, path.Join(sess.dir, arg))
+	case "OPTS":
+		sess.reply(200, "ok")
+	case "HASH":
+		s.mu.Lock()
+		data, ok := s.files[path.Join(sess.dir, arg)]
+		hash := s.hash
+		s.mu.Unlock()
+		switch {
+		case !hash:
+			sess.reply(502, "not implemented")
+		case !ok:
+			sess.reply(550, "no such file")
+		default:
+			sum := sha256.Sum256(data)
+			sess.reply(213, fmt.Sprintf("SHA-256 0-%d %s %s", len(data), hex.EncodeToString(sum[:]), arg))
+		}
+	case "QUIT":
+		sess.reply(221, "bye")
+		return false
+	default:
+		sess.reply(502, "not implemented")
+	}
+	return true
+}
+
+func (s *Server) store(sess *session, p string) {
+	offset := sess.rest
+	sess.rest = 0
+	s.mu.Lock()
+	existing := s.files[p]
+	interrupt := s.interruptNext
+	s.interruptNext = 0
+	s.mu.Unlock()
+	if offset > int64(len(existing)) {
+		sess.reply(554, "offset beyond end of file")
+		return
+	}
+
+	data, ok := s.open(sess)
+	if !ok {
+		return
+	}
+	var r io.Reader = data
+	if interrupt > 0 {
+		r = io.LimitReader(data, interrupt)
+	}
+	received, err := io.ReadAll(r)
+	data.Close()
+
+	s.mu.Lock()
+	s.files[p] = append(append([]byte(nil), existing[:offset]...), received...)
+	s.mu.Unlock()
+
+	if err != nil || interrupt > 0 {
+		sess.reply(426, "connection closed, transfer aborted")
+		return
+	}
+	sess.reply(226, "transfer complete")
+}
+
+// open accepts the passive data connection after replying 150.
+func (s *Server) open(sess *session) (net.Conn, bool) {
+	if sess.passive == nil {
+		sess.reply(425, "use PASV first")
+		return nil, false
+	}
+	defer func() {
+		sess.passive.Close()
+		sess.passive = nil
+	}()
+	sess.reply(150, "opening data connection")
+	sess.passive.(*net.TCPListener).SetDeadline(time.Now().Add(10 * time.Second))
+	conn, err := sess.passive.Accept()
+	if err != nil {
+		sess.reply(425, "cannot open data connection")
+		return nil, false
+	}
+	secure := tls.Server(conn, s.tlsConfig)
+	if err := secure.Handshake(); err != nil {
+		conn.Close()
+		sess.reply(425, "TLS handshake failed")
+		return nil, false
+	}
+	return secure, true
+}
+
+func (sess *session) reply(code int, msg string) {
+	sess.tc.PrintfLine("%d %s", code, msg)
+}
+
+// selfSigned returns a certificate for 127.0.0.1 and a pool that trusts it.
+func selfSigned() (tls.Certificate, *x509.CertPool, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ftpstest"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(leaf)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, pool, nil
+}
@@ -0,0 +1,148 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	jksMagic   = 0xfeedfeed
+	jksVersion = 2
+
+	jksPrivateKeyEntry  = 1
+	jksTrustedCertEntry = 2
+
+	// jksWhitener is mixed into the integrity digest of every JKS keystore.
+	jksWhitener = "Mighty Aphrodite"
+)
+
+// oidJKSKeyProtector is Sun's proprietary algorithm which protects the private keys of JKS keystores.
+var oidJKSKeyProtector = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 42, 2, 17, 1, 1}
+
+// JKS encodes a private key and its certificate chain, leaf first, as a JKS keystore with a single entry.
+// JKS is the legacy format of Java keystores, prefer PKCS12 unless a client requires JKS.
+// Java stores aliases in lower case, so the alias is lower cased.
+func JKS(key crypto.PrivateKey, chain []*x509.Certificate, alias, password string) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("the certificate chain is empty")
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	protected, err := protectJKSKey(pkcs8, password)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := asn1.Marshal(encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidJKSKeyProtector, Parameters: asn1.NullRawValue},
+		EncryptedData: protected,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	w := newJKSWriter(1)
+	w.int32(jksPrivateKeyEntry)
+	w.utf(strings.ToLower(alias))
+	w.int64(time.Now().UnixMilli())
+	w.bytes(keyInfo)
+	w.int32(int32(len(chain)))
+	for _, cert := range chain {
+		w.utf("X.509")
+		w.bytes(cert.Raw)
+	}
+	return w.finish(password), nil
+}
+
+// JKSTruststore encodes certificates as trusted entries of a JKS keystore. The first entry is named alias,
+// the others alias-1, alias-2 and so on.
+func JKSTruststore(certs []*x509.Certificate, alias, password string) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates to trust")
+	}
+	w := newJKSWriter(len(certs))
+	for i, cert := range certs {
+		w.int32(jksTrustedCertEntry)
+		w.utf(strings.ToLower(trustAlias(alias, i)))
+		w.int64(time.Now().UnixMilli())
+		w.utf("X.509")
+		w.bytes(cert.Raw)
+	}
+	return w.finish(password), nil
+}
+
+type jksWriter struct {
+	buf bytes.Buffer
+}
+
+func newJKSWriter(entries int) *jksWriter {
+	w := &jksWriter{}
+	w.uint32(jksMagic)
+	w.uint32(jksVersion)
+	w.uint32(uint32(entries))
+	return w
+}
+
+func (w *jksWriter) uint32(v uint32) { _ = binary.Write(&w.buf, binary.BigEndian, v) }
+
+func (w *jksWriter) int32(v int32) { w.uint32(uint32(v)) }
+
+func (w *jksWriter) int64(v int64) { _ = binary.Write(&w.buf, binary.BigEndian, v) }
+
+// utf writes a string like Java's DataOutput.writeUTF. Modified UTF-8 only differs from UTF-8 for NUL and
+// characters outside the BMP, which are not expected in aliases, so they are written as UTF-8.
+func (w *jksWriter) utf(s string) {
+	_ = binary.Write(&w.buf, binary.BigEndian, uint16(len(s)))
+	w.buf.WriteString(s)
+}
+
+func (w *jksWriter) bytes(b []byte) {
+	w.int32(int32(len(b)))
+	w.buf.Write(b)
+}
+
+// finish appends the integrity digest, SHA-1 over the password, the whitener and the keystore.
+func (w *jksWriter) finish(password string) []byte {
+	h := sha1.New()
+	h.Write(utf16BE(password))
+	h.Write([]byte(jksWhitener))
+	h.Write(w.buf.Bytes())
+	return h.Sum(w.buf.Bytes())
+}
+
+// protectJKSKey encrypts a PKCS#8 key like sun.security.provider.KeyProtector: the key is XORed with a
+// key stream of chained SHA-1 digests over the password, and followed by a digest to check the password.
+func protectJKSKey(pkcs8 []byte, password string) ([]byte, error) {
+	passwd := utf16BE(password)
+	salt := make([]byte, sha1.Size)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	out := append([]byte{}, salt...)
+	digest := salt
+	for i := 0; i < len(pkcs8); i += sha1.Size {
+		h := sha1.New()
+		h.Write(passwd)
+		h.Write(digest)
+		digest = h.Sum(nil)
+		for j := 0; j < sha1.Size && i+j < len(pkcs8); j++ {
+			out = append(out, pkcs8[i+j]^digest[j])
+		}
+	}
+
+	check := sha1.New()
+	check.Write(passwd)
+	check.Write(pkcs8)
+	return check.Sum(out), nil
+}
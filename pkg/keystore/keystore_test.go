@@ -0,0 +1,284 @@
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testKeyPair(t *testing.T) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "alice"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, cert
+}
+
+func TestPKCS12KDF(t *testing.T) {
+	// Vectors computed with "openssl kdf PKCS12KDF", which takes the password bytes as is.
+	salt, _ := hex.DecodeString("0001020304050607")
+	got := pkcs12KDF(sha256.New, salt, []byte("s3cret"), 3, 3, 32)
+	assert.Equal(t, "02605c44436ae3ab69d438556c56103ce96f67c0bfec00f60bc129f6c4f6a365", hex.EncodeToString(got))
+
+	salt, _ = hex.DecodeString("0a58cf64530d823f")
+	got = pkcs12KDF(sha1.New, salt, []byte("smeg"), 1, 1, 40)
+	assert.Equal(t, "cdf5d700bdb714fdc96a189db3acc281be29a33e39facb89441acd2ada1e04c598b516c8863711ea", hex.EncodeToString(got))
+}
+
+// decodeSafeContents verifies the MAC of a PKCS#12 keystore and returns its bags.
+func decodeSafeContents(t *testing.T, der []byte, password string) []safeBag {
+	var p pfx
+	if _, err := asn1.Unmarshal(der, &p); err != nil {
+		t.Fatal(err)
+	}
+	var authSafe []byte
+	if _, err := asn1.Unmarshal(p.AuthSafe.Content.Bytes, &authSafe); err != nil {
+		t.Fatal(err)
+	}
+	key := pkcs12KDF(sha256.New, p.MacData.MacSalt, bmpPassword(password), p.MacData.Iterations, 3, 32)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(authSafe)
+	assert.True(t, hmac.Equal(mac.Sum(nil), p.MacData.Mac.Digest), "MAC")
+
+	var infos []contentInfo
+	if _, err := asn1.Unmarshal(authSafe, &infos); err != nil {
+		t.Fatal(err)
+	}
+	var bags []safeBag
+	for _, info := range infos {
+		var data []byte
+		if _, err := asn1.Unmarshal(info.Content.Bytes, &data); err != nil {
+			t.Fatal(err)
+		}
+		var contents []safeBag
+		if _, err := asn1.Unmarshal(data, &contents); err != nil {
+			t.Fatal(err)
+		}
+		bags = append(bags, contents...)
+	}
+	return bags
+}
+
+// findAttribute returns the attribute of a bag with the id. Attributes are a DER set, which sorts them.
+func findAttribute(bag safeBag, id asn1.ObjectIdentifier) attribute {
+	for _, a := range bag.Attributes {
+		if a.ID.Equal(id) {
+			return a
+		}
+	}
+	return attribute{}
+}
+
+func decryptPBES2(t *testing.T, info encryptedPrivateKeyInfo, password string) []byte {
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		t.Fatal(err)
+	}
+	var kdf pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+		t.Fatal(err)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		t.Fatal(err)
+	}
+	key, _ := pbkdf2.Key(sha256.New, password, kdf.Salt, kdf.Iterations, 32)
+	block, _ := aes.NewCipher(key)
+	plain := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, info.EncryptedData)
+	return plain[:len(plain)-int(plain[len(plain)-1])]
+}
+
+func TestPKCS12(t *testing.T) {
+	key, cert := testKeyPair(t)
+	der, err := PKCS12(key, []*x509.Certificate{cert}, "alice", "s3cret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bags := decodeSafeContents(t, der, "s3cret")
+	if !assert.Len(t, bags, 2) {
+		return
+	}
+	assert.Equal(t, oidShroudedKeyBag, bags[0].ID)
+	assert.Equal(t, oidCertBag, bags[1].ID)
+	assert.Equal(t, bags[0].Attributes, bags[1].Attributes)
+	assert.Equal(t, friendlyName("alice").Values.Bytes, findAttribute(bags[0], oidFriendlyName).Values.Bytes)
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(bags[0].Value.Bytes, &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, oidPBES2, info.Algorithm.Algorithm)
+	decoded, err := x509.ParsePKCS8PrivateKey(decryptPBES2(t, info, "s3cret"))
+	if assert.NoError(t, err) {
+		assert.True(t, key.Equal(decoded))
+	}
+
+	_, err = PKCS12(key, nil, "alice", "s3cret")
+	assert.Error(t, err)
+}
+
+func TestPKCS12Truststore(t *testing.T) {
+	_, cert := testKeyPair(t)
+	der, err := PKCS12Truststore([]*x509.Certificate{cert, cert}, "ca", "changeit")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	bags := decodeSafeContents(t, der, "changeit")
+	if !assert.Len(t, bags, 2) {
+		return
+	}
+	for i, name := range []string{"ca", "ca-1"} {
+		assert.Equal(t, oidCertBag, bags[i].ID)
+		assert.Equal(t, friendlyName(name).Values.Bytes, findAttribute(bags[i], oidFriendlyName).Values.Bytes)
+		assert.Equal(t, oidJavaTrustedKeyUsage, findAttribute(bags[i], oidJavaTrustedKeyUsage).ID)
+	}
+	var cb certBag
+	if _, err := asn1.Unmarshal(bags[0].Value.Bytes, &cb); err != nil {
+		t.Fatal(err)
+	}
+	var raw []byte
+	if _, err := asn1.Unmarshal(cb.Data.Bytes, &raw); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, cert.Raw, raw)
+}
+
+// jksReader reads the fields of a JKS keystore.
+type jksReader struct {
+	r *bytes.Reader
+}
+
+func (r jksReader) int32() int32 {
+	var v int32
+	_ = binary.Read(r.r, binary.BigEndian, &v)
+	return v
+}
+
+func (r jksReader) utf() string {
+	var n uint16
+	_ = binary.Read(r.r, binary.BigEndian, &n)
+	b := make([]byte, n)
+	_, _ = r.r.Read(b)
+	return string(b)
+}
+
+func (r jksReader) bytes() []byte {
+	b := make([]byte, r.int32())
+	_, _ = r.r.Read(b)
+	return b
+}
+
+// verifyJKS checks the integrity digest and header of a JKS keystore and returns a reader of its entries.
+func verifyJKS(t *testing.T, der []byte, password string, entries int32) jksReader {
+	body, digest := der[:len(der)-sha1.Size], der[len(der)-sha1.Size:]
+	h := sha1.New()
+	h.Write(utf16BE(password))
+	h.Write([]byte(jksWhitener))
+	h.Write(body)
+	assert.Equal(t, h.Sum(nil), digest, "digest")
+
+	r := jksReader{r: bytes.NewReader(body)}
+	assert.Equal(t, uint32(jksMagic), uint32(r.int32()))
+	assert.Equal(t, int32(jksVersion), r.int32())
+	assert.Equal(t, entries, r.int32())
+	return r
+}
+
+func TestJKS(t *testing.T) {
+	key, cert := testKeyPair(t)
+	der, err := JKS(key, []*x509.Certificate{cert}, "Alice", "s3cret")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r := verifyJKS(t, der, "s3cret", 1)
+	assert.Equal(t, int32(jksPrivateKeyEntry), r.int32())
+	assert.Equal(t, "alice", r.utf())
+	r.r.Seek(8, 1) // timestamp
+
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(r.bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, oidJKSKeyProtector, info.Algorithm.Algorithm)
+
+	// Undo the key protection, see protectJKSKey.
+	protected := info.EncryptedData
+	salt, encrypted := protected[:sha1.Size], protected[sha1.Size:len(protected)-sha1.Size]
+	plain := make([]byte, len(encrypted))
+	digest := salt
+	for i := range encrypted {
+		if i%sha1.Size == 0 {
+			h := sha1.New()
+			h.Write(utf16BE("s3cret"))
+			h.Write(digest)
+			digest = h.Sum(nil)
+		}
+		plain[i] = encrypted[i] ^ digest[i%sha1.Size]
+	}
+	check := sha1.New()
+	check.Write(utf16BE("s3cret"))
+	check.Write(plain)
+	assert.Equal(t, check.Sum(nil), protected[len(protected)-sha1.Size:])
+	decoded, err := x509.ParsePKCS8PrivateKey(plain)
+	if assert.NoError(t, err) {
+		assert.True(t, key.Equal(decoded))
+	}
+
+	assert.Equal(t, int32(1), r.int32())
+	assert.Equal(t, "X.509", r.utf())
+	assert.Equal(t, cert.Raw, r.bytes())
+	assert.Zero(t, r.r.Len())
+}
+
+func TestJKSTruststore(t *testing.T) {
+	_, cert := testKeyPair(t)
+	der, err := JKSTruststore([]*x509.Certificate{cert, cert}, "ca", "changeit")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	r := verifyJKS(t, der, "changeit", 2)
+	for _, alias := range []string{"ca", "ca-1"} {
+		assert.Equal(t, int32(jksTrustedCertEntry), r.int32())
+		assert.Equal(t, alias, r.utf())
+		r.r.Seek(8, 1) // timestamp
+		assert.Equal(t, "X.509", r.utf())
+		assert.Equal(t, cert.Raw, r.bytes())
+	}
+	assert.Zero(t, r.r.Len())
+}
@@ -0,0 +1,373 @@
+// Package keystore encodes private keys and certificates as Java keystores, in the PKCS#12 and JKS formats.
+// Only encoding is supported, the keystores are meant for JVM clients.
+package keystore
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+// iterations of the key derivations. This is the default of current JDKs.
+const iterations = 10000
+
+var (
+	oidData                = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidShroudedKeyBag      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidX509Certificate     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+	oidFriendlyName        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+	oidPBES2               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA256      = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES256CBC           = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidSHA256              = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidJavaTrustedKeyUsage = asn1.ObjectIdentifier{2, 16, 840, 1, 113894, 746875, 1, 1}
+	oidAnyExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37, 0}
+)
+
+type pfx struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue
+}
+
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int
+}
+
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue
+	Attributes []attribute `asn1:"set,optional"`
+}
+
+type attribute struct {
+	ID     asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data asn1.RawValue
+}
+
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	PRF        pkix.AlgorithmIdentifier
+}
+
+// PKCS12 encodes a private key and its certificate chain, leaf first, as a PKCS#12 keystore with a single entry.
+// The key is encrypted with AES-256 and PBKDF2, like current JDKs and OpenSSL 3 do, and the same password protects the keystore.
+func PKCS12(key crypto.PrivateKey, chain []*x509.Certificate, alias, password string) ([]byte, error) {
+	if len(chain) == 0 {
+		return nil, errors.New("the certificate chain is empty")
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptPBES2(pkcs8, password)
+	if err != nil {
+		return nil, err
+	}
+
+	localKeyID := sha1.Sum(chain[0].Raw)
+	attrs := []attribute{friendlyName(alias), localKeyIDAttribute(localKeyID[:])}
+	keyBag, err := newSafeBag(oidShroudedKeyBag, encrypted, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	certBags := make([]safeBag, 0, len(chain))
+	for i, cert := range chain {
+		var certAttrs []attribute
+		if i == 0 {
+			certAttrs = attrs
+		}
+		bag, err := newCertBag(cert, certAttrs)
+		if err != nil {
+			return nil, err
+		}
+		certBags = append(certBags, bag)
+	}
+	return encodePFX(password, []safeBag{keyBag}, certBags)
+}
+
+// PKCS12Truststore encodes certificates as trusted entries of a PKCS#12 keystore. The first entry is named alias,
+// the others alias-1, alias-2 and so on.
+func PKCS12Truststore(certs []*x509.Certificate, alias, password string) ([]byte, error) {
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates to trust")
+	}
+	trusted, err := asn1.Marshal(oidAnyExtendedKeyUsage)
+	if err != nil {
+		return nil, err
+	}
+
+	bags := make([]safeBag, 0, len(certs))
+	for i, cert := range certs {
+		// Java only loads certificates without a key as trusted entries if they carry this attribute.
+		attrs := []attribute{
+			friendlyName(trustAlias(alias, i)),
+			{ID: oidJavaTrustedKeyUsage, Values: set(trusted)},
+		}
+		bag, err := newCertBag(cert, attrs)
+		if err != nil {
+			return nil, err
+		}
+		bags = append(bags, bag)
+	}
+	return encodePFX(password, bags)
+}
+
+func trustAlias(alias string, i int) string {
+	if i == 0 {
+		return alias
+	}
+	return fmt.Sprintf("%s-%d", alias, i)
+}
+
+// encodePFX encodes each list of bags as an unencrypted content, and adds the password based MAC over all of them.
+func encodePFX(password string, contents ...[]safeBag) ([]byte, error) {
+	var infos []contentInfo
+	for _, bags := range contents {
+		der, err := asn1.Marshal(bags)
+		if err != nil {
+			return nil, err
+		}
+		info, err := dataContentInfo(der)
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	authSafe, err := asn1.Marshal(infos)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	macKey := pkcs12KDF(sha256.New, salt, bmpPassword(password), iterations, 3, sha256.Size)
+	mac := hmac.New(sha256.New, macKey)
+	mac.Write(authSafe)
+
+	info, err := dataContentInfo(authSafe)
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pfx{
+		Version:  3,
+		AuthSafe: info,
+		MacData: macData{
+			Mac: digestInfo{
+				Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+				Digest:    mac.Sum(nil),
+			},
+			MacSalt:    salt,
+			Iterations: iterations,
+		},
+	})
+}
+
+func dataContentInfo(data []byte) (contentInfo, error) {
+	octets, err := asn1.Marshal(data)
+	if err != nil {
+		return contentInfo{}, err
+	}
+	return contentInfo{ContentType: oidData, Content: explicit(octets)}, nil
+}
+
+func newSafeBag(id asn1.ObjectIdentifier, value any, attrs []attribute) (safeBag, error) {
+	der, err := asn1.Marshal(value)
+	if err != nil {
+		return safeBag{}, err
+	}
+	return safeBag{ID: id, Value: explicit(der), Attributes: attrs}, nil
+}
+
+func newCertBag(cert *x509.Certificate, attrs []attribute) (safeBag, error) {
+	octets, err := asn1.Marshal(cert.Raw)
+	if err != nil {
+		return safeBag{}, err
+	}
+	return newSafeBag(oidCertBag, certBag{ID: oidX509Certificate, Data: explicit(octets)}, attrs)
+}
+
+func friendlyName(name string) attribute {
+	bmp, _ := asn1.Marshal(asn1.RawValue{Tag: asn1.TagBMPString, Bytes: utf16BE(name)})
+	return attribute{ID: oidFriendlyName, Values: set(bmp)}
+}
+
+func localKeyIDAttribute(id []byte) attribute {
+	octets, _ := asn1.Marshal(id)
+	return attribute{ID: oidLocalKeyID, Values: set(octets)}
+}
+
+// explicit wraps DER as the [0] EXPLICIT tagged value used throughout PKCS#7 and PKCS#12.
+func explicit(der []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: der}
+}
+
+func set(der ...[]byte) asn1.RawValue {
+	v := asn1.RawValue{Tag: asn1.TagSet, IsCompound: true}
+	for _, b := range der {
+		v.Bytes = append(v.Bytes, b...)
+	}
+	return v
+}
+
+// encryptPBES2 encrypts data with AES-256-CBC and a key derived by PBKDF2 with HMAC-SHA256 (RFC 8018).
+func encryptPBES2(data []byte, password string) (encryptedPrivateKeyInfo, error) {
+	salt := make([]byte, 16)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	key, err := pbkdf2.Key(sha256.New, password, salt, iterations, 32)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	padding := aes.BlockSize - len(data)%aes.BlockSize
+	padded := append(append([]byte{}, data...), make([]byte, padding)...)
+	for i := len(data); i < len(padded); i++ {
+		padded[i] = byte(padding)
+	}
+	encrypted := make([]byte, len(padded))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, padded)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:       salt,
+		Iterations: iterations,
+		PRF:        pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	ivOctets, err := asn1.Marshal(iv)
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivOctets}},
+	})
+	if err != nil {
+		return encryptedPrivateKeyInfo{}, err
+	}
+	return encryptedPrivateKeyInfo{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	}, nil
+}
+
+// bmpPassword is the password as a null terminated BMPString, as used by the PKCS#12 key derivation.
+func bmpPassword(password string) []byte {
+	return append(utf16BE(password), 0, 0)
+}
+
+// utf16BE encodes s as UTF-16 big-endian, the encoding of BMPStrings and of Java's chars.
+func utf16BE(s string) []byte {
+	var b []byte
+	for _, r := range utf16.Encode([]rune(s)) {
+		b = append(b, byte(r>>8), byte(r))
+	}
+	return b
+}
+
+// pkcs12KDF derives key material as described in RFC 7292, appendix B.2. id is 1 for keys, 2 for IVs and 3 for MAC keys.
+func pkcs12KDF(newHash func() hash.Hash, salt, password []byte, iterations int, id byte, size int) []byte {
+	h := newHash()
+	u, v := h.Size(), h.BlockSize()
+
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(b)+v-1)/v))
+		for i := range out {
+			out[i] = b[i%len(b)]
+		}
+		return out
+	}
+	d := make([]byte, v)
+	for i := range d {
+		d[i] = id
+	}
+	in := append(fill(salt), fill(password)...)
+
+	var out []byte
+	for len(out) < size {
+		h.Reset()
+		h.Write(d)
+		h.Write(in)
+		a := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(a)
+			a = h.Sum(a[:0])
+		}
+		out = append(out, a...)
+
+		// Add a+1 to every block of the input, treating both as big-endian integers.
+		b := make([]byte, v)
+		for i := range b {
+			b[i] = a[i%u]
+		}
+		for j := 0; j < len(in); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(in[j+k]) + int(b[k]) + carry
+				in[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return out[:size]
+}
@@ -0,0 +1,192 @@
+package ociregistry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// annotationTitle holds the file name of a layer pushed as a file, e.g. with `oras push`.
+const annotationTitle = "org.opencontainers.image.title"
+
+// ArtifactOptions configure PullArtifactFile.
+type ArtifactOptions struct {
+	// TokenName and TokenPassword default to the IONOS_CR_TOKEN_NAME and IONOS_CR_TOKEN_PASSWORD environment variables.
+	TokenName     string
+	TokenPassword string
+	PlainHTTP     bool
+	// Platform selects a single image of a multi-platform index, e.g. linux/amd64.
+	Platform   string
+	HTTPClient *http.Client
+	Log        func(format string, args ...any)
+}
+
+// ArtifactFile describes a file pulled from an artifact.
+type ArtifactFile struct {
+	Name   string // Base name of the file
+	Digest string // Digest of the layer the file was read from
+	Size   int64
+}
+
+// PullArtifactFile writes the first file of the artifact at ref whose name matches to w. Files pushed
+// as layers, e.g. with `oras push`, are found by the title annotation of the layer. Otherwise, the
+// layer tarballs of a container image are searched, from the last layer to the first, e.g. for the
+// disk of a KubeVirt container disk.
+//
+// The digest of every layer read is verified, but only after its content was written, so w must
+// be discarded if an error is returned.
+func PullArtifactFile(ctx context.Context, ref string, opts ArtifactOptions, match func(name string) bool, w io.Writer) (ArtifactFile, error) {
+	src, err := ParseReference(ref)
+	if err != nil {
+		return ArtifactFile{}, err
+	}
+	if opts.HTTPClient == nil {
+		// No timeout, layers can be large. Requests are canceled with the context.
+		opts.HTTPClient = &http.Client{}
+	}
+	if opts.Log == nil {
+		opts.Log = func(string, ...any) {}
+	}
+	creds := Credentials{Username: opts.TokenName, Password: opts.TokenPassword}
+	if creds.Username == "" {
+		creds.Username = os.Getenv(EnvTokenName)
+	}
+	if creds.Password == "" {
+		creds.Password = os.Getenv(EnvTokenPassword)
+	}
+
+	client := newRegistryClient(src.Host, opts.PlainHTTP, creds, opts.HTTPClient)
+	if err := client.login(ctx, repositoryScope(src.Repository, "pull")); err != nil {
+		return ArtifactFile{}, err
+	}
+	repo := newRepository(client, src.Repository)
+	desc, err := repo.resolve(ctx, src.Reference())
+	if err != nil {
+		return ArtifactFile{}, err
+	}
+	m, err := fetchImageManifest(ctx, repo, desc, opts.Platform)
+	if err != nil {
+		return ArtifactFile{}, err
+	}
+
+	for _, layer := range m.Layers {
+		name := path.Base(layer.Annotations[annotationTitle])
+		if layer.Annotations[annotationTitle] == "" || !match(name) {
+			continue
+		}
+		opts.Log("Pulling %s from layer %s of %s", name, layer.Digest, src)
+		n, err := copyBlob(ctx, repo, layer, func(r io.Reader) (int64, error) { return io.Copy(w, r) })
+		return ArtifactFile{Name: name, Digest: layer.Digest, Size: n}, err
+	}
+
+	for i := len(m.Layers) - 1; i >= 0; i-- {
+		layer := m.Layers[i]
+		if !strings.Contains(layer.MediaType, "tar") {
+			continue
+		}
+		var file ArtifactFile
+		_, err := copyBlob(ctx, repo, layer, func(r io.Reader) (int64, error) {
+			var err error
+			file, err = extractLayerFile(r, layer.MediaType, match, w)
+			return file.Size, err
+		})
+		if err != nil {
+			return ArtifactFile{}, fmt.Errorf("reading layer %s: %w", layer.Digest, err)
+		}
+		if file.Name != "" {
+			opts.Log("Pulled %s from layer %s of %s", file.Name, layer.Digest, src)
+			file.Digest = layer.Digest
+			return file, nil
+		}
+	}
+	return ArtifactFile{}, fmt.Errorf("no file of the artifact %s matches", src)
+}
+
+// fetchImageManifest returns the image manifest of desc, selecting the platform of an index.
+func fetchImageManifest(ctx context.Context, repo *repository, desc descriptor, platform string) (manifest, error) {
+	data, err := repo.FetchManifest(ctx, desc)
+	if err != nil {
+		return manifest{}, err
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return m, err
+	}
+	if !isIndex(desc.MediaType) && !isIndex(m.MediaType) {
+		return m, nil
+	}
+
+	switch {
+	case platform != "":
+		desc, err = selectPlatform(m, platform)
+	case len(m.Manifests) == 1:
+		desc = m.Manifests[0]
+	default:
+		err = fmt.Errorf("the artifact has %d platforms, %w", len(m.Manifests), ErrPlatformRequired)
+	}
+	if err != nil {
+		return manifest{}, err
+	}
+	return fetchImageManifest(ctx, repo, desc, "")
+}
+
+// copyBlob reads the blob with fn, then reads the rest of it, so its size and digest are always verified.
+func copyBlob(ctx context.Context, repo *repository, desc descriptor, fn func(r io.Reader) (int64, error)) (int64, error) {
+	blob, err := repo.FetchBlob(ctx, desc)
+	if err != nil {
+		return 0, err
+	}
+	defer blob.Close()
+	v, err := newVerifyingReader(blob, desc)
+	if err != nil {
+		return 0, err
+	}
+	n, err := fn(v)
+	if err != nil {
+		return n, err
+	}
+	if _, err := io.Copy(io.Discard, v); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// extractLayerFile writes the first regular file of the layer tarball whose name matches to w. An empty
+// name is returned if no file matches.
+func extractLayerFile(r io.Reader, mediaType string, match func(name string) bool, w io.Writer) (ArtifactFile, error) {
+	switch {
+	case strings.HasSuffix(mediaType, "gzip"):
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return ArtifactFile{}, err
+		}
+		defer gz.Close()
+		r = gz
+	case strings.HasSuffix(mediaType, "zstd"):
+		return ArtifactFile{}, fmt.Errorf("zstd compressed layers are not supported")
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return ArtifactFile{}, nil
+		}
+		if err != nil {
+			return ArtifactFile{}, err
+		}
+		name := path.Base(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || !match(name) {
+			continue
+		}
+		n, err := io.Copy(w, tr)
+		return ArtifactFile{Name: name, Size: n}, err
+	}
+}
@@ -0,0 +1,122 @@
+package ociregistry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testLayer struct {
+	desc descriptor
+	data []byte
+}
+
+// addArtifact stores the layers and a manifest referencing them in the fake registry, and tags it.
+func (r *fakeRegistry) addArtifact(t *testing.T, repoName, tag string, layers ...testLayer) descriptor {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	repo := r.repo(repoName)
+
+	config := []byte("{}")
+	configDesc := descriptor{MediaType: "application/vnd.oci.empty.v1+json", Digest: digestOf(config), Size: int64(len(config))}
+	repo.blobs[configDesc.Digest] = config
+	m := manifest{SchemaVersion: 2, MediaType: mediaTypeOCIManifest, Config: &configDesc}
+	for _, l := range layers {
+		l.desc.Digest, l.desc.Size = digestOf(l.data), int64(len(l.data))
+		repo.blobs[l.desc.Digest] = l.data
+		m.Layers = append(m.Layers, l.desc)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc := descriptor{MediaType: mediaTypeOCIManifest, Digest: digestOf(data), Size: int64(len(data))}
+	repo.manifests[desc.Digest] = data
+	repo.types[desc.Digest] = mediaTypeOCIManifest
+	repo.tags[tag] = desc.Digest
+	return desc
+}
+
+func gzipTar(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func isQcow2(name string) bool { return path.Ext(name) == ".qcow2" }
+
+func TestPullArtifactFile(t *testing.T) {
+	reg := newFakeRegistry(t, "user", "secret")
+	opts := ArtifactOptions{TokenName: "user", TokenPassword: "secret", PlainHTTP: true, HTTPClient: reg.server.Client()}
+	disk := bytes.Repeat([]byte("disk"), 1000)
+
+	file := func(name string, data []byte) testLayer {
+		return testLayer{descriptor{MediaType: "application/octet-stream", Annotations: map[string]string{annotationTitle: name}}, data}
+	}
+	reg.addArtifact(t, "images/oras", "1.0", file("README.md", []byte("readme")), file("disk.qcow2", disk))
+	reg.addArtifact(t, "images/containerdisk", "1.0",
+		testLayer{descriptor{MediaType: "application/vnd.oci.image.layer.v1.tar+gzip"}, gzipTar(t, map[string][]byte{"disk/disk.qcow2": disk, "etc/os-release": []byte("ID=test")})},
+		testLayer{descriptor{MediaType: "application/vnd.oci.image.layer.v1.tar"}, []byte{}},
+	)
+
+	t.Run("layer with title", func(t *testing.T) {
+		var b bytes.Buffer
+		file, err := PullArtifactFile(context.Background(), reg.host+"/images/oras:1.0", opts, isQcow2, &b)
+		assert.NoError(t, err)
+		assert.Equal(t, ArtifactFile{Name: "disk.qcow2", Digest: digestOf(disk), Size: int64(len(disk))}, file)
+		assert.Equal(t, disk, b.Bytes())
+	})
+
+	t.Run("file in layer tarball", func(t *testing.T) {
+		var b bytes.Buffer
+		file, err := PullArtifactFile(context.Background(), reg.host+"/images/containerdisk:1.0", opts, isQcow2, &b)
+		assert.NoError(t, err)
+		assert.Equal(t, "disk.qcow2", file.Name)
+		assert.Equal(t, disk, b.Bytes())
+	})
+
+	t.Run("no matching file", func(t *testing.T) {
+		_, err := PullArtifactFile(context.Background(), reg.host+"/images/oras:1.0", opts, func(string) bool { return false }, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "no file")
+	})
+
+	t.Run("digest mismatch", func(t *testing.T) {
+		reg.mu.Lock()
+		reg.corrupt = digestOf(disk)
+		reg.mu.Unlock()
+		defer func() { reg.corrupt = "" }()
+		_, err := PullArtifactFile(context.Background(), reg.host+"/images/oras:1.0", opts, isQcow2, &bytes.Buffer{})
+		assert.ErrorContains(t, err, "digest mismatch")
+	})
+
+	t.Run("multi-platform index requires platform", func(t *testing.T) {
+		l := writeTestLayout(t, "1.0", "linux/amd64", "linux/arm64")
+		_, err := Push(context.Background(), l.dir, reg.ref(t, "images/multi:1.0"), "", reg.opts())
+		assert.NoError(t, err)
+		_, err = PullArtifactFile(context.Background(), reg.host+"/images/multi:1.0", opts, isQcow2, &bytes.Buffer{})
+		assert.ErrorIs(t, err, ErrPlatformRequired)
+	})
+}
@@ -0,0 +1,414 @@
+package ociregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxManifestSize limits how much of a manifest response is read, as registries do on push.
+const maxManifestSize = 4 << 20
+
+// Credentials are the name and password of a registry token, see `container-registry token create`.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// registryClient speaks the OCI distribution protocol with a single registry host.
+type registryClient struct {
+	base  *url.URL
+	creds Credentials
+	http  *http.Client
+	// auth is the Authorization header value obtained by login.
+	auth string
+}
+
+func newRegistryClient(host string, plainHTTP bool, creds Credentials, httpClient *http.Client) *registryClient {
+	scheme := "https"
+	if plainHTTP {
+		scheme = "http"
+	}
+	return &registryClient{
+		base:  &url.URL{Scheme: scheme, Host: host},
+		creds: creds,
+		http:  httpClient,
+	}
+}
+
+// registryError is an error response of the distribution API.
+type registryError struct {
+	StatusCode int
+	Errors     []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func (e *registryError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("registry responded with %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Code + ": " + err.Message
+	}
+	return fmt.Sprintf("registry responded with %d: %s", e.StatusCode, strings.Join(msgs, "; "))
+}
+
+func checkResponse(resp *http.Response, expected ...int) error {
+	for _, code := range expected {
+		if resp.StatusCode == code {
+			return nil
+		}
+	}
+	rerr := &registryError{StatusCode: resp.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	_ = json.Unmarshal(body, rerr)
+	return rerr
+}
+
+// login authenticates for the given scopes, e.g. "repository:app:pull,push". Registries
+// answer the version check with a challenge, either for basic auth or for a bearer
+// token which is issued by the realm of the challenge in exchange for the credentials.
+func (r *registryClient) login(ctx context.Context, scopes ...string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.base.JoinPath("/v2/").String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return fmt.Errorf("%s is not an OCI registry: %w", r.base.Host, checkResponse(resp))
+	}
+
+	scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	if r.creds.Username == "" || r.creds.Password == "" {
+		return fmt.Errorf("%s: %w", r.base.Host, ErrAuthRequired)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		r.auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(r.creds.Username+":"+r.creds.Password))
+		return nil
+	case "bearer":
+		token, err := r.fetchToken(ctx, params, scopes)
+		if err != nil {
+			return fmt.Errorf("authenticating with %s: %w", r.base.Host, err)
+		}
+		r.auth = "Bearer " + token
+		return nil
+	}
+	return fmt.Errorf("%s requested unsupported authentication scheme %q", r.base.Host, scheme)
+}
+
+func (r *registryClient) fetchToken(ctx context.Context, challenge map[string]string, scopes []string) (string, error) {
+	realm, err := url.Parse(challenge["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", challenge["realm"])
+	}
+
+	q := realm.Query()
+	if service := challenge["service"]; service != "" {
+		q.Set("service", service)
+	}
+	for _, scope := range scopes {
+		q.Add("scope", scope)
+	}
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(r.creds.Username, r.creds.Password)
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return "", errors.New("invalid token name or password")
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+	return "", errors.New("token response contains no token")
+}
+
+// parseChallenge parses a WWW-Authenticate header like
+// Bearer realm="https://auth.example.com/token",service="registry".
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(params[key])
+		}
+		rest = strings.TrimLeft(rest, ", ")
+	}
+	return scheme, params
+}
+
+// do sends a request to the registry. target is a path like /v2/app/blobs/<digest>, or a
+// location returned by the registry, which may be an absolute URL.
+func (r *registryClient) do(ctx context.Context, method, target string, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	u, err := r.base.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid location %q: %w", target, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	if r.auth != "" && u.Host == r.base.Host {
+		req.Header.Set("Authorization", r.auth)
+	}
+	return r.http.Do(req)
+}
+
+// repository is an image repository in a registry. It is both a source and a target.
+type repository struct {
+	client *registryClient
+	name   string
+	// mountFrom is another repository in the same registry which blobs are mounted from.
+	mountFrom string
+
+	// manifests caches manifests read by resolve, by digest.
+	manifests map[string][]byte
+	// uploads are upload sessions started by a failed mount, by digest.
+	uploads map[string]string
+}
+
+func newRepository(client *registryClient, name string) *repository {
+	return &repository{client: client, name: name, manifests: map[string][]byte{}, uploads: map[string]string{}}
+}
+
+func (r *repository) path(kind, ref string) string {
+	return "/v2/" + r.name + "/" + kind + "/" + ref
+}
+
+func manifestAccept() http.Header {
+	return http.Header{"Accept": []string{strings.Join(manifestMediaTypes, ", ")}}
+}
+
+// resolve returns the descriptor of the manifest a tag or digest points to.
+func (r *repository) resolve(ctx context.Context, ref string) (descriptor, error) {
+	resp, err := r.client.do(ctx, http.MethodGet, r.path("manifests", ref), manifestAccept(), nil, 0)
+	if err != nil {
+		return descriptor{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return descriptor{}, fmt.Errorf("image %s:%s not found", r.name, ref)
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return descriptor{}, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return descriptor{}, err
+	}
+	if len(data) > maxManifestSize {
+		return descriptor{}, fmt.Errorf("manifest %s:%s is larger than %d bytes", r.name, ref, maxManifestSize)
+	}
+
+	desc := descriptor{Digest: digestOf(data), Size: int64(len(data))}
+	if strings.HasPrefix(ref, "sha256:") && desc.Digest != ref {
+		return descriptor{}, fmt.Errorf("digest mismatch for %s: got %s", ref, desc.Digest)
+	}
+	if mt, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && isManifest(mt) {
+		desc.MediaType = mt
+	}
+
+	r.manifests[desc.Digest] = data
+	return desc, nil
+}
+
+func (r *repository) FetchManifest(ctx context.Context, desc descriptor) ([]byte, error) {
+	if data, ok := r.manifests[desc.Digest]; ok {
+		return data, nil
+	}
+
+	resp, err := r.client.do(ctx, http.MethodGet, r.path("manifests", desc.Digest), manifestAccept(), nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		return nil, fmt.Errorf("fetching manifest %s: %w", desc.Digest, err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyDigest(desc, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (r *repository) FetchBlob(ctx context.Context, desc descriptor) (io.ReadCloser, error) {
+	resp, err := r.client.do(ctx, http.MethodGet, r.path("blobs", desc.Digest), nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("fetching blob %s: %w", desc.Digest, err)
+	}
+	return resp.Body, nil
+}
+
+func (r *repository) Exists(ctx context.Context, desc descriptor) (bool, error) {
+	kind := "blobs"
+	var header http.Header
+	if isManifest(desc.MediaType) {
+		kind, header = "manifests", manifestAccept()
+	}
+
+	resp, err := r.client.do(ctx, http.MethodHead, r.path(kind, desc.Digest), header, nil, 0)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, checkResponse(resp)
+}
+
+// Mount links a blob from mountFrom. If the registry does not mount it, e.g. because the
+// token has no pull access to mountFrom, it starts a regular upload which PushBlob uses.
+func (r *repository) Mount(ctx context.Context, desc descriptor) (bool, error) {
+	if r.mountFrom == "" {
+		return false, nil
+	}
+
+	q := url.Values{"mount": {desc.Digest}, "from": {r.mountFrom}}
+	resp, err := r.client.do(ctx, http.MethodPost, "/v2/"+r.name+"/blobs/uploads/?"+q.Encode(), nil, nil, 0)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated:
+		return true, nil
+	case http.StatusAccepted:
+		if loc := resp.Header.Get("Location"); loc != "" {
+			r.uploads[desc.Digest] = loc
+		}
+		return false, nil
+	}
+	return false, checkResponse(resp)
+}
+
+// PushBlob uploads a blob in a single request, see the "monolithic upload" of the distribution spec.
+func (r *repository) PushBlob(ctx context.Context, desc descriptor, body io.Reader) error {
+	location, ok := r.uploads[desc.Digest]
+	if !ok {
+		resp, err := r.client.do(ctx, http.MethodPost, "/v2/"+r.name+"/blobs/uploads/", nil, nil, 0)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if err := checkResponse(resp, http.StatusAccepted); err != nil {
+			return err
+		}
+		if location = resp.Header.Get("Location"); location == "" {
+			return errors.New("registry did not return an upload location")
+		}
+	}
+	delete(r.uploads, desc.Digest)
+
+	u, err := r.client.base.Parse(location)
+	if err != nil {
+		return fmt.Errorf("invalid upload location %q: %w", location, err)
+	}
+	q := u.Query()
+	q.Set("digest", desc.Digest)
+	u.RawQuery = q.Encode()
+
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	resp, err := r.client.do(ctx, http.MethodPut, u.String(), header, body, desc.Size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp, http.StatusCreated)
+}
+
+func (r *repository) PushManifest(ctx context.Context, desc descriptor, data []byte, tag string) error {
+	ref := desc.Digest
+	if tag != "" {
+		ref = tag
+	}
+
+	header := http.Header{"Content-Type": {desc.MediaType}}
+	resp, err := r.client.do(ctx, http.MethodPut, r.path("manifests", ref), header, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp, http.StatusCreated)
+}
@@ -0,0 +1,415 @@
+package ociregistry
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// The files of an OCI image layout, see https://github.com/opencontainers/image-spec/blob/main/image-layout.md.
+const (
+	layoutFile    = "oci-layout"
+	layoutIndex   = "index.json"
+	layoutVersion = "1.0.0"
+)
+
+func blobPath(digest string) string {
+	alg, hex, _ := strings.Cut(digest, ":")
+	return path.Join("blobs", alg, hex)
+}
+
+// isTarPath reports whether a pull destination is written as tarball instead of directory.
+func isTarPath(p string) bool {
+	return strings.HasSuffix(p, ".tar")
+}
+
+// layoutReader reads an OCI layout from a directory or a tarball, e.g. the output of
+// `docker save` or `skopeo copy ... oci-archive:`.
+type layoutReader struct {
+	path  string
+	open  func(name string) (io.ReadCloser, error)
+	close func() error
+	index manifest
+}
+
+func openLayout(p string) (*layoutReader, error) {
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &layoutReader{path: p, close: func() error { return nil }}
+	if fi.IsDir() {
+		root := os.DirFS(p)
+		l.open = func(name string) (io.ReadCloser, error) { return root.Open(name) }
+	} else if err := l.openTar(p); err != nil {
+		return nil, err
+	}
+
+	if err := l.readIndex(); err != nil {
+		_ = l.close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// openTar indexes the members of an uncompressed tarball, so they can be read without
+// extracting it. Compressed tarballs are extracted to a temporary directory instead.
+func (l *layoutReader) openTar(p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+
+	magic, _ := bufio.NewReader(f).Peek(2)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		defer f.Close()
+		return l.extractGzip(f)
+	}
+
+	members := map[string]*io.SectionReader{}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			f.Close()
+			return fmt.Errorf("%s is neither an OCI layout directory nor a tarball: %w", p, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		// Reading from the current position of the underlying file is only valid for
+		// uncompressed tarballs, where member data is stored contiguously.
+		offset, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		members[path.Clean(hdr.Name)] = io.NewSectionReader(f, offset, hdr.Size)
+	}
+
+	l.open = func(name string) (io.ReadCloser, error) {
+		m, ok := members[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+		}
+		return io.NopCloser(io.NewSectionReader(m, 0, m.Size())), nil
+	}
+	l.close = f.Close
+	return nil
+}
+
+func (l *layoutReader) extractGzip(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "ionosctl-oci-")
+	if err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			os.RemoveAll(dir)
+			return fmt.Errorf("extracting %s: %w", l.path, err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if !filepath.IsLocal(name) {
+			os.RemoveAll(dir)
+			return fmt.Errorf("extracting %s: invalid member name %q", l.path, hdr.Name)
+		}
+		if err := writeFile(filepath.Join(dir, filepath.FromSlash(name)), tr); err != nil {
+			os.RemoveAll(dir)
+			return err
+		}
+	}
+
+	root := os.DirFS(dir)
+	l.open = func(name string) (io.ReadCloser, error) { return root.Open(name) }
+	l.close = func() error { return os.RemoveAll(dir) }
+	return nil
+}
+
+func (l *layoutReader) readIndex() error {
+	data, err := l.readFile(layoutFile, 1<<10)
+	if err != nil {
+		return fmt.Errorf("%s is not an OCI image layout: %w", l.path, err)
+	}
+	var marker struct {
+		ImageLayoutVersion string `json:"imageLayoutVersion"`
+	}
+	if err := json.Unmarshal(data, &marker); err != nil || marker.ImageLayoutVersion != layoutVersion {
+		return fmt.Errorf("%s: unsupported OCI image layout version %q", l.path, marker.ImageLayoutVersion)
+	}
+
+	data, err = l.readFile(layoutIndex, maxManifestSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", l.path, err)
+	}
+	if l.index, err = parseManifest(data); err != nil {
+		return fmt.Errorf("%s: %s: %w", l.path, layoutIndex, err)
+	}
+	return nil
+}
+
+func (l *layoutReader) readFile(name string, limit int64) ([]byte, error) {
+	f, err := l.open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit))
+}
+
+// root returns the manifest to push. A layout may hold several images, which are
+// told apart by their ref name annotation, usually the tag.
+func (l *layoutReader) root(refName string) (descriptor, error) {
+	var names []string
+	for _, m := range l.index.Manifests {
+		name := m.Annotations[AnnotationRefName]
+		if refName != "" && name == refName {
+			return m, nil
+		}
+		names = append(names, name)
+	}
+
+	switch {
+	case len(l.index.Manifests) == 0:
+		return descriptor{}, fmt.Errorf("%s contains no images", l.path)
+	case refName != "":
+		return descriptor{}, fmt.Errorf("%s contains no image named %q, available: %s", l.path, refName, strings.Join(names, ", "))
+	case len(l.index.Manifests) > 1:
+		return descriptor{}, fmt.Errorf("%s contains %d images (%s), %w",
+			l.path, len(l.index.Manifests), strings.Join(names, ", "), ErrLayoutRefRequired)
+	}
+	return l.index.Manifests[0], nil
+}
+
+func (l *layoutReader) FetchManifest(_ context.Context, desc descriptor) ([]byte, error) {
+	data, err := l.readFile(blobPath(desc.Digest), maxManifestSize)
+	if err != nil {
+		return nil, err
+	}
+	return data, verifyDigest(desc, data)
+}
+
+func (l *layoutReader) FetchBlob(_ context.Context, desc descriptor) (io.ReadCloser, error) {
+	return l.open(blobPath(desc.Digest))
+}
+
+func (l *layoutReader) Close() error {
+	return l.close()
+}
+
+// layoutFiles stores the files of a layout that is written.
+type layoutFiles interface {
+	exists(name string) bool
+	write(name string, r io.Reader, size int64) error
+	read(name string) ([]byte, error)
+	// close finishes the layout, abort removes what was written.
+	close() error
+	abort() error
+}
+
+// layoutWriter writes an OCI layout. Blobs are written as they are pulled, the index
+// is written by finish once all content referenced by the image is there.
+type layoutWriter struct {
+	files layoutFiles
+}
+
+// createLayout writes to a tarball if p ends with .tar, otherwise to a directory.
+// Images are added to an existing layout directory, a tarball is always replaced.
+func createLayout(p string) (*layoutWriter, error) {
+	if isTarPath(p) {
+		f, err := os.Create(p)
+		if err != nil {
+			return nil, err
+		}
+		return &layoutWriter{files: &tarFiles{f: f, tw: tar.NewWriter(f), names: map[string]bool{}}}, nil
+	}
+
+	entries, err := os.ReadDir(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(p, layoutFile)); err != nil {
+			return nil, fmt.Errorf("%s is not empty and not an OCI image layout", p)
+		}
+	}
+	if err := os.MkdirAll(p, 0o755); err != nil {
+		return nil, err
+	}
+	return &layoutWriter{files: dirFiles(p)}, nil
+}
+
+func (l *layoutWriter) Exists(_ context.Context, desc descriptor) (bool, error) {
+	return l.files.exists(blobPath(desc.Digest)), nil
+}
+
+func (l *layoutWriter) PushBlob(_ context.Context, desc descriptor, r io.Reader) error {
+	return l.files.write(blobPath(desc.Digest), r, desc.Size)
+}
+
+// PushManifest stores the manifest as blob. Tags are recorded in the index by finish.
+func (l *layoutWriter) PushManifest(_ context.Context, desc descriptor, data []byte, _ string) error {
+	if l.files.exists(blobPath(desc.Digest)) {
+		return nil
+	}
+	return l.files.write(blobPath(desc.Digest), bytes.NewReader(data), int64(len(data)))
+}
+
+// finish adds the image to index.json, replacing an image with the same ref name.
+func (l *layoutWriter) finish(root descriptor, refName string) error {
+	index := manifest{SchemaVersion: 2, MediaType: mediaTypeOCIIndex}
+	if data, err := l.files.read(layoutIndex); err == nil {
+		if index, err = parseManifest(data); err != nil {
+			return fmt.Errorf("%s: %w", layoutIndex, err)
+		}
+	}
+
+	root.Annotations = nil
+	if refName != "" {
+		root.Annotations = map[string]string{AnnotationRefName: refName}
+	}
+
+	manifests := index.Manifests[:0]
+	for _, m := range index.Manifests {
+		if m.Annotations[AnnotationRefName] == refName && (refName != "" || m.Digest == root.Digest) {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	index.Manifests = append(manifests, root)
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := l.files.write(layoutIndex, bytes.NewReader(indexData), int64(len(indexData))); err != nil {
+		return err
+	}
+
+	marker := []byte(`{"imageLayoutVersion":"` + layoutVersion + `"}`)
+	if err := l.files.write(layoutFile, bytes.NewReader(marker), int64(len(marker))); err != nil {
+		return err
+	}
+	return l.files.close()
+}
+
+func (l *layoutWriter) abort() error {
+	return l.files.abort()
+}
+
+type dirFiles string
+
+func (d dirFiles) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(name))
+}
+
+func (d dirFiles) exists(name string) bool {
+	_, err := os.Stat(d.path(name))
+	return err == nil
+}
+
+func (d dirFiles) write(name string, r io.Reader, _ int64) error {
+	return writeFile(d.path(name), r)
+}
+
+func (d dirFiles) read(name string) ([]byte, error) {
+	return os.ReadFile(d.path(name))
+}
+
+func (d dirFiles) close() error { return nil }
+
+// abort keeps the directory, since it may hold other images. Blobs are only written
+// once they are complete, so the layout stays consistent.
+func (d dirFiles) abort() error { return nil }
+
+// writeFile writes to a temporary file which is renamed once r is read completely,
+// so interrupted or failed downloads never leave a partial blob behind.
+func writeFile(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".partial-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+type tarFiles struct {
+	f     *os.File
+	tw    *tar.Writer
+	names map[string]bool
+}
+
+func (t *tarFiles) exists(name string) bool {
+	return t.names[name]
+}
+
+func (t *tarFiles) write(name string, r io.Reader, size int64) error {
+	if err := t.tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: size, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(t.tw, r); err != nil {
+		return err
+	}
+	t.names[name] = true
+	return nil
+}
+
+// read returns nothing, tarballs are always written from scratch.
+func (t *tarFiles) read(name string) ([]byte, error) {
+	return nil, fs.ErrNotExist
+}
+
+func (t *tarFiles) close() error {
+	if err := t.tw.Close(); err != nil {
+		t.f.Close()
+		return err
+	}
+	return t.f.Close()
+}
+
+func (t *tarFiles) abort() error {
+	t.f.Close()
+	return os.Remove(t.f.Name())
+}
@@ -0,0 +1,147 @@
+package ociregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+const (
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex       = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// AnnotationRefName holds the tag of a manifest in an OCI layout index.json.
+	AnnotationRefName = "org.opencontainers.image.ref.name"
+)
+
+// manifestMediaTypes are accepted when fetching manifests, in order of preference.
+var manifestMediaTypes = []string{mediaTypeOCIIndex, mediaTypeOCIManifest, mediaTypeDockerList, mediaTypeDockerManifest}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *platform         `json:"platform,omitempty"`
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+func (p *platform) String() string {
+	if p == nil {
+		return "unknown"
+	}
+	s := p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		s += "/" + p.Variant
+	}
+	return s
+}
+
+// matches reports whether p satisfies a platform like linux/arm64 or linux/arm/v7.
+func (p *platform) matches(want string) bool {
+	if p == nil {
+		return false
+	}
+	parts := strings.Split(want, "/")
+	if len(parts) < 2 || parts[0] != p.OS || parts[1] != p.Architecture {
+		return false
+	}
+	return len(parts) < 3 || parts[2] == p.Variant
+}
+
+// manifest covers image manifests and indexes of both the OCI and the Docker formats.
+// It is only used to find referenced content, manifests are always copied byte by byte
+// so their digests don't change.
+type manifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        *descriptor  `json:"config,omitempty"`
+	Layers        []descriptor `json:"layers,omitempty"`
+	Manifests     []descriptor `json:"manifests,omitempty"`
+}
+
+func isIndex(mediaType string) bool {
+	return mediaType == mediaTypeOCIIndex || mediaType == mediaTypeDockerList
+}
+
+func isManifest(mediaType string) bool {
+	return isIndex(mediaType) || mediaType == mediaTypeOCIManifest || mediaType == mediaTypeDockerManifest
+}
+
+func parseManifest(data []byte) (manifest, error) {
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if m.SchemaVersion != 2 {
+		return m, fmt.Errorf("unsupported manifest schema version %d", m.SchemaVersion)
+	}
+	return m, nil
+}
+
+// blobs returns the config and layers of an image manifest.
+func (m manifest) blobs() []descriptor {
+	var blobs []descriptor
+	if m.Config != nil {
+		blobs = append(blobs, *m.Config)
+	}
+	return append(blobs, m.Layers...)
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// verifyDigest checks data against desc, so content is never stored or pushed under a wrong digest.
+func verifyDigest(desc descriptor, data []byte) error {
+	if got := digestOf(data); got != desc.Digest {
+		return fmt.Errorf("digest mismatch for %s: got %s", desc.Digest, got)
+	}
+	return nil
+}
+
+// verifyingReader returns an error instead of io.EOF if the content read does not
+// match the size and digest of the descriptor.
+type verifyingReader struct {
+	r    io.Reader
+	desc descriptor
+	h    hash.Hash
+	n    int64
+}
+
+func newVerifyingReader(r io.Reader, desc descriptor) (*verifyingReader, error) {
+	if !digestPattern.MatchString(desc.Digest) {
+		return nil, fmt.Errorf("unsupported digest %q, only sha256 is supported", desc.Digest)
+	}
+	return &verifyingReader{r: r, desc: desc, h: sha256.New()}, nil
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+	v.n += int64(n)
+	if v.n > v.desc.Size {
+		return n, fmt.Errorf("size mismatch for %s: more than %d bytes", v.desc.Digest, v.desc.Size)
+	}
+	if err == io.EOF {
+		if v.n != v.desc.Size {
+			return n, fmt.Errorf("size mismatch for %s: got %d bytes, expected %d", v.desc.Digest, v.n, v.desc.Size)
+		}
+		if got := "sha256:" + hex.EncodeToString(v.h.Sum(nil)); got != v.desc.Digest {
+			return n, fmt.Errorf("digest mismatch for %s: got %s", v.desc.Digest, got)
+		}
+	}
+	return n, err
+}
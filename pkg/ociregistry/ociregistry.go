@@ -0,0 +1,161 @@
+// Package ociregistry moves images and artifacts to, from and between registries without a Docker daemon.
+// It speaks the OCI distribution protocol directly, authenticating with a registry token, and reads and
+// writes OCI image layouts, either as directory or as tarball.
+package ociregistry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// The token credentials can be set in the environment, to keep the password out of the shell history.
+const (
+	EnvTokenName     = "IONOS_CR_TOKEN_NAME"
+	EnvTokenPassword = "IONOS_CR_TOKEN_PASSWORD"
+)
+
+// Errors which the caller can resolve, e.g. by naming the flag that sets the missing value.
+var (
+	ErrAuthRequired      = errors.New("authentication required")
+	ErrPlatformRequired  = errors.New("select a platform")
+	ErrLayoutRefRequired = errors.New("select an image")
+)
+
+// Options are shared by Push, Pull and Copy.
+type Options struct {
+	// Creds authenticate with the registry of the destination for Push, of the source for
+	// Pull, and of both for Copy, unless SourceCreds are set.
+	Creds       Credentials
+	SourceCreds *Credentials
+	PlainHTTP   bool
+	// Platform selects a single image of a multi-platform index, e.g. linux/amd64.
+	Platform   string
+	HTTPClient *http.Client
+	Log        func(format string, args ...any)
+}
+
+// Copy copies the image src to dst, without storing it locally. Within the same registry, blobs are mounted
+// instead of downloaded and uploaded again.
+func Copy(ctx context.Context, src, dst Reference, opts Options) (Result, error) {
+	if dst.Digest != "" {
+		return Result{}, fmt.Errorf("destination %s must be tagged, not addressed by digest", dst)
+	}
+
+	dstClient := newRegistryClient(dst.Host, opts.PlainHTTP, opts.Creds, opts.HTTPClient)
+	dstRepo := newRepository(dstClient, dst.Repository)
+
+	var srcRepo *repository
+	if src.Host == dst.Host && opts.SourceCreds == nil {
+		// One token covers both repositories, which allows the registry to mount blobs.
+		if err := dstClient.login(ctx, repositoryScope(dst.Repository, "pull,push"), repositoryScope(src.Repository, "pull")); err != nil {
+			return Result{}, err
+		}
+		srcRepo = newRepository(dstClient, src.Repository)
+		if src.Repository != dst.Repository {
+			dstRepo.mountFrom = src.Repository
+		}
+	} else {
+		srcCreds := opts.Creds
+		if opts.SourceCreds != nil {
+			srcCreds = *opts.SourceCreds
+		}
+		srcClient := newRegistryClient(src.Host, opts.PlainHTTP, srcCreds, opts.HTTPClient)
+		if err := srcClient.login(ctx, repositoryScope(src.Repository, "pull")); err != nil {
+			return Result{}, err
+		}
+		if err := dstClient.login(ctx, repositoryScope(dst.Repository, "pull,push")); err != nil {
+			return Result{}, err
+		}
+		srcRepo = newRepository(srcClient, src.Repository)
+	}
+
+	root, err := srcRepo.resolve(ctx, src.Reference())
+	if err != nil {
+		return Result{}, err
+	}
+
+	t := &transfer{src: srcRepo, dst: dstRepo, platform: opts.Platform, log: opts.Log}
+	if _, err := t.run(ctx, root, dst.Tag); err != nil {
+		return Result{}, err
+	}
+
+	t.result.Source = src.String()
+	t.result.Destination = dst.String()
+	return t.result, nil
+}
+
+// Pull pulls the image src into the OCI image layout at layoutPath, a tarball if it ends with .tar.
+func Pull(ctx context.Context, src Reference, layoutPath string, opts Options) (result Result, err error) {
+	client := newRegistryClient(src.Host, opts.PlainHTTP, opts.Creds, opts.HTTPClient)
+	if err := client.login(ctx, repositoryScope(src.Repository, "pull")); err != nil {
+		return result, err
+	}
+
+	repo := newRepository(client, src.Repository)
+	root, err := repo.resolve(ctx, src.Reference())
+	if err != nil {
+		return result, err
+	}
+
+	layout, err := createLayout(layoutPath)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err != nil {
+			_ = layout.abort()
+		}
+	}()
+
+	t := &transfer{src: repo, dst: layout, platform: opts.Platform, log: opts.Log}
+	pulled, err := t.run(ctx, root, src.Tag)
+	if err != nil {
+		return result, err
+	}
+	if err := layout.finish(pulled, src.Tag); err != nil {
+		return result, err
+	}
+
+	t.result.Source = src.String()
+	t.result.Destination = layoutPath
+	return t.result, nil
+}
+
+// Push pushes the image of the OCI image layout at layoutPath to dst. layoutRef selects an image of
+// a layout with several.
+func Push(ctx context.Context, layoutPath string, dst Reference, layoutRef string, opts Options) (Result, error) {
+	if dst.Digest != "" {
+		return Result{}, fmt.Errorf("destination %s must be tagged, not addressed by digest", dst)
+	}
+
+	layout, err := openLayout(layoutPath)
+	if err != nil {
+		return Result{}, err
+	}
+	defer layout.Close()
+
+	root, err := layout.root(layoutRef)
+	if err != nil {
+		return Result{}, err
+	}
+
+	client := newRegistryClient(dst.Host, opts.PlainHTTP, opts.Creds, opts.HTTPClient)
+	if err := client.login(ctx, repositoryScope(dst.Repository, "pull,push")); err != nil {
+		return Result{}, err
+	}
+
+	t := &transfer{src: layout, dst: newRepository(client, dst.Repository), platform: opts.Platform, log: opts.Log}
+	if _, err := t.run(ctx, root, dst.Tag); err != nil {
+		return Result{}, err
+	}
+
+	t.result.Source = layoutPath
+	t.result.Destination = dst.String()
+	return t.result, nil
+}
+
+func repositoryScope(repo string, actions string) string {
+	return "repository:" + repo + ":" + actions
+}
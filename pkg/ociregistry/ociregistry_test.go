@@ -0,0 +1,248 @@
+package ociregistry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Reference
+		wantErr string
+	}{
+		{in: "myreg.cr.de-fra.ionos.com/app:1.0", want: Reference{Host: "myreg.cr.de-fra.ionos.com", Repository: "app", Tag: "1.0"}},
+		{in: "myreg.cr.de-fra.ionos.com/team/app", want: Reference{Host: "myreg.cr.de-fra.ionos.com", Repository: "team/app", Tag: "latest"}},
+		{in: "localhost:5000/app@sha256:" + sha("a"), want: Reference{Host: "localhost:5000", Repository: "app", Digest: "sha256:" + sha("a")}},
+		{in: "localhost/app:v1@sha256:" + sha("b"), want: Reference{Host: "localhost", Repository: "app", Tag: "v1", Digest: "sha256:" + sha("b")}},
+		{in: "app:1.0", wantErr: "must start with the registry host"},
+		{in: "library/app:1.0", wantErr: "must start with the registry host"},
+		{in: "myreg.example.com/App:1.0", wantErr: `invalid repository name "App"`},
+		{in: "myreg.example.com/app:-x", wantErr: `invalid tag "-x"`},
+		{in: "myreg.example.com/app@sha256:abc", wantErr: "digest must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseReference(tt.in)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func sha(c string) string {
+	s := ""
+	for len(s) < 64 {
+		s += c
+	}
+	return s
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="harbor-registry",scope="repository:a/b:pull,push"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "harbor-registry",
+		"scope":   "repository:a/b:pull,push",
+	}, params)
+}
+
+func TestPushPull(t *testing.T) {
+	reg := newFakeRegistry(t, "ci", "secret")
+	layout := writeTestLayout(t, "1.0", "linux/amd64")
+	ctx := context.Background()
+
+	result, err := Push(ctx, layout.dir, reg.ref(t, "team/app:1.0"), "", reg.opts())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, layout.root.Digest, result.Digest)
+	assert.Equal(t, mediaTypeOCIManifest, result.MediaType)
+	assert.Equal(t, 3, result.Blobs)
+	assert.Equal(t, layout.root.Digest, reg.repos["team/app"].tags["1.0"])
+	assert.Contains(t, reg.scopes, "repository:team/app:pull,push")
+
+	// Pushing again only moves the tag.
+	result, err = Push(ctx, tarLayout(t, layout.dir, "app.tar"), reg.ref(t, "team/app:latest"), "1.0", reg.opts())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, result.Blobs)
+	assert.Equal(t, 3, result.Existing)
+	assert.Equal(t, layout.root.Digest, reg.repos["team/app"].tags["latest"])
+
+	dir := filepath.Join(t.TempDir(), "pulled")
+	result, err = Pull(ctx, reg.ref(t, "team/app:1.0"), dir, reg.opts())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, result.Blobs)
+
+	pulled, err := openLayout(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer pulled.Close()
+	root, err := pulled.root("1.0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, layout.root.Digest, root.Digest)
+	for _, b := range layout.blobs {
+		assert.FileExists(t, filepath.Join(dir, blobPath(b.Digest)))
+	}
+
+	// Pulling another tag into the same directory adds it to the index and reuses the blobs.
+	result, err = Pull(ctx, reg.ref(t, "team/app:latest"), dir, reg.opts())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, result.Blobs)
+	assert.Equal(t, 3, result.Existing)
+
+	data, err := os.ReadFile(filepath.Join(dir, layoutIndex))
+	if !assert.NoError(t, err) {
+		return
+	}
+	var index manifest
+	_ = json.Unmarshal(data, &index)
+	assert.Len(t, index.Manifests, 2)
+}
+
+func TestPullTarball(t *testing.T) {
+	reg := newFakeRegistry(t, "", "")
+	layout := writeTestLayout(t, "1.0", "linux/amd64")
+	ctx := context.Background()
+
+	if _, err := Push(ctx, tarLayout(t, layout.dir, "app.tar.gz"), reg.ref(t, "app:1.0"), "", reg.opts()); !assert.NoError(t, err) {
+		return
+	}
+
+	p := filepath.Join(t.TempDir(), "app.tar")
+	if _, err := Pull(ctx, reg.ref(t, "app:1.0"), p, reg.opts()); !assert.NoError(t, err) {
+		return
+	}
+
+	pulled, err := openLayout(p)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer pulled.Close()
+	root, err := pulled.root("")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1.0", root.Annotations[AnnotationRefName])
+
+	// The tarball is a complete layout, which can be pushed again.
+	result, err := Push(ctx, p, reg.ref(t, "copy:1.0"), "", reg.opts())
+	if assert.NoError(t, err) {
+		assert.Equal(t, layout.root.Digest, result.Digest)
+	}
+}
+
+func TestPullDigestMismatch(t *testing.T) {
+	reg := newFakeRegistry(t, "", "")
+	layout := writeTestLayout(t, "1.0", "linux/amd64")
+	ctx := context.Background()
+
+	if _, err := Push(ctx, layout.dir, reg.ref(t, "app:1.0"), "", reg.opts()); !assert.NoError(t, err) {
+		return
+	}
+	reg.corrupt = layout.blobs[0].Digest
+
+	dir := t.TempDir()
+	_, err := Pull(ctx, reg.ref(t, "app:1.0"), dir, reg.opts())
+	assert.ErrorContains(t, err, "digest mismatch for "+layout.blobs[0].Digest)
+	assert.NoFileExists(t, filepath.Join(dir, blobPath(layout.blobs[0].Digest)))
+	assert.NoFileExists(t, filepath.Join(dir, layoutIndex))
+
+	tarball := filepath.Join(t.TempDir(), "app.tar")
+	_, err = Pull(ctx, reg.ref(t, "app:1.0"), tarball, reg.opts())
+	assert.Error(t, err)
+	assert.NoFileExists(t, tarball)
+}
+
+func TestCopy(t *testing.T) {
+	reg := newFakeRegistry(t, "ci", "secret")
+	layout := writeTestLayout(t, "1.0", "linux/amd64", "linux/arm64")
+	ctx := context.Background()
+
+	if _, err := Push(ctx, layout.dir, reg.ref(t, "staging/app:1.0"), "", reg.opts()); !assert.NoError(t, err) {
+		return
+	}
+
+	// Within a registry, blobs are mounted.
+	result, err := Copy(ctx, reg.ref(t, "staging/app:1.0"), reg.ref(t, "prod/app:1.0"), reg.opts())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, layout.root.Digest, result.Digest)
+	assert.Equal(t, mediaTypeOCIIndex, result.MediaType)
+	assert.Equal(t, 0, result.Blobs)
+	assert.Equal(t, 5, result.Mounted)
+	assert.Equal(t, 5, reg.mounts)
+	assert.Contains(t, reg.scopes, "repository:staging/app:pull")
+	assert.Len(t, reg.repos["prod/app"].manifests, 3)
+
+	// Between registries, blobs are streamed, with separate credentials for the source.
+	other := newFakeRegistry(t, "prod", "other-secret")
+	opts := other.opts()
+	opts.SourceCreds = &Credentials{Username: "ci", Password: "secret"}
+	opts.Platform = "linux/arm64"
+	result, err = Copy(ctx, reg.ref(t, "prod/app:1.0"), other.ref(t, "app:1.0"), opts)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, layout.manifests[1].Digest, result.Digest)
+	assert.Equal(t, 3, result.Blobs)
+	assert.Equal(t, layout.manifests[1].Digest, other.repos["app"].tags["1.0"])
+
+	opts.Platform = "windows/amd64"
+	_, err = Copy(ctx, reg.ref(t, "prod/app:1.0"), other.ref(t, "app:1.0"), opts)
+	assert.ErrorContains(t, err, "platform windows/amd64 not found, the image is available for: linux/amd64, linux/arm64")
+}
+
+func TestLoginFailures(t *testing.T) {
+	reg := newFakeRegistry(t, "ci", "secret")
+	layout := writeTestLayout(t, "1.0", "linux/amd64")
+
+	opts := reg.opts()
+	opts.Creds.Password = "wrong"
+	_, err := Push(context.Background(), layout.dir, reg.ref(t, "app:1.0"), "", opts)
+	assert.ErrorContains(t, err, "invalid token name or password")
+
+	opts.Creds = Credentials{}
+	_, err = Push(context.Background(), layout.dir, reg.ref(t, "app:1.0"), "", opts)
+	assert.ErrorIs(t, err, ErrAuthRequired)
+}
+
+func TestLayoutRoot(t *testing.T) {
+	layout := writeTestLayout(t, "1.0", "linux/amd64")
+	l, err := openLayout(layout.dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	l.index.Manifests = append(l.index.Manifests, descriptor{Annotations: map[string]string{AnnotationRefName: "2.0"}})
+	_, err = l.root("")
+	assert.ErrorContains(t, err, "contains 2 images (1.0, 2.0), select an image")
+	assert.ErrorIs(t, err, ErrLayoutRefRequired)
+	_, err = l.root("3.0")
+	assert.ErrorContains(t, err, `contains no image named "3.0"`)
+
+	_, err = openLayout(t.TempDir())
+	assert.ErrorContains(t, err, "is not an OCI image layout")
+}
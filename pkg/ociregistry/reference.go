@@ -0,0 +1,79 @@
+package ociregistry
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const defaultTag = "latest"
+
+var (
+	repositoryPattern = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagPattern        = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	digestPattern     = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+)
+
+// Reference is a parsed image reference such as myreg.cr.de-fra.ionos.com/app:1.0 or
+// myreg.cr.de-fra.ionos.com/app@sha256:...
+type Reference struct {
+	Host       string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// ParseReference parses an image reference. Unlike Docker, the registry host is required,
+// since there is no default registry to fall back to.
+func ParseReference(s string) (Reference, error) {
+	var ref Reference
+
+	host, rest, ok := strings.Cut(s, "/")
+	if !ok || !(strings.ContainsAny(host, ".:") || host == "localhost") {
+		return ref, fmt.Errorf("invalid image reference %q: must start with the registry host, e.g. myreg.cr.de-fra.ionos.com/app:1.0", s)
+	}
+	ref.Host = host
+
+	if name, digest, ok := strings.Cut(rest, "@"); ok {
+		if !digestPattern.MatchString(digest) {
+			return ref, fmt.Errorf("invalid image reference %q: digest must be sha256:<64 hex characters>", s)
+		}
+		rest, ref.Digest = name, digest
+	}
+
+	// The tag separator is the last colon after the last slash, the host was cut off already.
+	if i := strings.LastIndex(rest, ":"); i > strings.LastIndex(rest, "/") {
+		rest, ref.Tag = rest[:i], rest[i+1:]
+		if !tagPattern.MatchString(ref.Tag) {
+			return ref, fmt.Errorf("invalid image reference %q: invalid tag %q", s, ref.Tag)
+		}
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	if !repositoryPattern.MatchString(rest) {
+		return ref, fmt.Errorf("invalid image reference %q: invalid repository name %q", s, rest)
+	}
+	ref.Repository = rest
+	return ref, nil
+}
+
+// Reference returns the tag or digest used to address the manifest.
+func (r Reference) Reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+func (r Reference) String() string {
+	s := r.Host + "/" + r.Repository
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
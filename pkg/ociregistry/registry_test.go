@@ -0,0 +1,304 @@
+package ociregistry
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeRegistry is a minimal in-memory OCI distribution registry. If username is set,
+// clients have to get a bearer token from /token first.
+type fakeRegistry struct {
+	username, password string
+
+	mu      sync.Mutex
+	repos   map[string]*fakeRepo
+	uploads map[string]string
+	scopes  []string
+	mounts  int
+	// corrupt makes GET requests for this blob return different content.
+	corrupt string
+
+	server *httptest.Server
+	host   string
+}
+
+type fakeRepo struct {
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	types     map[string]string
+	tags      map[string]string
+}
+
+const fakeToken = "fake-token"
+
+func newFakeRegistry(t *testing.T, username, password string) *fakeRegistry {
+	r := &fakeRegistry{username: username, password: password, repos: map[string]*fakeRepo{}, uploads: map[string]string{}}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	t.Cleanup(r.server.Close)
+	r.host = strings.TrimPrefix(r.server.URL, "http://")
+	return r
+}
+
+func (r *fakeRegistry) repo(name string) *fakeRepo {
+	repo, ok := r.repos[name]
+	if !ok {
+		repo = &fakeRepo{blobs: map[string][]byte{}, manifests: map[string][]byte{}, types: map[string]string{}, tags: map[string]string{}}
+		r.repos[name] = repo
+	}
+	return repo
+}
+
+func (r *fakeRegistry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		if u, p, ok := req.BasicAuth(); !ok || u != r.username || p != r.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.scopes = append(r.scopes, req.URL.Query()["scope"]...)
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": fakeToken})
+		return
+	}
+
+	if r.username != "" && req.Header.Get("Authorization") != "Bearer "+fakeToken {
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake"`, r.server.URL))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if req.URL.Path == "/v2/" {
+		return
+	}
+
+	p := strings.TrimPrefix(req.URL.Path, "/v2/")
+	for _, kind := range []string{"/blobs/uploads/", "/blobs/", "/manifests/"} {
+		if i := strings.LastIndex(p, kind); i > 0 {
+			r.serveRepo(w, req, r.repo(p[:i]), p[:i], kind, p[i+len(kind):])
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (r *fakeRegistry) serveRepo(w http.ResponseWriter, req *http.Request, repo *fakeRepo, name, kind, ref string) {
+	switch {
+	case kind == "/blobs/uploads/" && req.Method == http.MethodPost:
+		if mount := req.URL.Query().Get("mount"); mount != "" {
+			if data, ok := r.repo(req.URL.Query().Get("from")).blobs[mount]; ok {
+				repo.blobs[mount] = data
+				r.mounts++
+				w.WriteHeader(http.StatusCreated)
+				return
+			}
+		}
+		id := fmt.Sprintf("upload-%d", len(r.uploads))
+		r.uploads[id] = name
+		w.Header().Set("Location", "/v2/"+name+"/blobs/uploads/"+id+"?_state=x")
+		w.WriteHeader(http.StatusAccepted)
+
+	case kind == "/blobs/uploads/" && req.Method == http.MethodPut:
+		if r.uploads[ref] != name || req.URL.Query().Get("_state") != "x" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		data, _ := io.ReadAll(req.Body)
+		digest := req.URL.Query().Get("digest")
+		if digestOf(data) != digest {
+			writeRegistryError(w, http.StatusBadRequest, "DIGEST_INVALID")
+			return
+		}
+		repo.blobs[digest] = data
+		w.WriteHeader(http.StatusCreated)
+
+	case kind == "/blobs/":
+		data, ok := repo.blobs[ref]
+		if !ok {
+			writeRegistryError(w, http.StatusNotFound, "BLOB_UNKNOWN")
+			return
+		}
+		if req.Method == http.MethodGet {
+			if ref == r.corrupt {
+				data = []byte(strings.ToUpper(string(data)))
+			}
+			_, _ = w.Write(data)
+		}
+
+	case kind == "/manifests/" && req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		m, err := parseManifest(data)
+		if err != nil {
+			writeRegistryError(w, http.StatusBadRequest, "MANIFEST_INVALID")
+			return
+		}
+		for _, d := range append(m.blobs(), m.Manifests...) {
+			_, blob := repo.blobs[d.Digest]
+			_, child := repo.manifests[d.Digest]
+			if !blob && !child {
+				writeRegistryError(w, http.StatusBadRequest, "MANIFEST_BLOB_UNKNOWN")
+				return
+			}
+		}
+		digest := digestOf(data)
+		repo.manifests[digest] = data
+		repo.types[digest] = req.Header.Get("Content-Type")
+		if !strings.HasPrefix(ref, "sha256:") {
+			repo.tags[ref] = digest
+		}
+		w.WriteHeader(http.StatusCreated)
+
+	case kind == "/manifests/":
+		digest := ref
+		if d, ok := repo.tags[ref]; ok {
+			digest = d
+		}
+		data, ok := repo.manifests[digest]
+		if !ok {
+			writeRegistryError(w, http.StatusNotFound, "MANIFEST_UNKNOWN")
+			return
+		}
+		w.Header().Set("Content-Type", repo.types[digest])
+		w.Header().Set("Docker-Content-Digest", digest)
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func writeRegistryError(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": []map[string]string{{"code": code, "message": strings.ToLower(code)}}})
+}
+
+func (r *fakeRegistry) ref(t *testing.T, s string) Reference {
+	ref, err := ParseReference(r.host + "/" + s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ref
+}
+
+func (r *fakeRegistry) opts() Options {
+	return Options{
+		Creds:      Credentials{Username: r.username, Password: r.password},
+		PlainHTTP:  true,
+		HTTPClient: r.server.Client(),
+	}
+}
+
+// testLayout writes an OCI layout with one image per platform. With a single platform the
+// image manifest is the root, otherwise an index of all of them.
+type testLayout struct {
+	dir       string
+	root      descriptor
+	manifests []descriptor
+	blobs     []descriptor
+}
+
+func writeTestLayout(t *testing.T, refName string, platforms ...string) testLayout {
+	t.Helper()
+	l := testLayout{dir: t.TempDir()}
+
+	writeBlob := func(mediaType string, data []byte) descriptor {
+		d := descriptor{MediaType: mediaType, Digest: digestOf(data), Size: int64(len(data))}
+		p := filepath.Join(l.dir, filepath.FromSlash(blobPath(d.Digest)))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	writeJSON := func(mediaType string, v any) descriptor {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return writeBlob(mediaType, data)
+	}
+
+	layer := writeBlob("application/vnd.oci.image.layer.v1.tar", []byte("shared base layer"))
+	l.blobs = append(l.blobs, layer)
+	for _, p := range platforms {
+		goos, arch, _ := strings.Cut(p, "/")
+		config := writeJSON("application/vnd.oci.image.config.v1+json", map[string]string{"os": goos, "architecture": arch})
+		app := writeBlob("application/vnd.oci.image.layer.v1.tar", []byte("app layer for "+p))
+		l.blobs = append(l.blobs, config, app)
+
+		m := writeJSON(mediaTypeOCIManifest, manifest{SchemaVersion: 2, MediaType: mediaTypeOCIManifest, Config: &config, Layers: []descriptor{layer, app}})
+		m.Platform = &platform{OS: goos, Architecture: arch}
+		l.manifests = append(l.manifests, m)
+	}
+
+	l.root = l.manifests[0]
+	if len(platforms) > 1 {
+		l.root = writeJSON(mediaTypeOCIIndex, manifest{SchemaVersion: 2, MediaType: mediaTypeOCIIndex, Manifests: l.manifests})
+	} else {
+		l.root.Platform = nil
+	}
+
+	index := manifest{SchemaVersion: 2, MediaType: mediaTypeOCIIndex, Manifests: []descriptor{l.root}}
+	index.Manifests[0].Annotations = map[string]string{AnnotationRefName: refName}
+	data, _ := json.Marshal(index)
+	if err := os.WriteFile(filepath.Join(l.dir, layoutIndex), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(l.dir, layoutFile), []byte(`{"imageLayoutVersion":"1.0.0"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
+
+// tarLayout packs a layout directory into a tarball, compressed if the name ends with .gz.
+func tarLayout(t *testing.T, dir, name string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var w io.Writer = f
+	if strings.HasSuffix(name, ".gz") {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, p)
+		if err := tw.WriteHeader(&tar.Header{Name: filepath.ToSlash(rel), Mode: 0o644, Size: fi.Size(), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
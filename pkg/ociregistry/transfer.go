@@ -0,0 +1,182 @@
+package ociregistry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// source is where an image is read from: a registry repository or an OCI layout.
+type source interface {
+	FetchManifest(ctx context.Context, desc descriptor) ([]byte, error)
+	FetchBlob(ctx context.Context, desc descriptor) (io.ReadCloser, error)
+}
+
+// target is where an image is written to: a registry repository or an OCI layout.
+type target interface {
+	Exists(ctx context.Context, desc descriptor) (bool, error)
+	PushBlob(ctx context.Context, desc descriptor, r io.Reader) error
+	// PushManifest stores a manifest by digest, and additionally under tag if it is set.
+	PushManifest(ctx context.Context, desc descriptor, data []byte, tag string) error
+}
+
+// mounter is implemented by targets which can link a blob from another repository
+// of the same registry, without downloading and uploading it again.
+type mounter interface {
+	Mount(ctx context.Context, desc descriptor) (bool, error)
+}
+
+// Result summarizes a Copy, Pull or Push.
+type Result struct {
+	Source      string `json:"Source"`
+	Destination string `json:"Destination"`
+	Digest      string `json:"Digest"`
+	MediaType   string `json:"MediaType"`
+	// Blobs is the number of blobs copied, Bytes their total size.
+	Blobs    int   `json:"Blobs"`
+	Bytes    int64 `json:"Bytes"`
+	Mounted  int   `json:"Mounted"`
+	Existing int   `json:"Existing"`
+}
+
+// transfer copies an image with all manifests and blobs it references from src to dst.
+// Content already present in dst is skipped.
+type transfer struct {
+	src source
+	dst target
+	// platform selects a single image of a multi-platform index, e.g. linux/amd64.
+	platform string
+	log      func(format string, args ...any)
+
+	result Result
+}
+
+// run copies the image described by root and tags it in dst. It returns the descriptor
+// of the manifest which was actually copied, which differs from root if a platform was selected.
+func (t *transfer) run(ctx context.Context, root descriptor, tag string) (descriptor, error) {
+	if t.log == nil {
+		t.log = func(string, ...any) {}
+	}
+
+	desc, err := t.copyManifest(ctx, root, tag, true)
+	if err != nil {
+		return desc, err
+	}
+	t.result.Digest = desc.Digest
+	t.result.MediaType = desc.MediaType
+	return desc, nil
+}
+
+func (t *transfer) copyManifest(ctx context.Context, desc descriptor, tag string, root bool) (descriptor, error) {
+	// Registries only accept a manifest once everything it references exists, so an existing
+	// manifest can be skipped with all its content. The root is always pushed, to move its tag.
+	if !root {
+		exists, err := t.dst.Exists(ctx, desc)
+		if err != nil {
+			return desc, err
+		}
+		if exists {
+			t.result.Existing++
+			return desc, nil
+		}
+	}
+
+	data, err := t.src.FetchManifest(ctx, desc)
+	if err != nil {
+		return desc, err
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return desc, fmt.Errorf("%s: %w", desc.Digest, err)
+	}
+	if desc.MediaType == "" {
+		desc.MediaType = m.MediaType
+	}
+	if !isManifest(desc.MediaType) {
+		return desc, fmt.Errorf("%s: unsupported manifest media type %q", desc.Digest, desc.MediaType)
+	}
+
+	if isIndex(desc.MediaType) {
+		if root && t.platform != "" {
+			child, err := selectPlatform(m, t.platform)
+			if err != nil {
+				return desc, err
+			}
+			return t.copyManifest(ctx, child, tag, true)
+		}
+		for _, child := range m.Manifests {
+			if _, err := t.copyManifest(ctx, child, "", false); err != nil {
+				return desc, err
+			}
+		}
+	} else {
+		if root && t.platform != "" {
+			t.log("%s is not a multi-platform image, ignoring platform %s", desc.Digest, t.platform)
+		}
+		for _, blob := range m.blobs() {
+			if err := t.copyBlob(ctx, blob); err != nil {
+				return desc, err
+			}
+		}
+	}
+
+	if err := t.dst.PushManifest(ctx, desc, data, tag); err != nil {
+		return desc, fmt.Errorf("pushing manifest %s: %w", desc.Digest, err)
+	}
+	return desc, nil
+}
+
+func (t *transfer) copyBlob(ctx context.Context, desc descriptor) error {
+	exists, err := t.dst.Exists(ctx, desc)
+	if err != nil {
+		return err
+	}
+	if exists {
+		t.log("Blob %s already exists", desc.Digest)
+		t.result.Existing++
+		return nil
+	}
+
+	if m, ok := t.dst.(mounter); ok {
+		mounted, err := m.Mount(ctx, desc)
+		if err != nil {
+			return err
+		}
+		if mounted {
+			t.log("Mounted blob %s", desc.Digest)
+			t.result.Mounted++
+			return nil
+		}
+	}
+
+	t.log("Copying blob %s (%d bytes)", desc.Digest, desc.Size)
+	rc, err := t.src.FetchBlob(ctx, desc)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	vr, err := newVerifyingReader(rc, desc)
+	if err != nil {
+		return err
+	}
+	if err := t.dst.PushBlob(ctx, desc, vr); err != nil {
+		return fmt.Errorf("pushing blob %s: %w", desc.Digest, err)
+	}
+
+	t.result.Blobs++
+	t.result.Bytes += desc.Size
+	return nil
+}
+
+func selectPlatform(index manifest, want string) (descriptor, error) {
+	var available []string
+	for _, m := range index.Manifests {
+		if m.Platform.matches(want) {
+			return m, nil
+		}
+		available = append(available, m.Platform.String())
+	}
+	return descriptor{}, fmt.Errorf("platform %s not found, the image is available for: %s", want, strings.Join(available, ", "))
+}
@@ -0,0 +1,188 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReedSolomon(t *testing.T) {
+	// "HELLO WORLD" as version 1-M, from the worked example of the standard.
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t, []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}, reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestFormatAndVersionInformation(t *testing.T) {
+	assert.Equal(t, 0b111011111000100, formatInformation(Low, 0))
+	assert.Equal(t, 0b101010000010010, formatInformation(Medium, 0))
+	assert.Equal(t, 0b110100101110110, formatInformation(Low, 7))
+	assert.Equal(t, 0x07C94, versionInformation(7))
+	assert.Equal(t, 0x28C69, versionInformation(40))
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	assert.Nil(t, alignmentPatternPositions(1))
+	assert.Equal(t, []int{6, 18}, alignmentPatternPositions(2))
+	assert.Equal(t, []int{6, 22, 38}, alignmentPatternPositions(7))
+	assert.Equal(t, []int{6, 26, 48, 70}, alignmentPatternPositions(15))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, alignmentPatternPositions(32))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPatternPositions(40))
+}
+
+func TestCapacity(t *testing.T) {
+	// Byte mode capacities from the standard.
+	for _, tt := range []struct {
+		version int
+		level   Level
+		bytes   int
+	}{
+		{1, Low, 17}, {1, Medium, 14}, {10, Low, 271}, {10, Medium, 213}, {40, Low, 2953}, {40, Medium, 2331},
+	} {
+		assert.Equal(t, tt.bytes, (numDataCodewords(tt.version, tt.level)*8-4-charCountBits(tt.version))/8, "version %d", tt.version)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		data    string
+		level   Level
+		version int
+	}{
+		{"hello", Medium, 1},
+		{strings.Repeat("[Interface]\nPrivateKey = x\n", 10), Low, 10},
+		{strings.Repeat("0123456789", 60), Medium, 19},
+	} {
+		c, err := Encode([]byte(tt.data), tt.level)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, tt.version, c.Version)
+		assert.Equal(t, tt.data, decode(t, c, tt.level))
+	}
+
+	_, err := Encode(make([]byte, 2954), Low)
+	assert.ErrorIs(t, err, ErrTooLong)
+}
+
+func TestWriteTerminal(t *testing.T) {
+	c, err := Encode([]byte("hello"), Low)
+	if !assert.NoError(t, err) {
+		return
+	}
+	var buf bytes.Buffer
+	if !assert.NoError(t, c.WriteTerminal(&buf)) {
+		return
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	// 21 modules and a quiet zone of 2 on each side, two rows per line.
+	assert.Len(t, lines, 13)
+	assert.Equal(t, strings.Repeat("█", 25), lines[0])
+	// The top edge of the finder pattern, the light separator and the light ring inside.
+	assert.Equal(t, "██ ▄▄▄▄▄ █", string([]rune(lines[1])[:10]))
+}
+
+// decode reads the data back from the code: it reads the mask from the format information,
+// unmasks the data modules and undoes the interleaving.
+func decode(t *testing.T, c *Code, level Level) string {
+	t.Helper()
+
+	format := 0
+	for i := 14; i >= 9; i-- {
+		format = format<<1 | bit(c.Dark(14-i, 8))
+	}
+	format = format<<1 | bit(c.Dark(7, 8))
+	format = format<<1 | bit(c.Dark(8, 8))
+	format = format<<1 | bit(c.Dark(8, 7))
+	for i := 5; i >= 0; i-- {
+		format = format<<1 | bit(c.Dark(8, i))
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatInformation(level, m) == format {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("invalid format information %015b", format)
+	}
+
+	ref := newCode(c.Version)
+	ref.drawFunctionPatterns(level)
+	ref.modules = c.modules
+	ref.applyMask(mask)
+	defer ref.applyMask(mask)
+
+	var raw []byte
+	var n int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if ref.function[y][x] {
+					continue
+				}
+				if n%8 == 0 {
+					raw = append(raw, 0)
+				}
+				raw[n/8] |= byte(bit(ref.modules[y][x]) << (7 - n%8))
+				n++
+			}
+		}
+	}
+
+	numBlocks := numErrorCorrectionBlocks[level][c.Version]
+	eccLen := eccCodewordsPerBlock[level][c.Version]
+	rawCodewords := numRawDataModules(c.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortData := rawCodewords/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortData; i++ {
+		for j := range blocks {
+			if i < shortData || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	for _, b := range blocks {
+		data = append(data, b...)
+	}
+
+	if data[0]>>4 != 0b0100 {
+		t.Fatalf("unexpected mode %04b", data[0]>>4)
+	}
+	var bb bitBuffer
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	count := 0
+	for _, b := range bb[4 : 4+charCountBits(c.Version)] {
+		count = count<<1 | bit(b)
+	}
+	out := make([]byte, count)
+	start := 4 + charCountBits(c.Version)
+	for i := range out {
+		for _, b := range bb[start+8*i : start+8*i+8] {
+			out[i] = out[i]<<1 | byte(bit(b))
+		}
+	}
+	return string(out)
+}
+
+func bit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+// Package sarif writes the subset of SARIF 2.1.0 used by code scanning tools, see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+package sarif
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+
+	// InformationURI is the information URI of the ionosctl tool drivers.
+	InformationURI = "https://github.com/ionos-cloud/ionosctl"
+)
+
+// Levels of results.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+type (
+	Log struct {
+		Version string `json:"version"`
+		Schema  string `json:"$schema"`
+		Runs    []Run  `json:"runs"`
+	}
+	Run struct {
+		Tool    Tool     `json:"tool"`
+		Results []Result `json:"results"`
+	}
+	Tool struct {
+		Driver Driver `json:"driver"`
+	}
+	Driver struct {
+		Name           string `json:"name"`
+		InformationURI string `json:"informationUri"`
+		Rules          []Rule `json:"rules"`
+	}
+	Rule struct {
+		ID               string            `json:"id"`
+		ShortDescription Message           `json:"shortDescription"`
+		FullDescription  *Message          `json:"fullDescription,omitempty"`
+		HelpURI          string            `json:"helpUri,omitempty"`
+		Help             *Message          `json:"help,omitempty"`
+		Properties       map[string]any    `json:"properties,omitempty"`
+		DefaultConfig    map[string]string `json:"defaultConfiguration,omitempty"`
+	}
+	Result struct {
+		RuleID       string        `json:"ruleId"`
+		Level        string        `json:"level"`
+		Message      Message       `json:"message"`
+		Locations    []Location    `json:"locations"`
+		Suppressions []Suppression `json:"suppressions,omitempty"`
+	}
+	Message struct {
+		Text string `json:"text"`
+	}
+	// Location is either a physical location, e.g. an artifact, or a logical one, e.g. a cloud resource.
+	Location struct {
+		PhysicalLocation *PhysicalLocation `json:"physicalLocation,omitempty"`
+		LogicalLocations []LogicalLocation `json:"logicalLocations,omitempty"`
+	}
+	PhysicalLocation struct {
+		ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+		Region           Region           `json:"region"`
+	}
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+	Region struct {
+		StartLine int `json:"startLine"`
+	}
+	LogicalLocation struct {
+		Name               string `json:"name"`
+		FullyQualifiedName string `json:"fullyQualifiedName"`
+		Kind               string `json:"kind"`
+	}
+	Suppression struct {
+		Kind          string `json:"kind"`
+		Justification string `json:"justification,omitempty"`
+	}
+)
+
+// NewRun returns a run of the tool driver without rules and results. SARIF requires the lists, even if empty.
+func NewRun(driverName string) Run {
+	return Run{
+		Tool: Tool{Driver: Driver{
+			Name:           driverName,
+			InformationURI: InformationURI,
+			Rules:          []Rule{},
+		}},
+		Results: []Result{},
+	}
+}
+
+// Write writes a log of the runs as indented JSON.
+func Write(w io.Writer, runs ...Run) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Log{Version: Version, Schema: Schema, Runs: runs})
+}
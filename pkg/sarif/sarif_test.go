@@ -0,0 +1,37 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrite(t *testing.T) {
+	run := NewRun("ionosctl test")
+	run.Results = append(run.Results, Result{
+		RuleID:    "rule",
+		Level:     LevelNote,
+		Message:   Message{Text: "found"},
+		Locations: []Location{{LogicalLocations: []LogicalLocation{{Name: "a", FullyQualifiedName: "b", Kind: "resource"}}}},
+	})
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, Write(&buf, run)) {
+		return
+	}
+	var raw map[string]any
+	if !assert.NoError(t, json.Unmarshal(buf.Bytes(), &raw)) {
+		return
+	}
+	assert.Equal(t, "2.1.0", raw["version"])
+	assert.Equal(t, Schema, raw["$schema"])
+
+	runs := raw["runs"].([]any)
+	driver := runs[0].(map[string]any)["tool"].(map[string]any)["driver"].(map[string]any)
+	assert.Equal(t, []any{}, driver["rules"])
+	location := runs[0].(map[string]any)["results"].([]any)[0].(map[string]any)["locations"].([]any)[0].(map[string]any)
+	assert.NotContains(t, location, "physicalLocation")
+	assert.Contains(t, location, "logicalLocations")
+}
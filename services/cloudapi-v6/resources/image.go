@@ -1,17 +1,20 @@
 package resources
 
 import (
-	"bufio"
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
+	"github.com/ionos-cloud/ionosctl/v6/pkg/ftps"
 
 	ionoscloud "github.com/ionos-cloud/sdk-go/v6"
-	"github.com/kardianos/ftps"
 )
 
 type Image struct {
@@ -30,11 +33,13 @@ type ImageProperties struct {
 type UploadProperties struct {
 	ImageFileProperties
 	FTPServerProperties
+	TransferProperties
 }
 
 type ImageFileProperties struct {
-	Path       string // File name, server path (not local) and file extension included
-	DataBuffer *bufio.Reader
+	Path string // File name, server path (not local) and file extension included
+	Data io.ReadSeeker
+	Size int64 // Size of Data in bytes
 }
 type FTPServerProperties struct {
 	Url               string // Server URL without any directory path. Example: ftp-fkb.ionos.com
@@ -45,6 +50,28 @@ type FTPServerProperties struct {
 	Password          string
 }
 
+// TransferProperties control how the image is transferred and verified.
+type TransferProperties struct {
+	Resume       bool // Continue an interrupted upload from the size of the file already on the server
+	SkipChecksum bool // Only compare the size of the uploaded file, not its SHA-256
+	// ResumeUnverified resumes even if the part of the file already on the server cannot be compared with the
+	// local file, because the server cannot hash files or with SkipChecksum.
+	ResumeUnverified bool
+	// Wrap wraps the data sent to the server, e.g. to report progress or to limit the bandwidth.
+	// offset is the number of bytes already on the server. Optional.
+	Wrap func(offset int64, r io.Reader) io.Reader
+}
+
+// UploadResult describes a finished upload.
+type UploadResult struct {
+	Offset   int64  // Bytes already on the server from an interrupted upload, which were not sent again
+	Sha256   string // Hex encoded SHA-256 of the local file, empty with SkipChecksum
+	Verified string // How the uploaded file was verified: "sha256", or "size" if the server cannot hash files
+	// Unverified is the number of bytes at the start of the file which were already on the server, and could
+	// not be compared with the local file. Only the size of the file was verified then.
+	Unverified int64
+}
+
 // ImagesService is a wrapper around ionoscloud.Image
 type ImagesService interface {
 	List() (Images, *Response, error)
@@ -67,57 +94,130 @@ func NewImageService(client *client.Client, ctx context.Context) ImagesService {
 	}
 }
 
-func FtpUpload(ctx context.Context, p UploadProperties) error {
+func FtpUpload(ctx context.Context, p UploadProperties) (UploadResult, error) {
 	tlsConfig := tls.Config{
 		InsecureSkipVerify: p.SkipVerify,
 		ServerName:         p.Url,
 		RootCAs:            p.ServerCertificate,
 		MaxVersion:         tls.VersionTLS12,
 	}
-	dialOptions := ftps.DialOptions{
-		Host:        p.Url,
-		Port:        p.Port,
-		Username:    p.Username,
-		Passowrd:    p.Password,
-		ExplicitTLS: true,
-		TLSConfig:   &tlsConfig,
-	}
-
-	c, err := ftps.Dial(ctx, dialOptions)
+	c, err := ftps.Dial(ctx, ftps.DialOptions{
+		Host:      p.Url,
+		Port:      p.Port,
+		Username:  p.Username,
+		Password:  p.Password,
+		TLSConfig: &tlsConfig,
+	})
 	if err != nil {
-		return fmt.Errorf("dialing FTP server failed. Check username & password. FTP server doesn't support usage of JWT token: %w", err)
+		return UploadResult{}, fmt.Errorf("dialing FTP server failed. Check username & password. FTP server doesn't support usage of JWT token: %w", err)
 	}
+	defer c.Close()
 
 	err = c.Chdir(filepath.Dir(p.Path))
 	if err != nil {
-		return fmt.Errorf("failed while changing directory within FTP server: %w", err)
+		return UploadResult{}, fmt.Errorf("failed while changing directory within FTP server: %w", err)
 	}
 
-	files, err := c.List(ctx)
-	if err != nil {
-		return fmt.Errorf("failed while listing files within FTP server: %w", err)
+	// Check if there already exists an image with the given name at the location, e.g. from an interrupted upload
+	desiredFileName := filepath.Base(p.Path)
+	var result UploadResult
+	remoteSize, err := c.Size(desiredFileName)
+	switch {
+	case errors.Is(err, ftps.ErrNotFound):
+	case err != nil:
+		return result, fmt.Errorf("failed while checking for %s on FTP server: %w", desiredFileName, err)
+	case !p.Resume:
+		return result, fmt.Errorf("%s already exists at %s. If a previous upload of it was interrupted, use --resume to continue it. "+
+			"Otherwise, please contact support at support@cloud.ionos.com to delete the old image - or choose a different image name. We're sorry for the inconvenience", desiredFileName, p.Url)
+	case remoteSize > p.Size:
+		return result, fmt.Errorf("cannot resume: %s at %s is larger (%d bytes) than the local image (%d bytes), so it is not an interrupted upload of it", desiredFileName, p.Url, remoteSize, p.Size)
+	default:
+		result.Offset = remoteSize
 	}
 
-	// Check if there already exists an image with the given name at the location
-	desiredFileName := filepath.Base(p.Path)
-	var errExists error
-	for _, f := range files {
-		if f.Name == desiredFileName {
-			errExists = fmt.Errorf("%s might already exist at %s. Please contact support at support@cloud.ionos.com to delete the old image - or choose a different image name. We're sorry for the inconvenience", desiredFileName, p.Url)
+	// The SHA-256 covers the whole image, so the part already on the server is read, but not sent again
+	hash := sha256.New()
+	if p.SkipChecksum {
+		if _, err = p.Data.Seek(result.Offset, io.SeekStart); err != nil {
+			return result, fmt.Errorf("failed seeking to offset %d of the image: %w", result.Offset, err)
+		}
+	} else if _, err = io.CopyN(hash, p.Data, result.Offset); err != nil {
+		return result, fmt.Errorf("failed hashing the image: %w", err)
+	}
+	if result.Offset > 0 {
+		if err = verifyResumeOffset(c, p, desiredFileName, result.Offset, hex.EncodeToString(hash.Sum(nil))); err != nil {
+			if !errors.Is(err, errResumeUnverifiable) {
+				return result, err
+			}
+			if !p.ResumeUnverified {
+				return result, fmt.Errorf("cannot resume: %w. Use --resume-unverified to resume anyway, "+
+					"if %s at %s is known to be an interrupted upload of the image", err, desiredFileName, p.Url)
+			}
+			result.Unverified = result.Offset
 		}
 	}
 
-	err = c.Upload(ctx, desiredFileName, p.DataBuffer)
-	if err != nil {
-		err = fmt.Errorf("failed while uploading %s to FTP server: %w", desiredFileName, err)
-		if errExists != nil {
-			err = fmt.Errorf("%w\nNote: %w", err, errExists)
+	var data io.Reader = p.Data
+	if !p.SkipChecksum {
+		data = io.TeeReader(p.Data, hash)
+	}
+	if p.Wrap != nil {
+		data = p.Wrap(result.Offset, data)
+	}
+	if result.Offset < p.Size {
+		err = c.Store(ctx, desiredFileName, result.Offset, data)
+		if err != nil {
+			return result, fmt.Errorf("failed while uploading %s to FTP server, use --resume to continue the upload: %w", desiredFileName, err)
+		}
+	} else if _, err = io.Copy(io.Discard, data); err != nil {
+		return result, fmt.Errorf("failed hashing the image: %w", err)
+	}
+
+	if !p.SkipChecksum {
+		result.Sha256 = hex.EncodeToString(hash.Sum(nil))
+		remoteSha256, err := c.Hash(desiredFileName)
+		switch {
+		case errors.Is(err, ftps.ErrHashUnsupported):
+		case err != nil:
+			return result, fmt.Errorf("failed verifying the checksum of %s on FTP server: %w", desiredFileName, err)
+		case remoteSha256 != result.Sha256:
+			return result, fmt.Errorf("checksum mismatch for %s at %s: SHA-256 of the local image is %s, but %s on the FTP server", desiredFileName, p.Url, result.Sha256, remoteSha256)
+		default:
+			result.Verified = "sha256"
+			return result, nil
 		}
-		return err
+	}
 
+	remoteSize, err = c.Size(desiredFileName)
+	if err != nil {
+		return result, fmt.Errorf("failed verifying the size of %s on FTP server: %w", desiredFileName, err)
+	}
+	if remoteSize != p.Size {
+		return result, fmt.Errorf("size mismatch for %s at %s: the local image has %d bytes, but %d on the FTP server", desiredFileName, p.Url, p.Size, remoteSize)
 	}
+	result.Verified = "size"
+	return result, nil
+}
 
-	return c.Close()
+// errResumeUnverifiable is returned by verifyResumeOffset if the part of the file on the server cannot be verified.
+var errResumeUnverifiable = errors.New("the part already on the server cannot be verified")
+
+// verifyResumeOffset compares the file on the server, the first offset bytes of the image, with prefixSha256, the
+// SHA-256 of these bytes of the local image.
+func verifyResumeOffset(c *ftps.Client, p UploadProperties, name string, offset int64, prefixSha256 string) error {
+	if p.SkipChecksum {
+		return fmt.Errorf("%w with --skip-checksum", errResumeUnverifiable)
+	}
+	remoteSha256, err := c.Hash(name)
+	switch {
+	case errors.Is(err, ftps.ErrHashUnsupported):
+		return fmt.Errorf("%w, the FTP server cannot hash files", errResumeUnverifiable)
+	case err != nil:
+		return fmt.Errorf("failed verifying the part of %s on FTP server: %w", name, err)
+	case remoteSha256 != prefixSha256:
+		return fmt.Errorf("cannot resume: the first %d bytes of %s at %s differ from the local image, so it is not an interrupted upload of it", offset, name, p.Url)
+	}
+	return nil
 }
 
 func (s *imagesService) List() (Images, *Response, error) {
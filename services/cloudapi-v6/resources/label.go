@@ -2,6 +2,8 @@ package resources
 
 import (
 	"context"
+	"net/url"
+	"strconv"
 
 	"github.com/ionos-cloud/ionosctl/v6/internal/client"
 
@@ -70,10 +72,58 @@ func (svc *labelResourcesService) GetByUrn(labelurn string) (*Label, *Response,
 	return &Label{ls}, &Response{*res}, err
 }
 
+// labelsPageLimit is the number of labels List requests per page.
+const labelsPageLimit = 1000
+
+// List returns all labels. It requests them page by page, until a page has less than labelsPageLimit labels, or
+// only labels of the previous pages, in case offset and limit are ignored.
 func (svc *labelResourcesService) List() (Labels, *Response, error) {
-	req := svc.client.LabelsApi.LabelsGet(svc.context)
-	ls, res, err := svc.client.LabelsApi.LabelsGetExecute(req)
-	return Labels{ls}, &Response{*res}, err
+	var all Labels
+	items := []ionoscloud.Label{}
+	seen := map[string]bool{}
+	for offset := 0; ; offset += labelsPageLimit {
+		client := svc.pageClient(offset, labelsPageLimit)
+		ls, res, err := client.LabelsApi.LabelsGetExecute(client.LabelsApi.LabelsGet(svc.context))
+		if err != nil {
+			if res == nil {
+				return Labels{ls}, nil, err
+			}
+			return Labels{ls}, &Response{*res}, err
+		}
+		all.Labels = ls
+		page := ls.GetItems()
+		if page == nil {
+			page = &[]ionoscloud.Label{}
+		}
+		added := false
+		for _, l := range *page {
+			if l.Id != nil {
+				if seen[*l.Id] {
+					continue
+				}
+				seen[*l.Id] = true
+			}
+			items = append(items, l)
+			added = true
+		}
+		if len(*page) < labelsPageLimit || !added {
+			all.Items = &items
+			return all, &Response{*res}, nil
+		}
+	}
+}
+
+// pageClient returns a client that requests the page of limit items at offset. The labels request of the SDK has no
+// offset and limit, so they are set as default query parameters of a copy of the configuration.
+func (svc *labelResourcesService) pageClient(offset, limit int) *ionoscloud.APIClient {
+	cfg := *svc.client.GetConfig()
+	cfg.DefaultQueryParams = url.Values{}
+	for k, v := range svc.client.GetConfig().DefaultQueryParams {
+		cfg.DefaultQueryParams[k] = v
+	}
+	cfg.DefaultQueryParams.Set("offset", strconv.Itoa(offset))
+	cfg.DefaultQueryParams.Set("limit", strconv.Itoa(limit))
+	return ionoscloud.NewAPIClient(&cfg)
 }
 
 func (svc *labelResourcesService) DatacenterList(datacenterId string) (LabelResources, *Response, error) {